@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Meeting time zones must resolve even where the host lacks a zoneinfo database
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -59,7 +66,7 @@ func main() {
 	}
 
 	// Initialize router
-	router := routes.Setup(db, *cfg)
+	router, shutdown := routes.Setup(db, *cfg)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -71,7 +78,24 @@ func main() {
 	log.Printf("📊 Database: %s:%s/%s", cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
 	log.Printf("🔧 Environment: %s", cfg.Server.GinMode)
 
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	server := &http.Server{Addr: ":" + port, Handler: router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	// Stop on SIGINT or SIGTERM, letting requests in flight finish and
+	// leaving the WebSocket hub cluster
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown error: %v", err)
 	}
+	shutdown()
 }
\ No newline at end of file
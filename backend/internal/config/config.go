@@ -1,7 +1,12 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -15,6 +20,11 @@ type Config struct {
 	Redis    RedisConfig
 	LiveKit  LiveKitConfig
 	TURN     TURNConfig
+	WebSocket WebSocketConfig
+	Meeting   MeetingConfig
+	Attachment AttachmentConfig
+	Chat       ChatConfig
+	WebRTC     WebRTCConfig
 }
 
 type ServerConfig struct {
@@ -62,6 +72,62 @@ type TURNConfig struct {
 	Secret string
 }
 
+type MeetingConfig struct {
+	EmptyGracePeriod    time.Duration // How long a live meeting may stay empty before it ends
+	PasscodeMaxAttempts int           // Wrong passcodes a client may enter before being locked out
+	PasscodeLockout     time.Duration // How long the lockout lasts
+	JoinURLBase         string        // Frontend meeting page; the meeting ID is appended to build join links
+	CalendarFeedURLBase string        // Public calendar feed endpoint; the feed token is appended
+}
+
+type AttachmentConfig struct {
+	Driver        string        // "local" or "s3"
+	MaxSize       int64         // Largest accepted upload, in bytes
+	AllowedTypes  []string      // MIME types accepted after content sniffing
+	URLExpiry     time.Duration // Lifetime of signed download URLs
+	SigningSecret string        // Signs local download URLs; derived from the JWT secret unless set
+	UnclaimedTTL  time.Duration // Uploads not sent in a message by then are deleted
+	LocalDir      string        // Where the local driver keeps files
+	LocalURLBase  string        // Public endpoint serving local files; the object key is appended
+	S3Endpoint    string        // S3-compatible endpoint, e.g. https://s3.amazonaws.com or a MinIO URL
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3PathStyle   bool          // Address the bucket in the path rather than the host name (MinIO)
+}
+
+type ChatConfig struct {
+	FilterWords     []string // Words the content filter acts on
+	FilterAction    string   // What it does with them: "block", "mask" or "flag"
+	FilterRulesFile string   // JSON file of further rules: [{"name", "words" or "pattern", "action"}]
+	CustomEmoji     []string // Custom emoji names, like ":partyparrot:", accepted as reactions
+	CustomEmojiOnly bool     // Accept only the custom emoji as reactions, not any Unicode emoji
+}
+
+type WebSocketConfig struct {
+	HubBackend        string // "memory" (single node) or "redis" (clustered)
+	NodeID            string
+	EventLogSize      int           // Chat and roster events kept per meeting for reconnecting clients
+	EventLogRetention time.Duration // How long a quiet meeting's events are kept
+}
+
+// WebRTCConfig holds the default media policy of meetings that set none of
+// their own, and limits on the signaling that goes through the server
+type WebRTCConfig struct {
+	AllowedCodecs []string // Codecs peers may negotiate, like "opus" or "vp8"; empty allows any
+	MaxBitrate    int      // Kbps per video stream, written into the SDP as b=AS; 0 is unlimited
+	Simulcast     bool     // Let peers send simulcast layers
+	PrivacyMode   bool     // Strip host candidates so peers never learn each other's local addresses
+	MaxSDPSize    int      // Largest offer or answer accepted, in bytes
+
+	ICERestartTimeout        time.Duration // How long a peer may stay disconnected or failed before an ICE restart is requested
+	MaxRenegotiationAttempts int           // ICE restarts requested per peer pair before giving up on it
+
+	StatsRetention   time.Duration // How long client-reported quality samples are kept
+	StatsMinInterval time.Duration // Shortest time between two quality samples of a peer
+}
+
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
@@ -101,9 +167,77 @@ func Load() *Config {
 			Server: getEnv("TURN_SERVER", "127.0.0.1"),
 			Secret: getEnv("TURN_SECRET", "your-turn-secret-key"),
 		},
+		WebSocket: WebSocketConfig{
+			HubBackend:        getEnv("WS_HUB_BACKEND", "memory"),
+			NodeID:            getEnv("WS_NODE_ID", defaultNodeID()),
+			EventLogSize:      getIntEnv("WS_EVENT_LOG_SIZE", 500),
+			EventLogRetention: getDurationEnv("WS_EVENT_LOG_RETENTION", 15*time.Minute),
+		},
+		Meeting: MeetingConfig{
+			EmptyGracePeriod:    getDurationEnv("MEETING_EMPTY_GRACE_PERIOD", 5*time.Minute),
+			PasscodeMaxAttempts: getIntEnv("MEETING_PASSCODE_MAX_ATTEMPTS", 5),
+			PasscodeLockout:     getDurationEnv("MEETING_PASSCODE_LOCKOUT", 15*time.Minute),
+			JoinURLBase:         getEnv("MEETING_JOIN_URL_BASE", "http://localhost:3000/meeting"),
+			CalendarFeedURLBase: getEnv("CALENDAR_FEED_URL_BASE", "http://localhost:8080/api/v1/calendar/feed"),
+		},
+		Attachment: AttachmentConfig{
+			Driver:        getEnv("ATTACHMENT_DRIVER", "local"),
+			MaxSize:       int64(getIntEnv("ATTACHMENT_MAX_SIZE_MB", 25)) << 20,
+			AllowedTypes:  getStringSliceEnv("ATTACHMENT_ALLOWED_TYPES", []string{"image/jpeg", "image/png", "image/gif", "image/webp", "application/pdf", "text/plain", "application/zip"}),
+			URLExpiry:     getDurationEnv("ATTACHMENT_URL_EXPIRY", 15*time.Minute),
+			SigningSecret: getEnv("ATTACHMENT_SIGNING_SECRET", deriveSecret(getEnv("JWT_SECRET", "your-secret-key"), "attachment-url-signing")),
+			UnclaimedTTL:  getDurationEnv("ATTACHMENT_UNCLAIMED_TTL", 24*time.Hour),
+			LocalDir:      getEnv("ATTACHMENT_LOCAL_DIR", "./data/attachments"),
+			LocalURLBase:  getEnv("ATTACHMENT_LOCAL_URL_BASE", "http://localhost:8080/api/v1/attachments/files"),
+			S3Endpoint:    getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			S3Region:      getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:      getEnv("S3_BUCKET", ""),
+			S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle:   getBoolEnv("S3_PATH_STYLE", true),
+		},
+		Chat: ChatConfig{
+			FilterWords:     getStringSliceEnv("CHAT_FILTER_WORDS", nil),
+			FilterAction:    getEnv("CHAT_FILTER_ACTION", "mask"),
+			FilterRulesFile: getEnv("CHAT_FILTER_RULES_FILE", ""),
+			CustomEmoji:     getStringSliceEnv("CHAT_CUSTOM_EMOJI", nil),
+			CustomEmojiOnly: getBoolEnv("CHAT_CUSTOM_EMOJI_ONLY", false),
+		},
+		WebRTC: WebRTCConfig{
+			AllowedCodecs: getStringSliceEnv("WEBRTC_ALLOWED_CODECS", nil),
+			MaxBitrate:    getIntEnv("WEBRTC_MAX_BITRATE", 0),
+			Simulcast:     getBoolEnv("WEBRTC_SIMULCAST", true),
+			PrivacyMode:   getBoolEnv("WEBRTC_PRIVACY_MODE", false),
+			MaxSDPSize:    getIntEnv("WEBRTC_MAX_SDP_SIZE", 64<<10),
+
+			ICERestartTimeout:        getDurationEnv("WEBRTC_ICE_RESTART_TIMEOUT", 5*time.Second),
+			MaxRenegotiationAttempts: getIntEnv("WEBRTC_MAX_RENEGOTIATION_ATTEMPTS", 3),
+
+			StatsRetention:   getDurationEnv("WEBRTC_STATS_RETENTION", 24*time.Hour),
+			StatsMinInterval: getDurationEnv("WEBRTC_STATS_MIN_INTERVAL", 2*time.Second),
+		},
 	}
 }
 
+// defaultNodeID derives a node identifier unique to this process
+func defaultNodeID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "node"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// deriveSecret derives a key for one purpose from a master secret with HKDF,
+// so that a key leaked from that use cannot sign anything else
+func deriveSecret(master, purpose string) string {
+	key, err := hkdf.Key(sha256.New, []byte(master), nil, purpose, sha256.Size)
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(key)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -120,6 +254,24 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 func getStringSliceEnv(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		// Split by comma and trim spaces
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+	"github.com/your-org/gomeet-backend/internal/services"
+	"github.com/your-org/gomeet-backend/internal/utils"
+)
+
+// multipartOverhead is the room left in the request body for the multipart
+// framing around the file
+const multipartOverhead = 1 << 20
+
+type AttachmentController struct {
+	attachmentService *services.AttachmentService
+	publicUserService *services.PublicUserService
+	localStorage      *services.LocalAttachmentStorage // Set when files are served by the backend
+}
+
+func NewAttachmentController(attachmentService *services.AttachmentService, publicUserService *services.PublicUserService, storage models.AttachmentStorage) *AttachmentController {
+	localStorage, _ := storage.(*services.LocalAttachmentStorage)
+	return &AttachmentController{
+		attachmentService: attachmentService,
+		publicUserService: publicUserService,
+		localStorage:      localStorage,
+	}
+}
+
+// UploadAttachment handles uploading a file to a meeting's chat
+// @Summary Upload chat attachment
+// @Description Upload a file for the meeting chat as multipart form field "file". The type is detected from the content and must be allowed; images get a thumbnail. Send it by passing the returned ID as attachmentId with a chat message.
+// @Tags chat
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Meeting ID"
+// @Param sessionId query string false "Session ID for public users"
+// @Param file formData file true "File to upload"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 413 {object} utils.ErrorResponse
+// @Failure 415 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/attachments [post]
+func (c *AttachmentController) UploadAttachment(ctx *gin.Context) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+
+	actor, ok := c.resolveActor(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.attachmentService.MaxSize()+multipartOverhead)
+	file, header, err := ctx.Request.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.handleError(ctx, errors.New("attachment too large"))
+			return
+		}
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_REQUEST", "A file is required in the \"file\" form field")
+		return
+	}
+	defer file.Close()
+
+	attachment, err := c.attachmentService.Upload(meetingID, actor, header.Filename, file)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, attachment.ToResponse(), "Attachment uploaded successfully")
+}
+
+// GetAttachment handles fetching an attachment with fresh download URLs
+// @Summary Get chat attachment
+// @Description Get an attachment of the meeting chat with newly signed download and thumbnail URLs (meeting members only)
+// @Tags chat
+// @Produce json
+// @Param id path string true "Meeting ID"
+// @Param attachmentId path string true "Attachment ID"
+// @Param sessionId query string false "Session ID for public users"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/attachments/{attachmentId} [get]
+func (c *AttachmentController) GetAttachment(ctx *gin.Context) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+	attachmentID, err := uuid.Parse(ctx.Param("attachmentId"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_ATTACHMENT_ID", "Invalid attachment ID")
+		return
+	}
+
+	actor, ok := c.resolveActor(ctx)
+	if !ok {
+		return
+	}
+
+	attachment, err := c.attachmentService.GetAttachment(meetingID, attachmentID, actor)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, attachment.ToResponse(), "Attachment retrieved successfully")
+}
+
+// ServeFile serves a file of the local storage driver through a signed URL
+// @Summary Download attachment file
+// @Description Download an attachment file through a signed URL handed out with the attachment. The signature authenticates the request and the URL expires.
+// @Tags chat
+// @Produce octet-stream
+// @Param key path string true "Object key"
+// @Param expires query int true "Expiry (Unix time)"
+// @Param signature query string true "Signature"
+// @Success 200 {file} file
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/attachments/files/{key} [get]
+func (c *AttachmentController) ServeFile(ctx *gin.Context) {
+	if c.localStorage == nil {
+		utils.NotFoundResponse(ctx, "Attachment not found")
+		return
+	}
+
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	file, download, err := c.localStorage.Open(key, ctx.Request.URL.Query())
+	if err != nil {
+		switch err.Error() {
+		case "invalid signature", "url expired":
+			utils.ForbiddenResponse(ctx, "Download link is invalid or has expired")
+		case "attachment not found", "invalid attachment key":
+			utils.NotFoundResponse(ctx, "Attachment not found")
+		default:
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	ctx.Header("Content-Type", download.ContentType)
+	ctx.Header("Content-Disposition", download.ContentDisposition())
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Header("Cache-Control", "private, max-age=300")
+	http.ServeContent(ctx.Writer, ctx.Request, "", info.ModTime(), file)
+}
+
+// resolveActor identifies the caller: an authenticated user, or a public
+// user through the sessionId query parameter
+func (c *AttachmentController) resolveActor(ctx *gin.Context) (models.MeetingActor, bool) {
+	if userID, exists := utils.GetUserIDUUID(ctx); exists {
+		return models.MeetingActor{UserID: &userID}, true
+	}
+
+	sessionID := ctx.Query("sessionId")
+	if sessionID == "" {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "SESSION_REQUIRED", "Session ID required for public users")
+		return models.MeetingActor{}, false
+	}
+	publicUser, err := c.publicUserService.GetPublicUserBySessionID(sessionID)
+	if err != nil {
+		utils.UnauthorizedResponse(ctx, "Invalid session")
+		return models.MeetingActor{}, false
+	}
+	return models.MeetingActor{PublicUserID: &publicUser.ID}, true
+}
+
+// handleError maps attachment service errors to responses
+func (c *AttachmentController) handleError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "meeting not found":
+		utils.NotFoundResponse(ctx, "Meeting not found")
+	case "attachment not found":
+		utils.NotFoundResponse(ctx, "Attachment not found")
+	case "not a participant":
+		utils.ForbiddenResponse(ctx, "You are not a participant of this meeting")
+	case "permission denied":
+		utils.ForbiddenResponse(ctx, "Your role does not allow sending files")
+	case "attachment too large":
+		utils.SendErrorResponse(ctx, http.StatusRequestEntityTooLarge, "ATTACHMENT_TOO_LARGE",
+			fmt.Sprintf("Attachments may be at most %d MB", c.attachmentService.MaxSize()>>20))
+	case "attachment is empty":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "EMPTY_ATTACHMENT", "Attachment is empty")
+	case "unsupported attachment type":
+		utils.SendErrorResponse(ctx, http.StatusUnsupportedMediaType, "UNSUPPORTED_ATTACHMENT_TYPE", "This type of file cannot be sent")
+	default:
+		utils.InternalServerErrorResponse(ctx, err.Error())
+	}
+}
@@ -1,10 +1,13 @@
 package controllers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 
 	"github.com/your-org/gomeet-backend/internal/models"
 	"github.com/your-org/gomeet-backend/internal/services"
@@ -47,7 +50,7 @@ func (c *AuthController) Register(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.authService.Register(&req)
+	response, err := c.authService.Register(&req, clientInfo(ctx))
 	if err != nil {
 		utils.SendErrorResponse(ctx, http.StatusConflict, "AUTH_003", err.Error())
 		return
@@ -80,7 +83,7 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.authService.Login(&req)
+	response, err := c.authService.Login(&req, clientInfo(ctx))
 	if err != nil {
 		utils.SendErrorResponse(ctx, http.StatusUnauthorized, "AUTH_001", err.Error())
 		return
@@ -115,7 +118,7 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.authService.RefreshToken(req.RefreshToken)
+	response, err := c.authService.RefreshToken(req.RefreshToken, clientInfo(ctx))
 	if err != nil {
 		utils.SendErrorResponse(ctx, http.StatusUnauthorized, "AUTH_005", err.Error())
 		return
@@ -220,6 +223,7 @@ func (c *AuthController) UpdateProfile(ctx *gin.Context) {
 	var req struct {
 		Username string `json:"username,omitempty" validate:"omitempty,min=2,max=255"`
 		Email    string `json:"email,omitempty" validate:"omitempty,email"`
+		TimeZone string `json:"timeZone,omitempty" validate:"omitempty,max=64"` // IANA name, e.g. Europe/Berlin
 	}
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -232,7 +236,7 @@ func (c *AuthController) UpdateProfile(ctx *gin.Context) {
 		return
 	}
 
-	response, err := c.authService.UpdateProfile(userID, req.Username, req.Email)
+	response, err := c.authService.UpdateProfile(userID, req.Username, req.Email, req.TimeZone)
 	if err != nil {
 		if err.Error() == "user not found" {
 			utils.SendErrorResponse(ctx, http.StatusNotFound, "AUTH_002", err.Error())
@@ -242,6 +246,10 @@ func (c *AuthController) UpdateProfile(ctx *gin.Context) {
 			utils.SendErrorResponse(ctx, http.StatusConflict, "AUTH_003", err.Error())
 			return
 		}
+		if err.Error() == "invalid time zone" {
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_TIME_ZONE", "Time zone must be an IANA name such as Europe/Berlin")
+			return
+		}
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
 	}
@@ -251,14 +259,145 @@ func (c *AuthController) UpdateProfile(ctx *gin.Context) {
 
 // Logout handles user logout
 // @Summary Logout user
-// @Description Logout user (client-side token removal)
+// @Description Revoke the session the refresh token belongs to, or the session of the bearer token when no refresh token is sent
 // @Tags auth
+// @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param request body map[string]string false "Refresh token"
 // @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
 // @Router /api/auth/logout [post]
 func (c *AuthController) Logout(ctx *gin.Context) {
-	// In a stateless JWT implementation, logout is handled client-side
-	// by removing the tokens from storage
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	var err error
+	if req.RefreshToken != "" {
+		err = c.authService.Logout(req.RefreshToken)
+	} else if userID, exists := utils.GetUserID(ctx); exists && utils.GetSessionID(ctx) != uuid.Nil {
+		err = c.authService.RevokeSession(userID, utils.GetSessionID(ctx))
+	} else {
+		utils.UnauthorizedResponse(ctx, "Refresh token or authorization header is required")
+		return
+	}
+
+	if err != nil {
+		switch err.Error() {
+		case "invalid refresh token", "session not found":
+			utils.SendErrorResponse(ctx, http.StatusUnauthorized, "AUTH_005", err.Error())
+		default:
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+
 	utils.SuccessResponse(ctx, http.StatusOK, nil, "Logout successful")
-}
\ No newline at end of file
+}
+
+// GetSessions handles listing the user's active sessions
+// @Summary Get active sessions
+// @Description Get the authenticated user's active sessions (logged-in devices)
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/auth/sessions [get]
+func (c *AuthController) GetSessions(ctx *gin.Context) {
+	userID, exists := utils.GetUserID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	sessions, err := c.authService.GetSessions(userID, utils.GetSessionID(ctx))
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, gin.H{"sessions": sessions}, "Sessions retrieved successfully")
+}
+
+// RevokeSession handles revoking one of the user's sessions
+// @Summary Revoke session
+// @Description Sign out a single device of the authenticated user
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/auth/sessions/{sessionId} [delete]
+func (c *AuthController) RevokeSession(ctx *gin.Context) {
+	userID, exists := utils.GetUserID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	sessionID, err := uuid.Parse(ctx.Param("sessionId"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_SESSION_ID", "Invalid session ID format")
+		return
+	}
+
+	if err := c.authService.RevokeSession(userID, sessionID); err != nil {
+		if err.Error() == "session not found" {
+			utils.NotFoundResponse(ctx, err.Error())
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, nil, "Session revoked successfully")
+}
+
+// RevokeAllSessions handles revoking all of the user's sessions
+// @Summary Revoke all sessions
+// @Description Sign out every device of the authenticated user
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/auth/sessions [delete]
+func (c *AuthController) RevokeAllSessions(ctx *gin.Context) {
+	userID, exists := utils.GetUserID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	if err := c.authService.RevokeAllSessions(userID); err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, nil, "All sessions revoked successfully")
+}
+
+// clientInfo extracts the device information stored with a session
+func clientInfo(ctx *gin.Context) services.ClientInfo {
+	userAgent := ctx.Request.UserAgent()
+	if len(userAgent) > 500 {
+		userAgent = userAgent[:500]
+	}
+	return services.ClientInfo{
+		UserAgent: userAgent,
+		IPAddress: ctx.ClientIP(),
+	}
+}
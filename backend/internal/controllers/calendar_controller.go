@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+	"github.com/your-org/gomeet-backend/internal/services"
+	"github.com/your-org/gomeet-backend/internal/utils"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+type CalendarController struct {
+	calendarService *services.CalendarService
+}
+
+func NewCalendarController(calendarService *services.CalendarService) *CalendarController {
+	return &CalendarController{
+		calendarService: calendarService,
+	}
+}
+
+// ExportMeeting handles exporting a meeting as an iCalendar file
+// @Summary Export meeting to calendar
+// @Description Download the meeting as an iCalendar (.ics) event with the join link, the host as organizer and the invitees as attendees (host and participants only)
+// @Tags calendar
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {string} string "iCalendar data"
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/ics [get]
+func (c *CalendarController) ExportMeeting(ctx *gin.Context) {
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+
+	data, err := c.calendarService.GetMeetingICS(meetingID, userUUID)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="meeting-`+meetingID.String()+`.ics"`)
+	ctx.Data(http.StatusOK, calendarContentType, data)
+}
+
+// CreateFeed handles issuing a calendar subscription URL
+// @Summary Create calendar feed
+// @Description Issue a secret subscription URL serving the user's upcoming meetings as an iCalendar feed. Any previous feed URL stops working.
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} utils.APIResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/calendar/feed [post]
+func (c *CalendarController) CreateFeed(ctx *gin.Context) {
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	feed, url, err := c.calendarService.CreateFeed(userUUID)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	response := models.CalendarFeedResponse{
+		URL:       url,
+		CreatedAt: feed.CreatedAt,
+	}
+	utils.SuccessResponse(ctx, http.StatusCreated, response, "Calendar feed created successfully")
+}
+
+// RevokeFeed handles revoking the calendar subscription URL
+// @Summary Revoke calendar feed
+// @Description Stop the user's calendar subscription URL from working
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/calendar/feed [delete]
+func (c *CalendarController) RevokeFeed(ctx *gin.Context) {
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	if err := c.calendarService.RevokeFeed(userUUID); err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, nil, "Calendar feed revoked successfully")
+}
+
+// GetFeed serves a calendar subscription feed
+// @Summary Get calendar feed
+// @Description Serve the feed owner's upcoming meetings as an iCalendar feed; deleted meetings are listed as cancelled. The secret token authenticates the request.
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token"
+// @Success 200 {string} string "iCalendar data"
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/calendar/feed/{token} [get]
+func (c *CalendarController) GetFeed(ctx *gin.Context) {
+	data, err := c.calendarService.RenderFeed(ctx.Param("token"))
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Data(http.StatusOK, calendarContentType, data)
+}
+
+// handleError maps calendar service errors to responses
+func (c *CalendarController) handleError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "meeting not found":
+		utils.NotFoundResponse(ctx, "Meeting not found")
+	case "calendar feed not found":
+		utils.NotFoundResponse(ctx, "Calendar feed not found")
+	case "not a participant":
+		utils.ForbiddenResponse(ctx, "You are not a participant of this meeting")
+	default:
+		utils.InternalServerErrorResponse(ctx, err.Error())
+	}
+}
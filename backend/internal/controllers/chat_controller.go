@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -23,7 +24,9 @@ func NewChatController(chatService *services.ChatService) *ChatController {
 	}
 }
 
-// GetMessages retrieves paginated chat messages for a meeting
+// GetMessages retrieves a page of chat messages for a meeting. Pages are
+// addressed by cursor: direction=older (the default) or newer from a cursor,
+// or around=<messageId> for the messages surrounding one message.
 func (c *ChatController) GetMessages(ctx *gin.Context) {
 	// Get meeting ID from URL parameter
 	meetingIDStr := ctx.Param("id")
@@ -33,30 +36,52 @@ func (c *ChatController) GetMessages(ctx *gin.Context) {
 		return
 	}
 
-	// Parse pagination parameters
-	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
-	if err != nil || page < 1 {
-		page = 1
+	cursor, ok := parseChatCursor(ctx)
+	if !ok {
+		return
+	}
+
+	viewer, ok := c.resolveViewer(ctx)
+	if !ok {
+		return
 	}
 
-	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 50
+	query := models.ChatHistoryQuery{
+		Cursor: cursor,
+		Limit:  parseChatLimit(ctx),
+		Viewer: viewer,
 	}
 
-	// Get time filters
-	before := ctx.Query("before")
-	after := ctx.Query("after")
+	switch direction := models.ChatHistoryDirection(ctx.DefaultQuery("direction", string(models.ChatHistoryOlder))); direction {
+	case models.ChatHistoryOlder, models.ChatHistoryNewer:
+		query.Direction = direction
+	default:
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_DIRECTION", "Direction must be older or newer")
+		return
+	}
+
+	if around := ctx.Query("around"); around != "" {
+		aroundID, err := uuid.Parse(around)
+		if err != nil {
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MESSAGE_ID", "Invalid message ID")
+			return
+		}
+		query.Around = &aroundID
+	}
 
 	// Get messages
-	messages, pagination, err := c.chatService.GetMessages(meetingID, page, limit, before, after)
+	messages, pagination, err := c.chatService.GetMessages(meetingID, query)
 	if err != nil {
+		if err.Error() == "message not found" {
+			utils.NotFoundResponse(ctx, "Message not found")
+			return
+		}
 		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "GET_MESSAGES_FAILED", "Failed to get messages")
 		return
 	}
 
 	// Convert to response format
-	var messageResponses []models.ChatMessageResponse
+	messageResponses := make([]models.ChatMessageResponse, 0, len(messages))
 	for _, message := range messages {
 		messageResponses = append(messageResponses, message.ToResponse())
 	}
@@ -69,6 +94,206 @@ func (c *ChatController) GetMessages(ctx *gin.Context) {
 	utils.SuccessResponse(ctx, http.StatusOK, response, "Messages retrieved successfully")
 }
 
+// SearchMessages runs a full-text search over a meeting's chat, optionally
+// filtered by sender (senderId or publicSenderId) and by message type
+// (type, comma separated). Results are paged from the newest match with
+// cursor like GetMessages going older.
+func (c *ChatController) SearchMessages(ctx *gin.Context) {
+	// Get meeting ID from URL parameter
+	meetingIDStr := ctx.Param("id")
+	meetingID, err := uuid.Parse(meetingIDStr)
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+
+	cursor, ok := parseChatCursor(ctx)
+	if !ok {
+		return
+	}
+
+	viewer, ok := c.resolveActor(ctx)
+	if !ok {
+		return
+	}
+
+	query := models.ChatSearchQuery{
+		Query:  ctx.Query("q"),
+		Cursor: cursor,
+		Limit:  parseChatLimit(ctx),
+		Viewer: viewer,
+	}
+
+	if senderID := ctx.Query("senderId"); senderID != "" {
+		id, err := uuid.Parse(senderID)
+		if err != nil {
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_SENDER_ID", "Invalid sender ID")
+			return
+		}
+		query.SenderUserID = &id
+	}
+	if senderID := ctx.Query("publicSenderId"); senderID != "" {
+		id, err := uuid.Parse(senderID)
+		if err != nil {
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_SENDER_ID", "Invalid sender ID")
+			return
+		}
+		query.SenderPublicUserID = &id
+	}
+
+	if types := ctx.Query("type"); types != "" {
+		for _, name := range strings.Split(types, ",") {
+			messageType := models.MessageType(strings.TrimSpace(name))
+			if !messageType.IsValid() {
+				utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MESSAGE_TYPE", "Invalid message type: "+name)
+				return
+			}
+			query.MessageTypes = append(query.MessageTypes, messageType)
+		}
+	}
+
+	results, pagination, err := c.chatService.SearchMessages(meetingID, query)
+	if err != nil {
+		switch err.Error() {
+		case "search query required":
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_QUERY", "Search query is required")
+		case "meeting not found":
+			utils.NotFoundResponse(ctx, "Meeting not found")
+		case "not a participant", "permission denied":
+			utils.ForbiddenResponse(ctx, "Only the host and participants can search the chat")
+		default:
+			utils.SendErrorResponse(ctx, http.StatusInternalServerError, "SEARCH_MESSAGES_FAILED", "Failed to search messages")
+		}
+		return
+	}
+
+	response := models.SearchChatMessagesResponse{
+		Results:    results,
+		Pagination: pagination,
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, response, "Messages searched successfully")
+}
+
+// GetThread retrieves the message that started a thread with a page of its
+// replies. Replies are paged like GetMessages, and unreadCount counts the
+// replies in the whole thread the caller has not read.
+func (c *ChatController) GetThread(ctx *gin.Context) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+	messageID, err := uuid.Parse(ctx.Param("messageId"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MESSAGE_ID", "Invalid message ID")
+		return
+	}
+
+	actor, ok := c.resolveActor(ctx)
+	if !ok {
+		return
+	}
+
+	cursor, ok := parseChatCursor(ctx)
+	if !ok {
+		return
+	}
+
+	query := models.ChatHistoryQuery{
+		Cursor: cursor,
+		Limit:  parseChatLimit(ctx),
+	}
+
+	switch direction := models.ChatHistoryDirection(ctx.DefaultQuery("direction", string(models.ChatHistoryOlder))); direction {
+	case models.ChatHistoryOlder, models.ChatHistoryNewer:
+		query.Direction = direction
+	default:
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_DIRECTION", "Direction must be older or newer")
+		return
+	}
+
+	if around := ctx.Query("around"); around != "" {
+		aroundID, err := uuid.Parse(around)
+		if err != nil {
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MESSAGE_ID", "Invalid message ID")
+			return
+		}
+		query.Around = &aroundID
+	}
+
+	thread, err := c.chatService.GetThread(meetingID, messageID, actor, query)
+	if err != nil {
+		switch err.Error() {
+		case "message not found":
+			utils.NotFoundResponse(ctx, "Message not found")
+		case "meeting not found":
+			utils.NotFoundResponse(ctx, "Meeting not found")
+		case "not a participant", "permission denied":
+			utils.ForbiddenResponse(ctx, "Only the host and participants can read threads")
+		default:
+			utils.SendErrorResponse(ctx, http.StatusInternalServerError, "GET_THREAD_FAILED", "Failed to get thread")
+		}
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, thread.ToResponse(), "Thread retrieved successfully")
+}
+
+// resolveActor identifies the caller: an authenticated user, or a public
+// user through the sessionId query parameter
+func (c *ChatController) resolveActor(ctx *gin.Context) (models.MeetingActor, bool) {
+	if userID, exists := utils.GetUserIDUUID(ctx); exists {
+		return models.MeetingActor{UserID: &userID}, true
+	}
+
+	sessionID := ctx.Query("sessionId")
+	if sessionID == "" {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "SESSION_REQUIRED", "Session ID required for public users")
+		return models.MeetingActor{}, false
+	}
+	publicUser, err := c.chatService.GetPublicUserBySessionID(sessionID)
+	if err != nil {
+		utils.UnauthorizedResponse(ctx, "Invalid session")
+		return models.MeetingActor{}, false
+	}
+	return models.MeetingActor{PublicUserID: &publicUser.ID}, true
+}
+
+// resolveViewer identifies the caller like resolveActor when they say who
+// they are; anonymous callers only see meeting-wide messages
+func (c *ChatController) resolveViewer(ctx *gin.Context) (models.MeetingActor, bool) {
+	if _, exists := utils.GetUserIDUUID(ctx); !exists && ctx.Query("sessionId") == "" {
+		return models.MeetingActor{}, true
+	}
+	return c.resolveActor(ctx)
+}
+
+// parseChatCursor reads the optional cursor query parameter, responding
+// with 400 when it is malformed
+func parseChatCursor(ctx *gin.Context) (*models.ChatCursor, bool) {
+	raw := ctx.Query("cursor")
+	if raw == "" {
+		return nil, true
+	}
+	cursor, err := models.DecodeChatCursor(raw)
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_CURSOR", "Invalid cursor")
+		return nil, false
+	}
+	return cursor, true
+}
+
+// parseChatLimit reads the page size, falling back to the default when it
+// is missing or out of range
+func parseChatLimit(ctx *gin.Context) int {
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit < 1 || limit > models.MaxChatPageSize {
+		return models.DefaultChatPageSize
+	}
+	return limit
+}
+
 // SendMessage sends a new chat message
 func (c *ChatController) SendMessage(ctx *gin.Context) {
 	// Get meeting ID from URL parameter
@@ -86,8 +311,8 @@ func (c *ChatController) SendMessage(ctx *gin.Context) {
 		return
 	}
 
-	// Validate required fields
-	if req.Content == "" || strings.TrimSpace(req.Content) == "" {
+	// Validate required fields; a file may be sent without text
+	if strings.TrimSpace(req.Content) == "" && req.AttachmentID == nil {
 		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_CONTENT", "Message content cannot be empty")
 		return
 	}
@@ -134,6 +359,41 @@ func (c *ChatController) SendMessage(ctx *gin.Context) {
 	// Send message
 	message, err := c.chatService.SendMessage(userIDPtr, publicUserID, &req)
 	if err != nil {
+		switch err.Error() {
+		case "permission denied":
+			utils.ForbiddenResponse(ctx, "Your role does not allow sending messages")
+			return
+		case "attachment not found":
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_ATTACHMENT", "Attachment not found or already sent")
+			return
+		case "private chat disabled":
+			utils.ForbiddenResponse(ctx, "Private messages are disabled in this meeting")
+			return
+		case "private chat restricted to hosts":
+			utils.ForbiddenResponse(ctx, "Private messages are limited to the host in this meeting")
+			return
+		case "announcements restricted to hosts":
+			utils.ForbiddenResponse(ctx, "Only hosts and co-hosts can send announcements")
+			return
+		case "announcements must be meeting-wide":
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_ANNOUNCEMENT", "Announcements cannot be private or replies")
+			return
+		case "recipient not found", "too many recipients", "private messages cannot be threaded":
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_RECIPIENTS", err.Error())
+			return
+		case "meeting not found":
+			utils.NotFoundResponse(ctx, "Meeting not found")
+			return
+		case "muted in chat":
+			utils.ForbiddenResponse(ctx, "A moderator muted you in chat")
+			return
+		case "slow mode":
+			utils.TooManyRequestsResponse(ctx, "Slow mode is on, wait before sending another message")
+			return
+		case "message blocked by content filter":
+			utils.SendErrorResponse(ctx, http.StatusUnprocessableEntity, "CONTENT_BLOCKED", "Message blocked by the content filter")
+			return
+		}
 		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "SEND_MESSAGE_FAILED", err.Error())
 		return
 	}
@@ -180,7 +440,16 @@ func (c *ChatController) UpdateMessage(ctx *gin.Context) {
 	// Update message
 	message, err := c.chatService.UpdateMessage(uid, messageID, &req)
 	if err != nil {
-		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "UPDATE_MESSAGE_FAILED", err.Error())
+		switch err.Error() {
+		case "message not found":
+			utils.NotFoundResponse(ctx, "Message not found")
+		case "unauthorized", "permission denied":
+			utils.ForbiddenResponse(ctx, "You cannot change this message")
+		case "message blocked by content filter":
+			utils.SendErrorResponse(ctx, http.StatusUnprocessableEntity, "CONTENT_BLOCKED", "Message blocked by the content filter")
+		default:
+			utils.SendErrorResponse(ctx, http.StatusInternalServerError, "UPDATE_MESSAGE_FAILED", err.Error())
+		}
 		return
 	}
 
@@ -197,36 +466,17 @@ func (c *ChatController) MarkMessageRead(ctx *gin.Context) {
 		return
 	}
 
-	// Get user info from context (authenticated user)
-	userID, exists := ctx.Get("userID")
-	var userIDPtr *uuid.UUID
-	if exists {
-		if uid, ok := userID.(uuid.UUID); ok {
-			userIDPtr = &uid
-		}
+	actor, ok := c.resolveActor(ctx)
+	if !ok {
+		return
 	}
 
-	// Handle public user
-	var publicUserID *uuid.UUID
-	if userIDPtr == nil {
-		// Get session ID from query parameter for public users
-		sessionID := ctx.Query("sessionId")
-		if sessionID == "" {
-			utils.SendErrorResponse(ctx, http.StatusBadRequest, "SESSION_REQUIRED", "Session ID required for public users")
-			return
-		}
-
-		// Get public user
-		publicUser, err := c.chatService.GetPublicUserBySessionID(sessionID)
-		if err != nil {
-			utils.UnauthorizedResponse(ctx, "Invalid session")
+	// Mark message as read
+	if err := c.chatService.MarkMessageRead(actor.UserID, actor.PublicUserID, messageID); err != nil {
+		if err.Error() == "message not found" {
+			utils.NotFoundResponse(ctx, "Message not found")
 			return
 		}
-		publicUserID = &publicUser.ID
-	}
-
-	// Mark message as read
-	if err := c.chatService.MarkMessageRead(userIDPtr, publicUserID, messageID); err != nil {
 		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "MARK_READ_FAILED", err.Error())
 		return
 	}
@@ -244,32 +494,9 @@ func (c *ChatController) ToggleReaction(ctx *gin.Context) {
 		return
 	}
 
-	// Get user info from context (authenticated user)
-	userID, exists := ctx.Get("userID")
-	var userIDPtr *uuid.UUID
-	if exists {
-		if uid, ok := userID.(uuid.UUID); ok {
-			userIDPtr = &uid
-		}
-	}
-
-	// Handle public user
-	var publicUserID *uuid.UUID
-	if userIDPtr == nil {
-		// Get session ID from query parameter for public users
-		sessionID := ctx.Query("sessionId")
-		if sessionID == "" {
-			utils.SendErrorResponse(ctx, http.StatusBadRequest, "SESSION_REQUIRED", "Session ID required for public users")
-			return
-		}
-
-		// Get public user
-		publicUser, err := c.chatService.GetPublicUserBySessionID(sessionID)
-		if err != nil {
-			utils.UnauthorizedResponse(ctx, "Invalid session")
-			return
-		}
-		publicUserID = &publicUser.ID
+	actor, ok := c.resolveActor(ctx)
+	if !ok {
+		return
 	}
 
 	// Parse request body
@@ -283,18 +510,33 @@ func (c *ChatController) ToggleReaction(ctx *gin.Context) {
 	req.MessageID = messageID
 
 	// Toggle reaction
-	reaction, err := c.chatService.ToggleReaction(userIDPtr, publicUserID, &req)
+	reaction, err := c.chatService.ToggleReaction(actor.UserID, actor.PublicUserID, &req)
 	if err != nil {
-		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "TOGGLE_REACTION_FAILED", err.Error())
+		if err.Error() == "permission denied" {
+			utils.ForbiddenResponse(ctx, "Your role does not allow reacting to messages")
+			return
+		}
+		if err.Error() == "muted in chat" {
+			utils.ForbiddenResponse(ctx, "A moderator muted you in chat")
+			return
+		}
+		switch err.Error() {
+		case "invalid reaction":
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_REACTION", "Reactions must be a single emoji")
+		case "message not found":
+			utils.NotFoundResponse(ctx, "Message not found")
+		case "reaction limit reached":
+			utils.SendErrorResponse(ctx, http.StatusConflict, "REACTION_LIMIT_REACHED", fmt.Sprintf("A message can collect at most %d different reactions", models.MaxDistinctReactions))
+		default:
+			utils.SendErrorResponse(ctx, http.StatusInternalServerError, "TOGGLE_REACTION_FAILED", err.Error())
+		}
 		return
 	}
 
-	if reaction == nil {
-		// Reaction was removed
-		utils.SuccessResponse(ctx, http.StatusOK, nil, "Reaction removed")
+	if reaction.ReactedByMe {
+		utils.SuccessResponse(ctx, http.StatusCreated, reaction, "Reaction added")
 	} else {
-		// Reaction was added
-		utils.SuccessResponse(ctx, http.StatusCreated, reaction.ToResponse(), "Reaction added")
+		utils.SuccessResponse(ctx, http.StatusOK, reaction, "Reaction removed")
 	}
 }
 
@@ -308,44 +550,359 @@ func (c *ChatController) GetUnreadCount(ctx *gin.Context) {
 		return
 	}
 
-	// Get user info from context (authenticated user)
-	userID, exists := ctx.Get("userID")
-	var userIDPtr *uuid.UUID
-	if exists {
-		if uid, ok := userID.(uuid.UUID); ok {
-			userIDPtr = &uid
-		}
+	actor, ok := c.resolveActor(ctx)
+	if !ok {
+		return
 	}
 
-	// Handle public user
-	var publicUserID *uuid.UUID
-	if userIDPtr == nil {
-		// Get session ID from query parameter for public users
-		sessionID := ctx.Query("sessionId")
-		if sessionID == "" {
-			utils.SendErrorResponse(ctx, http.StatusBadRequest, "SESSION_REQUIRED", "Session ID required for public users")
-			return
-		}
+	// Get unread count
+	count, err := c.chatService.GetUnreadCount(actor.UserID, actor.PublicUserID, meetingID)
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "GET_UNREAD_COUNT_FAILED", err.Error())
+		return
+	}
 
-		// Get public user
-		publicUser, err := c.chatService.GetPublicUserBySessionID(sessionID)
-		if err != nil {
-			utils.UnauthorizedResponse(ctx, "Invalid session")
+	response, err := c.unreadCounts(meetingID, actor, count)
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "GET_UNREAD_COUNT_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, response, "Unread count retrieved successfully")
+}
+
+// MarkReadUpTo marks a message and everything before it, in the main
+// timeline or in its thread, as read. It responds with the updated unread
+// counts.
+func (c *ChatController) MarkReadUpTo(ctx *gin.Context) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+
+	actor, ok := c.resolveActor(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.MarkReadUpToRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || req.MessageID == uuid.Nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request format")
+		return
+	}
+
+	if _, err := c.chatService.MarkReadUpTo(meetingID, actor, req.MessageID); err != nil {
+		if err.Error() == "message not found" {
+			utils.NotFoundResponse(ctx, "Message not found")
 			return
 		}
-		publicUserID = &publicUser.ID
+		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "MARK_READ_FAILED", err.Error())
+		return
 	}
 
-	// Get unread count
-	count, err := c.chatService.GetUnreadCount(userIDPtr, publicUserID, meetingID)
+	count, err := c.chatService.GetUnreadCount(actor.UserID, actor.PublicUserID, meetingID)
 	if err != nil {
 		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "GET_UNREAD_COUNT_FAILED", err.Error())
 		return
 	}
+	response, err := c.unreadCounts(meetingID, actor, count)
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "GET_UNREAD_COUNT_FAILED", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, response, "Messages marked as read")
+}
+
+// unreadCounts completes the main timeline's unread count with the thread
+// counts and the reader's cursor
+func (c *ChatController) unreadCounts(meetingID uuid.UUID, actor models.MeetingActor, count int) (models.GetUnreadCountResponse, error) {
+	threads, err := c.chatService.GetThreadUnreadCounts(actor.UserID, actor.PublicUserID, meetingID)
+	if err != nil {
+		return models.GetUnreadCountResponse{}, err
+	}
+	cursor, err := c.chatService.GetReadCursor(meetingID, nil, actor)
+	if err != nil {
+		return models.GetUnreadCountResponse{}, err
+	}
 
 	response := models.GetUnreadCountResponse{
 		UnreadCount: count,
+		Threads:     threads,
+	}
+	if cursor != nil {
+		response.LastReadMessageID = &cursor.LastReadMessageID
+	}
+	return response, nil
+}
+// GetFlaggedMessages lists the messages the content filter flagged, oldest
+// first, for hosts and co-hosts to review. status selects pending (the
+// default), dismissed or removed flags.
+func (c *ChatController) GetFlaggedMessages(ctx *gin.Context) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+	userID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "Authentication required")
+		return
 	}
 
-	utils.SuccessResponse(ctx, http.StatusOK, response, "Unread count retrieved successfully")
-}
\ No newline at end of file
+	status := models.ChatFlagStatus(ctx.DefaultQuery("status", string(models.ChatFlagPending)))
+	switch status {
+	case models.ChatFlagPending, models.ChatFlagDismissed, models.ChatFlagRemoved:
+	default:
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_STATUS", "Status must be pending, dismissed or removed")
+		return
+	}
+
+	flags, err := c.chatService.GetFlaggedMessages(meetingID, userID, status)
+	if err != nil {
+		c.handleModerationError(ctx, err)
+		return
+	}
+
+	responses := make([]models.ChatMessageFlagResponse, 0, len(flags))
+	for i := range flags {
+		responses = append(responses, flags[i].ToResponse())
+	}
+	utils.SuccessResponse(ctx, http.StatusOK, responses, "Flagged messages retrieved successfully")
+}
+
+// ReviewFlag settles a flagged message: dismiss keeps it, remove deletes it
+// and leaves a tombstone
+func (c *ChatController) ReviewFlag(ctx *gin.Context) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+	flagID, err := uuid.Parse(ctx.Param("flagId"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_FLAG_ID", "Invalid flag ID")
+		return
+	}
+	userID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "Authentication required")
+		return
+	}
+
+	var req models.ReviewChatFlagRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request format")
+		return
+	}
+
+	flag, err := c.chatService.ReviewFlag(meetingID, flagID, userID, &req)
+	if err != nil {
+		c.handleModerationError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, flag.ToResponse(), "Flag reviewed successfully")
+}
+
+// handleModerationError maps chat moderation errors to responses
+func (c *ChatController) handleModerationError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "meeting not found":
+		utils.NotFoundResponse(ctx, "Meeting not found")
+	case "flag not found":
+		utils.NotFoundResponse(ctx, "Flag not found")
+	case "message not found":
+		utils.NotFoundResponse(ctx, "Message not found")
+	case "not a participant", "permission denied", "unauthorized":
+		utils.ForbiddenResponse(ctx, "You don't have permission to moderate this meeting")
+	case "flag already reviewed":
+		utils.SendErrorResponse(ctx, http.StatusConflict, "ALREADY_REVIEWED", "This flag was already reviewed")
+	case "invalid review action":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_ACTION", "Action must be dismiss or remove")
+	case "message not pinned":
+		utils.NotFoundResponse(ctx, "Message is not pinned")
+	case "message already pinned":
+		utils.SendErrorResponse(ctx, http.StatusConflict, "ALREADY_PINNED", "This message is already pinned")
+	case "pin limit reached":
+		utils.SendErrorResponse(ctx, http.StatusConflict, "PIN_LIMIT_REACHED", fmt.Sprintf("A meeting can pin at most %d messages", models.MaxPinnedMessages))
+	case "private messages cannot be pinned":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_PIN", "Private messages cannot be pinned")
+	case "message does not require acknowledgement":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "ACK_NOT_REQUIRED", "This message does not ask for acknowledgement")
+	default:
+		utils.InternalServerErrorResponse(ctx, err.Error())
+	}
+}
+
+// GetPinnedMessages lists a meeting's pinned messages, most recently pinned
+// first
+func (c *ChatController) GetPinnedMessages(ctx *gin.Context) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+
+	viewer, ok := c.resolveActor(ctx)
+	if !ok {
+		return
+	}
+
+	messages, err := c.chatService.GetPinnedMessages(meetingID, viewer)
+	if err != nil {
+		switch err.Error() {
+		case "meeting not found":
+			utils.NotFoundResponse(ctx, "Meeting not found")
+		case "not a participant", "permission denied":
+			utils.ForbiddenResponse(ctx, "Only the host and participants can see pinned messages")
+		default:
+			utils.SendErrorResponse(ctx, http.StatusInternalServerError, "GET_PINNED_FAILED", "Failed to get pinned messages")
+		}
+		return
+	}
+
+	responses := make([]models.ChatMessageResponse, 0, len(messages))
+	for i := range messages {
+		responses = append(responses, messages[i].ToResponse())
+	}
+	utils.SuccessResponse(ctx, http.StatusOK, responses, "Pinned messages retrieved successfully")
+}
+
+// PinMessage pins a message for everyone in the meeting (hosts and co-hosts
+// only)
+func (c *ChatController) PinMessage(ctx *gin.Context) {
+	meetingID, messageID, userID, ok := parseModeratedMessage(ctx)
+	if !ok {
+		return
+	}
+
+	message, err := c.chatService.PinMessage(meetingID, messageID, userID)
+	if err != nil {
+		c.handleModerationError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, message.ToResponse(), "Message pinned successfully")
+}
+
+// UnpinMessage takes a message off the pinned list (hosts and co-hosts only)
+func (c *ChatController) UnpinMessage(ctx *gin.Context) {
+	meetingID, messageID, userID, ok := parseModeratedMessage(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.chatService.UnpinMessage(meetingID, messageID, userID); err != nil {
+		c.handleModerationError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, nil, "Message unpinned successfully")
+}
+
+// AcknowledgeMessage acknowledges an announcement that asked for it
+func (c *ChatController) AcknowledgeMessage(ctx *gin.Context) {
+	messageID, err := uuid.Parse(ctx.Param("messageId"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MESSAGE_ID", "Invalid message ID")
+		return
+	}
+
+	actor, ok := c.resolveActor(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.chatService.AcknowledgeMessage(actor.UserID, actor.PublicUserID, messageID); err != nil {
+		c.handleModerationError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, nil, "Announcement acknowledged")
+}
+
+// GetAcknowledgements shows hosts and co-hosts who acknowledged an
+// announcement and who has yet to
+func (c *ChatController) GetAcknowledgements(ctx *gin.Context) {
+	meetingID, messageID, userID, ok := parseModeratedMessage(ctx)
+	if !ok {
+		return
+	}
+
+	summary, err := c.chatService.GetAcknowledgements(meetingID, messageID, userID)
+	if err != nil {
+		c.handleModerationError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, summary, "Acknowledgements retrieved successfully")
+}
+
+// ExportTranscript downloads a meeting's chat as a JSON, Markdown, HTML or
+// CSV transcript (host and participants only). Times are shown in the
+// timeZone query parameter, or the user's own time zone.
+func (c *ChatController) ExportTranscript(ctx *gin.Context) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+
+	format := models.ChatExportFormat(ctx.DefaultQuery("format", string(models.ChatExportJSON)))
+	if !format.IsValid() {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_FORMAT", "Format must be json, md, html or csv")
+		return
+	}
+
+	actor, ok := c.resolveActor(ctx)
+	if !ok {
+		return
+	}
+
+	transcript, err := c.chatService.PrepareTranscript(meetingID, actor, format, ctx.Query("timeZone"))
+	if err != nil {
+		switch err.Error() {
+		case "meeting not found":
+			utils.NotFoundResponse(ctx, "Meeting not found")
+		case "not a participant", "permission denied":
+			utils.ForbiddenResponse(ctx, "Only the host and participants can export the chat")
+		case "invalid time zone":
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_TIME_ZONE", "Invalid time zone")
+		default:
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+
+	ctx.Header("Content-Type", format.ContentType())
+	ctx.Header("Content-Disposition", `attachment; filename="chat-`+meetingID.String()+"."+string(format)+`"`)
+	ctx.Status(http.StatusOK)
+
+	// The response has started; a failure can only cut the transcript short
+	if err := c.chatService.WriteTranscript(ctx.Writer, transcript); err != nil {
+		_ = ctx.Error(err)
+	}
+}
+
+// parseModeratedMessage reads the meeting and message IDs of a moderation
+// request on a message, and the authenticated user making it
+func parseModeratedMessage(ctx *gin.Context) (uuid.UUID, uuid.UUID, uuid.UUID, bool) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	messageID, err := uuid.Parse(ctx.Param("messageId"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MESSAGE_ID", "Invalid message ID")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	userID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "Authentication required")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+	return meetingID, messageID, userID, true
+}
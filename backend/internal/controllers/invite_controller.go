@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+	"github.com/your-org/gomeet-backend/internal/services"
+	"github.com/your-org/gomeet-backend/internal/utils"
+)
+
+type InviteController struct {
+	accessService *services.MeetingAccessService
+	validator     *validator.Validate
+}
+
+func NewInviteController(accessService *services.MeetingAccessService) *InviteController {
+	return &InviteController{
+		accessService: accessService,
+		validator:     validator.New(),
+	}
+}
+
+// CreateInvite handles issuing a signed invite link
+// @Summary Create meeting invite
+// @Description Issue a signed invite token with an expiry, optional use limit and optional pre-assigned role (hosts and co-hosts only)
+// @Tags invites
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param request body models.CreateMeetingInviteRequest true "Invite options"
+// @Success 201 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/invites [post]
+func (c *InviteController) CreateInvite(ctx *gin.Context) {
+	userUUID, meetingID, ok := c.parseParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.CreateMeetingInviteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	invite, token, err := c.accessService.CreateInvite(meetingID, userUUID, &req)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	response := invite.ToResponse()
+	response.Token = token
+	utils.SuccessResponse(ctx, http.StatusCreated, response, "Invite created successfully")
+}
+
+// GetInvites handles listing a meeting's invites
+// @Summary Get meeting invites
+// @Description List the meeting's invites with their use counts (hosts and co-hosts only)
+// @Tags invites
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/invites [get]
+func (c *InviteController) GetInvites(ctx *gin.Context) {
+	userUUID, meetingID, ok := c.parseParams(ctx)
+	if !ok {
+		return
+	}
+
+	invites, err := c.accessService.GetInvites(meetingID, userUUID)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	responses := make([]models.MeetingInviteResponse, len(invites))
+	for i := range invites {
+		responses[i] = invites[i].ToResponse()
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, responses, "Invites retrieved successfully")
+}
+
+// RevokeInvite handles revoking an invite link
+// @Summary Revoke meeting invite
+// @Description Stop an invite link from being redeemed (hosts and co-hosts only)
+// @Tags invites
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param inviteId path string true "Invite ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/invites/{inviteId} [delete]
+func (c *InviteController) RevokeInvite(ctx *gin.Context) {
+	userUUID, meetingID, ok := c.parseParams(ctx)
+	if !ok {
+		return
+	}
+
+	inviteID, err := uuid.Parse(ctx.Param("inviteId"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_INVITE_ID", "Invalid invite ID")
+		return
+	}
+
+	invite, err := c.accessService.RevokeInvite(meetingID, inviteID, userUUID)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, invite.ToResponse(), "Invite revoked successfully")
+}
+
+func (c *InviteController) parseParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userUUID, meetingID, true
+}
+
+// handleError maps invite service errors to responses
+func (c *InviteController) handleError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "meeting not found":
+		utils.NotFoundResponse(ctx, "Meeting not found")
+	case "invite not found":
+		utils.NotFoundResponse(ctx, "Invite not found")
+	case "not a participant", "permission denied":
+		utils.ForbiddenResponse(ctx, "You don't have permission to manage this meeting's invites")
+	case "role not assignable":
+		utils.ForbiddenResponse(ctx, "You cannot hand out this role")
+	case "invalid invite expiry":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_EXPIRY", "Invite expiry must be in the future and within 90 days")
+	default:
+		utils.InternalServerErrorResponse(ctx, err.Error())
+	}
+}
+
+// handleMeetingAccessError responds to passcode and invite failures on join.
+// It reports whether err was one of them.
+func handleMeetingAccessError(ctx *gin.Context, err error) bool {
+	switch err.Error() {
+	case "passcode required":
+		utils.SendErrorResponse(ctx, http.StatusUnauthorized, "PASSCODE_REQUIRED", "This meeting requires a passcode")
+	case "invalid passcode":
+		utils.SendErrorResponse(ctx, http.StatusForbidden, "INVALID_PASSCODE", "Invalid passcode")
+	case "too many passcode attempts":
+		utils.TooManyRequestsResponse(ctx, "Too many passcode attempts, try again later")
+	case "invalid invite", "invite revoked":
+		utils.SendErrorResponse(ctx, http.StatusForbidden, "INVALID_INVITE", "This invite is not valid")
+	case "invite expired":
+		utils.SendErrorResponse(ctx, http.StatusForbidden, "INVITE_EXPIRED", "This invite has expired")
+	case "invite exhausted":
+		utils.SendErrorResponse(ctx, http.StatusForbidden, "INVITE_EXHAUSTED", "This invite has been used up")
+	default:
+		return false
+	}
+	return true
+}
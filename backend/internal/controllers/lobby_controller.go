@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+	"github.com/your-org/gomeet-backend/internal/services"
+	"github.com/your-org/gomeet-backend/internal/utils"
+)
+
+type LobbyController struct {
+	lobbyService *services.LobbyService
+}
+
+func NewLobbyController(lobbyService *services.LobbyService) *LobbyController {
+	return &LobbyController{
+		lobbyService: lobbyService,
+	}
+}
+
+// GetLobby handles listing the participants waiting in the lobby
+// @Summary Get meeting lobby
+// @Description List participants waiting for admission (hosts and co-hosts only)
+// @Tags lobby
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/lobby [get]
+func (c *LobbyController) GetLobby(ctx *gin.Context) {
+	userUUID, meetingID, ok := c.parseParams(ctx)
+	if !ok {
+		return
+	}
+
+	participants, err := c.lobbyService.GetPendingParticipants(meetingID, userUUID)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, toParticipantResponses(participants), "Lobby retrieved successfully")
+}
+
+// AdmitParticipants handles admitting participants from the lobby
+// @Summary Admit participants
+// @Description Admit the listed participants, or everyone waiting, into the meeting; listing a denied participant lifts the denial (hosts and co-hosts only)
+// @Tags lobby
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param request body models.LobbyDecisionRequest true "Participants to admit"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/lobby/admit [post]
+func (c *LobbyController) AdmitParticipants(ctx *gin.Context) {
+	c.decide(ctx, c.lobbyService.Admit, "Participants admitted successfully")
+}
+
+// DenyParticipants handles turning participants away from the lobby
+// @Summary Deny participants
+// @Description Deny entry to the listed participants, or everyone waiting (hosts and co-hosts only)
+// @Tags lobby
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param request body models.LobbyDecisionRequest true "Participants to deny"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/lobby/deny [post]
+func (c *LobbyController) DenyParticipants(ctx *gin.Context) {
+	c.decide(ctx, c.lobbyService.Deny, "Participants denied successfully")
+}
+
+func (c *LobbyController) decide(ctx *gin.Context, decision func(uuid.UUID, uuid.UUID, *models.LobbyDecisionRequest) ([]models.Participant, error), message string) {
+	userUUID, meetingID, ok := c.parseParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.LobbyDecisionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	participants, err := decision(meetingID, userUUID, &req)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, toParticipantResponses(participants), message)
+}
+
+func (c *LobbyController) parseParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userUUID, meetingID, true
+}
+
+// handleError maps lobby service errors to responses
+func (c *LobbyController) handleError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "meeting not found":
+		utils.NotFoundResponse(ctx, "Meeting not found")
+	case "not a participant", "permission denied":
+		utils.ForbiddenResponse(ctx, "You don't have permission to manage this meeting's lobby")
+	case "no participants selected":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "NO_PARTICIPANTS_SELECTED", "Select participants or set all to true")
+	default:
+		utils.InternalServerErrorResponse(ctx, err.Error())
+	}
+}
+
+func toParticipantResponses(participants []models.Participant) []models.ParticipantResponse {
+	responses := make([]models.ParticipantResponse, len(participants))
+	for i := range participants {
+		responses[i] = participants[i].ToResponse()
+	}
+	return responses
+}
@@ -3,6 +3,8 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -15,12 +17,14 @@ import (
 
 type MeetingController struct {
 	meetingService *services.MeetingService
+	roleService    *services.RoleService
 	validator      *validator.Validate
 }
 
-func NewMeetingController(meetingService *services.MeetingService) *MeetingController {
+func NewMeetingController(meetingService *services.MeetingService, roleService *services.RoleService) *MeetingController {
 	return &MeetingController{
 		meetingService: meetingService,
+		roleService:    roleService,
 		validator:      validator.New(),
 	}
 }
@@ -64,6 +68,9 @@ func (c *MeetingController) CreateMeeting(ctx *gin.Context) {
 
 	meeting, err := c.meetingService.CreateMeeting(userUUID, &req)
 	if err != nil {
+		if handleRecurrenceError(ctx, err) || handleScheduleError(ctx, err) {
+			return
+		}
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
 	}
@@ -80,7 +87,12 @@ func (c *MeetingController) CreateMeeting(ctx *gin.Context) {
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
 // @Param search query string false "Search term for meeting names"
+// @Param from query string false "Only meetings ending after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Only meetings starting before this time (RFC 3339 or YYYY-MM-DD, inclusive)"
+// @Param timeZone query string false "IANA time zone for date-only from/to values" default(UTC)
+// @Param category query string false "upcoming, in-progress or past"
 // @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /api/meetings [get]
@@ -110,7 +122,18 @@ func (c *MeetingController) GetMeetings(ctx *gin.Context) {
 		limit = 10
 	}
 
-	response, err := c.meetingService.GetMeetings(userUUID, page, limit, search)
+	filter, ok := parseListFilter(ctx)
+	if !ok {
+		return
+	}
+	category := models.MeetingCategory(ctx.Query("category"))
+	if category != "" && !category.IsValid() {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_CATEGORY", "Category must be upcoming, in-progress or past")
+		return
+	}
+	filter.Category = category
+
+	response, err := c.meetingService.GetMeetings(userUUID, page, limit, search, filter)
 	if err != nil {
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
@@ -198,7 +221,7 @@ func (c *MeetingController) GetMeetingPublic(ctx *gin.Context) {
 		return
 	}
 
-	utils.SuccessResponse(ctx, http.StatusOK, meeting.ToResponse(), "Meeting retrieved successfully")
+	utils.SuccessResponse(ctx, http.StatusOK, meeting.ToPublicResponse(), "Meeting retrieved successfully")
 }
 
 // UpdateMeeting handles updating a meeting
@@ -250,6 +273,9 @@ func (c *MeetingController) UpdateMeeting(ctx *gin.Context) {
 
 	meeting, err := c.meetingService.UpdateMeeting(meetingID, userUUID, &req)
 	if err != nil {
+		if handleRecurrenceError(ctx, err) || handleScheduleError(ctx, err) {
+			return
+		}
 		if err.Error() == "meeting not found or unauthorized" {
 			utils.ForbiddenResponse(ctx, "Meeting not found or you don't have permission to update it")
 			return
@@ -268,6 +294,7 @@ func (c *MeetingController) UpdateMeeting(ctx *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Meeting ID"
+// @Param scope query string false "Occurrences of a recurring meeting to delete: this, following or all" default(this)
 // @Success 200 {object} utils.APIResponse
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
@@ -295,7 +322,13 @@ func (c *MeetingController) DeleteMeeting(ctx *gin.Context) {
 		return
 	}
 
-	err = c.meetingService.DeleteMeeting(meetingID, userUUID)
+	scope := models.RecurrenceScope(ctx.DefaultQuery("scope", string(models.RecurrenceScopeThis)))
+	if !scope.IsValid() {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_SCOPE", "Scope must be this, following or all")
+		return
+	}
+
+	err = c.meetingService.DeleteMeeting(meetingID, userUUID, scope)
 	if err != nil {
 		if err.Error() == "meeting not found or unauthorized" {
 			utils.ForbiddenResponse(ctx, "Meeting not found or you don't have permission to delete it")
@@ -315,7 +348,11 @@ func (c *MeetingController) DeleteMeeting(ctx *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param limit query int false "Maximum number of meetings to return" default(5)
+// @Param from query string false "Only meetings ending after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Only meetings starting before this time (RFC 3339 or YYYY-MM-DD, inclusive)"
+// @Param timeZone query string false "IANA time zone for date-only from/to values" default(UTC)
 // @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /api/meetings/upcoming [get]
@@ -337,7 +374,12 @@ func (c *MeetingController) GetUpcomingMeetings(ctx *gin.Context) {
 		limit = 5
 	}
 
-	meetings, err := c.meetingService.GetUpcomingMeetings(userUUID, limit)
+	filter, ok := parseListFilter(ctx)
+	if !ok {
+		return
+	}
+
+	meetings, err := c.meetingService.GetUpcomingMeetings(userUUID, limit, filter)
 	if err != nil {
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
@@ -352,6 +394,46 @@ func (c *MeetingController) GetUpcomingMeetings(ctx *gin.Context) {
 	utils.SuccessResponse(ctx, http.StatusOK, meetingResponses, "Upcoming meetings retrieved successfully")
 }
 
+// GetInProgressMeetings handles retrieving meetings under way
+// @Summary Get in-progress meetings
+// @Description Get the authenticated user's meetings that are live or within their planned time
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Only meetings ending after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Only meetings starting before this time (RFC 3339 or YYYY-MM-DD, inclusive)"
+// @Param timeZone query string false "IANA time zone for date-only from/to values" default(UTC)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/meetings/in-progress [get]
+func (c *MeetingController) GetInProgressMeetings(ctx *gin.Context) {
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	filter, ok := parseListFilter(ctx)
+	if !ok {
+		return
+	}
+
+	meetings, err := c.meetingService.GetInProgressMeetings(userUUID, filter)
+	if err != nil {
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	meetingResponses := make([]models.MeetingResponse, len(meetings))
+	for i, meeting := range meetings {
+		meetingResponses[i] = meeting.ToResponse()
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, meetingResponses, "In-progress meetings retrieved successfully")
+}
+
 // GetPastMeetings handles retrieving past meetings
 // @Summary Get past meetings
 // @Description Get paginated list of past meetings for the authenticated user
@@ -360,7 +442,11 @@ func (c *MeetingController) GetUpcomingMeetings(ctx *gin.Context) {
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param from query string false "Only meetings ending after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Only meetings starting before this time (RFC 3339 or YYYY-MM-DD, inclusive)"
+// @Param timeZone query string false "IANA time zone for date-only from/to values" default(UTC)
 // @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /api/meetings/past [get]
@@ -383,7 +469,12 @@ func (c *MeetingController) GetPastMeetings(ctx *gin.Context) {
 		limit = 10
 	}
 
-	response, err := c.meetingService.GetPastMeetings(userUUID, page, limit)
+	filter, ok := parseListFilter(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.meetingService.GetPastMeetings(userUUID, page, limit, filter)
 	if err != nil {
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
@@ -401,9 +492,13 @@ func (c *MeetingController) GetPastMeetings(ctx *gin.Context) {
 // @Security BearerAuth
 // @Param request body models.JoinMeetingRequest true "Join meeting request"
 // @Success 200 {object} utils.APIResponse
+// @Success 202 {object} utils.APIResponse "Waiting in the lobby"
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /api/meetings/join [post]
 func (c *MeetingController) JoinMeeting(ctx *gin.Context) {
@@ -430,16 +525,45 @@ func (c *MeetingController) JoinMeeting(ctx *gin.Context) {
 		return
 	}
 
-	participant, err := c.meetingService.JoinMeeting(userUUID, req.MeetingID)
+	creds := models.MeetingCredentials{
+		Passcode:    req.Passcode,
+		InviteToken: req.InviteToken,
+		ClientIP:    ctx.ClientIP(),
+	}
+	participant, err := c.meetingService.JoinMeeting(userUUID, req.MeetingID, creds)
 	if err != nil {
+		if handleMeetingAccessError(ctx, err) {
+			return
+		}
 		if err.Error() == "meeting not found" {
 			utils.NotFoundResponse(ctx, "Meeting not found")
 			return
 		}
+		if err.Error() == "meeting is archived" {
+			utils.ConflictResponse(ctx, "Archived meetings cannot be joined")
+			return
+		}
+		if err.Error() == "banned from meeting" {
+			utils.SendErrorResponse(ctx, http.StatusForbidden, "BANNED_FROM_MEETING", "You have been banned from this meeting")
+			return
+		}
+		if err.Error() == "denied from lobby" {
+			utils.SendErrorResponse(ctx, http.StatusForbidden, "LOBBY_DENIED", "A host denied you entry to this meeting")
+			return
+		}
+		if err.Error() == "meeting is locked" {
+			utils.SendErrorResponse(ctx, http.StatusForbidden, "MEETING_LOCKED", "This meeting is locked")
+			return
+		}
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
 	}
 
+	if participant.LobbyStatus == models.LobbyStatusPending {
+		utils.SuccessResponse(ctx, http.StatusAccepted, participant, "Waiting for a host to admit you")
+		return
+	}
+
 	utils.SuccessResponse(ctx, http.StatusOK, participant, "Joined meeting successfully")
 }
 
@@ -502,6 +626,7 @@ func (c *MeetingController) LeaveMeeting(ctx *gin.Context) {
 // @Param id path string true "Meeting ID"
 // @Success 200 {object} utils.APIResponse
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /api/meetings/{id}/participants/public [get]
@@ -519,6 +644,10 @@ func (c *MeetingController) GetMeetingParticipantsPublic(ctx *gin.Context) {
 			utils.NotFoundResponse(ctx, "Meeting not found")
 			return
 		}
+		if err.Error() == "meeting is protected" {
+			utils.ForbiddenResponse(ctx, "Participants of passcode-protected meetings are not listed publicly")
+			return
+		}
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
 	}
@@ -580,7 +709,11 @@ func (c *MeetingController) GetMeetingParticipants(ctx *gin.Context) {
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param from query string false "Only meetings ending after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Only meetings starting before this time (RFC 3339 or YYYY-MM-DD, inclusive)"
+// @Param timeZone query string false "IANA time zone for date-only from/to values" default(UTC)
 // @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /api/meetings/joined [get]
@@ -609,7 +742,12 @@ func (c *MeetingController) GetJoinedMeetings(ctx *gin.Context) {
 		limit = 10
 	}
 
-	response, err := c.meetingService.GetJoinedMeetings(userUUID, page, limit)
+	filter, ok := parseListFilter(ctx)
+	if !ok {
+		return
+	}
+
+	response, err := c.meetingService.GetJoinedMeetings(userUUID, page, limit, filter)
 	if err != nil {
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
@@ -620,7 +758,7 @@ func (c *MeetingController) GetJoinedMeetings(ctx *gin.Context) {
 
 // StartMeeting handles starting a meeting
 // @Summary Start a meeting
-// @Description Activate a meeting to start it
+// @Description Put a scheduled or ended meeting live
 // @Tags meetings
 // @Produce json
 // @Security BearerAuth
@@ -630,6 +768,7 @@ func (c *MeetingController) GetJoinedMeetings(ctx *gin.Context) {
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 403 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /api/meetings/{id}/start [patch]
 func (c *MeetingController) StartMeeting(ctx *gin.Context) {
@@ -658,6 +797,10 @@ func (c *MeetingController) StartMeeting(ctx *gin.Context) {
 			utils.ForbiddenResponse(ctx, "Meeting not found or you don't have permission to start it")
 			return
 		}
+		if err.Error() == "meeting is archived" {
+			utils.ConflictResponse(ctx, "Archived meetings cannot be started")
+			return
+		}
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
 	}
@@ -667,7 +810,7 @@ func (c *MeetingController) StartMeeting(ctx *gin.Context) {
 
 // EndMeeting handles ending a meeting
 // @Summary End a meeting
-// @Description Deactivate a meeting to end it
+// @Description End a live meeting
 // @Tags meetings
 // @Produce json
 // @Security BearerAuth
@@ -677,6 +820,7 @@ func (c *MeetingController) StartMeeting(ctx *gin.Context) {
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 403 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /api/meetings/{id}/end [patch]
 func (c *MeetingController) EndMeeting(ctx *gin.Context) {
@@ -705,9 +849,207 @@ func (c *MeetingController) EndMeeting(ctx *gin.Context) {
 			utils.ForbiddenResponse(ctx, "Meeting not found or you don't have permission to end it")
 			return
 		}
+		if err.Error() == "meeting is not live" {
+			utils.ConflictResponse(ctx, "Only live meetings can be ended")
+			return
+		}
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
 	}
 
 	utils.SuccessResponse(ctx, http.StatusOK, meeting.ToResponse(), "Meeting ended successfully")
-}
\ No newline at end of file
+}
+
+// ArchiveMeeting handles archiving a meeting
+// @Summary Archive a meeting
+// @Description Archive a scheduled or ended meeting so it can no longer be joined
+// @Tags meetings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/archive [patch]
+func (c *MeetingController) ArchiveMeeting(ctx *gin.Context) {
+	userID, exists := utils.GetUserID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_USER_ID", "Invalid user ID")
+		return
+	}
+
+	meetingIDStr := ctx.Param("id")
+	meetingID, err := uuid.Parse(meetingIDStr)
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+
+	meeting, err := c.meetingService.ArchiveMeeting(meetingID, userUUID)
+	if err != nil {
+		if err.Error() == "meeting not found or unauthorized" {
+			utils.ForbiddenResponse(ctx, "Meeting not found or you don't have permission to archive it")
+			return
+		}
+		if err.Error() == "meeting is live" {
+			utils.ConflictResponse(ctx, "Live meetings must be ended before they are archived")
+			return
+		}
+		utils.InternalServerErrorResponse(ctx, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, meeting.ToResponse(), "Meeting archived successfully")
+}
+
+// UpdateParticipantRole handles changing a participant's role in a meeting
+// @Summary Change a participant's role
+// @Description Promote or demote a participant. Hosts and co-hosts may only assign roles below their own.
+// @Tags meetings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param participantId path string true "Participant ID"
+// @Param request body models.UpdateParticipantRoleRequest true "New role"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 500 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/participants/{participantId}/role [put]
+func (c *MeetingController) UpdateParticipantRole(ctx *gin.Context) {
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+
+	participantID, err := uuid.Parse(ctx.Param("participantId"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_PARTICIPANT_ID", "Invalid participant ID")
+		return
+	}
+
+	var req models.UpdateParticipantRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	participant, err := c.roleService.ChangeRole(meetingID, models.MeetingActor{UserID: &userUUID}, participantID, req.Role)
+	if err != nil {
+		switch err.Error() {
+		case "meeting not found":
+			utils.NotFoundResponse(ctx, "Meeting not found")
+		case "participant not found":
+			utils.NotFoundResponse(ctx, "Participant not found")
+		case "not a participant", "permission denied":
+			utils.ForbiddenResponse(ctx, "You don't have permission to change this participant's role")
+		default:
+			utils.InternalServerErrorResponse(ctx, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, participant.ToResponse(), "Participant role updated successfully")
+}
+
+// handleRecurrenceError writes the response for recurrence rule errors and
+// reports whether err was one
+func handleRecurrenceError(ctx *gin.Context, err error) bool {
+	switch {
+	case strings.HasPrefix(err.Error(), "invalid recurrence rule"):
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_RECURRENCE_RULE", err.Error())
+	case err.Error() == "recurrence rule has no occurrences":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "NO_OCCURRENCES", "The recurrence rule produces no occurrences")
+	case err.Error() == "recurrence rule can only be changed for the series":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_SCOPE", "Change the recurrence rule with the following or all scope")
+	default:
+		return false
+	}
+	return true
+}
+
+// handleScheduleError responds to invalid durations, time zones and
+// overlapping meetings. It reports whether err was one of them.
+func handleScheduleError(ctx *gin.Context, err error) bool {
+	switch err.Error() {
+	case "invalid time zone":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_TIME_ZONE", "Time zone must be an IANA name such as Europe/Berlin")
+	case "end time must be after start time":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_END_TIME", "End time must be after start time")
+	case "invalid meeting duration":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_DURATION", "Duration must be between 1 minute and 24 hours")
+	case "meeting overlaps another meeting":
+		utils.SendErrorResponse(ctx, http.StatusConflict, "MEETING_OVERLAP", "You already have a meeting at this time; set allowOverlap to schedule anyway")
+	default:
+		return false
+	}
+	return true
+}
+
+// parseListFilter reads the from/to date range of meeting list endpoints.
+// Dates without a time are whole days in the timeZone query parameter, so
+// to=2025-01-31 includes that day.
+func parseListFilter(ctx *gin.Context) (models.MeetingListFilter, bool) {
+	var filter models.MeetingListFilter
+
+	location, err := models.LoadTimeZone(ctx.Query("timeZone"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_TIME_ZONE", "Time zone must be an IANA name such as Europe/Berlin")
+		return filter, false
+	}
+
+	parse := func(name string, endOfDay bool) (*time.Time, bool) {
+		value := ctx.Query(name)
+		if value == "" {
+			return nil, true
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return &t, true
+		}
+		if t, err := time.ParseInLocation("2006-01-02", value, location); err == nil {
+			if endOfDay {
+				t = t.AddDate(0, 0, 1)
+			}
+			return &t, true
+		}
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_DATE_RANGE", "from and to must be RFC 3339 times or YYYY-MM-DD dates")
+		return nil, false
+	}
+
+	var ok bool
+	if filter.From, ok = parse("from", false); !ok {
+		return filter, false
+	}
+	if filter.To, ok = parse("to", true); !ok {
+		return filter, false
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_DATE_RANGE", "to must be after from")
+		return filter, false
+	}
+	return filter, true
+}
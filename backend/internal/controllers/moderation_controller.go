@@ -0,0 +1,472 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+	"github.com/your-org/gomeet-backend/internal/services"
+	"github.com/your-org/gomeet-backend/internal/utils"
+)
+
+type ModerationController struct {
+	moderationService *services.ModerationService
+	validator         *validator.Validate
+}
+
+func NewModerationController(moderationService *services.ModerationService) *ModerationController {
+	return &ModerationController{
+		moderationService: moderationService,
+		validator:         validator.New(),
+	}
+}
+
+// MuteParticipant handles force-muting a participant
+// @Summary Mute a participant
+// @Description Ask a participant's clients to stop publishing audio and/or video (hosts and co-hosts only)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param participantId path string true "Participant ID"
+// @Param request body models.MuteParticipantRequest true "Tracks to mute"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/participants/{participantId}/mute [post]
+func (c *ModerationController) MuteParticipant(ctx *gin.Context) {
+	userUUID, meetingID, participantID, ok := c.parseParticipantParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.MuteParticipantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	entry, err := c.moderationService.MuteParticipant(meetingID, userUUID, participantID, &req)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, entry, "Participant muted successfully")
+}
+
+// MuteParticipantChat handles muting a participant in chat
+// @Summary Mute a participant in chat
+// @Description Keep a participant from writing in the chat, for duration seconds or until unmuted (hosts and co-hosts only)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param participantId path string true "Participant ID"
+// @Param request body models.ChatMuteRequest false "Duration and reason"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/participants/{participantId}/chat-mute [post]
+func (c *ModerationController) MuteParticipantChat(ctx *gin.Context) {
+	userUUID, meetingID, participantID, ok := c.parseParticipantParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.ChatMuteRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.ValidationError(ctx, err)
+			return
+		}
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	entry, err := c.moderationService.MuteParticipantChat(meetingID, userUUID, participantID, &req)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, entry, "Participant muted in chat successfully")
+}
+
+// UnmuteParticipantChat handles lifting a participant's chat mute
+// @Summary Unmute a participant in chat
+// @Description Let a participant muted in chat write again (hosts and co-hosts only)
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param participantId path string true "Participant ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/participants/{participantId}/chat-mute [delete]
+func (c *ModerationController) UnmuteParticipantChat(ctx *gin.Context) {
+	userUUID, meetingID, participantID, ok := c.parseParticipantParams(ctx)
+	if !ok {
+		return
+	}
+
+	entry, err := c.moderationService.UnmuteParticipantChat(meetingID, userUUID, participantID)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, entry, "Participant unmuted in chat successfully")
+}
+
+// SetChatSlowMode handles changing the chat slow mode of a meeting
+// @Summary Set chat slow mode
+// @Description Make participants wait between two chat messages; 0 seconds turns it off (hosts and co-hosts only, who are exempt)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param request body models.ChatSlowModeRequest true "Interval in seconds"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/chat/slow-mode [patch]
+func (c *ModerationController) SetChatSlowMode(ctx *gin.Context) {
+	userUUID, meetingID, ok := c.parseMeetingParams(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.ChatSlowModeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+	if err := c.validator.Struct(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	meeting, err := c.moderationService.SetChatSlowMode(meetingID, userUUID, req.Seconds)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, meeting.ToResponse(), "Slow mode updated successfully")
+}
+
+// RemoveParticipant handles removing a participant from a meeting
+// @Summary Remove a participant
+// @Description Disconnect a participant from the meeting (hosts and co-hosts only)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param participantId path string true "Participant ID"
+// @Param request body models.RemoveParticipantRequest false "Reason"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/participants/{participantId}/remove [post]
+func (c *ModerationController) RemoveParticipant(ctx *gin.Context) {
+	userUUID, meetingID, participantID, ok := c.parseParticipantParams(ctx)
+	if !ok {
+		return
+	}
+
+	req, ok := c.bindRemoveRequest(ctx)
+	if !ok {
+		return
+	}
+
+	entry, err := c.moderationService.RemoveParticipant(meetingID, userUUID, participantID, req.Reason)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, entry, "Participant removed successfully")
+}
+
+// BanParticipant handles banning a participant from a meeting
+// @Summary Ban a participant
+// @Description Remove a participant and keep their account or public session from rejoining (hosts and co-hosts only)
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param participantId path string true "Participant ID"
+// @Param request body models.RemoveParticipantRequest false "Reason"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/participants/{participantId}/ban [post]
+func (c *ModerationController) BanParticipant(ctx *gin.Context) {
+	userUUID, meetingID, participantID, ok := c.parseParticipantParams(ctx)
+	if !ok {
+		return
+	}
+
+	req, ok := c.bindRemoveRequest(ctx)
+	if !ok {
+		return
+	}
+
+	entry, err := c.moderationService.BanParticipant(meetingID, userUUID, participantID, req.Reason)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, entry, "Participant banned successfully")
+}
+
+// GetBans handles listing the bans of a meeting
+// @Summary Get meeting bans
+// @Description List users and public sessions banned from a meeting (hosts and co-hosts only)
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/bans [get]
+func (c *ModerationController) GetBans(ctx *gin.Context) {
+	userUUID, meetingID, ok := c.parseMeetingParams(ctx)
+	if !ok {
+		return
+	}
+
+	bans, err := c.moderationService.GetBans(meetingID, userUUID)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, bans, "Bans retrieved successfully")
+}
+
+// UnbanParticipant handles lifting a ban
+// @Summary Lift a ban
+// @Description Allow a banned user or public session to rejoin the meeting (hosts and co-hosts only)
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param banId path string true "Ban ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/bans/{banId} [delete]
+func (c *ModerationController) UnbanParticipant(ctx *gin.Context) {
+	userUUID, meetingID, ok := c.parseMeetingParams(ctx)
+	if !ok {
+		return
+	}
+
+	banID, err := uuid.Parse(ctx.Param("banId"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_BAN_ID", "Invalid ban ID")
+		return
+	}
+
+	if err := c.moderationService.UnbanParticipant(meetingID, userUUID, banID); err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, nil, "Ban lifted successfully")
+}
+
+// LockMeeting handles locking a meeting
+// @Summary Lock a meeting
+// @Description Refuse new participants until the meeting is unlocked (hosts and co-hosts only)
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/lock [patch]
+func (c *ModerationController) LockMeeting(ctx *gin.Context) {
+	userUUID, meetingID, ok := c.parseMeetingParams(ctx)
+	if !ok {
+		return
+	}
+
+	meeting, err := c.moderationService.LockMeeting(meetingID, userUUID)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, meeting.ToResponse(), "Meeting locked successfully")
+}
+
+// UnlockMeeting handles unlocking a meeting
+// @Summary Unlock a meeting
+// @Description Let new participants join the meeting again (hosts and co-hosts only)
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/unlock [patch]
+func (c *ModerationController) UnlockMeeting(ctx *gin.Context) {
+	userUUID, meetingID, ok := c.parseMeetingParams(ctx)
+	if !ok {
+		return
+	}
+
+	meeting, err := c.moderationService.UnlockMeeting(meetingID, userUUID)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, meeting.ToResponse(), "Meeting unlocked successfully")
+}
+
+// GetModerationLog handles retrieving the moderation audit log
+// @Summary Get moderation log
+// @Description Get the most recent moderation actions of a meeting (hosts and co-hosts only)
+// @Tags moderation
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param limit query int false "Number of entries" default(50)
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/meetings/{id}/moderation-log [get]
+func (c *ModerationController) GetModerationLog(ctx *gin.Context) {
+	userUUID, meetingID, ok := c.parseMeetingParams(ctx)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+
+	entries, err := c.moderationService.GetModerationLog(meetingID, userUUID, limit)
+	if err != nil {
+		c.handleError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, entries, "Moderation log retrieved successfully")
+}
+
+// parseMeetingParams reads the authenticated user and the meeting ID. It
+// writes the error response and returns false when either is invalid.
+func (c *ModerationController) parseMeetingParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userUUID, meetingID, true
+}
+
+// parseParticipantParams is parseMeetingParams plus the participant ID
+func (c *ModerationController) parseParticipantParams(ctx *gin.Context) (uuid.UUID, uuid.UUID, uuid.UUID, bool) {
+	userUUID, meetingID, ok := c.parseMeetingParams(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	participantID, err := uuid.Parse(ctx.Param("participantId"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_PARTICIPANT_ID", "Invalid participant ID")
+		return uuid.Nil, uuid.Nil, uuid.Nil, false
+	}
+
+	return userUUID, meetingID, participantID, true
+}
+
+// bindRemoveRequest reads the optional removal reason
+func (c *ModerationController) bindRemoveRequest(ctx *gin.Context) (*models.RemoveParticipantRequest, bool) {
+	var req models.RemoveParticipantRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.ValidationError(ctx, err)
+			return nil, false
+		}
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// handleError maps moderation service errors to responses
+func (c *ModerationController) handleError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "meeting not found":
+		utils.NotFoundResponse(ctx, "Meeting not found")
+	case "participant not found":
+		utils.NotFoundResponse(ctx, "Participant not found")
+	case "ban not found":
+		utils.NotFoundResponse(ctx, "Ban not found")
+	case "not a participant", "permission denied":
+		utils.ForbiddenResponse(ctx, "You don't have permission to moderate this meeting")
+	case "cannot moderate yourself":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_TARGET", "You cannot moderate yourself")
+	case "nothing to mute":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "NOTHING_TO_MUTE", "Select audio, video or both to mute")
+	case "participant not muted in chat":
+		utils.SendErrorResponse(ctx, http.StatusConflict, "NOT_MUTED", "The participant is not muted in chat")
+	case "invalid slow mode":
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_SLOW_MODE", "Slow mode must be between 0 and 3600 seconds")
+	default:
+		utils.InternalServerErrorResponse(ctx, err.Error())
+	}
+}
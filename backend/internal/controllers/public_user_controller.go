@@ -64,8 +64,12 @@ func (c *PublicUserController) CreatePublicUser(ctx *gin.Context) {
 // @Produce json
 // @Param request body models.JoinMeetingAsPublicUserRequest true "Join meeting request"
 // @Success 200 {object} utils.APIResponse
+// @Success 202 {object} utils.APIResponse "Waiting in the lobby"
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
 // @Failure 500 {object} utils.ErrorResponse
 // @Router /api/public/meetings/join [post]
 func (c *PublicUserController) JoinMeetingAsPublicUser(ctx *gin.Context) {
@@ -86,8 +90,16 @@ func (c *PublicUserController) JoinMeetingAsPublicUser(ctx *gin.Context) {
 		return
 	}
 
-	participant, err := c.publicUserService.JoinMeetingAsPublicUser(req.SessionID, meetingID)
+	creds := models.MeetingCredentials{
+		Passcode:    req.Passcode,
+		InviteToken: req.InviteToken,
+		ClientIP:    ctx.ClientIP(),
+	}
+	participant, err := c.publicUserService.JoinMeetingAsPublicUser(req.SessionID, meetingID, creds)
 	if err != nil {
+		if handleMeetingAccessError(ctx, err) {
+			return
+		}
 		if err.Error() == "meeting not found" {
 			utils.NotFoundResponse(ctx, "Meeting not found")
 			return
@@ -96,10 +108,27 @@ func (c *PublicUserController) JoinMeetingAsPublicUser(ctx *gin.Context) {
 			utils.NotFoundResponse(ctx, "Public user not found")
 			return
 		}
+		if err.Error() == "banned from meeting" {
+			utils.SendErrorResponse(ctx, http.StatusForbidden, "BANNED_FROM_MEETING", "You have been banned from this meeting")
+			return
+		}
+		if err.Error() == "denied from lobby" {
+			utils.SendErrorResponse(ctx, http.StatusForbidden, "LOBBY_DENIED", "A host denied you entry to this meeting")
+			return
+		}
+		if err.Error() == "meeting is locked" {
+			utils.SendErrorResponse(ctx, http.StatusForbidden, "MEETING_LOCKED", "This meeting is locked")
+			return
+		}
 		utils.InternalServerErrorResponse(ctx, err.Error())
 		return
 	}
 
+	if participant.LobbyStatus == models.LobbyStatusPending {
+		utils.SuccessResponse(ctx, http.StatusAccepted, participant.ToResponse(), "Waiting for a host to admit you")
+		return
+	}
+
 	utils.SuccessResponse(ctx, http.StatusOK, participant.ToResponse(), "Joined meeting successfully")
 }
 
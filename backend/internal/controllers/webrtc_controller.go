@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -14,16 +16,22 @@ import (
 )
 
 type WebRTCController struct {
-	webrtcService *services.WebRTCService
-	db            *gorm.DB
-	validator     *validator.Validate
+	webrtcService      *services.WebRTCService
+	roleService        *services.RoleService
+	mediaPolicyService *services.MediaPolicyService
+	qualityService     *services.QualityService
+	db                 *gorm.DB
+	validator          *validator.Validate
 }
 
-func NewWebRTCController(webrtcService *services.WebRTCService, db *gorm.DB) *WebRTCController {
+func NewWebRTCController(webrtcService *services.WebRTCService, roleService *services.RoleService, mediaPolicyService *services.MediaPolicyService, qualityService *services.QualityService, db *gorm.DB) *WebRTCController {
 	return &WebRTCController{
-		webrtcService: webrtcService,
-		db:            db,
-		validator:     validator.New(),
+		webrtcService:      webrtcService,
+		roleService:        roleService,
+		mediaPolicyService: mediaPolicyService,
+		qualityService:     qualityService,
+		db:                 db,
+		validator:          validator.New(),
 	}
 }
 
@@ -68,16 +76,8 @@ func (c *WebRTCController) GetMeetingPeers(ctx *gin.Context) {
 	}
 
 	// Check if user has access to this meeting (host or participant)
-	hasAccess := false
-	if meeting.HostID == userUUID {
-		hasAccess = true
-	} else {
-		// Check if user is a participant
-		var participant models.Participant
-		if err := c.db.Where("meeting_id = ? AND user_id = ?", meetingID, userUUID).First(&participant).Error; err == nil {
-			hasAccess = true
-		}
-	}
+	_, roleErr := c.roleService.GetRole(meetingID, models.MeetingActor{UserID: &userUUID})
+	hasAccess := roleErr == nil
 
 	if !hasAccess {
 		utils.ForbiddenResponse(ctx, "You don't have access to this meeting")
@@ -97,6 +97,7 @@ func (c *WebRTCController) GetMeetingPeers(ctx *gin.Context) {
 			UserID:           peer.UserID,
 			PublicUserID:     peer.PublicUserID,
 			State:            peer.State,
+			Codecs:           peer.Codecs,
 			JoinedAt:         peer.JoinedAt,
 			LastSeen:         peer.LastSeen,
 		}
@@ -111,7 +112,7 @@ func (c *WebRTCController) GetMeetingPeers(ctx *gin.Context) {
 
 // JoinMeeting joins a WebRTC meeting
 // @Summary Join WebRTC meeting
-// @Description Join a WebRTC meeting as a peer
+// @Description Join a WebRTC meeting as a peer, using one of the user's WebSocket connections to the meeting
 // @Tags webrtc
 // @Accept json
 // @Produce json
@@ -122,6 +123,7 @@ func (c *WebRTCController) GetMeetingPeers(ctx *gin.Context) {
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
 // @Router /api/v1/webrtc/meetings/{id}/join [post]
 func (c *WebRTCController) JoinMeeting(ctx *gin.Context) {
 	meetingIDStr := ctx.Param("id")
@@ -152,16 +154,8 @@ func (c *WebRTCController) JoinMeeting(ctx *gin.Context) {
 	}
 
 	// Check if user has access to this meeting
-	hasAccess := false
-	if meeting.HostID == userUUID {
-		hasAccess = true
-	} else {
-		// Check if user is a participant
-		var participant models.Participant
-		if err := c.db.Where("meeting_id = ? AND user_id = ?", meetingID, userUUID).First(&participant).Error; err == nil {
-			hasAccess = true
-		}
-	}
+	_, roleErr := c.roleService.GetRole(meetingID, models.MeetingActor{UserID: &userUUID})
+	hasAccess := roleErr == nil
 
 	if !hasAccess {
 		utils.ForbiddenResponse(ctx, "You don't have access to this meeting")
@@ -179,29 +173,22 @@ func (c *WebRTCController) JoinMeeting(ctx *gin.Context) {
 		return
 	}
 
-	// Get user details
-	var user models.User
-	if err := c.db.Where("id = ?", userUUID).First(&user).Error; err != nil {
-		utils.SendErrorResponse(ctx, http.StatusNotFound, "USER_NOT_FOUND", "User not found")
-		return
-	}
-
-	// Generate peer ID if not provided
-	peerID := req.PeerID
-	if peerID == "" {
-		peerID = uuid.New().String()
-	}
-
-	// Join meeting
-	peer, err := c.webrtcService.JoinMeeting(meetingIDStr, peerID, &userUUID, nil, user.Username, true)
+	// Join meeting as one of the user's WebSocket connections
+	peer, err := c.webrtcService.JoinMeeting(meetingIDStr, req.PeerID, models.MeetingActor{UserID: &userUUID})
 	if err != nil {
-		utils.SendErrorResponse(ctx, http.StatusInternalServerError, "JOIN_FAILED", err.Error())
+		switch err.Error() {
+		case "meeting not found":
+			utils.NotFoundResponse(ctx, "Meeting not found")
+		case "peer not owned":
+			utils.ForbiddenResponse(ctx, "The peer belongs to someone else")
+		case "peer not connected":
+			utils.SendErrorResponse(ctx, http.StatusConflict, "PEER_NOT_CONNECTED", "Connect to the meeting's WebSocket before joining WebRTC")
+		default:
+			utils.SendErrorResponse(ctx, http.StatusInternalServerError, "JOIN_FAILED", err.Error())
+		}
 		return
 	}
 
-	// Update peer state to connecting
-	c.webrtcService.UpdatePeerState(meetingIDStr, peerID, models.PeerStateConnecting)
-
 	utils.SuccessResponse(ctx, http.StatusOK, models.WebRTCPeerResponse{
 		ID:               peer.ID,
 		Name:             peer.Name,
@@ -226,6 +213,8 @@ func (c *WebRTCController) JoinMeeting(ctx *gin.Context) {
 // @Success 200 {object} utils.APIResponse
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
 // @Router /api/v1/webrtc/meetings/{id}/leave [post]
 func (c *WebRTCController) LeaveMeeting(ctx *gin.Context) {
 	meetingIDStr := ctx.Param("id")
@@ -241,8 +230,20 @@ func (c *WebRTCController) LeaveMeeting(ctx *gin.Context) {
 		return
 	}
 
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	// Only the peer's own user may close it
+	peer, ok := c.resolveOwnPeer(ctx, meetingIDStr, req.PeerID, userUUID)
+	if !ok {
+		return
+	}
+
 	// Leave meeting
-	err := c.webrtcService.LeaveMeeting(meetingIDStr, req.PeerID)
+	err := c.webrtcService.LeaveMeeting(meetingIDStr, peer.ID)
 	if err != nil {
 		utils.SendErrorResponse(ctx, http.StatusBadRequest, "LEAVE_FAILED", err.Error())
 		return
@@ -263,6 +264,8 @@ func (c *WebRTCController) LeaveMeeting(ctx *gin.Context) {
 // @Success 200 {object} utils.APIResponse
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
 // @Router /api/v1/webrtc/meetings/{id}/offer [post]
 func (c *WebRTCController) SendOffer(ctx *gin.Context) {
 	meetingIDStr := ctx.Param("id")
@@ -291,23 +294,14 @@ func (c *WebRTCController) SendOffer(ctx *gin.Context) {
 		return
 	}
 
-	// Find the peer ID for this user
-	peers := c.webrtcService.GetMeetingPeers(meetingIDStr)
-	var fromPeerID string
-	for _, peer := range peers {
-		if peer.UserID != nil && *peer.UserID == userUUID {
-			fromPeerID = peer.ID
-			break
-		}
-	}
-
-	if fromPeerID == "" {
-		utils.SendErrorResponse(ctx, http.StatusBadRequest, "NOT_IN_MEETING", "You are not in this WebRTC meeting")
+	// Find the user's peer, the one named in the request or else their first
+	peer, ok := c.resolveOwnPeer(ctx, meetingIDStr, req.PeerID, userUUID)
+	if !ok {
 		return
 	}
 
 	// Send offer
-	err = c.webrtcService.SendOffer(meetingIDStr, fromPeerID, req.To, req.Offer)
+	err = c.webrtcService.SendOffer(meetingIDStr, peer.ID, req.To, req.Offer)
 	if err != nil {
 		utils.SendErrorResponse(ctx, http.StatusBadRequest, "OFFER_FAILED", err.Error())
 		return
@@ -328,6 +322,8 @@ func (c *WebRTCController) SendOffer(ctx *gin.Context) {
 // @Success 200 {object} utils.APIResponse
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
 // @Router /api/v1/webrtc/meetings/{id}/answer [post]
 func (c *WebRTCController) SendAnswer(ctx *gin.Context) {
 	meetingIDStr := ctx.Param("id")
@@ -356,23 +352,14 @@ func (c *WebRTCController) SendAnswer(ctx *gin.Context) {
 		return
 	}
 
-	// Find the peer ID for this user
-	peers := c.webrtcService.GetMeetingPeers(meetingIDStr)
-	var fromPeerID string
-	for _, peer := range peers {
-		if peer.UserID != nil && *peer.UserID == userUUID {
-			fromPeerID = peer.ID
-			break
-		}
-	}
-
-	if fromPeerID == "" {
-		utils.SendErrorResponse(ctx, http.StatusBadRequest, "NOT_IN_MEETING", "You are not in this WebRTC meeting")
+	// Find the user's peer, the one named in the request or else their first
+	peer, ok := c.resolveOwnPeer(ctx, meetingIDStr, req.PeerID, userUUID)
+	if !ok {
 		return
 	}
 
 	// Send answer
-	err = c.webrtcService.SendAnswer(meetingIDStr, fromPeerID, req.To, req.Answer)
+	err = c.webrtcService.SendAnswer(meetingIDStr, peer.ID, req.To, req.Answer)
 	if err != nil {
 		utils.SendErrorResponse(ctx, http.StatusBadRequest, "ANSWER_FAILED", err.Error())
 		return
@@ -393,6 +380,8 @@ func (c *WebRTCController) SendAnswer(ctx *gin.Context) {
 // @Success 200 {object} utils.APIResponse
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
 // @Router /api/v1/webrtc/meetings/{id}/ice-candidate [post]
 func (c *WebRTCController) SendIceCandidate(ctx *gin.Context) {
 	meetingIDStr := ctx.Param("id")
@@ -421,23 +410,14 @@ func (c *WebRTCController) SendIceCandidate(ctx *gin.Context) {
 		return
 	}
 
-	// Find the peer ID for this user
-	peers := c.webrtcService.GetMeetingPeers(meetingIDStr)
-	var fromPeerID string
-	for _, peer := range peers {
-		if peer.UserID != nil && *peer.UserID == userUUID {
-			fromPeerID = peer.ID
-			break
-		}
-	}
-
-	if fromPeerID == "" {
-		utils.SendErrorResponse(ctx, http.StatusBadRequest, "NOT_IN_MEETING", "You are not in this WebRTC meeting")
+	// Find the user's peer, the one named in the request or else their first
+	peer, ok := c.resolveOwnPeer(ctx, meetingIDStr, req.PeerID, userUUID)
+	if !ok {
 		return
 	}
 
 	// Send ICE candidate
-	err = c.webrtcService.SendIceCandidate(meetingIDStr, fromPeerID, req.To, req.Candidate)
+	err = c.webrtcService.SendIceCandidate(meetingIDStr, peer.ID, req.To, req.Candidate)
 	if err != nil {
 		utils.SendErrorResponse(ctx, http.StatusBadRequest, "ICE_CANDIDATE_FAILED", err.Error())
 		return
@@ -448,7 +428,7 @@ func (c *WebRTCController) SendIceCandidate(ctx *gin.Context) {
 
 // UpdatePeerState updates a peer's connection state
 // @Summary Update peer state
-// @Description Update a WebRTC peer's connection state
+// @Description Update a WebRTC peer's connection state. A peer that stays disconnected or failed gets ICE restarts requested on its behalf, up to a limit per pair of peers.
 // @Tags webrtc
 // @Accept json
 // @Produce json
@@ -458,6 +438,8 @@ func (c *WebRTCController) SendIceCandidate(ctx *gin.Context) {
 // @Success 200 {object} utils.APIResponse
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
 // @Router /api/v1/webrtc/meetings/{id}/peer-state [put]
 func (c *WebRTCController) UpdatePeerState(ctx *gin.Context) {
 	meetingIDStr := ctx.Param("id")
@@ -486,23 +468,14 @@ func (c *WebRTCController) UpdatePeerState(ctx *gin.Context) {
 		return
 	}
 
-	// Find the peer ID for this user
-	peers := c.webrtcService.GetMeetingPeers(meetingIDStr)
-	var peerID string
-	for _, peer := range peers {
-		if peer.UserID != nil && *peer.UserID == userUUID {
-			peerID = peer.ID
-			break
-		}
-	}
-
-	if peerID == "" {
-		utils.SendErrorResponse(ctx, http.StatusBadRequest, "NOT_IN_MEETING", "You are not in this WebRTC meeting")
+	// Find the user's peer, the one named in the request or else their first
+	peer, ok := c.resolveOwnPeer(ctx, meetingIDStr, req.PeerID, userUUID)
+	if !ok {
 		return
 	}
 
 	// Update peer state
-	err = c.webrtcService.UpdatePeerState(meetingIDStr, peerID, models.PeerConnectionState(req.State))
+	err = c.webrtcService.UpdatePeerState(meetingIDStr, peer.ID, models.PeerConnectionState(req.State))
 	if err != nil {
 		utils.SendErrorResponse(ctx, http.StatusBadRequest, "UPDATE_FAILED", err.Error())
 		return
@@ -513,7 +486,7 @@ func (c *WebRTCController) UpdatePeerState(ctx *gin.Context) {
 
 // GetRoomStats returns statistics about a WebRTC room
 // @Summary Get room statistics
-// @Description Get statistics about a WebRTC room (host only)
+// @Description Get statistics about a WebRTC room (hosts and co-hosts only)
 // @Tags webrtc
 // @Produce json
 // @Security BearerAuth
@@ -544,15 +517,13 @@ func (c *WebRTCController) GetRoomStats(ctx *gin.Context) {
 		return
 	}
 
-	// Check if user is the meeting host
-	var meeting models.Meeting
-	if err := c.db.Where("id = ?", meetingID).First(&meeting).Error; err != nil {
-		utils.NotFoundResponse(ctx, "Meeting not found")
-		return
-	}
-
-	if meeting.HostID != userUUID {
-		utils.ForbiddenResponse(ctx, "Only meeting hosts can view room statistics")
+	// Check if user may manage the meeting
+	if err := c.roleService.Authorize(meetingID, models.MeetingActor{UserID: &userUUID}, models.PermissionManageMeeting); err != nil {
+		if err.Error() == "meeting not found" {
+			utils.NotFoundResponse(ctx, "Meeting not found")
+			return
+		}
+		utils.ForbiddenResponse(ctx, "Only meeting hosts and co-hosts can view room statistics")
 		return
 	}
 
@@ -562,9 +533,364 @@ func (c *WebRTCController) GetRoomStats(ctx *gin.Context) {
 	utils.SuccessResponse(ctx, http.StatusOK, stats, "Room statistics retrieved successfully")
 }
 
+// GetMediaPolicy returns the media policy enforced on a meeting's signaling
+// @Summary Get meeting media policy
+// @Description Get the codecs, bitrate cap, simulcast and privacy settings enforced on the offers, answers and ICE candidates of a meeting's peers
+// @Tags webrtc
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Success 200 {object} utils.APIResponse{data=models.MediaPolicyResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/webrtc/meetings/{id}/media-policy [get]
+func (c *WebRTCController) GetMediaPolicy(ctx *gin.Context) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+
+	userID, exists := utils.GetUserID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_USER_ID", "Invalid user ID")
+		return
+	}
+
+	policy, err := c.mediaPolicyService.GetMediaPolicy(meetingID, models.MeetingActor{UserID: &userUUID})
+	if err != nil {
+		switch err.Error() {
+		case "meeting not found":
+			utils.NotFoundResponse(ctx, "Meeting not found")
+		case "not a participant":
+			utils.ForbiddenResponse(ctx, "You don't have access to this meeting")
+		default:
+			utils.InternalServerErrorResponse(ctx, "Failed to get media policy")
+		}
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, policy.ToResponse(), "Media policy retrieved successfully")
+}
+
+// UpdateMediaPolicy changes the media policy enforced on a meeting's signaling
+// @Summary Update meeting media policy
+// @Description Change the codecs, bitrate cap, simulcast and privacy settings enforced on a meeting's signaling (hosts and co-hosts only). Fields left out keep their current value.
+// @Tags webrtc
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param request body models.UpdateMediaPolicyRequest true "Media policy changes"
+// @Success 200 {object} utils.APIResponse{data=models.MediaPolicyResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/webrtc/meetings/{id}/media-policy [put]
+func (c *WebRTCController) UpdateMediaPolicy(ctx *gin.Context) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return
+	}
+
+	var req models.UpdateMediaPolicyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	userID, exists := utils.GetUserID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_USER_ID", "Invalid user ID")
+		return
+	}
+
+	policy, err := c.mediaPolicyService.UpdateMediaPolicy(meetingID, models.MeetingActor{UserID: &userUUID}, &req)
+	if err != nil {
+		switch err.Error() {
+		case "meeting not found":
+			utils.NotFoundResponse(ctx, "Meeting not found")
+		case "not a participant", "permission denied":
+			utils.ForbiddenResponse(ctx, "Only meeting hosts and co-hosts can change the media policy")
+		case "invalid codec":
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_CODEC", "Codec names may only contain lower case letters, digits, '.', '_' and '-'")
+		default:
+			utils.InternalServerErrorResponse(ctx, "Failed to update media policy")
+		}
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, policy.ToResponse(), "Media policy updated successfully")
+}
+
+// SubmitQualityStats records a peer's connection quality
+// @Summary Submit connection quality stats
+// @Description Submit a summary of a peer connection's getStats() report. Clients send one every few seconds, here or as a quality-stats WebSocket message.
+// @Tags webrtc
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param request body SubmitQualityStatsRequest true "Quality stats"
+// @Success 201 {object} utils.APIResponse{data=models.QualitySample}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Router /api/v1/webrtc/meetings/{id}/quality [post]
+func (c *WebRTCController) SubmitQualityStats(ctx *gin.Context) {
+	meetingIDStr := ctx.Param("id")
+
+	var req SubmitQualityStatsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		utils.ValidationError(ctx, err)
+		return
+	}
+
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return
+	}
+
+	// Find the user's peer, the one named in the request or else their first
+	peer, ok := c.resolveOwnPeer(ctx, meetingIDStr, req.PeerID, userUUID)
+	if !ok {
+		return
+	}
+
+	sample, err := c.qualityService.RecordSample(peer, &req.QualityStatsPayload)
+	if err != nil {
+		switch err.Error() {
+		case "stats submitted too often":
+			utils.TooManyRequestsResponse(ctx, "Stats were submitted too often")
+		case "meeting not found":
+			utils.NotFoundResponse(ctx, "Meeting not found")
+		default:
+			utils.InternalServerErrorResponse(ctx, "Failed to record quality stats")
+		}
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, sample, "Quality stats recorded successfully")
+}
+
+// GetMeetingQuality returns the meeting's connection quality
+// @Summary Get meeting connection quality
+// @Description Get each peer's average stats and quality score over a recent window, worst first, and the meeting's overall score (hosts and co-hosts only)
+// @Tags webrtc
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param window query int false "Window in seconds, 300 by default"
+// @Success 200 {object} utils.APIResponse{data=models.MeetingQualityResponse}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/webrtc/meetings/{id}/quality [get]
+func (c *WebRTCController) GetMeetingQuality(ctx *gin.Context) {
+	meetingID, actor, ok := c.resolveQualityViewer(ctx)
+	if !ok {
+		return
+	}
+	window, ok := parseQualityWindow(ctx)
+	if !ok {
+		return
+	}
+
+	quality, err := c.qualityService.GetMeetingQuality(meetingID, actor, window)
+	if err != nil {
+		respondQualityError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, quality, "Meeting quality retrieved successfully")
+}
+
+// GetPoorConnections returns the peers with a poor connection
+// @Summary Get participants with a poor connection
+// @Description Get the peers whose average quality score over a recent window is below a threshold, worst first (hosts and co-hosts only)
+// @Tags webrtc
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param window query int false "Window in seconds, 60 by default"
+// @Param threshold query int false "Score from 1 to 100 below which a connection is poor, 50 by default"
+// @Success 200 {object} utils.APIResponse{data=[]models.PeerQualitySummary}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/webrtc/meetings/{id}/quality/poor [get]
+func (c *WebRTCController) GetPoorConnections(ctx *gin.Context) {
+	meetingID, actor, ok := c.resolveQualityViewer(ctx)
+	if !ok {
+		return
+	}
+	window, ok := parseQualityWindow(ctx)
+	if !ok {
+		return
+	}
+
+	threshold := models.PoorQualityScore
+	if raw := ctx.Query("threshold"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > 100 {
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_THRESHOLD", "Threshold must be a score from 1 to 100")
+			return
+		}
+		threshold = value
+	}
+
+	peers, err := c.qualityService.GetPoorConnections(meetingID, actor, window, threshold)
+	if err != nil {
+		respondQualityError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, peers, "Poor connections retrieved successfully")
+}
+
+// GetPeerQuality returns a peer's quality samples
+// @Summary Get a peer's connection quality history
+// @Description Get the stats a peer submitted, oldest first (hosts and co-hosts only)
+// @Tags webrtc
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Meeting ID"
+// @Param peerId path string true "Peer ID"
+// @Param since query string false "Only samples taken after this RFC 3339 time"
+// @Param limit query int false "Samples to return, 1000 at most"
+// @Success 200 {object} utils.APIResponse{data=[]models.QualitySample}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/webrtc/meetings/{id}/quality/peers/{peerId} [get]
+func (c *WebRTCController) GetPeerQuality(ctx *gin.Context) {
+	meetingID, actor, ok := c.resolveQualityViewer(ctx)
+	if !ok {
+		return
+	}
+
+	var since time.Time
+	if raw := ctx.Query("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_SINCE", "Since must be an RFC 3339 time")
+			return
+		}
+		since = parsed
+	}
+	limit, _ := strconv.Atoi(ctx.Query("limit"))
+
+	samples, err := c.qualityService.GetPeerSamples(meetingID, actor, ctx.Param("peerId"), since, limit)
+	if err != nil {
+		respondQualityError(ctx, err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, samples, "Peer quality retrieved successfully")
+}
+
+// resolveQualityViewer reads the meeting ID and the authenticated user of a
+// quality query, responding with an error when either is missing
+func (c *WebRTCController) resolveQualityViewer(ctx *gin.Context) (uuid.UUID, models.MeetingActor, bool) {
+	meetingID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_MEETING_ID", "Invalid meeting ID")
+		return uuid.Nil, models.MeetingActor{}, false
+	}
+
+	userUUID, exists := utils.GetUserIDUUID(ctx)
+	if !exists {
+		utils.UnauthorizedResponse(ctx, "User not authenticated")
+		return uuid.Nil, models.MeetingActor{}, false
+	}
+	return meetingID, models.MeetingActor{UserID: &userUUID}, true
+}
+
+// parseQualityWindow reads the optional window query parameter, in seconds;
+// zero leaves the choice to the quality service
+func parseQualityWindow(ctx *gin.Context) (time.Duration, bool) {
+	raw := ctx.Query("window")
+	if raw == "" {
+		return 0, true
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds < 1 {
+		utils.SendErrorResponse(ctx, http.StatusBadRequest, "INVALID_WINDOW", "Window must be a positive number of seconds")
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+func respondQualityError(ctx *gin.Context, err error) {
+	switch err.Error() {
+	case "meeting not found":
+		utils.NotFoundResponse(ctx, "Meeting not found")
+	case "not a participant", "permission denied":
+		utils.ForbiddenResponse(ctx, "Only meeting hosts and co-hosts can view connection quality")
+	default:
+		utils.InternalServerErrorResponse(ctx, "Failed to get connection quality")
+	}
+}
+
+// resolveOwnPeer finds the user's peer named peerID, or their first one
+// when peerID is empty, and responds with an error when there is none or it
+// belongs to someone else
+func (c *WebRTCController) resolveOwnPeer(ctx *gin.Context, meetingID string, peerID string, userID uuid.UUID) (*models.WebRTCPeer, bool) {
+	peer, err := c.webrtcService.FindActorPeer(meetingID, peerID, models.MeetingActor{UserID: &userID})
+	if err != nil {
+		switch {
+		case err.Error() == "peer not owned":
+			utils.ForbiddenResponse(ctx, "The peer belongs to someone else")
+		case peerID != "":
+			utils.NotFoundResponse(ctx, "Peer not found")
+		default:
+			utils.SendErrorResponse(ctx, http.StatusBadRequest, "NOT_IN_MEETING", "You are not in this WebRTC meeting")
+		}
+		return nil, false
+	}
+	return peer, true
+}
+
 // Request types
 type JoinWebRTCMeetingRequest struct {
-	PeerID string `json:"peerId,omitempty"`
+	PeerID string `json:"peerId,omitempty"` // Client ID of the WebSocket connection; defaults to the user's first one
+}
+
+type SubmitQualityStatsRequest struct {
+	PeerID string `json:"peerId,omitempty"` // Client ID of the WebSocket connection; defaults to the user's first one
+	models.QualityStatsPayload
 }
 
 type LeaveWebRTCMeetingRequest struct {
@@ -572,5 +898,6 @@ type LeaveWebRTCMeetingRequest struct {
 }
 
 type UpdatePeerStateRequest struct {
-	State models.PeerConnectionState `json:"state" validate:"required,oneof=new connecting connected disconnected failed closed"`
+	PeerID string                     `json:"peerId,omitempty"` // Client ID of the WebSocket connection; defaults to the user's first one
+	State  models.PeerConnectionState `json:"state" validate:"required,oneof=new connecting connected disconnected failed closed"`
 }
\ No newline at end of file
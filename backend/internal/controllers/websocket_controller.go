@@ -15,13 +15,15 @@ import (
 
 type WebSocketController struct {
 	websocketService *services.WebSocketService
+	roleService      *services.RoleService
 	db               *gorm.DB
 	validator        *validator.Validate
 }
 
-func NewWebSocketController(websocketService *services.WebSocketService, db *gorm.DB) *WebSocketController {
+func NewWebSocketController(websocketService *services.WebSocketService, roleService *services.RoleService, db *gorm.DB) *WebSocketController {
 	return &WebSocketController{
 		websocketService: websocketService,
+		roleService:      roleService,
 		db:               db,
 		validator:        validator.New(),
 	}
@@ -32,8 +34,10 @@ func NewWebSocketController(websocketService *services.WebSocketService, db *gor
 // @Description Establish WebSocket connection for real-time communication in a meeting room
 // @Tags websocket
 // @Param id path string true "Meeting ID"
-// @Param clientId query string false "Client ID (optional, will be generated if not provided)"
+// @Param clientId query string false "Client ID (optional; derived from the caller's identity, and the connection is refused if it differs)"
 // @Param sessionId query string false "Session ID for public users"
+// @Param passcode query string false "Meeting passcode for protected meetings"
+// @Param inviteToken query string false "Invite token from an invite link"
 // @Security BearerAuth
 // @Success 101 {string} string "WebSocket connection established"
 // @Failure 400 {object} utils.ErrorResponse
@@ -90,14 +94,12 @@ func (c *WebSocketController) GetMeetingParticipants(ctx *gin.Context) {
 	}
 
 	if !userInMeeting {
-		// Check if user is the meeting host
-		var meeting models.Meeting
-		if err := c.db.Where("id = ?", meetingID).First(&meeting).Error; err != nil {
-			utils.NotFoundResponse(ctx, "Meeting not found")
-			return
-		}
-		
-		if meeting.HostID != userUUID {
+		// Check if user is the meeting host or a participant
+		if _, err := c.roleService.GetRole(meetingID, models.MeetingActor{UserID: &userUUID}); err != nil {
+			if err.Error() == "meeting not found" {
+				utils.NotFoundResponse(ctx, "Meeting not found")
+				return
+			}
 			utils.ForbiddenResponse(ctx, "You don't have access to this meeting")
 			return
 		}
@@ -107,11 +109,12 @@ func (c *WebSocketController) GetMeetingParticipants(ctx *gin.Context) {
 	participantResponses := make([]WebSocketParticipantResponse, len(participants))
 	for i, participant := range participants {
 		participantResponses[i] = WebSocketParticipantResponse{
-			ID:             participant.ID,
+			ID:             participant.ClientID,
 			Name:           participant.Name,
 			IsAuthenticated: participant.IsAuth,
 			UserID:         participant.UserID,
 			PublicUserID:   participant.PublicUserID,
+			PeerState:      participant.ToPeer().State,
 		}
 	}
 
@@ -193,15 +196,13 @@ func (c *WebSocketController) SendMessageToMeeting(ctx *gin.Context) {
 		return
 	}
 
-	// Check if user is the meeting host
-	var meeting models.Meeting
-	if err := c.db.Where("id = ?", meetingID).First(&meeting).Error; err != nil {
-		utils.NotFoundResponse(ctx, "Meeting not found")
-		return
-	}
-
-	if meeting.HostID != userUUID {
-		utils.ForbiddenResponse(ctx, "Only meeting hosts can send messages to all participants")
+	// Check if user may manage the meeting
+	if err := c.roleService.Authorize(meetingID, models.MeetingActor{UserID: &userUUID}, models.PermissionManageMeeting); err != nil {
+		if err.Error() == "meeting not found" {
+			utils.NotFoundResponse(ctx, "Meeting not found")
+			return
+		}
+		utils.ForbiddenResponse(ctx, "Only meeting hosts and co-hosts can send messages to all participants")
 		return
 	}
 
@@ -235,6 +236,7 @@ type WebSocketParticipantResponse struct {
 	IsAuthenticated bool       `json:"isAuthenticated"`
 	UserID         *uuid.UUID `json:"userId,omitempty"`
 	PublicUserID   *uuid.UUID `json:"publicUserId,omitempty"`
+	PeerState      models.PeerConnectionState `json:"peerState"`
 }
 
 type WebSocketParticipantsResponse struct {
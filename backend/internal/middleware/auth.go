@@ -48,6 +48,7 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
 		c.Set("username", claims.Username)
+		c.Set("sessionID", claims.SessionID)
 
 		c.Next()
 	}
@@ -78,6 +79,7 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
 		c.Set("username", claims.Username)
+		c.Set("sessionID", claims.SessionID)
 
 		c.Next()
 	}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"io"
+	"mime"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttachmentStorage keeps uploaded chat files. Objects are addressed by key
+// and handed to clients through signed, expiring URLs, so the bucket or
+// directory behind a driver never needs to be public.
+type AttachmentStorage interface {
+	// Put stores size bytes read from body under key
+	Put(key string, body io.Reader, size int64, contentType string) error
+	// Delete removes the object; deleting a missing object is not an error
+	Delete(key string) error
+	// SignedURL returns a URL that downloads the object until it expires
+	SignedURL(key string, download AttachmentDownload, expiry time.Duration) (string, error)
+}
+
+// AttachmentDownload describes how a signed URL serves the object
+type AttachmentDownload struct {
+	FileName    string
+	ContentType string
+	Inline      bool // Display in the browser rather than save as a file
+}
+
+// ContentDisposition returns the Content-Disposition header for the download
+func (d AttachmentDownload) ContentDisposition() string {
+	disposition := "attachment"
+	if d.Inline {
+		disposition = "inline"
+	}
+	if d.FileName != "" {
+		if header := mime.FormatMediaType(disposition, map[string]string{"filename": d.FileName}); header != "" {
+			return header
+		}
+	}
+	return disposition
+}
+
+// ChatAttachment is a file uploaded to a meeting's chat. It is uploaded
+// first and then claimed by the message that carries it.
+type ChatAttachment struct {
+	ID                   uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	MeetingID            uuid.UUID  `gorm:"type:uuid;not null;index" json:"meetingId"`
+	MessageID            *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"messageId,omitempty"`
+	UploaderUserID       *uuid.UUID `gorm:"type:uuid" json:"uploaderUserId,omitempty"`
+	UploaderPublicUserID *uuid.UUID `gorm:"type:uuid" json:"uploaderPublicUserId,omitempty"`
+	FileName             string     `gorm:"size:255;not null" json:"fileName"`
+	ContentType          string     `gorm:"size:100;not null" json:"contentType"`
+	Size                 int64      `gorm:"not null" json:"size"`
+	StorageKey           string     `gorm:"size:500;not null" json:"-"`
+	ThumbnailKey         string     `gorm:"size:500" json:"-"`
+	ThumbnailType        string     `gorm:"size:100" json:"-"`
+	Width                int        `json:"width,omitempty"`
+	Height               int        `json:"height,omitempty"`
+	CreatedAt            time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Signed download URLs, filled in when the attachment is served
+	URL          string    `gorm:"-" json:"-"`
+	ThumbnailURL string    `gorm:"-" json:"-"`
+	URLExpiresAt time.Time `gorm:"-" json:"-"`
+}
+
+type ChatAttachmentResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	MeetingID    uuid.UUID  `json:"meetingId"`
+	MessageID    *uuid.UUID `json:"messageId,omitempty"`
+	FileName     string     `json:"fileName"`
+	ContentType  string     `json:"contentType"`
+	Size         int64      `json:"size"`
+	Width        int        `json:"width,omitempty"`
+	Height       int        `json:"height,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	ThumbnailURL string     `json:"thumbnailUrl,omitempty"`
+	URLExpiresAt *time.Time `json:"urlExpiresAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// IsImage reports whether the attachment is shown as an image
+func (a *ChatAttachment) IsImage() bool {
+	switch a.ContentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+func (a *ChatAttachment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+func (a *ChatAttachment) ToResponse() ChatAttachmentResponse {
+	response := ChatAttachmentResponse{
+		ID:           a.ID,
+		MeetingID:    a.MeetingID,
+		MessageID:    a.MessageID,
+		FileName:     a.FileName,
+		ContentType:  a.ContentType,
+		Size:         a.Size,
+		Width:        a.Width,
+		Height:       a.Height,
+		URL:          a.URL,
+		ThumbnailURL: a.ThumbnailURL,
+		CreatedAt:    a.CreatedAt,
+	}
+	if !a.URLExpiresAt.IsZero() {
+		expiresAt := a.URLExpiresAt
+		response.URLExpiresAt = &expiresAt
+	}
+	return response
+}
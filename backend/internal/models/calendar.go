@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CalendarFeed is a user's calendar subscription. The feed URL carries a
+// random token; only its SHA-256 hash is stored.
+type CalendarFeed struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"userId"`
+	TokenHash      string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	LastAccessedAt *time.Time `json:"lastAccessedAt,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+type CalendarFeedResponse struct {
+	URL       string    `json:"url"` // Only returned when the feed is created
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// MeetingCancellation remembers an upcoming meeting that was deleted so
+// calendar feeds can publish it as cancelled
+type MeetingCancellation struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	MeetingID   uuid.UUID `gorm:"type:uuid;not null;index" json:"meetingId"`
+	HostID      uuid.UUID `gorm:"type:uuid;not null;index" json:"hostId"`
+	Name        string    `gorm:"not null;size:255" json:"name"`
+	StartTime   time.Time `gorm:"not null" json:"startTime"`
+	EndTime     time.Time `gorm:"not null" json:"endTime"`
+	Sequence    int       `gorm:"not null;default:0" json:"sequence"` // iCalendar SEQUENCE of the cancellation
+	CancelledAt time.Time `gorm:"not null" json:"cancelledAt"`
+}
+
+// BeforeCreate hook to generate UUID
+func (f *CalendarFeed) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
+
+// BeforeCreate hook to generate UUID
+func (c *MeetingCancellation) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
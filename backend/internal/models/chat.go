@@ -15,6 +15,7 @@ const (
 	MessageTypeFile     MessageType = "file"
 	MessageTypeSystem   MessageType = "system"
 	MessageTypeReaction MessageType = "reaction"
+	MessageTypeAnnouncement MessageType = "announcement" // Sent by hosts and co-hosts, shown apart from the conversation
 )
 
 type MessageStatus string
@@ -27,13 +28,16 @@ const (
 )
 
 type ChatMessage struct {
-	ID            uuid.UUID     `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ID            uuid.UUID     `gorm:"type:uuid;primary_key" json:"id"`
 	MeetingID     uuid.UUID     `gorm:"type:uuid;not null" json:"meetingId"`
 	UserID        *uuid.UUID    `gorm:"type:uuid;default:null" json:"userId,omitempty"`
 	PublicUserID  *uuid.UUID    `gorm:"type:uuid;default:null" json:"publicUserId,omitempty"`
 	MessageType   MessageType   `gorm:"type:varchar(20);not null;default:'text'" json:"messageType"`
 	Content       string        `gorm:"type:text;not null" json:"content"`
 	ReplyToID     *uuid.UUID    `gorm:"type:uuid;default:null" json:"replyToId,omitempty"`
+	ReplyCount    int           `gorm:"not null;default:0" json:"replyCount"`      // Replies in the thread this message starts
+	LastReplyAt   *time.Time    `json:"lastReplyAt,omitempty"`
+	IsPrivate     bool          `gorm:"not null;default:false" json:"isPrivate"` // Only the sender and Recipients see it
 	AttachmentURL string        `gorm:"size:500" json:"attachmentUrl,omitempty"`
 	AttachmentType string       `gorm:"size:50" json:"attachmentType,omitempty"`
 	AttachmentName string       `gorm:"size:255" json:"attachmentName,omitempty"`
@@ -41,6 +45,11 @@ type ChatMessage struct {
 	EditedAt      *time.Time    `json:"editedAt,omitempty"`
 	IsDeleted     bool          `gorm:"default:false" json:"isDeleted"`
 	DeletedAt     *time.Time    `json:"deletedAt,omitempty"`
+	DeletedByID   *uuid.UUID    `gorm:"type:uuid" json:"deletedById,omitempty"` // Moderator who removed the message; it stays as a tombstone
+	IsPinned      bool          `gorm:"not null;default:false" json:"isPinned"`
+	PinnedAt      *time.Time    `json:"pinnedAt,omitempty"`
+	PinnedByID    *uuid.UUID    `gorm:"type:uuid" json:"pinnedById,omitempty"`
+	RequiresAck   bool          `gorm:"not null;default:false" json:"requiresAck"` // An announcement participants are asked to acknowledge
 	MessageStatus MessageStatus `gorm:"type:varchar(20);default:'sent'" json:"messageStatus"`
 	CreatedAt     time.Time     `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt     time.Time     `gorm:"autoUpdateTime" json:"updatedAt"`
@@ -51,8 +60,18 @@ type ChatMessage struct {
 	PublicUser    *PublicUser                `gorm:"foreignKey:PublicUserID" json:"publicUser,omitempty"`
 	ReplyTo       *ChatMessage               `gorm:"foreignKey:ReplyToID" json:"replyTo,omitempty"`
 	Replies       []ChatMessage              `gorm:"foreignKey:ReplyToID" json:"replies,omitempty"`
-	ReadStatus    []ChatMessageReadStatus    `gorm:"foreignKey:MessageID" json:"readStatus,omitempty"`
 	Reactions     []ChatMessageReaction      `gorm:"foreignKey:MessageID" json:"reactions,omitempty"`
+	Attachment    *ChatAttachment            `gorm:"foreignKey:MessageID" json:"attachment,omitempty"`
+	Recipients    []ChatMessageRecipient     `gorm:"foreignKey:MessageID" json:"recipients,omitempty"`
+	Acknowledgements []ChatMessageAcknowledgement `gorm:"foreignKey:MessageID" json:"acknowledgements,omitempty"`
+
+	// Readers whose read cursor has passed the message, the sender aside;
+	// filled in when the message is listed
+	SeenBy        int                        `gorm:"-" json:"seenBy"`
+
+	// Reactions aggregated per emoji for whoever the message is served to;
+	// filled in when the message is listed
+	ReactionSummaries []ChatReactionSummary  `gorm:"-" json:"reactionSummaries,omitempty"`
 }
 
 type ChatMessageResponse struct {
@@ -63,6 +82,9 @@ type ChatMessageResponse struct {
 	MessageType    MessageType               `json:"messageType"`
 	Content        string                    `json:"content"`
 	ReplyToID      *uuid.UUID                `json:"replyToId,omitempty"`
+	ReplyCount     int                       `json:"replyCount"`
+	LastReplyAt    *time.Time                `json:"lastReplyAt,omitempty"`
+	IsPrivate      bool                      `json:"isPrivate"`
 	AttachmentURL  string                    `json:"attachmentUrl,omitempty"`
 	AttachmentType string                    `json:"attachmentType,omitempty"`
 	AttachmentName string                    `json:"attachmentName,omitempty"`
@@ -70,32 +92,41 @@ type ChatMessageResponse struct {
 	EditedAt       *time.Time                `json:"editedAt,omitempty"`
 	IsDeleted      bool                      `json:"isDeleted"`
 	DeletedAt      *time.Time                `json:"deletedAt,omitempty"`
+	DeletedByID    *uuid.UUID                `json:"deletedById,omitempty"`
+	IsPinned       bool                      `json:"isPinned"`
+	PinnedAt       *time.Time                `json:"pinnedAt,omitempty"`
+	PinnedByID     *uuid.UUID                `json:"pinnedById,omitempty"`
+	RequiresAck    bool                      `json:"requiresAck"`
 	MessageStatus  MessageStatus             `json:"messageStatus"`
 	CreatedAt      time.Time                 `json:"createdAt"`
 	UpdatedAt      time.Time                 `json:"updatedAt"`
+	SeenBy         int                       `json:"seenBy"`
 
 	// Included relationships
 	User           *UserResponse             `json:"user,omitempty"`
 	PublicUser     *PublicUserResponse       `json:"publicUser,omitempty"`
 	ReplyTo        *ChatMessageResponse      `json:"replyTo,omitempty"`
 	Replies        []ChatMessageResponse     `json:"replies,omitempty"`
-	ReadStatus     []ChatMessageReadStatusResponse `json:"readStatus,omitempty"`
-	Reactions      []ChatMessageReactionResponse `json:"reactions,omitempty"`
+	Reactions      []ChatReactionSummary     `json:"reactions,omitempty"`
+	Attachment     *ChatAttachmentResponse   `json:"attachment,omitempty"`
+	Recipients     []ChatMessageRecipientResponse `json:"recipients,omitempty"`
+	Acknowledgements []ChatMessageAcknowledgementResponse `json:"acknowledgements,omitempty"`
 }
 
 type CreateChatMessageRequest struct {
 	MeetingID      uuid.UUID  `json:"meetingId" validate:"required"`
 	MessageType    MessageType `json:"messageType" validate:"required"`
-	Content        string     `json:"content" validate:"required,max=2000"`
+	Content        string     `json:"content" validate:"max=2000"` // May be empty when an attachment is sent
 	ReplyToID      *uuid.UUID `json:"replyToId,omitempty"`
-	AttachmentURL  string     `json:"attachmentUrl,omitempty"`
-	AttachmentType string     `json:"attachmentType,omitempty"`
-	AttachmentName string     `json:"attachmentName,omitempty"`
+	AttachmentID   *uuid.UUID `json:"attachmentId,omitempty"` // An attachment uploaded by the sender and not sent yet
+	RecipientIDs   []uuid.UUID `json:"recipientIds,omitempty"` // Participant IDs; makes the message private to them and the sender
+	RequiresAck    bool       `json:"requiresAck,omitempty"` // Asks participants to acknowledge an announcement; ignored for other message types
 }
 
 type UpdateChatMessageRequest struct {
 	Content        *string `json:"content,omitempty" validate:"omitempty,max=2000"`
 	IsDeleted      *bool   `json:"isDeleted,omitempty"`
+	Reason         string  `json:"reason,omitempty" validate:"max=500"` // Why a moderator removed someone else's message
 }
 
 type ChatMessageListResponse struct {
@@ -105,11 +136,13 @@ type ChatMessageListResponse struct {
 
 type GetChatMessagesResponse struct {
 	Messages   []ChatMessageResponse `json:"messages"`
-	Pagination PaginationInfo        `json:"pagination"`
+	Pagination ChatCursorPagination  `json:"pagination"`
 }
 
 type GetUnreadCountResponse struct {
-	UnreadCount int `json:"unreadCount"`
+	UnreadCount       int               `json:"unreadCount"`       // Unread messages of the main timeline
+	Threads           map[uuid.UUID]int `json:"threads,omitempty"` // Unread replies by thread, for threads that have any
+	LastReadMessageID *uuid.UUID        `json:"lastReadMessageId,omitempty"` // Where the reader's main timeline cursor is
 }
 
 func (m *ChatMessage) BeforeCreate(tx *gorm.DB) error {
@@ -128,6 +161,9 @@ func (m *ChatMessage) ToResponse() ChatMessageResponse {
 		MessageType:    m.MessageType,
 		Content:        m.Content,
 		ReplyToID:      m.ReplyToID,
+		ReplyCount:     m.ReplyCount,
+		LastReplyAt:    m.LastReplyAt,
+		IsPrivate:      m.IsPrivate,
 		AttachmentURL:  m.AttachmentURL,
 		AttachmentType: m.AttachmentType,
 		AttachmentName: m.AttachmentName,
@@ -135,9 +171,15 @@ func (m *ChatMessage) ToResponse() ChatMessageResponse {
 		EditedAt:       m.EditedAt,
 		IsDeleted:      m.IsDeleted,
 		DeletedAt:      m.DeletedAt,
+		DeletedByID:    m.DeletedByID,
+		IsPinned:       m.IsPinned,
+		PinnedAt:       m.PinnedAt,
+		PinnedByID:     m.PinnedByID,
+		RequiresAck:    m.RequiresAck,
 		MessageStatus:  m.MessageStatus,
 		CreatedAt:      m.CreatedAt,
 		UpdatedAt:      m.UpdatedAt,
+		SeenBy:         m.SeenBy,
 	}
 
 	// Include relationships
@@ -160,76 +202,32 @@ func (m *ChatMessage) ToResponse() ChatMessageResponse {
 		response.Replies = append(response.Replies, reply.ToResponse())
 	}
 
-	for _, status := range m.ReadStatus {
-		response.ReadStatus = append(response.ReadStatus, status.ToResponse())
-	}
-
-	for _, reaction := range m.Reactions {
-		response.Reactions = append(response.Reactions, reaction.ToResponse())
-	}
-
-	return response
-}
-
-type ChatMessageReadStatus struct {
-	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	MessageID    uuid.UUID  `gorm:"type:uuid;not null" json:"messageId"`
-	UserID       *uuid.UUID `gorm:"type:uuid;default:null" json:"userId,omitempty"`
-	PublicUserID *uuid.UUID `gorm:"type:uuid;default:null" json:"publicUserId,omitempty"`
-	ReadAt       time.Time  `gorm:"autoCreateTime" json:"readAt"`
-
-	// Relationships
-	Message    ChatMessage  `gorm:"foreignKey:MessageID" json:"message,omitempty"`
-	User       *User        `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	PublicUser *PublicUser  `gorm:"foreignKey:PublicUserID" json:"publicUser,omitempty"`
-}
-
-type ChatMessageReadStatusResponse struct {
-	ID           uuid.UUID  `json:"id"`
-	MessageID    uuid.UUID  `json:"messageId"`
-	UserID       *uuid.UUID `json:"userId,omitempty"`
-	PublicUserID *uuid.UUID `json:"publicUserId,omitempty"`
-	ReadAt       time.Time  `json:"readAt"`
-
-	User         *UserResponse       `json:"user,omitempty"`
-	PublicUser   *PublicUserResponse `json:"publicUser,omitempty"`
-}
-
-func (m *ChatMessageReadStatus) BeforeCreate(tx *gorm.DB) error {
-	if m.ID == uuid.Nil {
-		m.ID = uuid.New()
-	}
-	return nil
-}
+	response.Reactions = m.ReactionSummaries
 
-func (m *ChatMessageReadStatus) ToResponse() ChatMessageReadStatusResponse {
-	response := ChatMessageReadStatusResponse{
-		ID:           m.ID,
-		MessageID:    m.MessageID,
-		UserID:       m.UserID,
-		PublicUserID: m.PublicUserID,
-		ReadAt:       m.ReadAt,
+	if m.Attachment != nil {
+		attachmentResponse := m.Attachment.ToResponse()
+		response.Attachment = &attachmentResponse
+		// Stored messages keep no URL; it is signed whenever the message is served
+		response.AttachmentURL = m.Attachment.URL
 	}
 
-	if m.User != nil {
-		userResponse := m.User.ToResponse()
-		response.User = &userResponse
+	for _, recipient := range m.Recipients {
+		response.Recipients = append(response.Recipients, recipient.ToResponse())
 	}
 
-	if m.PublicUser != nil {
-		publicUserResponse := m.PublicUser.ToResponse()
-		response.PublicUser = &publicUserResponse
+	for _, acknowledgement := range m.Acknowledgements {
+		response.Acknowledgements = append(response.Acknowledgements, acknowledgement.ToResponse())
 	}
 
 	return response
 }
 
 type ChatMessageReaction struct {
-	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
 	MessageID    uuid.UUID  `gorm:"type:uuid;not null" json:"messageId"`
 	UserID       *uuid.UUID `gorm:"type:uuid;default:null" json:"userId,omitempty"`
 	PublicUserID *uuid.UUID `gorm:"type:uuid;default:null" json:"publicUserId,omitempty"`
-	Reaction     string     `gorm:"size:10;not null" json:"reaction"`
+	Reaction     string     `gorm:"size:64;not null" json:"reaction"`
 	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"createdAt"`
 
 	// Relationships
@@ -238,21 +236,9 @@ type ChatMessageReaction struct {
 	PublicUser *PublicUser  `gorm:"foreignKey:PublicUserID" json:"publicUser,omitempty"`
 }
 
-type ChatMessageReactionResponse struct {
-	ID           uuid.UUID  `json:"id"`
-	MessageID    uuid.UUID  `json:"messageId"`
-	UserID       *uuid.UUID `json:"userId,omitempty"`
-	PublicUserID *uuid.UUID `json:"publicUserId,omitempty"`
-	Reaction     string     `json:"reaction"`
-	CreatedAt    time.Time  `json:"createdAt"`
-
-	User         *UserResponse       `json:"user,omitempty"`
-	PublicUser   *PublicUserResponse `json:"publicUser,omitempty"`
-}
-
 type CreateChatMessageReactionRequest struct {
 	MessageID uuid.UUID `json:"messageId" validate:"required"`
-	Reaction  string    `json:"reaction" validate:"required,max=10"`
+	Reaction  string    `json:"reaction" validate:"required,max=64"` // A Unicode emoji or one of the deployment's custom emoji
 }
 
 func (r *ChatMessageReaction) BeforeCreate(tx *gorm.DB) error {
@@ -260,27 +246,4 @@ func (r *ChatMessageReaction) BeforeCreate(tx *gorm.DB) error {
 		r.ID = uuid.New()
 	}
 	return nil
-}
-
-func (r *ChatMessageReaction) ToResponse() ChatMessageReactionResponse {
-	response := ChatMessageReactionResponse{
-		ID:           r.ID,
-		MessageID:    r.MessageID,
-		UserID:       r.UserID,
-		PublicUserID: r.PublicUserID,
-		Reaction:     r.Reaction,
-		CreatedAt:    r.CreatedAt,
-	}
-
-	if r.User != nil {
-		userResponse := r.User.ToResponse()
-		response.User = &userResponse
-	}
-
-	if r.PublicUser != nil {
-		publicUserResponse := r.PublicUser.ToResponse()
-		response.PublicUser = &publicUserResponse
-	}
-
-	return response
 }
\ No newline at end of file
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChatMessageAcknowledgement records that a participant acknowledged an
+// announcement that asked for it
+type ChatMessageAcknowledgement struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	MessageID      uuid.UUID  `gorm:"type:uuid;not null" json:"messageId"`
+	UserID         *uuid.UUID `gorm:"type:uuid;default:null" json:"userId,omitempty"`
+	PublicUserID   *uuid.UUID `gorm:"type:uuid;default:null" json:"publicUserId,omitempty"`
+	AcknowledgedAt time.Time  `gorm:"autoCreateTime" json:"acknowledgedAt"`
+
+	// Relationships
+	Message    ChatMessage `gorm:"foreignKey:MessageID" json:"message,omitempty"`
+	User       *User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	PublicUser *PublicUser `gorm:"foreignKey:PublicUserID" json:"publicUser,omitempty"`
+}
+
+type ChatMessageAcknowledgementResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	MessageID      uuid.UUID  `json:"messageId"`
+	UserID         *uuid.UUID `json:"userId,omitempty"`
+	PublicUserID   *uuid.UUID `json:"publicUserId,omitempty"`
+	AcknowledgedAt time.Time  `json:"acknowledgedAt"`
+
+	User       *UserResponse       `json:"user,omitempty"`
+	PublicUser *PublicUserResponse `json:"publicUser,omitempty"`
+}
+
+// ChatAcknowledgementSummary tells the host who acknowledged an announcement
+// and which of the meeting's active participants have yet to
+type ChatAcknowledgementSummary struct {
+	MessageID    uuid.UUID                            `json:"messageId"`
+	Acknowledged []ChatMessageAcknowledgementResponse `json:"acknowledged"`
+	Pending      []ChatPendingAcknowledgement         `json:"pending"`
+}
+
+type ChatPendingAcknowledgement struct {
+	ParticipantID uuid.UUID  `json:"participantId"`
+	UserID        *uuid.UUID `json:"userId,omitempty"`
+	PublicUserID  *uuid.UUID `json:"publicUserId,omitempty"`
+	Name          string     `json:"name"`
+}
+
+// ChatAcknowledgedPayload tells hosts and co-hosts that a participant
+// acknowledged an announcement
+type ChatAcknowledgedPayload struct {
+	MessageID         uuid.UUID  `json:"messageId"`
+	UserID            *uuid.UUID `json:"userId,omitempty"`
+	PublicUserID      *uuid.UUID `json:"publicUserId,omitempty"`
+	AcknowledgedAt    time.Time  `json:"acknowledgedAt"`
+	AcknowledgedCount int        `json:"acknowledgedCount"`
+}
+
+func (a *ChatMessageAcknowledgement) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+func (a *ChatMessageAcknowledgement) ToResponse() ChatMessageAcknowledgementResponse {
+	response := ChatMessageAcknowledgementResponse{
+		ID:             a.ID,
+		MessageID:      a.MessageID,
+		UserID:         a.UserID,
+		PublicUserID:   a.PublicUserID,
+		AcknowledgedAt: a.AcknowledgedAt,
+	}
+
+	if a.User != nil {
+		userResponse := a.User.ToResponse()
+		response.User = &userResponse
+	}
+
+	if a.PublicUser != nil {
+		publicUserResponse := a.PublicUser.ToResponse()
+		response.PublicUser = &publicUserResponse
+	}
+
+	return response
+}
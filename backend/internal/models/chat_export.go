@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatExportFormat is the file format of a chat transcript
+type ChatExportFormat string
+
+const (
+	ChatExportJSON     ChatExportFormat = "json"
+	ChatExportMarkdown ChatExportFormat = "md"
+	ChatExportHTML     ChatExportFormat = "html" // A single page with its styles inlined
+	ChatExportCSV      ChatExportFormat = "csv"
+)
+
+// IsValid reports whether f is a known export format
+func (f ChatExportFormat) IsValid() bool {
+	switch f {
+	case ChatExportJSON, ChatExportMarkdown, ChatExportHTML, ChatExportCSV:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type transcripts in f are served with
+func (f ChatExportFormat) ContentType() string {
+	switch f {
+	case ChatExportMarkdown:
+		return "text/markdown; charset=utf-8"
+	case ChatExportHTML:
+		return "text/html; charset=utf-8"
+	case ChatExportCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// ChatTranscript is a meeting's chat export prepared for one reader: the
+// messages they can see, with times in their time zone
+type ChatTranscript struct {
+	MeetingID  uuid.UUID
+	Title      string
+	Format     ChatExportFormat
+	Location   *time.Location
+	Viewer     MeetingActor
+	ExportedAt time.Time
+}
+
+// ChatTranscriptEntry is a message as it appears in a transcript, with the
+// names of the people involved resolved
+type ChatTranscriptEntry struct {
+	ID            uuid.UUID                 `json:"id"`
+	SentAt        time.Time                 `json:"sentAt"`
+	Sender        string                    `json:"sender"`
+	MessageType   MessageType               `json:"messageType"`
+	Content       string                    `json:"content"`
+	ReplyToID     *uuid.UUID                `json:"replyToId,omitempty"`
+	ReplyToSender string                    `json:"replyToSender,omitempty"`
+	Recipients    []string                  `json:"recipients,omitempty"` // Set on private messages
+	EditedAt      *time.Time                `json:"editedAt,omitempty"`
+	Removed       bool                      `json:"removed,omitempty"` // A moderator removed the message
+	Reactions     []ChatTranscriptReaction  `json:"reactions,omitempty"`
+	Attachment    *ChatTranscriptAttachment `json:"attachment,omitempty"`
+}
+
+type ChatTranscriptReaction struct {
+	Reaction string `json:"reaction"`
+	Count    int    `json:"count"`
+}
+
+type ChatTranscriptAttachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	URL         string `json:"url"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultChatPageSize = 50
+	MaxChatPageSize     = 100
+)
+
+// ChatCursor is a position in a meeting's chat history. Messages are ordered
+// by creation time, with the ID breaking ties between messages created in
+// the same instant.
+type ChatCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorOf returns the cursor positioned at message
+func CursorOf(message *ChatMessage) ChatCursor {
+	return ChatCursor{CreatedAt: message.CreatedAt, ID: message.ID}
+}
+
+// Encode returns the opaque form handed to clients
+func (c ChatCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.Format(time.RFC3339Nano) + "|" + c.ID.String()))
+}
+
+// DecodeChatCursor parses a cursor produced by ChatCursor.Encode
+func DecodeChatCursor(s string) (*ChatCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return nil, errors.New("invalid cursor")
+	}
+	cursor := &ChatCursor{}
+	// The offset is kept so the cursor compares equal to the stored timestamp
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	if cursor.ID, err = uuid.Parse(id); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// ChatHistoryDirection is which way a history page extends from its cursor
+type ChatHistoryDirection string
+
+const (
+	ChatHistoryOlder ChatHistoryDirection = "older"
+	ChatHistoryNewer ChatHistoryDirection = "newer"
+)
+
+// ChatHistoryQuery selects a page of chat history. Without a cursor, older
+// pages start from the latest message and newer pages from the first one.
+// Around centres the page on a message instead, for jumping to it. Private
+// messages are only included when Viewer sent or received them.
+type ChatHistoryQuery struct {
+	Cursor    *ChatCursor
+	Direction ChatHistoryDirection
+	Around    *uuid.UUID
+	Limit     int
+	Viewer    MeetingActor
+}
+
+// ChatCursorPagination describes where a page of messages sits in the
+// history. The cursors point at the oldest and newest message of the page
+// and are passed back to fetch the adjacent pages.
+type ChatCursorPagination struct {
+	Limit       int    `json:"limit"`
+	OlderCursor string `json:"olderCursor,omitempty"`
+	NewerCursor string `json:"newerCursor,omitempty"`
+	HasOlder    bool   `json:"hasOlder"`
+	HasNewer    bool   `json:"hasNewer"`
+}
+
+// ChatSearchQuery is a full-text search over a meeting's chat, newest match
+// first, optionally narrowed to one sender and to some message types. Like
+// the history, it only matches private messages Viewer can see.
+type ChatSearchQuery struct {
+	Query              string
+	SenderUserID       *uuid.UUID
+	SenderPublicUserID *uuid.UUID
+	MessageTypes       []MessageType
+	Cursor             *ChatCursor
+	Limit              int
+	Viewer             MeetingActor
+}
+
+// ChatSearchResult is a matching message with an excerpt of its content.
+// The snippet is HTML-escaped text in which the matched terms are wrapped in
+// <mark> elements.
+type ChatSearchResult struct {
+	Message ChatMessageResponse `json:"message"`
+	Snippet string              `json:"snippet"`
+	Rank    float64             `json:"rank"`
+}
+
+type SearchChatMessagesResponse struct {
+	Results    []ChatSearchResult   `json:"results"`
+	Pagination ChatCursorPagination `json:"pagination"`
+}
+
+// IsValid reports whether t is a known message type
+func (t MessageType) IsValid() bool {
+	switch t {
+	case MessageTypeText, MessageTypeImage, MessageTypeFile, MessageTypeSystem, MessageTypeReaction:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChatFilterAction is what the content filter does with a message matching
+// one of its rules
+type ChatFilterAction string
+
+const (
+	ChatFilterBlock ChatFilterAction = "block" // Refuse the message
+	ChatFilterMask  ChatFilterAction = "mask"  // Replace the matched text with asterisks
+	ChatFilterFlag  ChatFilterAction = "flag"  // Send it, and queue it for a moderator to review
+)
+
+// IsValid reports whether a is a known filter action
+func (a ChatFilterAction) IsValid() bool {
+	switch a {
+	case ChatFilterBlock, ChatFilterMask, ChatFilterFlag:
+		return true
+	}
+	return false
+}
+
+// ChatFilterRule matches either any of Words, as whole words and ignoring
+// case, or the regular expression Pattern
+type ChatFilterRule struct {
+	Name    string           `json:"name"`
+	Words   []string         `json:"words,omitempty"`
+	Pattern string           `json:"pattern,omitempty"`
+	Action  ChatFilterAction `json:"action"`
+}
+
+// ChatContentFilter checks the text of chat messages before they are stored
+type ChatContentFilter interface {
+	Check(content string) ChatFilterResult
+}
+
+// ChatFilterResult is the content filter's verdict on a message
+type ChatFilterResult struct {
+	Content string   // The text to store, masked where rules asked for it
+	Blocked bool     // The message must not be sent
+	Flags   []string // Names of the flagging rules that matched
+}
+
+// ChatFlagStatus tracks a flagged message through review
+type ChatFlagStatus string
+
+const (
+	ChatFlagPending   ChatFlagStatus = "pending"
+	ChatFlagDismissed ChatFlagStatus = "dismissed" // A moderator found the message acceptable
+	ChatFlagRemoved   ChatFlagStatus = "removed"   // The message was deleted
+)
+
+// ChatMessageFlag queues a message the content filter flagged for a
+// moderator's review
+type ChatMessageFlag struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	MeetingID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"meetingId"`
+	MessageID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"messageId"`
+	Rule         string         `gorm:"size:100;not null" json:"rule"`
+	Status       ChatFlagStatus `gorm:"size:20;not null;default:pending" json:"status"`
+	ReviewedByID *uuid.UUID     `gorm:"type:uuid" json:"reviewedById,omitempty"`
+	ReviewedAt   *time.Time     `json:"reviewedAt,omitempty"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relationships
+	Message ChatMessage `gorm:"foreignKey:MessageID" json:"-"`
+}
+
+type ChatMessageFlagResponse struct {
+	ID           uuid.UUID           `json:"id"`
+	MessageID    uuid.UUID           `json:"messageId"`
+	Rule         string              `json:"rule"`
+	Status       ChatFlagStatus      `json:"status"`
+	ReviewedByID *uuid.UUID          `json:"reviewedById,omitempty"`
+	ReviewedAt   *time.Time          `json:"reviewedAt,omitempty"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	Message      ChatMessageResponse `json:"message"`
+}
+
+// ReviewChatFlagRequest settles a flag: dismiss keeps the message, remove
+// deletes it like a moderator would
+type ReviewChatFlagRequest struct {
+	Action string `json:"action" validate:"required,oneof=dismiss remove"`
+	Reason string `json:"reason,omitempty" validate:"max=500"`
+}
+
+func (f *ChatMessageFlag) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	if f.Status == "" {
+		f.Status = ChatFlagPending
+	}
+	return nil
+}
+
+func (f *ChatMessageFlag) ToResponse() ChatMessageFlagResponse {
+	return ChatMessageFlagResponse{
+		ID:           f.ID,
+		MessageID:    f.MessageID,
+		Rule:         f.Rule,
+		Status:       f.Status,
+		ReviewedByID: f.ReviewedByID,
+		ReviewedAt:   f.ReviewedAt,
+		CreatedAt:    f.CreatedAt,
+		Message:      f.Message.ToResponse(),
+	}
+}
+
+// ChatMessageFlaggedPayload tells hosts and co-hosts that a message joined
+// the review queue
+type ChatMessageFlaggedPayload struct {
+	MessageID uuid.UUID `json:"messageId"`
+	Rules     []string  `json:"rules"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxPinnedMessages caps the messages a meeting keeps pinned at once, so
+// the pinned bar stays short
+const MaxPinnedMessages = 5
+
+// ChatPinPayload is broadcast when a message is pinned or unpinned. A pin
+// carries the message so clients can show it without fetching it.
+type ChatPinPayload struct {
+	MessageID  uuid.UUID            `json:"messageId"`
+	PinnedByID *uuid.UUID           `json:"pinnedById,omitempty"`
+	PinnedAt   *time.Time           `json:"pinnedAt,omitempty"`
+	Message    *ChatMessageResponse `json:"message,omitempty"`
+}
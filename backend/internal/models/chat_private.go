@@ -0,0 +1,50 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxChatRecipients caps the audience of a private message; larger groups
+// belong in the meeting-wide chat
+const MaxChatRecipients = 8
+
+// ChatMessageRecipient is a participant a private message is addressed to.
+// The user IDs are copied from the participant so visibility checks need no
+// join.
+type ChatMessageRecipient struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	MessageID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"messageId"`
+	ParticipantID uuid.UUID  `gorm:"type:uuid;not null" json:"participantId"`
+	UserID        *uuid.UUID `gorm:"type:uuid;index" json:"userId,omitempty"`
+	PublicUserID  *uuid.UUID `gorm:"type:uuid;index" json:"publicUserId,omitempty"`
+	Name          string     `gorm:"size:255" json:"name"`
+}
+
+type ChatMessageRecipientResponse struct {
+	ParticipantID uuid.UUID  `json:"participantId"`
+	UserID        *uuid.UUID `json:"userId,omitempty"`
+	PublicUserID  *uuid.UUID `json:"publicUserId,omitempty"`
+	Name          string     `json:"name"`
+}
+
+// Actor returns the user the recipient stands for
+func (r *ChatMessageRecipient) Actor() MeetingActor {
+	return MeetingActor{UserID: r.UserID, PublicUserID: r.PublicUserID}
+}
+
+func (r *ChatMessageRecipient) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *ChatMessageRecipient) ToResponse() ChatMessageRecipientResponse {
+	return ChatMessageRecipientResponse{
+		ParticipantID: r.ParticipantID,
+		UserID:        r.UserID,
+		PublicUserID:  r.PublicUserID,
+		Name:          r.Name,
+	}
+}
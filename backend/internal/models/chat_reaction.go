@@ -0,0 +1,33 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxDistinctReactions caps the different emoji a message can collect;
+	// reacting with one already on the message always works
+	MaxDistinctReactions = 20
+
+	// MaxReactionSampleUsers is how many reactors a reaction summary names
+	MaxReactionSampleUsers = 3
+
+	// MaxReactionLength bounds a reaction in bytes. Long ZWJ sequences, such
+	// as couples with skin tones, take up to 35.
+	MaxReactionLength = 64
+)
+
+// ChatReactionSummary aggregates the reactions to a message with one emoji
+type ChatReactionSummary struct {
+	Emoji       string             `json:"emoji"`
+	Count       int                `json:"count"`
+	ReactedByMe bool               `json:"reactedByMe"`
+	SampleUsers []ChatReactionUser `json:"sampleUsers"` // The first reactors, up to MaxReactionSampleUsers
+}
+
+// ChatReactionUser names someone who reacted to a message
+type ChatReactionUser struct {
+	UserID       *uuid.UUID `json:"userId,omitempty"`
+	PublicUserID *uuid.UUID `json:"publicUserId,omitempty"`
+	Name         string     `json:"name"`
+}
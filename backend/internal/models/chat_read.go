@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChatReadCursor is how far a reader has read a meeting's chat: everything
+// up to and including the last read message counts as read. Each reader has
+// one cursor for the main timeline (ThreadID nil) and one per thread they
+// read, as replies are read apart from the timeline.
+type ChatReadCursor struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	MeetingID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"meetingId"`
+	ThreadID          *uuid.UUID `gorm:"type:uuid;default:null" json:"threadId,omitempty"`
+	UserID            *uuid.UUID `gorm:"type:uuid;default:null" json:"userId,omitempty"`
+	PublicUserID      *uuid.UUID `gorm:"type:uuid;default:null" json:"publicUserId,omitempty"`
+	LastReadMessageID uuid.UUID  `gorm:"type:uuid;not null" json:"lastReadMessageId"`
+	LastReadAt        time.Time  `gorm:"not null" json:"lastReadAt"` // Creation time of the last read message
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// MarkReadUpToRequest marks a message and everything before it, in its
+// timeline or thread, as read
+type MarkReadUpToRequest struct {
+	MessageID uuid.UUID `json:"messageId" validate:"required"`
+}
+
+// ChatReadCursorPayload is broadcast when a reader's cursor moves, so
+// clients can update "seen by" counts
+type ChatReadCursorPayload struct {
+	ThreadID          *uuid.UUID `json:"threadId,omitempty"`
+	UserID            *uuid.UUID `json:"userId,omitempty"`
+	PublicUserID      *uuid.UUID `json:"publicUserId,omitempty"`
+	LastReadMessageID uuid.UUID  `json:"lastReadMessageId"`
+	LastReadAt        time.Time  `json:"lastReadAt"`
+}
+
+func (c *ChatReadCursor) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// Position returns where the cursor sits in the chat history
+func (c *ChatReadCursor) Position() ChatCursor {
+	return ChatCursor{CreatedAt: c.LastReadAt, ID: c.LastReadMessageID}
+}
+
+// Actor returns the reader the cursor belongs to
+func (c *ChatReadCursor) Actor() MeetingActor {
+	return MeetingActor{UserID: c.UserID, PublicUserID: c.PublicUserID}
+}
+
+// Covers reports whether message sits at or before the cursor, in the
+// order chat history is paged in
+func (c ChatCursor) Covers(message *ChatMessage) bool {
+	if !message.CreatedAt.Equal(c.CreatedAt) {
+		return message.CreatedAt.Before(c.CreatedAt)
+	}
+	return message.ID.String() <= c.ID.String()
+}
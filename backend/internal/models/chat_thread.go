@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatThread is a page of the replies to a message. Replies stay out of the
+// main timeline, where the message that started the thread shows their
+// count instead.
+type ChatThread struct {
+	Root        ChatMessage
+	Replies     []ChatMessage
+	UnreadCount int // Replies in the whole thread the reader has not read
+	Pagination  ChatCursorPagination
+}
+
+type GetChatThreadResponse struct {
+	Root        ChatMessageResponse   `json:"root"`
+	Replies     []ChatMessageResponse `json:"replies"`
+	UnreadCount int                   `json:"unreadCount"`
+	Pagination  ChatCursorPagination  `json:"pagination"`
+}
+
+func (t *ChatThread) ToResponse() GetChatThreadResponse {
+	replies := make([]ChatMessageResponse, 0, len(t.Replies))
+	for _, reply := range t.Replies {
+		replies = append(replies, reply.ToResponse())
+	}
+	return GetChatThreadResponse{
+		Root:        t.Root.ToResponse(),
+		Replies:     replies,
+		UnreadCount: t.UnreadCount,
+		Pagination:  t.Pagination,
+	}
+}
+
+// ChatThreadUpdatedPayload is broadcast when a reply is posted to or deleted
+// from a thread. The sender lets clients tell whether the reply counts
+// towards their own unread badge.
+type ChatThreadUpdatedPayload struct {
+	ThreadID          uuid.UUID  `json:"threadId"`
+	ReplyCount        int        `json:"replyCount"`
+	LastReplyAt       *time.Time `json:"lastReplyAt,omitempty"`
+	ReplyID           uuid.UUID  `json:"replyId"`
+	ReplyUserID       *uuid.UUID `json:"replyUserId,omitempty"`
+	ReplyPublicUserID *uuid.UUID `json:"replyPublicUserId,omitempty"`
+	Deleted           bool       `json:"deleted"`
+}
@@ -0,0 +1,216 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrHubClientNotFound is returned when a directed message targets a client
+// that is not connected to any node of the cluster
+var ErrHubClientNotFound = errors.New("client not found")
+
+// HubEnvelope wraps a signaling message relayed between hub nodes
+type HubEnvelope struct {
+	NodeID          string           `json:"nodeId"` // Node that published the envelope
+	Message         SignalingMessage `json:"message"`
+	TargetClientID  string           `json:"targetClientId,omitempty"`  // Set for directed messages
+	ExcludeClientID string           `json:"excludeClientId,omitempty"` // Set for meeting fan-out
+}
+
+// HubPresence describes a WebSocket client connected to some node of the cluster
+type HubPresence struct {
+	ClientID     string          `json:"clientId"`
+	MeetingID    string          `json:"meetingId"`
+	NodeID       string          `json:"nodeId"`
+	UserID       *uuid.UUID      `json:"userId,omitempty"`
+	PublicUserID *uuid.UUID      `json:"publicUserId,omitempty"`
+	Name         string          `json:"name"`
+	IsAuth       bool            `json:"isAuth"`
+	Role         ParticipantRole `json:"role,omitempty"`
+	JoinedAt     time.Time       `json:"joinedAt"`
+
+	// The client's WebRTC peer connection; every client is a peer
+	PeerState PeerConnectionState `json:"peerState,omitempty"`
+	Codecs    map[string]string   `json:"codecs,omitempty"` // Media kind -> negotiated codec
+	LastSeen  time.Time           `json:"lastSeen"`
+}
+
+// Matches reports whether the presence entry belongs to the actor
+func (p HubPresence) Matches(actor MeetingActor) bool {
+	if actor.UserID != nil && p.UserID != nil && *actor.UserID == *p.UserID {
+		return true
+	}
+	return actor.PublicUserID != nil && p.PublicUserID != nil && *actor.PublicUserID == *p.PublicUserID
+}
+
+// HubBroker relays hub traffic and presence between backend nodes.
+// A hub without a broker runs in single-node, in-memory mode.
+type HubBroker interface {
+	// NodeID returns the identifier of the local node
+	NodeID() string
+	// PublishMeeting fans an envelope out to every other node
+	PublishMeeting(envelope HubEnvelope) error
+	// PublishClient delivers an envelope to the node hosting clientID
+	PublishClient(clientID string, envelope HubEnvelope) error
+	// Subscribe starts delivering envelopes published by other nodes
+	Subscribe(deliver func(HubEnvelope)) error
+	// AddPresence registers a client connected to the local node
+	AddPresence(presence HubPresence) error
+	// RemovePresence removes a client registered by the local node
+	RemovePresence(meetingID, clientID string) error
+	// MeetingPresence returns every client in a meeting across the cluster
+	MeetingPresence(meetingID string) ([]HubPresence, error)
+	// Close stops the subscription and releases node resources
+	Close() error
+}
+
+// MemoryHubBroker connects several hubs living in the same process.
+// It is mainly useful for tests that simulate a multi-node deployment.
+type MemoryHubBroker struct {
+	nodeID  string
+	network *MemoryHubNetwork
+}
+
+// MemoryHubNetwork is the shared state behind a set of MemoryHubBrokers
+type MemoryHubNetwork struct {
+	mu       sync.RWMutex
+	nodes    map[string]func(HubEnvelope)
+	presence map[string]map[string]HubPresence // meetingID -> clientID -> presence
+}
+
+// NewMemoryHubNetwork creates an empty in-process hub network
+func NewMemoryHubNetwork() *MemoryHubNetwork {
+	return &MemoryHubNetwork{
+		nodes:    make(map[string]func(HubEnvelope)),
+		presence: make(map[string]map[string]HubPresence),
+	}
+}
+
+// NewBroker returns a broker attached to the network for the given node
+func (n *MemoryHubNetwork) NewBroker(nodeID string) *MemoryHubBroker {
+	return &MemoryHubBroker{
+		nodeID:  nodeID,
+		network: n,
+	}
+}
+
+// NodeID returns the identifier of the local node
+func (b *MemoryHubBroker) NodeID() string {
+	return b.nodeID
+}
+
+// PublishMeeting fans an envelope out to every other node
+func (b *MemoryHubBroker) PublishMeeting(envelope HubEnvelope) error {
+	b.network.mu.RLock()
+	var targets []func(HubEnvelope)
+	for nodeID, deliver := range b.network.nodes {
+		if nodeID != b.nodeID {
+			targets = append(targets, deliver)
+		}
+	}
+	b.network.mu.RUnlock()
+
+	for _, deliver := range targets {
+		deliver(envelope)
+	}
+	return nil
+}
+
+// PublishClient delivers an envelope to the node hosting clientID
+func (b *MemoryHubBroker) PublishClient(clientID string, envelope HubEnvelope) error {
+	b.network.mu.RLock()
+	var deliver func(HubEnvelope)
+	for _, meetingClients := range b.network.presence {
+		if presence, ok := meetingClients[clientID]; ok {
+			deliver = b.network.nodes[presence.NodeID]
+			break
+		}
+	}
+	b.network.mu.RUnlock()
+
+	if deliver == nil {
+		return ErrHubClientNotFound
+	}
+	envelope.TargetClientID = clientID
+	deliver(envelope)
+	return nil
+}
+
+// Subscribe starts delivering envelopes published by other nodes
+func (b *MemoryHubBroker) Subscribe(deliver func(HubEnvelope)) error {
+	b.network.mu.Lock()
+	defer b.network.mu.Unlock()
+	b.network.nodes[b.nodeID] = deliver
+	return nil
+}
+
+// AddPresence registers a client connected to the local node
+func (b *MemoryHubBroker) AddPresence(presence HubPresence) error {
+	b.network.mu.Lock()
+	defer b.network.mu.Unlock()
+
+	presence.NodeID = b.nodeID
+	if b.network.presence[presence.MeetingID] == nil {
+		b.network.presence[presence.MeetingID] = make(map[string]HubPresence)
+	}
+	b.network.presence[presence.MeetingID][presence.ClientID] = presence
+	return nil
+}
+
+// RemovePresence removes a client registered by the local node
+func (b *MemoryHubBroker) RemovePresence(meetingID, clientID string) error {
+	b.network.mu.Lock()
+	defer b.network.mu.Unlock()
+
+	meetingClients, ok := b.network.presence[meetingID]
+	if !ok {
+		return nil
+	}
+	// Only drop the entry if it still belongs to this node; the client may
+	// already have reconnected elsewhere
+	if presence, ok := meetingClients[clientID]; ok && presence.NodeID == b.nodeID {
+		delete(meetingClients, clientID)
+	}
+	if len(meetingClients) == 0 {
+		delete(b.network.presence, meetingID)
+	}
+	return nil
+}
+
+// MeetingPresence returns every client in a meeting across the network
+func (b *MemoryHubBroker) MeetingPresence(meetingID string) ([]HubPresence, error) {
+	b.network.mu.RLock()
+	defer b.network.mu.RUnlock()
+
+	var presences []HubPresence
+	for _, presence := range b.network.presence[meetingID] {
+		presences = append(presences, presence)
+	}
+	sort.Slice(presences, func(i, j int) bool {
+		return presences[i].JoinedAt.Before(presences[j].JoinedAt)
+	})
+	return presences, nil
+}
+
+// Close detaches the node from the network and drops its presence entries
+func (b *MemoryHubBroker) Close() error {
+	b.network.mu.Lock()
+	defer b.network.mu.Unlock()
+
+	delete(b.network.nodes, b.nodeID)
+	for meetingID, meetingClients := range b.network.presence {
+		for clientID, presence := range meetingClients {
+			if presence.NodeID == b.nodeID {
+				delete(meetingClients, clientID)
+			}
+		}
+		if len(meetingClients) == 0 {
+			delete(b.network.presence, meetingID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClusterHub(t *testing.T, network *MemoryHubNetwork, nodeID string) *WebSocketHub {
+	hub := NewWebSocketHub()
+	require.NoError(t, hub.SetBroker(network.NewBroker(nodeID)))
+	return hub
+}
+
+func newHubClient(hub *WebSocketHub, id, meetingID string) *WebSocketClient {
+	return &WebSocketClient{
+		ID:        id,
+		MeetingID: meetingID,
+		Name:      id,
+		Send:      make(chan SignalingMessage, 16),
+		Hub:       hub,
+		JoinedAt:  time.Now(),
+	}
+}
+
+// drain returns the messages currently queued for a client
+func drain(client *WebSocketClient) []SignalingMessage {
+	var messages []SignalingMessage
+	for {
+		select {
+		case message := <-client.Send:
+			messages = append(messages, message)
+		default:
+			return messages
+		}
+	}
+}
+
+func TestWebSocketHub_ClusterBroadcast(t *testing.T) {
+	network := NewMemoryHubNetwork()
+	hubA := newClusterHub(t, network, "node-a")
+	hubB := newClusterHub(t, network, "node-b")
+
+	alice := newHubClient(hubA, "alice", "meeting-1")
+	bob := newHubClient(hubB, "bob", "meeting-1")
+	carol := newHubClient(hubB, "carol", "meeting-2")
+	hubA.registerClient(alice)
+	hubB.registerClient(bob)
+	hubB.registerClient(carol)
+
+	// Alice hears about Bob joining from the other node
+	joined := drain(alice)
+	require.Len(t, joined, 1)
+	assert.Equal(t, SignalingTypeParticipantJoined, joined[0].Type)
+	assert.Equal(t, "bob", joined[0].From)
+	drain(bob)
+	drain(carol)
+
+	hubA.BroadcastToMeeting("meeting-1", SignalingMessage{
+		Type:      SignalingTypeChatMessage,
+		MeetingID: "meeting-1",
+		From:      "alice",
+	}, "alice")
+
+	assert.Empty(t, drain(alice), "sender must be excluded")
+	received := drain(bob)
+	require.Len(t, received, 1)
+	assert.Equal(t, SignalingTypeChatMessage, received[0].Type)
+	assert.Empty(t, drain(carol), "other meetings must not receive the message")
+}
+
+func TestWebSocketHub_ClusterDirectedMessage(t *testing.T) {
+	network := NewMemoryHubNetwork()
+	hubA := newClusterHub(t, network, "node-a")
+	hubB := newClusterHub(t, network, "node-b")
+
+	alice := newHubClient(hubA, "alice", "meeting-1")
+	bob := newHubClient(hubB, "bob", "meeting-1")
+	hubA.registerClient(alice)
+	hubB.registerClient(bob)
+	drain(alice)
+	drain(bob)
+
+	err := hubA.SendToClient("bob", SignalingMessage{
+		Type:      SignalingTypeOffer,
+		MeetingID: "meeting-1",
+		From:      "alice",
+		To:        "bob",
+	})
+	require.NoError(t, err)
+
+	received := drain(bob)
+	require.Len(t, received, 1)
+	assert.Equal(t, SignalingTypeOffer, received[0].Type)
+	assert.Empty(t, drain(alice))
+
+	err = hubA.SendToClient("nobody", SignalingMessage{Type: SignalingTypeOffer, MeetingID: "meeting-1"})
+	assert.ErrorIs(t, err, ErrHubClientNotFound)
+}
+
+func TestWebSocketHub_ClusterPresence(t *testing.T) {
+	network := NewMemoryHubNetwork()
+	hubA := newClusterHub(t, network, "node-a")
+	hubB := newClusterHub(t, network, "node-b")
+
+	alice := newHubClient(hubA, "alice", "meeting-1")
+	bob := newHubClient(hubB, "bob", "meeting-1")
+	bob.JoinedAt = alice.JoinedAt.Add(time.Second)
+	hubA.registerClient(alice)
+	hubB.registerClient(bob)
+
+	participants := hubA.GetMeetingParticipants("meeting-1")
+	require.Len(t, participants, 2)
+	assert.Equal(t, "alice", participants[0].ClientID)
+	assert.Equal(t, "node-a", participants[0].NodeID)
+	assert.Equal(t, "bob", participants[1].ClientID)
+	assert.Equal(t, "node-b", participants[1].NodeID)
+	assert.Equal(t, 2, hubB.GetParticipantCount("meeting-1"))
+
+	hubB.unregisterClient(bob)
+	assert.Equal(t, 1, hubA.GetParticipantCount("meeting-1"))
+
+	left := drain(alice)
+	require.NotEmpty(t, left)
+	assert.Equal(t, SignalingTypeParticipantLeft, left[len(left)-1].Type)
+}
+
+func TestWebSocketHub_SingleNode(t *testing.T) {
+	hub := NewWebSocketHub()
+
+	alice := newHubClient(hub, "alice", "meeting-1")
+	hub.registerClient(alice)
+
+	assert.Equal(t, 1, hub.GetParticipantCount("meeting-1"))
+	assert.True(t, hub.IsMeetingActive("meeting-1"))
+	assert.ErrorIs(t, hub.SendToClient("bob", SignalingMessage{}), ErrHubClientNotFound)
+
+	// A stale instance must not evict a newer connection with the same ID
+	replacement := newHubClient(hub, "alice", "meeting-1")
+	hub.registerClient(replacement)
+	hub.unregisterClient(alice)
+	assert.Equal(t, 1, hub.GetParticipantCount("meeting-1"))
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"sync"
+	"time"
+)
+
+// HubEventLog keeps each meeting's recent events, numbered in order, so that
+// clients that lost their connection can catch up on what they missed.
+//
+// Sequence numbers start from the time a meeting's log is created, in
+// microseconds, rather than from 1; a log that was lost with a restart or
+// expired keeps numbering above the events clients saw from the old one.
+type HubEventLog interface {
+	// Append numbers an event with its meeting's next sequence number and
+	// stores it
+	Append(envelope HubEnvelope) (uint64, error)
+	// Since returns a meeting's events numbered after seq, oldest first.
+	// complete is false when some of them are no longer kept.
+	Since(meetingID string, seq uint64) (events []HubEnvelope, complete bool, err error)
+	// LastSeq returns the number of a meeting's latest event
+	LastSeq(meetingID string) (uint64, error)
+}
+
+// IsReplayableSignalingType reports whether a message type is chat or
+// roster state that clients catch up on when they reconnect. Media
+// signaling, typing and directed messages are not kept.
+func IsReplayableSignalingType(messageType SignalingMessageType) bool {
+	switch messageType {
+	case SignalingTypeParticipantJoined, SignalingTypeParticipantLeft, SignalingTypeParticipantRoleChanged, SignalingTypeParticipantUpdated,
+		SignalingTypeMeetingStarted, SignalingTypeMeetingEnded, SignalingTypeMeetingLocked, SignalingTypeMeetingUnlocked,
+		SignalingTypeScreenShareStart, SignalingTypeScreenShareStop,
+		SignalingTypeChatMessage, SignalingTypeChatMessageEdit, SignalingTypeChatMessageDelete,
+		SignalingTypeChatReaction, SignalingTypeChatThreadUpdated, SignalingTypeChatSlowMode,
+		SignalingTypeChatPin, SignalingTypeChatUnpin, SignalingTypeChatReadCursor:
+		return true
+	}
+	return false
+}
+
+// MemoryHubEventLog is a HubEventLog for a single node. Each meeting keeps
+// its latest size events; meetings without events for longer than retention
+// are forgotten.
+type MemoryHubEventLog struct {
+	mu        sync.Mutex
+	size      int
+	retention time.Duration
+	meetings  map[string]*meetingEventLog
+	swept     time.Time
+}
+
+type meetingEventLog struct {
+	start   uint64 // Sequence number before the first event
+	seq     uint64
+	events  []HubEnvelope // The latest events; events[i] is numbered seq-len(events)+1+i
+	updated time.Time
+}
+
+func NewMemoryHubEventLog(size int, retention time.Duration) *MemoryHubEventLog {
+	return &MemoryHubEventLog{
+		size:      size,
+		retention: retention,
+		meetings:  make(map[string]*meetingEventLog),
+		swept:     time.Now(),
+	}
+}
+
+// Append numbers an event with its meeting's next sequence number and
+// stores it
+func (l *MemoryHubEventLog) Append(envelope HubEnvelope) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweepLocked(now)
+
+	meetingID := envelope.Message.MeetingID
+	log, ok := l.meetings[meetingID]
+	if !ok {
+		start := uint64(now.UnixMicro())
+		log = &meetingEventLog{start: start, seq: start}
+		l.meetings[meetingID] = log
+	}
+
+	log.seq++
+	envelope.Message.Seq = log.seq
+	log.events = append(log.events, envelope)
+	if len(log.events) > l.size {
+		log.events = append(log.events[:0:0], log.events[len(log.events)-l.size:]...)
+	}
+	log.updated = now
+	return log.seq, nil
+}
+
+// Since returns a meeting's events numbered after seq, oldest first
+func (l *MemoryHubEventLog) Since(meetingID string, seq uint64) ([]HubEnvelope, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	log, ok := l.meetings[meetingID]
+	if !ok {
+		return nil, false, nil
+	}
+	if seq > log.seq || seq < log.start {
+		return nil, false, nil
+	}
+	oldest := log.seq - uint64(len(log.events)) + 1
+	if seq+1 < oldest {
+		return nil, false, nil
+	}
+	events := make([]HubEnvelope, log.seq-seq)
+	copy(events, log.events[seq+1-oldest:])
+	return events, true, nil
+}
+
+// LastSeq returns the number of a meeting's latest event
+func (l *MemoryHubEventLog) LastSeq(meetingID string) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if log, ok := l.meetings[meetingID]; ok {
+		return log.seq, nil
+	}
+	return 0, nil
+}
+
+// sweepLocked forgets the meetings whose last event is older than the
+// retention, at most once per retention period. The caller must hold l.mu.
+func (l *MemoryHubEventLog) sweepLocked(now time.Time) {
+	if now.Sub(l.swept) < l.retention {
+		return
+	}
+	for meetingID, log := range l.meetings {
+		if now.Sub(log.updated) > l.retention {
+			delete(l.meetings, meetingID)
+		}
+	}
+	l.swept = now
+}
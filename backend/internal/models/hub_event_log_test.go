@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryHubEventLog(t *testing.T) {
+	events := NewMemoryHubEventLog(3, time.Minute)
+	appendEvent := func(meetingID string) uint64 {
+		seq, err := events.Append(HubEnvelope{Message: SignalingMessage{Type: SignalingTypeChatMessage, MeetingID: meetingID}})
+		require.NoError(t, err)
+		return seq
+	}
+
+	first := appendEvent("meeting-1")
+	assert.Equal(t, first+1, appendEvent("meeting-1"))
+	other := appendEvent("meeting-2")
+	assert.Equal(t, first+2, appendEvent("meeting-1"))
+	last, err := events.LastSeq("meeting-2")
+	require.NoError(t, err)
+	assert.Equal(t, other, last)
+
+	missed, complete, err := events.Since("meeting-1", first)
+	require.NoError(t, err)
+	require.True(t, complete)
+	require.Len(t, missed, 2)
+	assert.Equal(t, first+1, missed[0].Message.Seq)
+	assert.Equal(t, first+2, missed[1].Message.Seq)
+
+	missed, complete, _ = events.Since("meeting-1", first+2)
+	assert.True(t, complete)
+	assert.Empty(t, missed)
+
+	// Evicted events, unknown meetings and sequence numbers from another
+	// log cannot be caught up on
+	appendEvent("meeting-1")
+	appendEvent("meeting-1")
+	_, complete, _ = events.Since("meeting-1", first)
+	assert.False(t, complete)
+	missed, complete, _ = events.Since("meeting-1", first+1)
+	assert.True(t, complete)
+	assert.Len(t, missed, 3)
+	_, complete, _ = events.Since("meeting-1", first+10)
+	assert.False(t, complete)
+	_, complete, _ = events.Since("meeting-1", 0)
+	assert.False(t, complete)
+	_, complete, _ = events.Since("meeting-3", first)
+	assert.False(t, complete)
+}
+
+func TestWebSocketHub_EventLog(t *testing.T) {
+	network := NewMemoryHubNetwork()
+	hubA := newClusterHub(t, network, "node-a")
+	hubB := newClusterHub(t, network, "node-b")
+	// Clustered nodes share one log
+	events := NewMemoryHubEventLog(100, time.Minute)
+	hubA.SetEventLog(events)
+	hubB.SetEventLog(events)
+
+	alice := newHubClient(hubA, "alice", "meeting-1")
+	bob := newHubClient(hubB, "bob", "meeting-1")
+	hubA.registerClient(alice)
+	hubB.registerClient(bob)
+	joined := drain(alice)
+	require.Len(t, joined, 1)
+	start := joined[0].Seq
+	assert.NotZero(t, start)
+	drain(bob)
+
+	hubA.BroadcastToMeeting("meeting-1", SignalingMessage{Type: SignalingTypeChatMessage, MeetingID: "meeting-1", From: "alice"}, "alice")
+	hubB.BroadcastToMeeting("meeting-1", SignalingMessage{Type: SignalingTypeChatTyping, MeetingID: "meeting-1", From: "bob"}, "bob")
+	hubB.BroadcastToMeeting("meeting-1", SignalingMessage{Type: SignalingTypeChatMessage, MeetingID: "meeting-1", From: "bob"}, "bob")
+
+	received := drain(bob)
+	require.Len(t, received, 1)
+	assert.Equal(t, start+1, received[0].Seq)
+	received = drain(alice)
+	require.Len(t, received, 2)
+	assert.Zero(t, received[0].Seq, "typing is not logged")
+	assert.Equal(t, start+2, received[1].Seq)
+
+	// Catching up leaves out the events a client was excluded from
+	missed, through, ok := hubA.EventsSince("meeting-1", "alice", start)
+	require.True(t, ok)
+	assert.Equal(t, start+2, through)
+	require.Len(t, missed, 1)
+	assert.Equal(t, "bob", missed[0].From)
+
+	missed, through, ok = hubB.EventsSince("meeting-1", "bob", start)
+	require.True(t, ok)
+	assert.Equal(t, start+2, through)
+	require.Len(t, missed, 1)
+	assert.Equal(t, "alice", missed[0].From)
+	assert.Equal(t, start+2, hubA.LastSeq("meeting-1"))
+
+	_, _, ok = NewWebSocketHub().EventsSince("meeting-1", "alice", start)
+	assert.False(t, ok)
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ToPeer returns the WebRTC peer of the presence entry's client
+func (p HubPresence) ToPeer() *WebRTCPeer {
+	state := p.PeerState
+	if state == "" {
+		state = PeerStateNew
+	}
+	lastSeen := p.LastSeen
+	if lastSeen.IsZero() {
+		lastSeen = p.JoinedAt
+	}
+	return &WebRTCPeer{
+		ID:           p.ClientID,
+		MeetingID:    p.MeetingID,
+		UserID:       p.UserID,
+		PublicUserID: p.PublicUserID,
+		Name:         p.Name,
+		IsAuth:       p.IsAuth,
+		State:        state,
+		Codecs:       p.Codecs,
+		JoinedAt:     p.JoinedAt,
+		LastSeen:     lastSeen,
+	}
+}
+
+// IsPoliteTo reports whether the presence entry's client is the polite peer
+// of its pair with other: the one that joined the meeting later, or the one
+// with the greater client ID when they joined at once. The polite peer rolls
+// back its own offer when offers collide; the impolite peer ignores the
+// other's.
+func (p HubPresence) IsPoliteTo(other HubPresence) bool {
+	if !p.JoinedAt.Equal(other.JoinedAt) {
+		return p.JoinedAt.After(other.JoinedAt)
+	}
+	return p.ClientID > other.ClientID
+}
+
+// UpdatePeerState records the WebRTC peer state of a client admitted to a
+// meeting, wherever it is connected, and tells the meeting, the client
+// included. The node holding a remote client's connection records the state
+// when the update reaches it.
+func (h *WebSocketHub) UpdatePeerState(meetingID, clientID string, state PeerConnectionState) (HubPresence, error) {
+	return h.updatePeer(meetingID, clientID, func(update *PeerStatePayload) {
+		update.State = state
+	})
+}
+
+// UpdatePeerCodecs records the codecs a client's WebRTC peer connection
+// negotiated, by media kind, like UpdatePeerState does for its state
+func (h *WebSocketHub) UpdatePeerCodecs(meetingID, clientID string, codecs map[string]string) (HubPresence, error) {
+	return h.updatePeer(meetingID, clientID, func(update *PeerStatePayload) {
+		update.Codecs = codecs
+	})
+}
+
+// updatePeer applies change to the current peer details of a client and
+// broadcasts the result to the meeting
+func (h *WebSocketHub) updatePeer(meetingID, clientID string, change func(*PeerStatePayload)) (HubPresence, error) {
+	now := time.Now()
+
+	h.mu.RLock()
+	client, local := h.Meetings[meetingID][clientID]
+	h.mu.RUnlock()
+
+	var presence HubPresence
+	if local {
+		nodeID := ""
+		if h.broker != nil {
+			nodeID = h.broker.NodeID()
+		}
+		presence = client.ToPresence(nodeID)
+	} else {
+		if h.broker == nil {
+			return HubPresence{}, ErrHubClientNotFound
+		}
+		presences, err := h.broker.MeetingPresence(meetingID)
+		if err != nil {
+			return HubPresence{}, fmt.Errorf("failed to load meeting presence: %w", err)
+		}
+		found := false
+		for _, candidate := range presences {
+			if candidate.ClientID == clientID {
+				presence, found = candidate, true
+				break
+			}
+		}
+		if !found {
+			return HubPresence{}, ErrHubClientNotFound
+		}
+	}
+
+	peer := presence.ToPeer()
+	update := PeerStatePayload{
+		ParticipantID: clientID,
+		State:         peer.State,
+		Codecs:        peer.Codecs,
+		LastSeen:      now,
+	}
+	change(&update)
+	presence.PeerState = update.State
+	presence.Codecs = update.Codecs
+	presence.LastSeen = now
+
+	if local {
+		client.SetPeerState(update.State, now)
+		client.SetPeerCodecs(update.Codecs)
+		h.RefreshPresence(client)
+	}
+	// The node holding a remote client republishes its presence entry when
+	// the update reaches it, so a client that just left is not revived
+
+	h.broadcastToMeeting(meetingID, SignalingMessage{
+		Type:      SignalingTypeParticipantUpdated,
+		MeetingID: meetingID,
+		From:      clientID,
+		Data:      update,
+		Timestamp: now,
+	}, "")
+	return presence, nil
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketHub_UpdatePeerState(t *testing.T) {
+	network := NewMemoryHubNetwork()
+	hubA := newClusterHub(t, network, "node-a")
+	hubB := newClusterHub(t, network, "node-b")
+
+	alice := newHubClient(hubA, "alice", "meeting-1")
+	bob := newHubClient(hubB, "bob", "meeting-1")
+	hubA.registerClient(alice)
+	hubB.registerClient(bob)
+	drain(alice)
+	drain(bob)
+
+	// Clients start out as new peers
+	participants := hubB.GetMeetingParticipants("meeting-1")
+	require.Len(t, participants, 2)
+	for _, participant := range participants {
+		assert.Equal(t, PeerStateNew, participant.ToPeer().State)
+	}
+
+	// A local update shows in the cluster presence and reaches everyone
+	presence, err := hubA.UpdatePeerState("meeting-1", "alice", PeerStateConnected)
+	require.NoError(t, err)
+	assert.Equal(t, PeerStateConnected, presence.PeerState)
+	state, _ := alice.PeerState()
+	assert.Equal(t, PeerStateConnected, state)
+	for _, participant := range hubB.GetMeetingParticipants("meeting-1") {
+		if participant.ClientID == "alice" {
+			assert.Equal(t, PeerStateConnected, participant.PeerState)
+		}
+	}
+	for _, client := range []*WebSocketClient{alice, bob} {
+		received := drain(client)
+		require.Len(t, received, 1)
+		assert.Equal(t, SignalingTypeParticipantUpdated, received[0].Type)
+		assert.Equal(t, PeerStateConnected, received[0].Data.(PeerStatePayload).State)
+	}
+
+	// A remote update is left for the node holding the connection to record
+	presence, err = hubA.UpdatePeerState("meeting-1", "bob", PeerStateFailed)
+	require.NoError(t, err)
+	assert.Equal(t, PeerStateFailed, presence.PeerState)
+	received := drain(bob)
+	require.Len(t, received, 1)
+	assert.Equal(t, "bob", received[0].Data.(PeerStatePayload).ParticipantID)
+
+	_, err = hubA.UpdatePeerState("meeting-2", "alice", PeerStateConnected)
+	assert.ErrorIs(t, err, ErrHubClientNotFound)
+	hubB.unregisterClient(bob)
+	_, err = hubA.UpdatePeerState("meeting-1", "bob", PeerStateConnected)
+	assert.ErrorIs(t, err, ErrHubClientNotFound)
+}
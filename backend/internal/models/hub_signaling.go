@@ -0,0 +1,86 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrSignalingTargetRequired is returned when peer signaling names no target
+	ErrSignalingTargetRequired = errors.New("signaling target required")
+	// ErrSignalingTargetNotInMeeting is returned when the target of peer
+	// signaling is connected, but not admitted to the sender's meeting
+	ErrSignalingTargetNotInMeeting = errors.New("signaling target not in meeting")
+)
+
+// IsDirectedSignalingType reports whether messages of a type are meant for a
+// single peer and must never be fanned out to the meeting
+func IsDirectedSignalingType(messageType SignalingMessageType) bool {
+	switch messageType {
+	case SignalingTypeOffer, SignalingTypeAnswer, SignalingTypeIceCandidate:
+		return true
+	}
+	return false
+}
+
+// RouteSignal delivers an offer, answer or ICE candidate to the client named
+// by message.To, wherever it is connected, provided it is admitted to
+// message.MeetingID. It returns ErrHubClientNotFound when the target is gone.
+func (h *WebSocketHub) RouteSignal(message SignalingMessage) error {
+	if message.To == "" {
+		return ErrSignalingTargetRequired
+	}
+
+	h.mu.Lock()
+	client, local := h.Clients[message.To]
+	if local && h.Meetings[message.MeetingID][message.To] == client {
+		h.deliverLocked(client, message)
+		h.mu.Unlock()
+		return nil
+	}
+	h.mu.Unlock()
+	if local {
+		return ErrSignalingTargetNotInMeeting
+	}
+
+	if h.broker == nil {
+		return ErrHubClientNotFound
+	}
+
+	// A remote target must still be present in the meeting; one that is not
+	// has left it, as far as the sender can tell
+	presences, err := h.broker.MeetingPresence(message.MeetingID)
+	if err != nil {
+		return fmt.Errorf("failed to load meeting presence: %w", err)
+	}
+	present := false
+	for _, presence := range presences {
+		if presence.ClientID == message.To {
+			present = true
+			break
+		}
+	}
+	if !present {
+		return ErrHubClientNotFound
+	}
+
+	envelope := HubEnvelope{
+		NodeID:  h.broker.NodeID(),
+		Message: message,
+	}
+	return h.broker.PublishClient(message.To, envelope)
+}
+
+// deliverSignal sends peer signaling relayed by another node to a local
+// client, provided it is still admitted to the message's meeting
+func (h *WebSocketHub) deliverSignal(clientID string, message SignalingMessage) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client, ok := h.Meetings[message.MeetingID][clientID]
+	if !ok {
+		return false
+	}
+	h.deliverLocked(client, message)
+	return true
+}
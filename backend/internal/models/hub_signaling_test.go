@@ -0,0 +1,100 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketHub_RouteSignal(t *testing.T) {
+	hub := NewWebSocketHub()
+	alice := newHubClient(hub, "alice", "meeting-1")
+	bob := newHubClient(hub, "bob", "meeting-1")
+	carol := newHubClient(hub, "carol", "meeting-1")
+	dave := newHubClient(hub, "dave", "meeting-2")
+	erin := newHubClient(hub, "erin", "meeting-1")
+	erin.SetPending(true)
+	for _, client := range []*WebSocketClient{alice, bob, carol, dave, erin} {
+		hub.registerClient(client)
+	}
+	for _, client := range []*WebSocketClient{alice, bob, carol, dave, erin} {
+		drain(client)
+	}
+
+	offer := func(to string) SignalingMessage {
+		return SignalingMessage{Type: SignalingTypeOffer, MeetingID: "meeting-1", From: "alice", To: to}
+	}
+
+	// Only the target hears the offer, even when it goes through Broadcast
+	require.NoError(t, hub.RouteSignal(offer("bob")))
+	hub.broadcastMessage(SignalingMessage{Type: SignalingTypeIceCandidate, MeetingID: "meeting-1", From: "alice", To: "carol"})
+	received := drain(bob)
+	require.Len(t, received, 1)
+	assert.Equal(t, SignalingTypeOffer, received[0].Type)
+	received = drain(carol)
+	require.Len(t, received, 1)
+	assert.Equal(t, SignalingTypeIceCandidate, received[0].Type)
+	assert.Empty(t, drain(alice))
+
+	// Untargeted signaling is dropped rather than fanned out
+	assert.ErrorIs(t, hub.RouteSignal(offer("")), ErrSignalingTargetRequired)
+	hub.broadcastMessage(offer(""))
+	assert.Empty(t, drain(bob))
+	assert.Empty(t, drain(carol))
+
+	// Clients of other meetings and of the lobby are out of reach
+	assert.ErrorIs(t, hub.RouteSignal(offer("dave")), ErrSignalingTargetNotInMeeting)
+	assert.ErrorIs(t, hub.RouteSignal(offer("erin")), ErrSignalingTargetNotInMeeting)
+	assert.Empty(t, drain(dave))
+	assert.Empty(t, drain(erin))
+
+	// Other messages go to their meeting, never to a client they name
+	hub.broadcastMessage(SignalingMessage{Type: SignalingTypeChatReaction, MeetingID: "meeting-1", From: "alice", To: "dave"})
+	assert.Empty(t, drain(dave))
+	received = drain(bob)
+	require.Len(t, received, 1)
+	assert.Empty(t, received[0].To)
+
+	hub.unregisterClient(bob)
+	assert.ErrorIs(t, hub.RouteSignal(offer("bob")), ErrHubClientNotFound)
+}
+
+func TestWebSocketHub_ClusterRouteSignal(t *testing.T) {
+	network := NewMemoryHubNetwork()
+	hubA := newClusterHub(t, network, "node-a")
+	hubB := newClusterHub(t, network, "node-b")
+
+	alice := newHubClient(hubA, "alice", "meeting-1")
+	bob := newHubClient(hubB, "bob", "meeting-1")
+	carol := newHubClient(hubB, "carol", "meeting-1")
+	dave := newHubClient(hubB, "dave", "meeting-2")
+	hubA.registerClient(alice)
+	for _, client := range []*WebSocketClient{bob, carol, dave} {
+		hubB.registerClient(client)
+	}
+	for _, client := range []*WebSocketClient{alice, bob, carol, dave} {
+		drain(client)
+	}
+
+	require.NoError(t, hubA.RouteSignal(SignalingMessage{Type: SignalingTypeAnswer, MeetingID: "meeting-1", From: "alice", To: "carol"}))
+	received := drain(carol)
+	require.Len(t, received, 1)
+	assert.Equal(t, SignalingTypeAnswer, received[0].Type)
+	assert.Empty(t, drain(bob))
+
+	// A remote client of another meeting never receives the signaling
+	err := hubA.RouteSignal(SignalingMessage{Type: SignalingTypeAnswer, MeetingID: "meeting-1", From: "alice", To: "dave"})
+	assert.ErrorIs(t, err, ErrHubClientNotFound)
+	hubB.deliverRemote(HubEnvelope{
+		NodeID:         "node-a",
+		TargetClientID: "dave",
+		Message:        SignalingMessage{Type: SignalingTypeAnswer, MeetingID: "meeting-1", From: "alice", To: "dave"},
+	})
+	assert.Empty(t, drain(dave))
+
+	hubB.unregisterClient(carol)
+	drain(alice)
+	err = hubA.RouteSignal(SignalingMessage{Type: SignalingTypeAnswer, MeetingID: "meeting-1", From: "alice", To: "carol"})
+	assert.ErrorIs(t, err, ErrHubClientNotFound)
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxICECandidateLength bounds the ICE candidates relayed between peers
+const MaxICECandidateLength = 1024
+
+// MediaPolicy is a meeting's WebRTC media policy, enforced on the offers,
+// answers and ICE candidates its peers exchange. Meetings without one follow
+// the server's default policy.
+type MediaPolicy struct {
+	MeetingID     uuid.UUID `gorm:"type:uuid;primary_key" json:"meetingId"`
+	AllowedCodecs string    `gorm:"size:500;not null" json:"-"` // Comma-separated, lower case; empty allows any codec
+	MaxBitrate    int       `gorm:"not null" json:"maxBitrate"` // Kbps per video stream, written as b=AS; 0 is unlimited
+	Simulcast     bool      `gorm:"not null" json:"simulcast"`
+	PrivacyMode   bool      `gorm:"not null" json:"privacyMode"` // Host candidates are stripped
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	IsDefault bool `gorm:"-" json:"isDefault"` // The meeting follows the server's default policy
+}
+
+func (MediaPolicy) TableName() string {
+	return "meeting_media_policies"
+}
+
+// Codecs returns the codecs peers may negotiate; none means any
+func (p *MediaPolicy) Codecs() []string {
+	if p.AllowedCodecs == "" {
+		return nil
+	}
+	return strings.Split(p.AllowedCodecs, ",")
+}
+
+// SetCodecs sets the codecs peers may negotiate
+func (p *MediaPolicy) SetCodecs(codecs []string) {
+	names := make([]string, 0, len(codecs))
+	for _, codec := range codecs {
+		names = append(names, strings.ToLower(strings.TrimSpace(codec)))
+	}
+	p.AllowedCodecs = strings.Join(names, ",")
+}
+
+type MediaPolicyResponse struct {
+	MeetingID     uuid.UUID  `json:"meetingId"`
+	AllowedCodecs []string   `json:"allowedCodecs"`
+	MaxBitrate    int        `json:"maxBitrate"`
+	Simulcast     bool       `json:"simulcast"`
+	PrivacyMode   bool       `json:"privacyMode"`
+	IsDefault     bool       `json:"isDefault"`
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
+}
+
+func (p *MediaPolicy) ToResponse() MediaPolicyResponse {
+	response := MediaPolicyResponse{
+		MeetingID:     p.MeetingID,
+		AllowedCodecs: p.Codecs(),
+		MaxBitrate:    p.MaxBitrate,
+		Simulcast:     p.Simulcast,
+		PrivacyMode:   p.PrivacyMode,
+		IsDefault:     p.IsDefault,
+	}
+	if response.AllowedCodecs == nil {
+		response.AllowedCodecs = []string{}
+	}
+	if !p.IsDefault {
+		response.UpdatedAt = &p.UpdatedAt
+	}
+	return response
+}
+
+// UpdateMediaPolicyRequest changes a meeting's media policy; fields left out
+// keep their current value, or the server default's
+type UpdateMediaPolicyRequest struct {
+	AllowedCodecs *[]string `json:"allowedCodecs,omitempty" validate:"omitempty,max=20,dive,min=1,max=32"`
+	MaxBitrate    *int      `json:"maxBitrate,omitempty" validate:"omitempty,min=0,max=100000"`
+	Simulcast     *bool     `json:"simulcast,omitempty"`
+	PrivacyMode   *bool     `json:"privacyMode,omitempty"`
+}
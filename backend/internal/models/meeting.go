@@ -7,26 +7,120 @@ import (
 	"gorm.io/gorm"
 )
 
+// MeetingStatus is the lifecycle state of a meeting
+type MeetingStatus string
+
+const (
+	MeetingStatusScheduled MeetingStatus = "scheduled"
+	MeetingStatusLive      MeetingStatus = "live"
+	MeetingStatusEnded     MeetingStatus = "ended"
+	MeetingStatusArchived  MeetingStatus = "archived"
+)
+
+// meetingTransitions lists the states each status may move to. An ended
+// meeting can go live again when participants come back.
+var meetingTransitions = map[MeetingStatus][]MeetingStatus{
+	MeetingStatusScheduled: {MeetingStatusLive, MeetingStatusArchived},
+	MeetingStatusLive:      {MeetingStatusEnded},
+	MeetingStatusEnded:     {MeetingStatusLive, MeetingStatusArchived},
+}
+
+// CanTransitionTo reports whether a meeting may move from s to next
+func (s MeetingStatus) CanTransitionTo(next MeetingStatus) bool {
+	for _, allowed := range meetingTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionSources returns the statuses a meeting can move to s from
+func (s MeetingStatus) TransitionSources() []MeetingStatus {
+	var sources []MeetingStatus
+	for from, targets := range meetingTransitions {
+		for _, target := range targets {
+			if target == s {
+				sources = append(sources, from)
+			}
+		}
+	}
+	return sources
+}
+
+// LobbyMode decides which newcomers wait in the lobby for a host to admit them
+type LobbyMode string
+
+const (
+	LobbyModeOff      LobbyMode = "off"
+	LobbyModeGuests   LobbyMode = "guests"   // Public (guest) users wait
+	LobbyModeEveryone LobbyMode = "everyone" // Everyone except the host and previously admitted participants waits
+)
+
+// PrivateChatPolicy decides who may send private chat messages in a meeting
+type PrivateChatPolicy string
+
+const (
+	PrivateChatEveryone PrivateChatPolicy = "everyone"
+	PrivateChatHostOnly PrivateChatPolicy = "host-only" // Only to or from the host and co-hosts
+	PrivateChatOff      PrivateChatPolicy = "off"
+)
+
 type Meeting struct {
-	ID        uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
-	Name      string         `gorm:"not null;size:255" json:"name" validate:"required,min=1,max=255"`
-	StartTime time.Time      `gorm:"not null" json:"startTime"`
-	HostID    uuid.UUID      `gorm:"type:uuid;not null" json:"hostId"`
-	IsActive  bool           `gorm:"default:false" json:"isActive"`
-	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	ID         uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	Name       string         `gorm:"not null;size:255" json:"name" validate:"required,min=1,max=255"`
+	StartTime  time.Time      `gorm:"not null" json:"startTime"`
+	Duration   int            `gorm:"not null;default:60" json:"duration"` // Planned length in minutes
+	EndTime    time.Time      `gorm:"not null;index" json:"endTime"`        // Planned end, StartTime plus Duration
+	TimeZone   string         `gorm:"size:64;not null;default:UTC" json:"timeZone"` // IANA name; recurrences expand in it
+	HostID     uuid.UUID      `gorm:"type:uuid;not null" json:"hostId"`
+	IsActive   bool           `gorm:"default:false" json:"isActive"` // Mirrors Status == live
+	Status     MeetingStatus  `gorm:"size:20;not null;default:scheduled" json:"status"`
+	StartedAt  *time.Time     `json:"startedAt,omitempty"`
+	EndedAt    *time.Time     `json:"endedAt,omitempty"`
+	ArchivedAt *time.Time     `json:"archivedAt,omitempty"`
+	IsLocked   bool           `gorm:"default:false" json:"isLocked"`
+	LockedAt   *time.Time     `json:"lockedAt,omitempty"`
+	LobbyMode  LobbyMode      `gorm:"size:20;not null;default:off" json:"lobbyMode"`
+	PrivateChat PrivateChatPolicy `gorm:"size:20;not null;default:everyone" json:"privateChat"`
+	ChatSlowMode int          `gorm:"not null;default:0" json:"chatSlowMode"` // Seconds between two messages of a participant; 0 is off
+	PasscodeHash string       `gorm:"size:255" json:"-"`
+	SeriesID       *uuid.UUID `gorm:"type:uuid;index;uniqueIndex:idx_meetings_series_occurrence" json:"seriesId,omitempty"`
+	OccurrenceTime *time.Time `gorm:"uniqueIndex:idx_meetings_series_occurrence" json:"occurrenceTime,omitempty"` // Start time the series rule gave this occurrence (RECURRENCE-ID)
+	IsDetached     bool       `gorm:"default:false" json:"isDetached"` // Edited individually; series-wide edits leave it alone
+	CreatedAt  time.Time      `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
 
 	// Relationships
 	Host         User         `gorm:"foreignKey:HostID" json:"host,omitempty"`
 	Participants []Participant `gorm:"foreignKey:MeetingID" json:"participants,omitempty"`
+	Series       *MeetingSeries `gorm:"foreignKey:SeriesID" json:"series,omitempty"`
 }
 
 type MeetingResponse struct {
 	ID           uuid.UUID    `json:"id"`
 	Name         string       `json:"name"`
 	StartTime    time.Time    `json:"startTime"`
+	Duration     int          `json:"duration"`
+	EndTime      time.Time    `json:"endTime"`
+	TimeZone     string       `json:"timeZone"`
+	Category     MeetingCategory `json:"category"`
 	HostID       uuid.UUID    `json:"hostId"`
 	IsActive     bool         `json:"isActive"`
+	Status       MeetingStatus `json:"status"`
+	StartedAt    *time.Time   `json:"startedAt,omitempty"`
+	EndedAt      *time.Time   `json:"endedAt,omitempty"`
+	ArchivedAt   *time.Time   `json:"archivedAt,omitempty"`
+	IsLocked     bool         `json:"isLocked"`
+	LockedAt     *time.Time   `json:"lockedAt,omitempty"`
+	LobbyMode    LobbyMode    `json:"lobbyMode"`
+	PrivateChat  PrivateChatPolicy `json:"privateChat"`
+	ChatSlowMode int          `json:"chatSlowMode"`
+	HasPasscode  bool         `json:"hasPasscode"`
+	SeriesID       *uuid.UUID `json:"seriesId,omitempty"`
+	OccurrenceTime *time.Time `json:"occurrenceTime,omitempty"`
+	IsDetached     bool       `json:"isDetached,omitempty"`
+	RecurrenceRule string     `json:"recurrenceRule,omitempty"`
 	Host         UserResponse `json:"host,omitempty"`
 	Participants []ParticipantResponse `json:"participants,omitempty"`
 	CreatedAt    time.Time    `json:"createdAt"`
@@ -35,17 +129,44 @@ type MeetingResponse struct {
 type CreateMeetingRequest struct {
 	Name         string                    `json:"name" validate:"required,min=1,max=255"`
 	StartTime    time.Time                 `json:"startTime" validate:"required"`
+	Duration     int                       `json:"duration,omitempty" validate:"omitempty,min=1,max=1440"` // Minutes; defaults to 60
+	EndTime      *time.Time                `json:"endTime,omitempty"`                                      // Alternative to duration
+	TimeZone     string                    `json:"timeZone,omitempty" validate:"omitempty,max=64"`         // IANA name; defaults to the host's preference
+	AllowOverlap bool                      `json:"allowOverlap,omitempty"`                                 // Schedule even if the host has another meeting at the time
 	Participants []CreateParticipantRequest `json:"participants,omitempty"`
+	LobbyMode    LobbyMode                 `json:"lobbyMode,omitempty" validate:"omitempty,oneof=off guests everyone"`
+	PrivateChat  PrivateChatPolicy         `json:"privateChat,omitempty" validate:"omitempty,oneof=everyone host-only off"`
+	Passcode     string                    `json:"passcode,omitempty" validate:"omitempty,min=4,max=64"`
+	RecurrenceRule string                  `json:"recurrenceRule,omitempty" validate:"omitempty,max=500"` // iCalendar RRULE, e.g. FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10
+	ExceptionDates []time.Time             `json:"exceptionDates,omitempty"`                             // Occurrences to skip (EXDATE)
 }
 
 type UpdateMeetingRequest struct {
 	Name         *string                   `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
 	StartTime    *time.Time                `json:"startTime,omitempty"`
+	Duration     *int                      `json:"duration,omitempty" validate:"omitempty,min=1,max=1440"`
+	EndTime      *time.Time                `json:"endTime,omitempty"`
+	TimeZone     *string                   `json:"timeZone,omitempty" validate:"omitempty,max=64"`
+	AllowOverlap bool                      `json:"allowOverlap,omitempty"`
 	Participants *[]CreateParticipantRequest `json:"participants,omitempty"`
+	LobbyMode    *LobbyMode                `json:"lobbyMode,omitempty" validate:"omitempty,oneof=off guests everyone"`
+	PrivateChat  *PrivateChatPolicy        `json:"privateChat,omitempty" validate:"omitempty,oneof=everyone host-only off"`
+	Passcode     *string                   `json:"passcode,omitempty" validate:"omitempty,min=4,max=64"` // Empty string removes the passcode
+	RecurrenceRule *string                 `json:"recurrenceRule,omitempty" validate:"omitempty,max=500"` // Not allowed with the "this" scope
+	Scope        RecurrenceScope           `json:"scope,omitempty" validate:"omitempty,oneof=this following all"` // Occurrences of a series to update; defaults to "this"
+}
+
+// LobbyDecisionRequest admits or denies pending participants, either the
+// listed ones or everyone waiting
+type LobbyDecisionRequest struct {
+	ParticipantIDs []uuid.UUID `json:"participantIds,omitempty"`
+	All            bool        `json:"all"`
 }
 
 type JoinMeetingRequest struct {
-	MeetingID uuid.UUID `json:"meetingId" validate:"required"`
+	MeetingID   uuid.UUID `json:"meetingId" validate:"required"`
+	Passcode    string    `json:"passcode,omitempty"`
+	InviteToken string    `json:"inviteToken,omitempty"`
 }
 
 type LeaveMeetingRequest struct {
@@ -69,11 +190,32 @@ func (m *Meeting) ToResponse() MeetingResponse {
 		ID:        m.ID,
 		Name:      m.Name,
 		StartTime: m.StartTime,
+		Duration:  m.Duration,
+		EndTime:   m.EndTime,
+		TimeZone:  m.TimeZone,
+		Category:  m.CategoryAt(time.Now()),
 		HostID:    m.HostID,
 		IsActive:  m.IsActive,
+		Status:    m.Status,
+		StartedAt: m.StartedAt,
+		EndedAt:   m.EndedAt,
+		ArchivedAt: m.ArchivedAt,
+		IsLocked:  m.IsLocked,
+		LockedAt:  m.LockedAt,
+		LobbyMode: m.LobbyMode,
+		PrivateChat: m.PrivateChat,
+		ChatSlowMode: m.ChatSlowMode,
+		HasPasscode: m.HasPasscode(),
+		SeriesID:  m.SeriesID,
+		OccurrenceTime: m.OccurrenceTime,
+		IsDetached: m.IsDetached,
 		CreatedAt: m.CreatedAt,
 	}
 
+	if m.Series != nil {
+		response.RecurrenceRule = m.Series.RecurrenceRule
+	}
+
 	if m.Host.ID != uuid.Nil {
 		response.Host = m.Host.ToResponse()
 	}
@@ -85,10 +227,45 @@ func (m *Meeting) ToResponse() MeetingResponse {
 	return response
 }
 
+// HasPasscode reports whether newcomers need the passcode or an invite
+func (m *Meeting) HasPasscode() bool {
+	return m.PasscodeHash != ""
+}
+
+// ToPublicResponse is the view shown to anyone holding the meeting ID. Who
+// hosts and attends a passcode-protected meeting is not disclosed.
+func (m *Meeting) ToPublicResponse() MeetingResponse {
+	response := m.ToResponse()
+	if m.HasPasscode() {
+		response.HostID = uuid.Nil
+		response.Host = UserResponse{}
+		response.Participants = nil
+	}
+	return response
+}
+
 // BeforeCreate hook to generate UUID
 func (m *Meeting) BeforeCreate(tx *gorm.DB) error {
 	if m.ID == uuid.Nil {
 		m.ID = uuid.New()
 	}
+	if m.Status == "" {
+		m.Status = MeetingStatusScheduled
+	}
+	if m.Duration == 0 {
+		m.Duration = DefaultMeetingDuration
+	}
+	if m.EndTime.IsZero() {
+		m.EndTime = m.StartTime.Add(time.Duration(m.Duration) * time.Minute)
+	}
+	if m.TimeZone == "" {
+		m.TimeZone = DefaultTimeZone
+	}
+	if m.LobbyMode == "" {
+		m.LobbyMode = LobbyModeOff
+	}
+	if m.PrivateChat == "" {
+		m.PrivateChat = PrivateChatEveryone
+	}
 	return nil
 }
\ No newline at end of file
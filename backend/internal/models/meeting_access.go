@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MeetingInvite is a host-issued invitation. The invite link carries a signed
+// token referencing the record, so invites can be counted and revoked.
+type MeetingInvite struct {
+	ID        uuid.UUID       `gorm:"type:uuid;primary_key" json:"id"`
+	MeetingID uuid.UUID       `gorm:"type:uuid;not null;index" json:"meetingId"`
+	CreatedBy uuid.UUID       `gorm:"type:uuid;not null" json:"createdBy"`
+	Role      ParticipantRole `gorm:"size:20" json:"role,omitempty"`     // Role given to participants joining with the invite
+	MaxUses   int             `gorm:"not null;default:0" json:"maxUses"` // 0 means unlimited
+	Uses      int             `gorm:"not null;default:0" json:"uses"`
+	ExpiresAt time.Time       `gorm:"not null" json:"expiresAt"`
+	RevokedAt *time.Time      `json:"revokedAt,omitempty"`
+	CreatedAt time.Time       `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+type MeetingInviteResponse struct {
+	ID        uuid.UUID       `json:"id"`
+	MeetingID uuid.UUID       `json:"meetingId"`
+	Role      ParticipantRole `json:"role,omitempty"`
+	MaxUses   int             `json:"maxUses"`
+	Uses      int             `json:"uses"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+	RevokedAt *time.Time      `json:"revokedAt,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Token     string          `json:"token,omitempty"` // Only returned when the invite is created
+}
+
+type CreateMeetingInviteRequest struct {
+	ExpiresAt *time.Time      `json:"expiresAt,omitempty"`
+	MaxUses   int             `json:"maxUses" validate:"min=0,max=10000"`
+	Role      ParticipantRole `json:"role,omitempty" validate:"omitempty,oneof=co-host presenter attendee viewer"`
+}
+
+// MeetingCredentials is what a newcomer presents to enter a protected
+// meeting: the passcode or an invite token
+type MeetingCredentials struct {
+	Passcode    string
+	InviteToken string
+	ClientIP    string // Used to throttle passcode guessing
+}
+
+func (i *MeetingInvite) ToResponse() MeetingInviteResponse {
+	return MeetingInviteResponse{
+		ID:        i.ID,
+		MeetingID: i.MeetingID,
+		Role:      i.Role,
+		MaxUses:   i.MaxUses,
+		Uses:      i.Uses,
+		ExpiresAt: i.ExpiresAt,
+		RevokedAt: i.RevokedAt,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+// BeforeCreate hook to generate UUID
+func (i *MeetingInvite) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MeetingSeries holds the recurrence rule and the template of a recurring
+// meeting. Each occurrence is materialized as its own Meeting, with its own
+// participants and chat history, linked back through SeriesID.
+type MeetingSeries struct {
+	ID                uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	HostID            uuid.UUID `gorm:"type:uuid;not null;index" json:"hostId"`
+	Name              string    `gorm:"not null;size:255" json:"name"`
+	StartTime         time.Time `gorm:"not null" json:"startTime"` // DTSTART, the first occurrence
+	Duration          int       `gorm:"not null;default:60" json:"duration"`        // Minutes, copied to each occurrence
+	TimeZone          string    `gorm:"size:64;not null;default:UTC" json:"timeZone"` // The rule is expanded in this zone
+	RecurrenceRule    string    `gorm:"size:500;not null" json:"recurrenceRule"`
+	ExceptionDates    string    `gorm:"type:text" json:"-"` // EXDATE list, comma-separated RFC 3339
+	LobbyMode         LobbyMode `gorm:"size:20;not null;default:off" json:"lobbyMode"`
+	PrivateChat       PrivateChatPolicy `gorm:"size:20;not null;default:everyone" json:"privateChat"`
+	PasscodeHash      string    `gorm:"size:255" json:"-"`
+	Invitees          string    `gorm:"type:text" json:"-"` // JSON list of CreateParticipantRequest copied to each occurrence
+	MaterializedUntil time.Time `json:"-"`                   // Occurrences starting before this exist as meetings
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName keeps the plural table name readable
+func (MeetingSeries) TableName() string {
+	return "meeting_series"
+}
+
+type MeetingSeriesResponse struct {
+	ID             uuid.UUID   `json:"id"`
+	HostID         uuid.UUID   `json:"hostId"`
+	Name           string      `json:"name"`
+	StartTime      time.Time   `json:"startTime"`
+	Duration       int         `json:"duration"`
+	TimeZone       string      `json:"timeZone"`
+	RecurrenceRule string      `json:"recurrenceRule"`
+	ExceptionDates []time.Time `json:"exceptionDates,omitempty"`
+	LobbyMode      LobbyMode   `json:"lobbyMode"`
+	PrivateChat    PrivateChatPolicy `json:"privateChat"`
+	HasPasscode    bool        `json:"hasPasscode"`
+	CreatedAt      time.Time   `json:"createdAt"`
+}
+
+func (s *MeetingSeries) ToResponse() MeetingSeriesResponse {
+	return MeetingSeriesResponse{
+		ID:             s.ID,
+		HostID:         s.HostID,
+		Name:           s.Name,
+		StartTime:      s.StartTime,
+		Duration:       s.Duration,
+		TimeZone:       s.TimeZone,
+		RecurrenceRule: s.RecurrenceRule,
+		ExceptionDates: s.Exceptions(),
+		LobbyMode:      s.LobbyMode,
+		PrivateChat:    s.PrivateChat,
+		HasPasscode:    s.PasscodeHash != "",
+		CreatedAt:      s.CreatedAt,
+	}
+}
+
+// Rule parses the series' recurrence rule
+func (s *MeetingSeries) Rule() (*RecurrenceRule, error) {
+	return ParseRecurrenceRule(s.RecurrenceRule)
+}
+
+// Location returns the series' time zone, falling back to UTC
+func (s *MeetingSeries) Location() *time.Location {
+	location, err := LoadTimeZone(s.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// DTStart returns the first occurrence in the series' time zone, which the
+// rule is expanded from so occurrences keep their wall clock time across
+// daylight saving changes
+func (s *MeetingSeries) DTStart() time.Time {
+	return s.StartTime.In(s.Location())
+}
+
+// Exceptions returns the cancelled occurrence start times
+func (s *MeetingSeries) Exceptions() []time.Time {
+	var exceptions []time.Time
+	for _, value := range strings.Split(s.ExceptionDates, ",") {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			exceptions = append(exceptions, t)
+		}
+	}
+	return exceptions
+}
+
+// IsException reports whether the occurrence starting at t was cancelled
+func (s *MeetingSeries) IsException(t time.Time) bool {
+	for _, exception := range s.Exceptions() {
+		if exception.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddException cancels the occurrence starting at t
+func (s *MeetingSeries) AddException(t time.Time) {
+	if s.IsException(t) {
+		return
+	}
+	exceptions := append(s.Exceptions(), t)
+	sort.Slice(exceptions, func(i, j int) bool { return exceptions[i].Before(exceptions[j]) })
+
+	values := make([]string, len(exceptions))
+	for i, exception := range exceptions {
+		values[i] = exception.UTC().Format(time.RFC3339)
+	}
+	s.ExceptionDates = strings.Join(values, ",")
+}
+
+// InviteeList returns the participants copied to each new occurrence
+func (s *MeetingSeries) InviteeList() []CreateParticipantRequest {
+	var invitees []CreateParticipantRequest
+	if s.Invitees != "" {
+		json.Unmarshal([]byte(s.Invitees), &invitees)
+	}
+	return invitees
+}
+
+// SetInvitees stores the participants copied to each new occurrence
+func (s *MeetingSeries) SetInvitees(invitees []CreateParticipantRequest) {
+	if len(invitees) == 0 {
+		s.Invitees = ""
+		return
+	}
+	data, _ := json.Marshal(invitees)
+	s.Invitees = string(data)
+}
+
+// BeforeCreate hook to generate UUID
+func (s *MeetingSeries) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	if s.LobbyMode == "" {
+		s.LobbyMode = LobbyModeOff
+	}
+	if s.PrivateChat == "" {
+		s.PrivateChat = PrivateChatEveryone
+	}
+	if s.Duration == 0 {
+		s.Duration = DefaultMeetingDuration
+	}
+	if s.TimeZone == "" {
+		s.TimeZone = DefaultTimeZone
+	}
+	return nil
+}
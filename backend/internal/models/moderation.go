@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ModerationAction is an action a host takes on a participant or meeting
+type ModerationAction string
+
+const (
+	ModerationActionMute   ModerationAction = "mute"
+	ModerationActionRemove ModerationAction = "remove"
+	ModerationActionBan    ModerationAction = "ban"
+	ModerationActionUnban  ModerationAction = "unban"
+	ModerationActionLock   ModerationAction = "lock"
+	ModerationActionUnlock ModerationAction = "unlock"
+
+	ModerationActionDeleteMessage ModerationAction = "delete-message"
+	ModerationActionChatMute      ModerationAction = "chat-mute"
+	ModerationActionChatUnmute    ModerationAction = "chat-unmute"
+	ModerationActionSlowMode      ModerationAction = "slow-mode"
+	ModerationActionDismissFlag   ModerationAction = "dismiss-flag"
+)
+
+// MaxChatSlowMode is the longest slow mode interval, in seconds
+const MaxChatSlowMode = 3600
+
+// ModerationLog is the audit record of a moderation action
+type ModerationLog struct {
+	ID                  uuid.UUID        `gorm:"type:uuid;primary_key" json:"id"`
+	MeetingID           uuid.UUID        `gorm:"type:uuid;not null;index" json:"meetingId"`
+	ActorID             uuid.UUID        `gorm:"type:uuid;not null" json:"actorId"`
+	Action              ModerationAction `gorm:"size:20;not null" json:"action"`
+	TargetParticipantID *uuid.UUID       `gorm:"type:uuid" json:"targetParticipantId,omitempty"`
+	TargetUserID        *uuid.UUID       `gorm:"type:uuid" json:"targetUserId,omitempty"`
+	TargetPublicUserID  *uuid.UUID       `gorm:"type:uuid" json:"targetPublicUserId,omitempty"`
+	TargetMessageID     *uuid.UUID       `gorm:"type:uuid" json:"targetMessageId,omitempty"`
+	Reason              string           `gorm:"size:500" json:"reason,omitempty"`
+	Details             string           `gorm:"size:255" json:"details,omitempty"` // e.g. the muted tracks
+	CreatedAt           time.Time        `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// MeetingBan keeps a user or public session from rejoining a meeting
+type MeetingBan struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	MeetingID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"meetingId"`
+	UserID       *uuid.UUID `gorm:"type:uuid" json:"userId,omitempty"`
+	PublicUserID *uuid.UUID `gorm:"type:uuid" json:"publicUserId,omitempty"`
+	Name         string     `gorm:"size:255" json:"name"`
+	BannedByID   uuid.UUID  `gorm:"type:uuid;not null" json:"bannedById"`
+	Reason       string     `gorm:"size:500" json:"reason,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+type MuteParticipantRequest struct {
+	Audio bool `json:"audio"`
+	Video bool `json:"video"`
+}
+
+type RemoveParticipantRequest struct {
+	Reason string `json:"reason,omitempty" validate:"max=500"`
+}
+
+// ChatMuteRequest mutes a participant in chat, for Duration seconds or,
+// when it is 0, until a moderator lifts the mute
+type ChatMuteRequest struct {
+	Duration int    `json:"duration,omitempty" validate:"min=0,max=86400"`
+	Reason   string `json:"reason,omitempty" validate:"max=500"`
+}
+
+// ChatSlowModeRequest sets the seconds a participant waits between two chat
+// messages; 0 turns slow mode off
+type ChatSlowModeRequest struct {
+	Seconds int `json:"seconds" validate:"min=0,max=3600"`
+}
+
+// BeforeCreate hook to generate UUID
+func (l *ModerationLog) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// BeforeCreate hook to generate UUID
+func (b *MeetingBan) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
@@ -7,6 +7,15 @@ import (
 	"gorm.io/gorm"
 )
 
+// LobbyStatus tracks a participant's admission through the meeting lobby
+type LobbyStatus string
+
+const (
+	LobbyStatusPending  LobbyStatus = "pending"
+	LobbyStatusAdmitted LobbyStatus = "admitted"
+	LobbyStatusDenied   LobbyStatus = "denied"
+)
+
 type Participant struct {
 	ID           uuid.UUID   `gorm:"type:uuid;primary_key" json:"id"`
 	MeetingID    uuid.UUID   `gorm:"type:uuid;not null" json:"meetingId"`
@@ -14,6 +23,10 @@ type Participant struct {
 	PublicUserID *uuid.UUID  `gorm:"type:uuid;default:null" json:"publicUserId"` // Nullable for authenticated users
 	Name         string      `gorm:"not null;size:255" json:"name" validate:"required,min=1,max=255"`
 	AvatarURL    string      `gorm:"size:500" json:"avatarUrl,omitempty"`
+	Role         ParticipantRole `gorm:"size:20;not null;default:attendee" json:"role"`
+	LobbyStatus  LobbyStatus `gorm:"size:20;not null;default:admitted" json:"lobbyStatus"`
+	IsChatMuted  bool        `gorm:"not null;default:false" json:"isChatMuted"`
+	ChatMutedUntil *time.Time `json:"chatMutedUntil,omitempty"` // End of a timed chat mute; nil mutes until lifted
 	IsActive     bool        `gorm:"default:true" json:"isActive"`
 	JoinedAt     time.Time   `gorm:"autoCreateTime" json:"joinedAt"`
 	LeftAt       *time.Time  `json:"leftAt,omitempty"`
@@ -31,6 +44,10 @@ type ParticipantResponse struct {
 	PublicUserID *uuid.UUID `json:"publicUserId"`
 	Name         string     `json:"name"`
 	AvatarURL    string     `json:"avatarUrl,omitempty"`
+	Role         ParticipantRole `json:"role"`
+	LobbyStatus  LobbyStatus `json:"lobbyStatus"`
+	IsChatMuted  bool       `json:"isChatMuted"`
+	ChatMutedUntil *time.Time `json:"chatMutedUntil,omitempty"`
 	IsActive     bool       `json:"isActive"`
 	JoinedAt     time.Time  `json:"joinedAt"`
 	LeftAt       *time.Time `json:"leftAt,omitempty"`
@@ -47,8 +64,10 @@ type UpdateParticipantRequest struct {
 }
 
 type JoinMeetingAsPublicUserRequest struct {
-	SessionID string `json:"sessionId" validate:"required"`
-	MeetingID string `json:"meetingId" validate:"required"`
+	SessionID   string `json:"sessionId" validate:"required"`
+	MeetingID   string `json:"meetingId" validate:"required"`
+	Passcode    string `json:"passcode,omitempty"`
+	InviteToken string `json:"inviteToken,omitempty"`
 }
 
 type LeaveMeetingAsPublicUserRequest struct {
@@ -63,16 +82,31 @@ func (p *Participant) ToResponse() ParticipantResponse {
 		PublicUserID: p.PublicUserID,
 		Name:         p.Name,
 		AvatarURL:    p.AvatarURL,
+		Role:         p.Role,
+		LobbyStatus:  p.LobbyStatus,
+		IsChatMuted:  p.ChatMuted(time.Now()),
+		ChatMutedUntil: p.ChatMutedUntil,
 		IsActive:     p.IsActive,
 		JoinedAt:     p.JoinedAt,
 		LeftAt:       p.LeftAt,
 	}
 }
 
+// ChatMuted reports whether a moderator's chat mute is in effect at now
+func (p *Participant) ChatMuted(now time.Time) bool {
+	return p.IsChatMuted && (p.ChatMutedUntil == nil || now.Before(*p.ChatMutedUntil))
+}
+
 // BeforeCreate hook to generate UUID
 func (p *Participant) BeforeCreate(tx *gorm.DB) error {
 	if p.ID == uuid.Nil {
 		p.ID = uuid.New()
 	}
+	if p.Role == "" {
+		p.Role = RoleAttendee
+	}
+	if p.LobbyStatus == "" {
+		p.LobbyStatus = LobbyStatusAdmitted
+	}
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// ParticipantRole is the role a participant holds within a single meeting
+type ParticipantRole string
+
+const (
+	RoleHost      ParticipantRole = "host"
+	RoleCoHost    ParticipantRole = "co-host"
+	RolePresenter ParticipantRole = "presenter"
+	RoleAttendee  ParticipantRole = "attendee"
+	RoleViewer    ParticipantRole = "viewer"
+)
+
+// Permission is an action that can be granted to a meeting role
+type Permission string
+
+const (
+	PermissionManageMeeting Permission = "meeting:manage"     // Start/end/archive, room-wide announcements, room stats
+	PermissionManageRoles   Permission = "roles:manage"       // Promote/demote other participants
+	PermissionModerate      Permission = "meeting:moderate"   // Mute, remove and ban participants, lock the meeting
+	PermissionScreenShare   Permission = "media:screen-share" // Publish a screen share
+	PermissionChat          Permission = "chat:send"          // Send, edit and react to chat messages
+)
+
+var rolePermissions = map[ParticipantRole][]Permission{
+	RoleHost:      {PermissionManageMeeting, PermissionManageRoles, PermissionModerate, PermissionScreenShare, PermissionChat},
+	RoleCoHost:    {PermissionManageMeeting, PermissionManageRoles, PermissionModerate, PermissionScreenShare, PermissionChat},
+	RolePresenter: {PermissionScreenShare, PermissionChat},
+	RoleAttendee:  {PermissionChat},
+	RoleViewer:    {},
+}
+
+// roleRanks orders roles by authority; higher ranks may manage lower ones
+var roleRanks = map[ParticipantRole]int{
+	RoleHost:      4,
+	RoleCoHost:    3,
+	RolePresenter: 2,
+	RoleAttendee:  1,
+	RoleViewer:    0,
+}
+
+// signalingPermissions lists the signaling messages that require a permission.
+// Message types not listed here are allowed for every role.
+var signalingPermissions = map[SignalingMessageType]Permission{
+	SignalingTypeChatMessage:       PermissionChat,
+	SignalingTypeChatMessageEdit:   PermissionChat,
+	SignalingTypeChatMessageDelete: PermissionChat,
+	SignalingTypeChatReaction:      PermissionChat,
+	SignalingTypeChatTyping:        PermissionChat,
+	SignalingTypeChatTypingStop:    PermissionChat,
+	SignalingTypeScreenShareStart:  PermissionScreenShare,
+	SignalingTypeScreenShareStop:   PermissionScreenShare,
+}
+
+// IsValid reports whether r is a known role
+func (r ParticipantRole) IsValid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Can reports whether the role grants the permission
+func (r ParticipantRole) Can(permission Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Outranks reports whether r has strictly more authority than other
+func (r ParticipantRole) Outranks(other ParticipantRole) bool {
+	return roleRanks[r] > roleRanks[other]
+}
+
+// CanAssignRole reports whether a participant holding actor may move a
+// participant from current to next. Only roles below the actor's own can be
+// changed or handed out, and the host role is never assigned this way.
+func CanAssignRole(actor, current, next ParticipantRole) bool {
+	if !actor.Can(PermissionManageRoles) || !next.IsValid() || next == RoleHost {
+		return false
+	}
+	if actor == RoleHost {
+		return current != RoleHost
+	}
+	return actor.Outranks(current) && actor.Outranks(next)
+}
+
+// PermissionForSignalingType returns the permission required to send a
+// signaling message type, if any
+func PermissionForSignalingType(messageType SignalingMessageType) (Permission, bool) {
+	permission, ok := signalingPermissions[messageType]
+	return permission, ok
+}
+
+// MeetingActor identifies who performs an action in a meeting: either an
+// authenticated user or a public (guest) user
+type MeetingActor struct {
+	UserID       *uuid.UUID
+	PublicUserID *uuid.UUID
+}
+
+// UpdateParticipantRoleRequest is the body of a role change
+type UpdateParticipantRoleRequest struct {
+	Role ParticipantRole `json:"role" validate:"required,oneof=co-host presenter attendee viewer"`
+}
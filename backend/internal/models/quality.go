@@ -0,0 +1,125 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PoorQualityScore is the score below which a peer's connection counts as
+// poor, unless a query asks for another threshold
+const PoorQualityScore = 50
+
+// QualitySample is a summary of a peer's getStats() report, submitted by the
+// client every few seconds. Samples are kept for a limited time.
+type QualitySample struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	MeetingID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"meetingId"`
+	PeerID        string     `gorm:"size:100;not null" json:"peerId"` // Client ID of the peer's WebSocket connection
+	UserID        *uuid.UUID `gorm:"type:uuid;default:null" json:"userId,omitempty"`
+	PublicUserID  *uuid.UUID `gorm:"type:uuid;default:null" json:"publicUserId,omitempty"`
+	Name          string     `gorm:"size:255" json:"name"`
+	RTT           float64    `json:"rttMs"`
+	Jitter        float64    `json:"jitterMs"`
+	PacketLoss    float64    `json:"packetLoss"` // Percent of packets lost
+	Bitrate       float64    `json:"bitrateKbps"`
+	FrameRate     float64    `json:"frameRate"`
+	CandidateType string     `gorm:"size:10" json:"candidateType,omitempty"` // host, srflx, prflx or relay
+	Score         int        `gorm:"not null" json:"score"`
+	CreatedAt     time.Time  `gorm:"index" json:"createdAt"`
+}
+
+func (QualitySample) TableName() string {
+	return "webrtc_quality_samples"
+}
+
+func (s *QualitySample) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// QualityStatsPayload is what clients submit, over the API or as a
+// quality-stats WebSocket message: their peer connection's getStats()
+// summary since the previous submission
+type QualityStatsPayload struct {
+	RTT           float64 `json:"rttMs" validate:"min=0,max=60000"`
+	Jitter        float64 `json:"jitterMs" validate:"min=0,max=10000"`
+	PacketLoss    float64 `json:"packetLoss" validate:"min=0,max=100"`
+	Bitrate       float64 `json:"bitrateKbps" validate:"min=0,max=1000000"`
+	FrameRate     float64 `json:"frameRate" validate:"min=0,max=240"`
+	CandidateType string  `json:"candidateType,omitempty" validate:"omitempty,oneof=host srflx prflx relay"`
+}
+
+// Validate checks the same bounds as the validate tags, for stats that come
+// in over WebSocket
+func (p *QualityStatsPayload) Validate() error {
+	switch {
+	case p.RTT < 0 || p.RTT > 60000 || math.IsNaN(p.RTT):
+		return errors.New("rttMs must be between 0 and 60000")
+	case p.Jitter < 0 || p.Jitter > 10000 || math.IsNaN(p.Jitter):
+		return errors.New("jitterMs must be between 0 and 10000")
+	case p.PacketLoss < 0 || p.PacketLoss > 100 || math.IsNaN(p.PacketLoss):
+		return errors.New("packetLoss must be between 0 and 100")
+	case p.Bitrate < 0 || p.Bitrate > 1000000 || math.IsNaN(p.Bitrate):
+		return errors.New("bitrateKbps must be between 0 and 1000000")
+	case p.FrameRate < 0 || p.FrameRate > 240 || math.IsNaN(p.FrameRate):
+		return errors.New("frameRate must be between 0 and 240")
+	}
+	switch p.CandidateType {
+	case "", "host", "srflx", "prflx", "relay":
+		return nil
+	}
+	return errors.New("candidateType must be host, srflx, prflx or relay")
+}
+
+// Score rates the stats from 0 to 100 with a simplified E-model (ITU-T
+// G.107): delay, jitter and packet loss lower the transmission rating R,
+// which is scaled so that a perfect connection scores 100
+func (p *QualityStatsPayload) Score() int {
+	const maxRating = 93.2
+
+	// One-way delay, with jitter buffered away and some codec delay
+	latency := p.RTT/2 + 2*p.Jitter + 10
+	rating := maxRating - latency/40
+	if latency > 160 {
+		rating = maxRating - (latency-120)/10
+	}
+	// Packet loss impairs the rating ever less as it grows, for a codec
+	// that conceals some loss
+	rating -= 95 * p.PacketLoss / (p.PacketLoss + 10)
+
+	score := int(math.Round(rating / maxRating * 100))
+	return max(0, min(100, score))
+}
+
+// PeerQualitySummary aggregates a peer's samples over a window of time
+type PeerQualitySummary struct {
+	PeerID        string     `json:"peerId"`
+	Name          string     `json:"name"`
+	UserID        *uuid.UUID `json:"userId,omitempty"`
+	PublicUserID  *uuid.UUID `json:"publicUserId,omitempty"`
+	Samples       int        `json:"samples"`
+	Score         int        `json:"score"` // Average score of the samples
+	RTT           float64    `json:"rttMs"`
+	Jitter        float64    `json:"jitterMs"`
+	PacketLoss    float64    `json:"packetLoss"`
+	Bitrate       float64    `json:"bitrateKbps"`
+	FrameRate     float64    `json:"frameRate"`
+	CandidateType string     `json:"candidateType,omitempty"` // As of the latest sample
+	LastSampleAt  time.Time  `json:"lastSampleAt"`
+}
+
+// MeetingQualityResponse is a meeting's connection quality over a window of
+// time, for the peers that submitted stats in it
+type MeetingQualityResponse struct {
+	MeetingID     uuid.UUID            `json:"meetingId"`
+	Score         *int                 `json:"score"` // Average of the peers' scores; null without samples
+	WindowSeconds int                  `json:"windowSeconds"`
+	PoorCount     int                  `json:"poorCount"` // Peers scoring below PoorQualityScore
+	Peers         []PeerQualitySummary `json:"peers"`
+}
@@ -0,0 +1,345 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurrenceFrequency is the FREQ part of a recurrence rule
+type RecurrenceFrequency string
+
+const (
+	FrequencyDaily   RecurrenceFrequency = "DAILY"
+	FrequencyWeekly  RecurrenceFrequency = "WEEKLY"
+	FrequencyMonthly RecurrenceFrequency = "MONTHLY"
+)
+
+// maxRecurrenceIterations bounds rule expansion so a sparse rule (such as
+// the 31st of every other month) cannot loop forever
+const maxRecurrenceIterations = 10000
+
+// RecurrenceScope selects which occurrences of a series an edit applies to
+type RecurrenceScope string
+
+const (
+	RecurrenceScopeThis      RecurrenceScope = "this"
+	RecurrenceScopeFollowing RecurrenceScope = "following"
+	RecurrenceScopeAll       RecurrenceScope = "all"
+)
+
+// IsValid reports whether s is a known scope
+func (s RecurrenceScope) IsValid() bool {
+	switch s {
+	case RecurrenceScopeThis, RecurrenceScopeFollowing, RecurrenceScopeAll:
+		return true
+	}
+	return false
+}
+
+// RecurrenceDay is a BYDAY entry. N is the ordinal within the month for
+// monthly rules (1 = first, -1 = last); 0 means every such weekday.
+type RecurrenceDay struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// RecurrenceRule is the supported subset of an iCalendar RRULE: DAILY,
+// WEEKLY and MONTHLY frequencies with INTERVAL, BYDAY, COUNT and UNTIL.
+// Weeks start on Monday.
+type RecurrenceRule struct {
+	Freq     RecurrenceFrequency
+	Interval int
+	ByDay    []RecurrenceDay
+	Count    int
+	Until    *time.Time
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRecurrenceRule parses an RRULE value such as
+// "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10". A leading "RRULE:" is
+// accepted.
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, errors.New("empty recurrence rule")
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = RecurrenceFrequency(strings.ToUpper(val))
+		case "INTERVAL":
+			interval, err := strconv.Atoi(val)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			rule.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(val)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", val)
+			}
+			rule.Count = count
+		case "UNTIL":
+			until, err := parseRecurrenceTime(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL %q", val)
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, code := range strings.Split(val, ",") {
+				day, err := parseRecurrenceDay(code)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "WKST":
+			if strings.ToUpper(val) != "MO" {
+				return nil, errors.New("only WKST=MO is supported")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Validate checks that the rule only uses the supported subset
+func (r *RecurrenceRule) Validate() error {
+	switch r.Freq {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+	case "":
+		return errors.New("FREQ is required")
+	default:
+		return fmt.Errorf("unsupported FREQ %q", r.Freq)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return errors.New("COUNT and UNTIL cannot both be set")
+	}
+	for _, day := range r.ByDay {
+		if day.N != 0 && r.Freq != FrequencyMonthly {
+			return errors.New("ordinal BYDAY is only supported for MONTHLY rules")
+		}
+		if day.N < -5 || day.N > 5 {
+			return fmt.Errorf("invalid BYDAY ordinal %d", day.N)
+		}
+	}
+	return nil
+}
+
+// String formats the rule as an RRULE value
+func (r *RecurrenceRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			codes[i] = weekdayNames[day.Weekday]
+			if day.N != 0 {
+				codes[i] = strconv.Itoa(day.N) + codes[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences expands the rule from dtstart, the first occurrence, and
+// returns the occurrence start times in [from, to). At most limit times are
+// returned when limit is positive. Occurrences keep dtstart's location and
+// wall clock time.
+func (r *RecurrenceRule) Occurrences(dtstart, from, to time.Time, limit int) []time.Time {
+	var occurrences []time.Time
+	generated := 0
+
+	for period := 0; period < maxRecurrenceIterations; period++ {
+		candidates := r.periodCandidates(dtstart, period)
+		if len(candidates) == 0 && r.periodStart(dtstart, period).After(to) {
+			break
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Before(dtstart) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return occurrences
+			}
+			if r.Count > 0 && generated >= r.Count {
+				return occurrences
+			}
+			if !candidate.Before(to) {
+				return occurrences
+			}
+			generated++
+
+			if !candidate.Before(from) {
+				occurrences = append(occurrences, candidate)
+				if limit > 0 && len(occurrences) >= limit {
+					return occurrences
+				}
+			}
+		}
+	}
+	return occurrences
+}
+
+// periodStart returns the first day of the given period: the day itself,
+// the Monday of the week or the first of the month
+func (r *RecurrenceRule) periodStart(dtstart time.Time, period int) time.Time {
+	switch r.Freq {
+	case FrequencyWeekly:
+		offset := (int(dtstart.Weekday()) + 6) % 7 // days since Monday
+		return dtstart.AddDate(0, 0, period*r.Interval*7-offset)
+	case FrequencyMonthly:
+		first := time.Date(dtstart.Year(), dtstart.Month(), 1, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+		return first.AddDate(0, period*r.Interval, 0)
+	}
+	return dtstart.AddDate(0, 0, period*r.Interval)
+}
+
+// periodCandidates returns the sorted occurrence candidates of one period
+func (r *RecurrenceRule) periodCandidates(dtstart time.Time, period int) []time.Time {
+	start := r.periodStart(dtstart, period)
+
+	switch r.Freq {
+	case FrequencyWeekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []RecurrenceDay{{Weekday: dtstart.Weekday()}}
+		}
+		candidates := make([]time.Time, 0, len(days))
+		for _, day := range days {
+			offset := (int(day.Weekday) + 6) % 7
+			candidates = append(candidates, start.AddDate(0, 0, offset))
+		}
+		sortTimes(candidates)
+		return candidates
+
+	case FrequencyMonthly:
+		if len(r.ByDay) == 0 {
+			// Months without the day (e.g. the 31st) are skipped
+			candidate := start.AddDate(0, 0, dtstart.Day()-1)
+			if candidate.Month() != start.Month() {
+				return nil
+			}
+			return []time.Time{candidate}
+		}
+		var candidates []time.Time
+		for _, day := range r.ByDay {
+			candidates = append(candidates, monthlyWeekdays(start, day)...)
+		}
+		sortTimes(candidates)
+		return candidates
+	}
+
+	if len(r.ByDay) > 0 && !r.hasWeekday(start.Weekday()) {
+		return nil
+	}
+	return []time.Time{start}
+}
+
+func (r *RecurrenceRule) hasWeekday(weekday time.Weekday) bool {
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// monthlyWeekdays returns the days of the month starting at first that
+// match a BYDAY entry
+func monthlyWeekdays(first time.Time, day RecurrenceDay) []time.Time {
+	var matches []time.Time
+	for d := first; d.Month() == first.Month(); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == day.Weekday {
+			matches = append(matches, d)
+		}
+	}
+	switch {
+	case day.N > 0 && day.N <= len(matches):
+		return matches[day.N-1 : day.N]
+	case day.N < 0 && -day.N <= len(matches):
+		return matches[len(matches)+day.N : len(matches)+day.N+1]
+	case day.N == 0:
+		return matches
+	}
+	return nil
+}
+
+func parseRecurrenceDay(code string) (RecurrenceDay, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) < 2 {
+		return RecurrenceDay{}, fmt.Errorf("invalid BYDAY %q", code)
+	}
+	weekday, ok := weekdayCodes[code[len(code)-2:]]
+	if !ok {
+		return RecurrenceDay{}, fmt.Errorf("invalid BYDAY %q", code)
+	}
+	day := RecurrenceDay{Weekday: weekday}
+	if ordinal := code[:len(code)-2]; ordinal != "" {
+		n, err := strconv.Atoi(ordinal)
+		if err != nil || n == 0 {
+			return RecurrenceDay{}, fmt.Errorf("invalid BYDAY %q", code)
+		}
+		day.N = n
+	}
+	return day, nil
+}
+
+// parseRecurrenceTime accepts the iCalendar date-time forms used in UNTIL
+// and RFC 3339
+func parseRecurrenceTime(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL includes the whole day
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid time")
+}
+
+func sortTimes(times []time.Time) {
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParseRule(t *testing.T, value string) *RecurrenceRule {
+	rule, err := ParseRecurrenceRule(value)
+	require.NoError(t, err)
+	return rule
+}
+
+func dates(times []time.Time) []string {
+	formatted := make([]string, len(times))
+	for i, t := range times {
+		formatted[i] = t.Format("2006-01-02 15:04")
+	}
+	return formatted
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	rule := mustParseRule(t, "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10")
+	assert.Equal(t, FrequencyWeekly, rule.Freq)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, []RecurrenceDay{{Weekday: time.Monday}, {Weekday: time.Wednesday}}, rule.ByDay)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10", rule.String())
+
+	rule = mustParseRule(t, "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20270101T000000Z")
+	assert.Equal(t, []RecurrenceDay{{Weekday: time.Friday, N: -1}}, rule.ByDay)
+	assert.Equal(t, "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20270101T000000Z", rule.String())
+
+	for _, invalid := range []string{
+		"",
+		"FREQ=YEARLY",
+		"INTERVAL=2",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20270101",
+		"FREQ=WEEKLY;BYDAY=2MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		_, err := ParseRecurrenceRule(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestRecurrenceRule_Occurrences(t *testing.T) {
+	// Thursday 2026-01-01 09:00
+	dtstart := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	far := dtstart.AddDate(2, 0, 0)
+
+	tests := []struct {
+		rule     string
+		expected []string
+	}{
+		{"FREQ=DAILY;COUNT=3", []string{"2026-01-01 09:00", "2026-01-02 09:00", "2026-01-03 09:00"}},
+		{"FREQ=DAILY;INTERVAL=2;UNTIL=20260105T090000Z", []string{"2026-01-01 09:00", "2026-01-03 09:00", "2026-01-05 09:00"}},
+		{"FREQ=DAILY;BYDAY=MO,FR;COUNT=3", []string{"2026-01-02 09:00", "2026-01-05 09:00", "2026-01-09 09:00"}},
+		{"FREQ=WEEKLY;COUNT=3", []string{"2026-01-01 09:00", "2026-01-08 09:00", "2026-01-15 09:00"}},
+		// Days before DTSTART in its first week are skipped
+		{"FREQ=WEEKLY;BYDAY=MO,TH;COUNT=4", []string{"2026-01-01 09:00", "2026-01-05 09:00", "2026-01-08 09:00", "2026-01-12 09:00"}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=TH,FR;COUNT=4", []string{"2026-01-01 09:00", "2026-01-02 09:00", "2026-01-15 09:00", "2026-01-16 09:00"}},
+		{"FREQ=MONTHLY;COUNT=3", []string{"2026-01-01 09:00", "2026-02-01 09:00", "2026-03-01 09:00"}},
+		{"FREQ=MONTHLY;BYDAY=1MO;COUNT=3", []string{"2026-01-05 09:00", "2026-02-02 09:00", "2026-03-02 09:00"}},
+		{"FREQ=MONTHLY;BYDAY=-1FR;COUNT=2", []string{"2026-01-30 09:00", "2026-02-27 09:00"}},
+	}
+	for _, tt := range tests {
+		rule := mustParseRule(t, tt.rule)
+		assert.Equal(t, tt.expected, dates(rule.Occurrences(dtstart, dtstart, far, 0)), tt.rule)
+	}
+}
+
+func TestRecurrenceRule_OccurrencesWindow(t *testing.T) {
+	rule := mustParseRule(t, "FREQ=MONTHLY;COUNT=4")
+
+	// Months without the 31st are skipped, and COUNT counts from DTSTART
+	// even when the window starts later
+	dtstart := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	occurrences := rule.Occurrences(dtstart, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), dtstart.AddDate(2, 0, 0), 0)
+	assert.Equal(t, []string{"2026-05-31 09:00", "2026-07-31 09:00"}, dates(occurrences))
+
+	// Unbounded rules stop at the window end and the limit
+	daily := mustParseRule(t, "FREQ=DAILY")
+	assert.Len(t, daily.Occurrences(dtstart, dtstart, dtstart.AddDate(0, 0, 10), 0), 10)
+	assert.Len(t, daily.Occurrences(dtstart, dtstart, dtstart.AddDate(1, 0, 0), 4), 4)
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefreshToken is a persisted refresh token. Every rotation creates a new
+// token in the same family; a family represents one login session (device).
+type RefreshToken struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"` // Matches the token's jti claim
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	FamilyID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"familyId"`
+	ReplacedByID *uuid.UUID `gorm:"type:uuid" json:"replacedById,omitempty"`
+	UserAgent    string     `gorm:"size:500" json:"userAgent,omitempty"`
+	IPAddress    string     `gorm:"size:45" json:"ipAddress,omitempty"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expiresAt"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
+	SessionStart time.Time  `gorm:"not null" json:"sessionStart"` // When the family was created
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// SessionResponse describes an active login session of a user
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"` // Refresh token family ID
+	UserAgent  string    `json:"userAgent,omitempty"`
+	IPAddress  string    `json:"ipAddress,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
+
+// IsActive reports whether the token can still be exchanged
+func (rt *RefreshToken) IsActive() bool {
+	return rt.RevokedAt == nil && rt.ExpiresAt.After(time.Now())
+}
+
+func (rt *RefreshToken) ToSessionResponse(currentSessionID uuid.UUID) SessionResponse {
+	return SessionResponse{
+		ID:         rt.FamilyID,
+		UserAgent:  rt.UserAgent,
+		IPAddress:  rt.IPAddress,
+		CreatedAt:  rt.SessionStart,
+		LastUsedAt: rt.CreatedAt,
+		ExpiresAt:  rt.ExpiresAt,
+		Current:    rt.FamilyID == currentSessionID,
+	}
+}
+
+// BeforeCreate hook to generate UUID
+func (rt *RefreshToken) BeforeCreate(tx *gorm.DB) error {
+	if rt.ID == uuid.Nil {
+		rt.ID = uuid.New()
+	}
+	return nil
+}
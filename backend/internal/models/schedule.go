@@ -0,0 +1,87 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultMeetingDuration = 60      // Minutes planned when no duration or end time is given
+	MaxMeetingDuration     = 24 * 60 // Longest plannable meeting, in minutes
+	DefaultTimeZone        = "UTC"
+)
+
+// MeetingCategory groups meetings by where they are relative to now
+type MeetingCategory string
+
+const (
+	MeetingCategoryUpcoming   MeetingCategory = "upcoming"
+	MeetingCategoryInProgress MeetingCategory = "in-progress"
+	MeetingCategoryPast       MeetingCategory = "past"
+)
+
+// IsValid reports whether c is a known category
+func (c MeetingCategory) IsValid() bool {
+	switch c {
+	case MeetingCategoryUpcoming, MeetingCategoryInProgress, MeetingCategoryPast:
+		return true
+	}
+	return false
+}
+
+// MeetingListFilter narrows meeting lists to the meetings that overlap a
+// date range, either end of which may be open, and optionally to a category
+type MeetingListFilter struct {
+	From     *time.Time
+	To       *time.Time
+	Category MeetingCategory
+}
+
+// LoadTimeZone validates an IANA time zone name; an empty name is UTC
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	// time.LoadLocation also accepts "Local", which means nothing to clients
+	if name == "Local" {
+		return nil, errors.New("invalid time zone")
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("invalid time zone")
+	}
+	return location, nil
+}
+
+// Location returns the meeting's time zone, falling back to UTC
+func (m *Meeting) Location() *time.Location {
+	location, err := LoadTimeZone(m.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// CategoryAt classifies the meeting at now. A live meeting is in progress
+// whatever its planned times, and an ended one is past even if it ended
+// early; scheduled meetings are classified by their planned start and end.
+func (m *Meeting) CategoryAt(now time.Time) MeetingCategory {
+	switch m.Status {
+	case MeetingStatusLive:
+		return MeetingCategoryInProgress
+	case MeetingStatusEnded, MeetingStatusArchived:
+		return MeetingCategoryPast
+	}
+	switch {
+	case m.StartTime.After(now):
+		return MeetingCategoryUpcoming
+	case m.EndTime.After(now):
+		return MeetingCategoryInProgress
+	}
+	return MeetingCategoryPast
+}
+
+// Overlaps reports whether the meeting's planned time overlaps [start, end)
+func (m *Meeting) Overlaps(start, end time.Time) bool {
+	return m.StartTime.Before(end) && m.EndTime.After(start)
+}
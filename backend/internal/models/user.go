@@ -13,6 +13,7 @@ type User struct {
 	Email        string    `gorm:"uniqueIndex;not null;size:255" json:"email" validate:"required,email"`
 	PasswordHash string    `gorm:"not null;size:255" json:"-"`
 	AvatarURL    string    `gorm:"size:500" json:"avatarUrl,omitempty"`
+	TimeZone     string    `gorm:"size:64;not null;default:UTC" json:"timeZone"` // IANA name used for new meetings and displayed times
 	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
 
@@ -25,6 +26,7 @@ type UserResponse struct {
 	Username  string    `json:"username"`
 	Email     string    `json:"email"`
 	AvatarURL string    `json:"avatarUrl,omitempty"`
+	TimeZone  string    `json:"timeZone"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
@@ -50,6 +52,7 @@ func (u *User) ToResponse() UserResponse {
 		Username:  u.Username,
 		Email:     u.Email,
 		AvatarURL: u.AvatarURL,
+		TimeZone:  u.TimeZone,
 		CreatedAt: u.CreatedAt,
 	}
 }
@@ -59,5 +62,8 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.ID == uuid.Nil {
 		u.ID = uuid.New()
 	}
+	if u.TimeZone == "" {
+		u.TimeZone = DefaultTimeZone
+	}
 	return nil
 }
\ No newline at end of file
@@ -2,6 +2,8 @@ package models
 
 import (
 	"log"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,15 +21,46 @@ const (
 	SignalingTypeLeave      SignalingMessageType = "leave"
 	SignalingTypeParticipantJoined SignalingMessageType = "participant-joined"
 	SignalingTypeParticipantLeft  SignalingMessageType = "participant-left"
+	SignalingTypeParticipantUpdated SignalingMessageType = "participant-updated" // A participant's peer connection state changed
+	SignalingTypeMeetingStarted   SignalingMessageType = "meeting-started"
+	SignalingTypeMeetingEnded     SignalingMessageType = "meeting-ended"
+	SignalingTypeParticipantRoleChanged SignalingMessageType = "participant-role-changed"
+	SignalingTypeScreenShareStart SignalingMessageType = "screen-share-start"
+	SignalingTypeScreenShareStop  SignalingMessageType = "screen-share-stop"
+	SignalingTypeError            SignalingMessageType = "error"
+	SignalingTypeSignalingError   SignalingMessageType = "signaling-error" // An offer, answer or ICE candidate could not reach its target
+	SignalingTypeICERestartRequested SignalingMessageType = "ice-restart-requested" // The server asks a peer to restart ICE with another
+	SignalingTypeNegotiationFailed   SignalingMessageType = "negotiation-failed"    // The server gave up recovering the connection between two peers
+	SignalingTypeQualityStats        SignalingMessageType = "quality-stats"         // Sent by clients with their peer connection's getStats() summary
+	SignalingTypeForceMute          SignalingMessageType = "force-mute"
+	SignalingTypeParticipantRemoved SignalingMessageType = "participant-removed"
+	SignalingTypeMeetingLocked      SignalingMessageType = "meeting-locked"
+	SignalingTypeMeetingUnlocked    SignalingMessageType = "meeting-unlocked"
+	SignalingTypeLobbyRequest       SignalingMessageType = "lobby-request"
+	SignalingTypeLobbyAdmitted      SignalingMessageType = "lobby-admitted"
+	SignalingTypeLobbyDenied        SignalingMessageType = "lobby-denied"
 
 	// New chat message types
 	SignalingTypeChatMessage        SignalingMessageType = "chat-message"
 	SignalingTypeChatMessageEdit    SignalingMessageType = "chat-message-edit"
 	SignalingTypeChatMessageDelete  SignalingMessageType = "chat-message-delete"
 	SignalingTypeChatReaction       SignalingMessageType = "chat-reaction"
-	SignalingTypeChatReadStatus     SignalingMessageType = "chat-read-status"
 	SignalingTypeChatTyping         SignalingMessageType = "chat-typing"
 	SignalingTypeChatTypingStop     SignalingMessageType = "chat-typing-stop"
+	SignalingTypeChatThreadUpdated  SignalingMessageType = "chat-thread-updated"
+	SignalingTypeChatMuted          SignalingMessageType = "chat-muted"
+	SignalingTypeChatUnmuted        SignalingMessageType = "chat-unmuted"
+	SignalingTypeChatSlowMode       SignalingMessageType = "chat-slow-mode"
+	SignalingTypeChatMessageFlagged SignalingMessageType = "chat-message-flagged"
+	SignalingTypeChatPin            SignalingMessageType = "chat-pin"
+	SignalingTypeChatUnpin          SignalingMessageType = "chat-unpin"
+	SignalingTypeChatAcknowledged   SignalingMessageType = "chat-acknowledged"
+	SignalingTypeChatMarkRead       SignalingMessageType = "chat-mark-read"   // Sent by clients to move their read cursor
+	SignalingTypeChatReadCursor     SignalingMessageType = "chat-read-cursor" // A reader's cursor moved
+
+	// Session resumption
+	SignalingTypeResumed  SignalingMessageType = "resumed"  // Missed events were replayed to a reconnecting client
+	SignalingTypeSnapshot SignalingMessageType = "snapshot" // Too much was missed; the meeting's current state instead
 )
 
 // WebRTC signaling message structure
@@ -38,11 +71,13 @@ type SignalingMessage struct {
 	To        string               `json:"to"`        // Target participant ID (empty for broadcast)
 	Data      interface{}          `json:"data"`      // Message payload
 	Timestamp time.Time            `json:"timestamp"`
+	Seq       uint64               `json:"seq,omitempty"` // Position in the meeting's event log; set on replayable events only
 }
 
 // WebRTC offer/answer payload
 type OfferAnswerPayload struct {
-	SDP string `json:"sdp"`
+	SDP    string `json:"sdp"`
+	Polite *bool  `json:"polite,omitempty"` // Set by the server: whether the receiver is the polite peer of the pair
 }
 
 // WebRTC ICE candidate payload
@@ -52,12 +87,127 @@ type ICECandidatePayload struct {
 	SDPMid        string `json:"sdpMid"`
 }
 
+// Meeting lifecycle payload
+type MeetingStatusPayload struct {
+	MeetingID string        `json:"meetingId"`
+	Status    MeetingStatus `json:"status"`
+	StartedAt *time.Time    `json:"startedAt,omitempty"`
+	EndedAt   *time.Time    `json:"endedAt,omitempty"`
+	Reason    string        `json:"reason,omitempty"` // "host", "empty" or "participant-joined"
+}
+
+// Participant role change payload
+type RoleChangedPayload struct {
+	ParticipantID uuid.UUID       `json:"participantId"`
+	UserID        *uuid.UUID      `json:"userId,omitempty"`
+	PublicUserID  *uuid.UUID      `json:"publicUserId,omitempty"`
+	Role          ParticipantRole `json:"role"`
+	PreviousRole  ParticipantRole `json:"previousRole"`
+}
+
+// Error payload sent back to a client whose message was rejected
+type ErrorPayload struct {
+	Code        string               `json:"code"`
+	Message     string               `json:"message"`
+	MessageType SignalingMessageType `json:"messageType,omitempty"` // Type of the rejected message
+}
+
+// Signaling error payload, sent back to a client whose offer, answer or ICE
+// candidate could not be routed
+type SignalingErrorPayload struct {
+	Code        string               `json:"code"`
+	Message     string               `json:"message"`
+	MessageType SignalingMessageType `json:"messageType"`
+	To          string               `json:"to,omitempty"` // Target the message was meant for
+}
+
+// Moderation action payload
+type ModerationPayload struct {
+	Action        ModerationAction `json:"action"`
+	MeetingID     string           `json:"meetingId"`
+	ParticipantID *uuid.UUID       `json:"participantId,omitempty"`
+	UserID        *uuid.UUID       `json:"userId,omitempty"`
+	PublicUserID  *uuid.UUID       `json:"publicUserId,omitempty"`
+	Audio         bool             `json:"audio,omitempty"`
+	Video         bool             `json:"video,omitempty"`
+	Reason        string           `json:"reason,omitempty"`
+	ActorID       uuid.UUID        `json:"actorId"`
+	Until         *time.Time       `json:"until,omitempty"`    // End of a timed chat mute
+	SlowMode      *int             `json:"slowMode,omitempty"` // New slow mode interval in seconds
+}
+
+// Lobby payload for knock, admit and deny messages
+type LobbyPayload struct {
+	ParticipantID   uuid.UUID   `json:"participantId"`
+	UserID          *uuid.UUID  `json:"userId,omitempty"`
+	PublicUserID    *uuid.UUID  `json:"publicUserId,omitempty"`
+	Name            string      `json:"name"`
+	IsAuthenticated bool        `json:"isAuthenticated"`
+	Status          LobbyStatus `json:"status"`
+}
+
+// IsLobbySignalingType reports whether a message type is lobby traffic,
+// which is also delivered to clients waiting in the lobby
+func IsLobbySignalingType(messageType SignalingMessageType) bool {
+	switch messageType {
+	case SignalingTypeLobbyRequest, SignalingTypeLobbyAdmitted, SignalingTypeLobbyDenied:
+		return true
+	}
+	return false
+}
+
+// Resumed payload, sent after the events a reconnecting client missed
+type ResumedPayload struct {
+	FromSeq  uint64 `json:"fromSeq"` // Last event the client saw
+	ToSeq    uint64 `json:"toSeq"`   // Last event replayed; resume from here next time
+	Replayed int    `json:"replayed"`
+}
+
+// Snapshot payload, sent instead of the missed events when they are no
+// longer kept. Events numbered up to Seq are reflected in it.
+type SnapshotPayload struct {
+	Seq          uint64                `json:"seq"`
+	Status       MeetingStatus         `json:"status"`
+	IsLocked     bool                  `json:"isLocked"`
+	ChatSlowMode int                   `json:"chatSlowMode"`
+	Participants []JoinPayload         `json:"participants"`
+	Messages     []ChatMessageResponse `json:"messages"`   // The latest chat messages; older ones come from the chat history API
+	Pagination   ChatCursorPagination  `json:"pagination"` // Where Messages sit in the chat history
+	Pinned       []ChatMessageResponse `json:"pinned"`
+}
+
 // Participant join payload
 type JoinPayload struct {
-	ParticipantID   string `json:"participantId"`
-	Name            string `json:"name"`
-	AvatarURL       string `json:"avatarUrl,omitempty"`
-	IsAuthenticated bool   `json:"isAuthenticated"`
+	ParticipantID   string              `json:"participantId"`
+	Name            string              `json:"name"`
+	AvatarURL       string              `json:"avatarUrl,omitempty"`
+	IsAuthenticated bool                `json:"isAuthenticated"`
+	PeerState       PeerConnectionState `json:"peerState,omitempty"`
+}
+
+// Participant update payload, sent when a participant's peer connection
+// state changes
+type PeerStatePayload struct {
+	ParticipantID string              `json:"participantId"`
+	State         PeerConnectionState `json:"state"`
+	Codecs        map[string]string   `json:"codecs,omitempty"` // Media kind -> codec negotiated in the peer's latest answer
+	LastSeen      time.Time           `json:"lastSeen"`
+}
+
+// ICE restart request payload, sent to the impolite peer of a pair whose
+// connection stayed disconnected or failed for too long
+type ICERestartRequestPayload struct {
+	PeerID      string `json:"peerId"`      // Peer to restart ICE with
+	Attempt     int    `json:"attempt"`     // Restarts requested for the pair so far, this one included
+	MaxAttempts int    `json:"maxAttempts"`
+}
+
+// Negotiation failure payload, sent to both peers of a pair once the server
+// stops requesting ICE restarts for it
+type NegotiationFailedPayload struct {
+	PeerID   string `json:"peerId"` // The other peer of the pair
+	Attempts int    `json:"attempts"`
+	Reason   string `json:"reason"`
 }
 
 // Participant leave payload
@@ -123,18 +273,142 @@ type WebSocketClient struct {
 	PublicUserID *uuid.UUID
 	Name         string
 	IsAuth       bool
+	JoinedAt     time.Time
 	Conn         *websocket.Conn
 	Send         chan SignalingMessage
 	Hub          *WebSocketHub
+
+	stateMu        sync.RWMutex // guards role, pending, the chat mute and the peer state
+	role           ParticipantRole
+	pending        bool // waiting in the lobby
+	chatMuted      bool
+	chatMutedUntil *time.Time // end of a timed chat mute
+	peerState      PeerConnectionState
+	peerCodecs     map[string]string // media kind -> negotiated codec
+	peerSeen       time.Time         // when the peer state last changed
+}
+
+// Role returns the client's current meeting role
+func (c *WebSocketClient) Role() ParticipantRole {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+	return c.role
+}
+
+// SetRole updates the client's meeting role
+func (c *WebSocketClient) SetRole(role ParticipantRole) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	c.role = role
+}
+
+// IsChatMuted reports whether a moderator muted the client in chat
+func (c *WebSocketClient) IsChatMuted() bool {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+	return c.chatMuted && (c.chatMutedUntil == nil || time.Now().Before(*c.chatMutedUntil))
+}
+
+// SetChatMute mutes or unmutes the client in chat; a nil until mutes it
+// until lifted
+func (c *WebSocketClient) SetChatMute(muted bool, until *time.Time) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	c.chatMuted = muted
+	c.chatMutedUntil = until
+}
+
+// IsPending reports whether the client is waiting in the lobby
+func (c *WebSocketClient) IsPending() bool {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+	return c.pending
+}
+
+// SetPending marks the client as waiting in the lobby. It must be set before
+// the client is registered; use WebSocketHub.AdmitClient to admit it.
+func (c *WebSocketClient) SetPending(pending bool) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	c.pending = pending
+}
+
+// PeerState returns the state of the client's WebRTC peer connection and
+// when it last changed
+func (c *WebSocketClient) PeerState() (PeerConnectionState, time.Time) {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+	if c.peerState == "" {
+		return PeerStateNew, c.JoinedAt
+	}
+	return c.peerState, c.peerSeen
+}
+
+// SetPeerState records the state of the client's WebRTC peer connection
+func (c *WebSocketClient) SetPeerState(state PeerConnectionState, at time.Time) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	c.peerState = state
+	c.peerSeen = at
+}
+
+// PeerCodecs returns the codecs negotiated by the client's WebRTC peer
+// connection, by media kind
+func (c *WebSocketClient) PeerCodecs() map[string]string {
+	c.stateMu.RLock()
+	defer c.stateMu.RUnlock()
+	return c.peerCodecs
+}
+
+// SetPeerCodecs records the codecs negotiated by the client's WebRTC peer
+// connection. The map must not be modified afterwards.
+func (c *WebSocketClient) SetPeerCodecs(codecs map[string]string) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	c.peerCodecs = codecs
+}
+
+// Matches reports whether the client belongs to the given user or public user
+func (c *WebSocketClient) Matches(userID, publicUserID *uuid.UUID) bool {
+	if userID != nil && c.UserID != nil && *userID == *c.UserID {
+		return true
+	}
+	return publicUserID != nil && c.PublicUserID != nil && *publicUserID == *c.PublicUserID
+}
+
+// ToPresence returns the cluster presence entry for the client
+func (c *WebSocketClient) ToPresence(nodeID string) HubPresence {
+	peerState, lastSeen := c.PeerState()
+	return HubPresence{
+		ClientID:     c.ID,
+		MeetingID:    c.MeetingID,
+		NodeID:       nodeID,
+		UserID:       c.UserID,
+		PublicUserID: c.PublicUserID,
+		Name:         c.Name,
+		IsAuth:       c.IsAuth,
+		Role:         c.Role(),
+		JoinedAt:     c.JoinedAt,
+		PeerState:    peerState,
+		Codecs:       c.PeerCodecs(),
+		LastSeen:     lastSeen,
+	}
 }
 
 // WebSocket hub manages clients and message broadcasting
 type WebSocketHub struct {
 	Clients    map[string]*WebSocketClient // clientID -> client
 	Meetings   map[string]map[string]*WebSocketClient // meetingID -> clientID -> client
+	Lobbies    map[string]map[string]*WebSocketClient // meetingID -> clientID -> client waiting for admission
 	Register   chan *WebSocketClient
 	Unregister chan *WebSocketClient
 	Broadcast  chan SignalingMessage
+
+	mu     sync.RWMutex // guards Clients, Meetings and Lobbies
+	broker HubBroker    // nil in single-node mode
+
+	eventMu sync.Mutex  // numbers and delivers logged events one at a time, so they go out in order
+	events  HubEventLog // nil when events are not kept for replay
 }
 
 // NewWebSocketHub creates a new WebSocket hub
@@ -142,12 +416,28 @@ func NewWebSocketHub() *WebSocketHub {
 	return &WebSocketHub{
 		Clients:    make(map[string]*WebSocketClient),
 		Meetings:   make(map[string]map[string]*WebSocketClient),
+		Lobbies:    make(map[string]map[string]*WebSocketClient),
 		Register:   make(chan *WebSocketClient),
 		Unregister: make(chan *WebSocketClient),
 		Broadcast:  make(chan SignalingMessage),
 	}
 }
 
+// SetBroker attaches a cluster broker to the hub. It must be called before Run.
+func (h *WebSocketHub) SetBroker(broker HubBroker) error {
+	h.broker = broker
+	if broker == nil {
+		return nil
+	}
+	return broker.Subscribe(h.deliverRemote)
+}
+
+// SetEventLog keeps the meetings' replayable events in events, numbering
+// them with sequence numbers. It must be called before Run.
+func (h *WebSocketHub) SetEventLog(events HubEventLog) {
+	h.events = events
+}
+
 // Run starts the WebSocket hub
 func (h *WebSocketHub) Run() {
 	for {
@@ -169,14 +459,63 @@ func (h *WebSocketHub) registerClient(client *WebSocketClient) {
 	log.Printf("[DEBUG] Registering client: %s (name: %s) to meeting: %s", client.ID, client.Name, client.MeetingID)
 	
 	// VALIDASI DUPLICATE CLIENT
-	if existingClient, exists := h.Clients[client.ID]; exists {
+	h.mu.RLock()
+	existingClient, exists := h.Clients[client.ID]
+	h.mu.RUnlock()
+	if exists {
 		log.Printf("[DEBUG] Duplicate client registration detected: %s", client.ID)
 		log.Printf("[DEBUG] Force cleanup existing client: %s (meeting: %s)", existingClient.ID, existingClient.MeetingID)
 		h.unregisterClient(existingClient) // Force cleanup existing client
 	}
+
+	if client.JoinedAt.IsZero() {
+		client.JoinedAt = time.Now()
+	}
 	
+	h.mu.Lock()
 	h.Clients[client.ID] = client
-	
+
+	// Clients waiting in the lobby stay out of the meeting until admitted
+	if client.IsPending() {
+		if h.Lobbies[client.MeetingID] == nil {
+			h.Lobbies[client.MeetingID] = make(map[string]*WebSocketClient)
+		}
+		h.Lobbies[client.MeetingID][client.ID] = client
+		h.mu.Unlock()
+		log.Printf("[DEBUG] Client %s is waiting in the lobby of meeting: %s", client.ID, client.MeetingID)
+		return
+	}
+	h.addToMeetingLocked(client)
+	h.mu.Unlock()
+
+	h.announceJoin(client)
+}
+
+// AdmitClient moves a client from the lobby into its meeting. It reports
+// whether the client was waiting in the lobby of this node.
+func (h *WebSocketHub) AdmitClient(client *WebSocketClient) bool {
+	h.mu.Lock()
+	lobby := h.Lobbies[client.MeetingID]
+	if lobby == nil || lobby[client.ID] != client {
+		h.mu.Unlock()
+		return false
+	}
+	delete(lobby, client.ID)
+	if len(lobby) == 0 {
+		delete(h.Lobbies, client.MeetingID)
+	}
+	client.SetPending(false)
+	h.addToMeetingLocked(client)
+	h.mu.Unlock()
+
+	log.Printf("[DEBUG] Client %s admitted to meeting: %s", client.ID, client.MeetingID)
+	h.announceJoin(client)
+	return true
+}
+
+// addToMeetingLocked adds a client to its meeting room. The caller must hold
+// h.mu.
+func (h *WebSocketHub) addToMeetingLocked(client *WebSocketClient) {
 	// Add client to meeting
 	if h.Meetings[client.MeetingID] == nil {
 		h.Meetings[client.MeetingID] = make(map[string]*WebSocketClient)
@@ -184,6 +523,17 @@ func (h *WebSocketHub) registerClient(client *WebSocketClient) {
 	}
 	h.Meetings[client.MeetingID][client.ID] = client
 	log.Printf("[DEBUG] Added client to meeting. Total clients in meeting %s: %d", client.MeetingID, len(h.Meetings[client.MeetingID]))
+}
+
+// announceJoin publishes a client's presence and tells the rest of the
+// meeting that it joined
+func (h *WebSocketHub) announceJoin(client *WebSocketClient) {
+	// Announce presence to the rest of the cluster
+	if h.broker != nil {
+		if err := h.broker.AddPresence(client.ToPresence(h.broker.NodeID())); err != nil {
+			log.Printf("[ERROR] Failed to register presence for client %s: %v", client.ID, err)
+		}
+	}
 	
 	// Notify other participants about new join
 	joinMessage := SignalingMessage{
@@ -195,6 +545,7 @@ func (h *WebSocketHub) registerClient(client *WebSocketClient) {
 			Name:            client.Name,
 			AvatarURL:       "", // Will be populated from user data
 			IsAuthenticated: client.IsAuth,
+			PeerState:       PeerStateNew,
 		},
 		Timestamp: time.Now(),
 	}
@@ -206,118 +557,329 @@ func (h *WebSocketHub) registerClient(client *WebSocketClient) {
 // unregisterClient removes a client from the hub
 func (h *WebSocketHub) unregisterClient(client *WebSocketClient) {
 	log.Printf("[DEBUG] Unregistering client: %s (name: %s) from meeting: %s", client.ID, client.Name, client.MeetingID)
-	if _, ok := h.Clients[client.ID]; ok {
-		delete(h.Clients, client.ID)
-		
-		// Remove from meeting
-		if meetingClients, ok := h.Meetings[client.MeetingID]; ok {
-			delete(meetingClients, client.ID)
-			log.Printf("[DEBUG] Removed client from meeting. Remaining clients in meeting %s: %d", client.MeetingID, len(meetingClients))
-			
-			// Clean up empty meeting
-			if len(meetingClients) == 0 {
-				delete(h.Meetings, client.MeetingID)
-				log.Printf("[DEBUG] Cleaned up empty meeting: %s", client.MeetingID)
-			}
+	h.mu.Lock()
+	// Only remove the exact client instance; a newer connection may have
+	// replaced it under the same ID
+	current, ok := h.Clients[client.ID]
+	if !ok || current != client {
+		h.mu.Unlock()
+		return
+	}
+	h.removeClientLocked(client)
+	h.mu.Unlock()
+
+	// Nobody was told about a client that never left the lobby
+	if client.IsPending() {
+		return
+	}
+
+	if h.broker != nil {
+		if err := h.broker.RemovePresence(client.MeetingID, client.ID); err != nil {
+			log.Printf("[ERROR] Failed to remove presence for client %s: %v", client.ID, err)
 		}
+	}
 		
-		// Close connection
-		close(client.Send)
+	// Notify other participants about leave
+	leaveMessage := SignalingMessage{
+		Type:      SignalingTypeParticipantLeft,
+		MeetingID: client.MeetingID,
+		From:      client.ID,
+		Data: LeavePayload{
+			ParticipantID: client.ID,
+		},
+		Timestamp: time.Now(),
+	}
 		
-		// Notify other participants about leave
-		leaveMessage := SignalingMessage{
-			Type:      SignalingTypeParticipantLeft,
-			MeetingID: client.MeetingID,
-			From:      client.ID,
-			Data: LeavePayload{
-				ParticipantID: client.ID,
-			},
-			Timestamp: time.Now(),
+	log.Printf("[DEBUG] Broadcasting participant-left message for: %s to meeting: %s", client.ID, client.MeetingID)
+	h.broadcastToMeeting(client.MeetingID, leaveMessage, client.ID)
+}
+
+// removeClientLocked drops a client from the maps and closes its send
+// channel. The caller must hold h.mu.
+func (h *WebSocketHub) removeClientLocked(client *WebSocketClient) {
+	delete(h.Clients, client.ID)
+
+	// Remove from meeting
+	if meetingClients, ok := h.Meetings[client.MeetingID]; ok {
+		delete(meetingClients, client.ID)
+		log.Printf("[DEBUG] Removed client from meeting. Remaining clients in meeting %s: %d", client.MeetingID, len(meetingClients))
+
+		// Clean up empty meeting
+		if len(meetingClients) == 0 {
+			delete(h.Meetings, client.MeetingID)
+			log.Printf("[DEBUG] Cleaned up empty meeting: %s", client.MeetingID)
+		}
+	}
+	if lobbyClients, ok := h.Lobbies[client.MeetingID]; ok {
+		delete(lobbyClients, client.ID)
+		if len(lobbyClients) == 0 {
+			delete(h.Lobbies, client.MeetingID)
 		}
-		
-		log.Printf("[DEBUG] Broadcasting participant-left message for: %s to meeting: %s", client.ID, client.MeetingID)
-		h.broadcastToMeeting(client.MeetingID, leaveMessage, client.ID)
 	}
+
+	// Close connection
+	close(client.Send)
 }
 
-// broadcastMessage handles message broadcasting
+// broadcastMessage handles message broadcasting. Peer signaling is only
+// ever routed to its target, within its meeting; everything else goes to
+// the whole meeting, whatever its To says.
 func (h *WebSocketHub) broadcastMessage(message SignalingMessage) {
-	if message.To == "" {
-		// Broadcast to all participants in the meeting
-		h.broadcastToMeeting(message.MeetingID, message, "")
-	} else {
-		// Send to specific participant
-		h.sendToClient(message.To, message)
+	if IsDirectedSignalingType(message.Type) {
+		if err := h.RouteSignal(message); err != nil {
+			log.Printf("[DEBUG] Dropped message type: %s from client %s to %s: %v", message.Type, message.From, message.To, err)
+		}
+		return
 	}
+
+	// Broadcast to all participants in the meeting
+	message.To = ""
+	h.broadcastToMeeting(message.MeetingID, message, "")
 }
 
-// broadcastToMeeting sends message to all clients in a meeting except the sender
+// BroadcastToMeeting sends message to all clients in a meeting except the
+// excluded one, on every node of the cluster
+func (h *WebSocketHub) BroadcastToMeeting(meetingID string, message SignalingMessage, excludeClientID string) {
+	h.broadcastToMeeting(meetingID, message, excludeClientID)
+}
+
+// SendToClient sends message to a specific client, wherever it is connected
+func (h *WebSocketHub) SendToClient(clientID string, message SignalingMessage) error {
+	return h.sendToClient(clientID, message)
+}
+
+// broadcastToMeeting sends message to all clients in a meeting except the
+// sender. Replayable events are first numbered and logged.
 func (h *WebSocketHub) broadcastToMeeting(meetingID string, message SignalingMessage, excludeClientID string) {
+	if h.events != nil && IsReplayableSignalingType(message.Type) {
+		h.eventMu.Lock()
+		defer h.eventMu.Unlock()
+
+		message.MeetingID = meetingID
+		seq, err := h.events.Append(HubEnvelope{Message: message, ExcludeClientID: excludeClientID})
+		if err != nil {
+			log.Printf("[ERROR] Failed to log message type: %s for meeting: %s: %v", message.Type, meetingID, err)
+		} else {
+			message.Seq = seq
+		}
+	}
+
+	h.deliverToMeeting(meetingID, message, excludeClientID)
+
+	if h.broker != nil {
+		envelope := HubEnvelope{
+			NodeID:          h.broker.NodeID(),
+			Message:         message,
+			ExcludeClientID: excludeClientID,
+		}
+		if err := h.broker.PublishMeeting(envelope); err != nil {
+			log.Printf("[ERROR] Failed to publish message type: %s for meeting: %s: %v", message.Type, meetingID, err)
+		}
+	}
+}
+
+// sendToClient sends message to a specific client
+func (h *WebSocketHub) sendToClient(clientID string, message SignalingMessage) error {
+	if h.deliverToClient(clientID, message) {
+		return nil
+	}
+
+	if h.broker == nil {
+		return ErrHubClientNotFound
+	}
+
+	envelope := HubEnvelope{
+		NodeID:  h.broker.NodeID(),
+		Message: message,
+	}
+	return h.broker.PublishClient(clientID, envelope)
+}
+
+// deliverRemote handles an envelope published by another node
+func (h *WebSocketHub) deliverRemote(envelope HubEnvelope) {
+	if h.broker != nil && envelope.NodeID == h.broker.NodeID() {
+		return
+	}
+
+	if envelope.TargetClientID != "" {
+		if IsDirectedSignalingType(envelope.Message.Type) {
+			h.deliverSignal(envelope.TargetClientID, envelope.Message)
+			return
+		}
+		h.deliverToClient(envelope.TargetClientID, envelope.Message)
+		return
+	}
+	h.deliverToMeeting(envelope.Message.MeetingID, envelope.Message, envelope.ExcludeClientID)
+}
+
+// deliverToMeeting sends message to the local clients of a meeting
+func (h *WebSocketHub) deliverToMeeting(meetingID string, message SignalingMessage, excludeClientID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	if meetingClients, ok := h.Meetings[meetingID]; ok {
 		log.Printf("[DEBUG] Broadcasting message type: %s to %d clients in meeting: %s (excluding: %s)",
 			message.Type, len(meetingClients), meetingID, excludeClientID)
-		for clientID := range meetingClients {
+		for clientID, client := range meetingClients {
 			if clientID != excludeClientID {
-				h.sendToClient(clientID, message)
+				h.deliverLocked(client, message)
 			}
 		}
 	} else {
 		log.Printf("[DEBUG] No meeting clients found for meeting: %s when broadcasting message type: %s", meetingID, message.Type)
 	}
-}
 
-// sendToClient sends message to a specific client
-func (h *WebSocketHub) sendToClient(clientID string, message SignalingMessage) {
-	if client, ok := h.Clients[clientID]; ok {
-		select {
-		case client.Send <- message:
-		default:
-			// Client send channel is blocked, close connection
-			close(client.Send)
-			delete(h.Clients, clientID)
-			
-			// Remove from meeting
-			if meetingClients, ok := h.Meetings[client.MeetingID]; ok {
-				delete(meetingClients, clientID)
-				if len(meetingClients) == 0 {
-					delete(h.Meetings, client.MeetingID)
-				}
+	// Lobby traffic also reaches the clients waiting for admission
+	if IsLobbySignalingType(message.Type) {
+		for clientID, client := range h.Lobbies[meetingID] {
+			if clientID != excludeClientID {
+				h.deliverLocked(client, message)
 			}
 		}
 	}
 }
 
-// GetMeetingParticipants returns all active participants in a meeting
-func (h *WebSocketHub) GetMeetingParticipants(meetingID string) []*WebSocketClient {
-	var participants []*WebSocketClient
+// deliverToClient sends message to a client connected to this node. It
+// reports whether the client was found locally.
+func (h *WebSocketHub) deliverToClient(clientID string, message SignalingMessage) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client, ok := h.Clients[clientID]
+	if !ok {
+		return false
+	}
+	h.deliverLocked(client, message)
+	return true
+}
+
+// deliverLocked queues message on the client's send channel. The caller
+// must hold h.mu.
+func (h *WebSocketHub) deliverLocked(client *WebSocketClient, message SignalingMessage) {
+	select {
+	case client.Send <- message:
+	default:
+		// Client send channel is blocked, close connection
+		h.removeClientLocked(client)
+		if h.broker != nil {
+			go h.broker.RemovePresence(client.MeetingID, client.ID)
+		}
+	}
+}
+
+// EventsSince returns the events of a meeting numbered after seq that
+// clientID should have received, oldest first, and the sequence number of
+// the latest event it went through, whether the client was left out of it
+// or not. It reports false when some events are no longer kept, or when the
+// hub keeps no events.
+func (h *WebSocketHub) EventsSince(meetingID, clientID string, seq uint64) ([]SignalingMessage, uint64, bool) {
+	if h.events == nil {
+		return nil, 0, false
+	}
+	envelopes, complete, err := h.events.Since(meetingID, seq)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load events of meeting %s: %v", meetingID, err)
+		return nil, 0, false
+	}
+	if !complete {
+		return nil, 0, false
+	}
+
+	messages := make([]SignalingMessage, 0, len(envelopes))
+	for _, envelope := range envelopes {
+		if envelope.ExcludeClientID != clientID {
+			messages = append(messages, envelope.Message)
+		}
+	}
+	return messages, seq + uint64(len(envelopes)), true
+}
+
+// LastSeq returns the sequence number of a meeting's latest logged event,
+// or 0 when the hub keeps no events
+func (h *WebSocketHub) LastSeq(meetingID string) uint64 {
+	if h.events == nil {
+		return 0
+	}
+	seq, err := h.events.LastSeq(meetingID)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load the last event of meeting %s: %v", meetingID, err)
+		return 0
+	}
+	return seq
+}
+
+// GetMeetingParticipants returns all active participants in a meeting,
+// including clients connected to other nodes
+func (h *WebSocketHub) GetMeetingParticipants(meetingID string) []HubPresence {
+	if h.broker != nil {
+		presences, err := h.broker.MeetingPresence(meetingID)
+		if err == nil {
+			return presences
+		}
+		log.Printf("[ERROR] Failed to load cluster presence for meeting %s, using local clients: %v", meetingID, err)
+	}
+
+	return h.localParticipants(meetingID)
+}
+
+// RefreshPresence republishes a local client's presence entry after its
+// details (such as its role) changed
+func (h *WebSocketHub) RefreshPresence(client *WebSocketClient) {
+	if h.broker == nil {
+		return
+	}
+
+	h.mu.RLock()
+	registered := h.Clients[client.ID] == client
+	h.mu.RUnlock()
+	if !registered {
+		return
+	}
+
+	if err := h.broker.AddPresence(client.ToPresence(h.broker.NodeID())); err != nil {
+		log.Printf("[ERROR] Failed to refresh presence for client %s: %v", client.ID, err)
+	}
+}
+
+// localParticipants returns the participants connected to this node
+func (h *WebSocketHub) localParticipants(meetingID string) []HubPresence {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	nodeID := ""
+	if h.broker != nil {
+		nodeID = h.broker.NodeID()
+	}
+
+	var participants []HubPresence
 	if meetingClients, ok := h.Meetings[meetingID]; ok {
 		for _, client := range meetingClients {
-			participants = append(participants, client)
+			participants = append(participants, client.ToPresence(nodeID))
 		}
 	}
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].JoinedAt.Before(participants[j].JoinedAt)
+	})
 	return participants
 }
 
-// GetClientByID returns a client by ID
+// GetClientByID returns a client connected to this node by ID
 func (h *WebSocketHub) GetClientByID(clientID string) (*WebSocketClient, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
 	client, ok := h.Clients[clientID]
 	return client, ok
 }
 
 // IsMeetingActive checks if a meeting has active participants
 func (h *WebSocketHub) IsMeetingActive(meetingID string) bool {
-	if meetingClients, ok := h.Meetings[meetingID]; ok {
-		return len(meetingClients) > 0
-	}
-	return false
+	return h.GetParticipantCount(meetingID) > 0
 }
 
 // GetParticipantCount returns the number of active participants in a meeting
 func (h *WebSocketHub) GetParticipantCount(meetingID string) int {
-	if meetingClients, ok := h.Meetings[meetingID]; ok {
-		return len(meetingClients)
-	}
-	return 0
+	return len(h.GetMeetingParticipants(meetingID))
 }
 
 // WebRTC peer connection state
@@ -341,22 +903,26 @@ type WebRTCPeer struct {
 	Name         string               `json:"name"`
 	IsAuth       bool                 `json:"isAuth"`
 	State        PeerConnectionState  `json:"state"`
+	Codecs       map[string]string    `json:"codecs,omitempty"` // Media kind -> negotiated codec
 	JoinedAt     time.Time            `json:"joinedAt"`
 	LastSeen     time.Time            `json:"lastSeen"`
 }
 
 // WebRTC signaling request/response types
 type WebRTCOfferRequest struct {
-	To    string             `json:"to" validate:"required"`
-	Offer OfferAnswerPayload `json:"offer" validate:"required"`
+	PeerID string             `json:"peerId,omitempty"` // Client ID of the sender's WebSocket connection; defaults to the user's first one
+	To     string             `json:"to" validate:"required"`
+	Offer  OfferAnswerPayload `json:"offer" validate:"required"`
 }
 
 type WebRTCAnswerRequest struct {
+	PeerID string             `json:"peerId,omitempty"` // Client ID of the sender's WebSocket connection; defaults to the user's first one
 	To     string             `json:"to" validate:"required"`
 	Answer OfferAnswerPayload `json:"answer" validate:"required"`
 }
 
 type WebRTCIceCandidateRequest struct {
+	PeerID    string              `json:"peerId,omitempty"` // Client ID of the sender's WebSocket connection; defaults to the user's first one
 	To        string              `json:"to" validate:"required"`
 	Candidate ICECandidatePayload `json:"candidate" validate:"required"`
 }
@@ -368,6 +934,7 @@ type WebRTCPeerResponse struct {
 	UserID         *uuid.UUID        `json:"userId,omitempty"`
 	PublicUserID   *uuid.UUID        `json:"publicUserId,omitempty"`
 	State          PeerConnectionState `json:"state"`
+	Codecs         map[string]string `json:"codecs,omitempty"`
 	JoinedAt       time.Time         `json:"joinedAt"`
 	LastSeen       time.Time         `json:"lastSeen"`
 }
@@ -383,70 +950,3 @@ type WebRTCSignalingRequest struct {
 	To        string               `json:"to" validate:"required"`
 	Data      interface{}          `json:"data" validate:"required"`
 }
-
-// WebRTC room management
-type WebRTCRoom struct {
-	ID           string                    `json:"id"`
-	MeetingID    string                    `json:"meetingId"`
-	Peers        map[string]*WebRTCPeer    `json:"peers"`
-	CreatedAt    time.Time                 `json:"createdAt"`
-	LastActivity time.Time                `json:"lastActivity"`
-}
-
-// NewWebRTCRoom creates a new WebRTC room
-func NewWebRTCRoom(meetingID string) *WebRTCRoom {
-	now := time.Now()
-	return &WebRTCRoom{
-		ID:           uuid.New().String(),
-		MeetingID:    meetingID,
-		Peers:        make(map[string]*WebRTCPeer),
-		CreatedAt:    now,
-		LastActivity: now,
-	}
-}
-
-// AddPeer adds a peer to the room
-func (r *WebRTCRoom) AddPeer(peer *WebRTCPeer) {
-	r.Peers[peer.ID] = peer
-	r.LastActivity = time.Now()
-}
-
-// RemovePeer removes a peer from the room
-func (r *WebRTCRoom) RemovePeer(peerID string) {
-	delete(r.Peers, peerID)
-	r.LastActivity = time.Now()
-}
-
-// GetPeer returns a peer by ID
-func (r *WebRTCRoom) GetPeer(peerID string) (*WebRTCPeer, bool) {
-	peer, exists := r.Peers[peerID]
-	return peer, exists
-}
-
-// GetAllPeers returns all peers in the room
-func (r *WebRTCRoom) GetAllPeers() []*WebRTCPeer {
-	var peers []*WebRTCPeer
-	for _, peer := range r.Peers {
-		peers = append(peers, peer)
-	}
-	return peers
-}
-
-// GetPeerCount returns the number of peers in the room
-func (r *WebRTCRoom) GetPeerCount() int {
-	return len(r.Peers)
-}
-
-// UpdatePeerState updates a peer's connection state
-func (r *WebRTCRoom) UpdatePeerState(peerID string, state PeerConnectionState) {
-	if peer, exists := r.Peers[peerID]; exists {
-		peer.State = state
-		peer.LastSeen = time.Now()
-		r.LastActivity = time.Now()
-	}
-}
-
-// IsEmpty checks if the room has no peers
-func (r *WebRTCRoom) IsEmpty() bool {
-	return len(r.Peers) == 0
-}
\ No newline at end of file
@@ -1,6 +1,8 @@
 package routes
 
 import (
+	"log"
+
 	"gorm.io/gorm"
 
 	"github.com/gin-gonic/gin"
@@ -11,12 +13,16 @@ import (
 	"github.com/your-org/gomeet-backend/internal/config"
 	"github.com/your-org/gomeet-backend/internal/controllers"
 	"github.com/your-org/gomeet-backend/internal/middleware"
+	"github.com/your-org/gomeet-backend/internal/models"
 	"github.com/your-org/gomeet-backend/internal/services"
 
 	"github.com/redis/go-redis/v9"
 )
 
-func Setup(db *gorm.DB, cfg config.Config) *gin.Engine {
+// Setup builds the router and its services. The returned shutdown function
+// stops their background work and leaves the hub cluster; call it once the
+// server stopped serving.
+func Setup(db *gorm.DB, cfg config.Config) (*gin.Engine, func()) {
 	router := gin.New()
 
 	// Add middleware
@@ -35,19 +41,78 @@ func Setup(db *gorm.DB, cfg config.Config) *gin.Engine {
 	// Initialize services
 	jwtService := services.NewJWTService(cfg.JWT)
 	authService := services.NewAuthService(db, jwtService)
-	meetingService := services.NewMeetingService(db)
+	jwtService.SetSessionChecker(authService)
 	publicUserService := services.NewPublicUserService(db)
 	
 	// Initialize WebSocket service first without WebRTC dependency
 	websocketService := services.NewWebSocketService(db, jwtService, nil)
 	
+	// Initialize role service; every in-meeting permission check goes through it
+	roleService := services.NewRoleService(db, websocketService)
+	websocketService.SetRoleService(roleService)
+	
+	// Initialize meeting service; it broadcasts lifecycle changes over the WebSocket hub
+	meetingService := services.NewMeetingService(db, websocketService, roleService)
+	websocketService.SetMeetingService(meetingService)
+	
 	// Initialize WebRTC service
 	webrtcService := services.NewWebRTCService(db, websocketService)
+	webrtcService.SetMeetingService(meetingService, cfg.Meeting.EmptyGracePeriod)
+	webrtcService.SetICERestartPolicy(cfg.WebRTC.ICERestartTimeout, cfg.WebRTC.MaxRenegotiationAttempts)
 	
 	// Set WebRTC service reference in WebSocket service (breaking circular dependency)
 	websocketService.SetWebRTCService(webrtcService)
 	
-	chatService := services.NewChatService(db, websocketService, publicUserService)
+	chatService := services.NewChatService(db, websocketService, publicUserService, roleService)
+	websocketService.SetChatService(chatService)
+	
+	// Initialize attachment storage and service; chat messages carry files uploaded through it
+	attachmentStorage, err := services.NewAttachmentStorage(cfg.Attachment)
+	if err != nil {
+		panic("Failed to initialize attachment storage: " + err.Error())
+	}
+	attachmentService := services.NewAttachmentService(db, attachmentStorage, roleService, cfg.Attachment)
+	chatService.SetAttachmentService(attachmentService)
+	attachmentService.StartPruning()
+	
+	// Initialize the chat content filter from the configured word list and rules
+	chatFilter, err := services.NewChatContentFilter(cfg.Chat)
+	if err != nil {
+		panic("Failed to initialize chat content filter: " + err.Error())
+	}
+	if chatFilter != nil {
+		chatService.SetContentFilter(chatFilter)
+	}
+	emojiSet, err := services.NewEmojiSet(cfg.Chat)
+	if err != nil {
+		panic("Failed to initialize custom emoji: " + err.Error())
+	}
+	chatService.SetEmojiSet(emojiSet)
+	moderationService := services.NewModerationService(db, websocketService, roleService)
+	
+	// Initialize the media policy service; offers, answers and ICE candidates are checked and rewritten through it
+	mediaPolicyService, err := services.NewMediaPolicyService(db, roleService, cfg.WebRTC)
+	if err != nil {
+		panic("Failed to initialize media policy: " + err.Error())
+	}
+	websocketService.SetMediaPolicyService(mediaPolicyService)
+	
+	// Initialize the quality service; clients report their connection stats through it
+	qualityService := services.NewQualityService(db, roleService, cfg.WebRTC)
+	websocketService.SetQualityService(qualityService)
+	webrtcService.SetQualityService(qualityService)
+	
+	// Initialize lobby service; joins and WebSocket connects hold newcomers through it
+	lobbyService := services.NewLobbyService(db, websocketService, roleService)
+	websocketService.SetLobbyService(lobbyService)
+	meetingService.SetLobbyService(lobbyService)
+	publicUserService.SetLobbyService(lobbyService)
+	
+	// Initialize meeting access service; joins and WebSocket connects check passcodes and invites through it
+	accessService := services.NewMeetingAccessService(db, jwtService, roleService, cfg.Meeting.PasscodeMaxAttempts, cfg.Meeting.PasscodeLockout)
+	websocketService.SetAccessService(accessService)
+	meetingService.SetAccessService(accessService)
+	publicUserService.SetAccessService(accessService)
 	
 	// TEMPORARILY DISABLED FOR EMERGENCY WEBSOCKET FIX
 	// Initialize LiveKit service
@@ -63,6 +128,9 @@ func Setup(db *gorm.DB, cfg config.Config) *gin.Engine {
 	// 	panic("Failed to initialize LiveKit service: " + err.Error())
 	// }
 	
+	// Initialize calendar service; it exports meetings and serves subscription feeds
+	calendarService := services.NewCalendarService(db, meetingService, roleService, cfg.Meeting.JoinURLBase, cfg.Meeting.CalendarFeedURLBase)
+	
 	// Initialize feature flag service
 	featureFlagService := services.NewFeatureFlagService(redisClient, db)
 	
@@ -70,16 +138,34 @@ func Setup(db *gorm.DB, cfg config.Config) *gin.Engine {
 	// Initialize TURN service
 	// turnService := services.NewTurnService(db, redisClient, cfg.TURN.Secret, cfg.TURN.Server)
 
+	// Attach the Redis hub broker when running several backend nodes; the
+	// nodes then share the event log reconnecting clients resume from
+	var hubBroker *services.RedisHubBroker
+	if cfg.WebSocket.HubBackend == "redis" {
+		hubBroker = services.NewRedisHubBroker(redisClient, cfg.WebSocket.NodeID)
+		if err := websocketService.SetHubBroker(hubBroker); err != nil {
+			panic("Failed to initialize WebSocket hub broker: " + err.Error())
+		}
+		websocketService.SetEventLog(services.NewRedisHubEventLog(redisClient, cfg.WebSocket.EventLogSize, cfg.WebSocket.EventLogRetention))
+	} else {
+		websocketService.SetEventLog(models.NewMemoryHubEventLog(cfg.WebSocket.EventLogSize, cfg.WebSocket.EventLogRetention))
+	}
+
 	// Start WebSocket hub
 	websocketService.StartHub()
 
 	// Initialize controllers
 	authController := controllers.NewAuthController(authService)
-	meetingController := controllers.NewMeetingController(meetingService)
+	meetingController := controllers.NewMeetingController(meetingService, roleService)
 	publicUserController := controllers.NewPublicUserController(publicUserService)
-	websocketController := controllers.NewWebSocketController(websocketService, db)
-	webrtcController := controllers.NewWebRTCController(webrtcService, db)
+	websocketController := controllers.NewWebSocketController(websocketService, roleService, db)
+	webrtcController := controllers.NewWebRTCController(webrtcService, roleService, mediaPolicyService, qualityService, db)
 	chatController := controllers.NewChatController(chatService)
+	attachmentController := controllers.NewAttachmentController(attachmentService, publicUserService, attachmentStorage)
+	moderationController := controllers.NewModerationController(moderationService)
+	lobbyController := controllers.NewLobbyController(lobbyService)
+	inviteController := controllers.NewInviteController(accessService)
+	calendarController := controllers.NewCalendarController(calendarService)
 	// livekitController := controllers.NewLiveKitController(livekitService)
 	featureFlagController := controllers.NewFeatureFlagController(featureFlagService)
 	// turnController := controllers.NewTurnController(turnService)
@@ -107,7 +193,7 @@ func Setup(db *gorm.DB, cfg config.Config) *gin.Engine {
 			auth.POST("/register", authController.Register)
 			auth.POST("/login", authController.Login)
 			auth.POST("/refresh", authController.RefreshToken)
-			auth.POST("/logout", authController.Logout)
+			auth.POST("/logout", authMiddleware.OptionalAuth(), authController.Logout)
 			
 			// Protected auth routes
 			authProtected := auth.Group("/")
@@ -116,6 +202,9 @@ func Setup(db *gorm.DB, cfg config.Config) *gin.Engine {
 				authProtected.GET("/me", authController.GetMe)
 				authProtected.PUT("/update-password", authController.UpdatePassword)
 				authProtected.PUT("/update-profile", authController.UpdateProfile)
+				authProtected.GET("/sessions", authController.GetSessions)
+				authProtected.DELETE("/sessions", authController.RevokeAllSessions)
+				authProtected.DELETE("/sessions/:sessionId", authController.RevokeSession)
 			}
 		}
 
@@ -128,10 +217,54 @@ func Setup(db *gorm.DB, cfg config.Config) *gin.Engine {
 			meetings.GET("", meetingController.GetMeetings)
 			meetings.POST("", meetingController.CreateMeeting)
 			meetings.GET("/upcoming", meetingController.GetUpcomingMeetings)
+			meetings.GET("/in-progress", meetingController.GetInProgressMeetings)
 			meetings.GET("/past", meetingController.GetPastMeetings)
+			meetings.GET("/joined", meetingController.GetJoinedMeetings)
+			meetings.POST("/join", meetingController.JoinMeeting)
+			meetings.POST("/leave", meetingController.LeaveMeeting)
 			meetings.GET("/:id", meetingController.GetMeeting)
 			meetings.PUT("/:id", meetingController.UpdateMeeting)
 			meetings.DELETE("/:id", meetingController.DeleteMeeting)
+			meetings.GET("/:id/participants", meetingController.GetMeetingParticipants)
+			meetings.PUT("/:id/participants/:participantId/role", meetingController.UpdateParticipantRole)
+			meetings.PATCH("/:id/start", meetingController.StartMeeting)
+			meetings.PATCH("/:id/end", meetingController.EndMeeting)
+			meetings.PATCH("/:id/archive", meetingController.ArchiveMeeting)
+
+			// Moderation
+			meetings.POST("/:id/participants/:participantId/mute", moderationController.MuteParticipant)
+			meetings.POST("/:id/participants/:participantId/remove", moderationController.RemoveParticipant)
+			meetings.POST("/:id/participants/:participantId/ban", moderationController.BanParticipant)
+			meetings.POST("/:id/participants/:participantId/chat-mute", moderationController.MuteParticipantChat)
+			meetings.DELETE("/:id/participants/:participantId/chat-mute", moderationController.UnmuteParticipantChat)
+			meetings.PATCH("/:id/chat/slow-mode", moderationController.SetChatSlowMode)
+			meetings.GET("/:id/bans", moderationController.GetBans)
+			meetings.DELETE("/:id/bans/:banId", moderationController.UnbanParticipant)
+			meetings.PATCH("/:id/lock", moderationController.LockMeeting)
+			meetings.PATCH("/:id/unlock", moderationController.UnlockMeeting)
+			meetings.GET("/:id/moderation-log", moderationController.GetModerationLog)
+
+			// Lobby
+			meetings.GET("/:id/lobby", lobbyController.GetLobby)
+			meetings.POST("/:id/lobby/admit", lobbyController.AdmitParticipants)
+			meetings.POST("/:id/lobby/deny", lobbyController.DenyParticipants)
+
+			// Invites
+			meetings.POST("/:id/invites", inviteController.CreateInvite)
+			meetings.GET("/:id/invites", inviteController.GetInvites)
+			meetings.DELETE("/:id/invites/:inviteId", inviteController.RevokeInvite)
+
+			// Calendar export
+			meetings.GET("/:id/ics", calendarController.ExportMeeting)
+		}
+
+		// Calendar feed routes; the feed itself is authenticated by its secret token
+		v1.GET("/calendar/feed/:token", calendarController.GetFeed)
+		calendar := v1.Group("/calendar")
+		calendar.Use(authMiddleware.RequireAuth())
+		{
+			calendar.POST("/feed", calendarController.CreateFeed)
+			calendar.DELETE("/feed", calendarController.RevokeFeed)
 		}
 		
 		// Public user routes (no authentication required)
@@ -165,6 +298,12 @@ func Setup(db *gorm.DB, cfg config.Config) *gin.Engine {
 			webrtc.POST("/meetings/:id/ice-candidate", webrtcController.SendIceCandidate)
 			webrtc.PUT("/meetings/:id/peer-state", webrtcController.UpdatePeerState)
 			webrtc.GET("/meetings/:id/stats", webrtcController.GetRoomStats)
+			webrtc.GET("/meetings/:id/media-policy", webrtcController.GetMediaPolicy)
+			webrtc.PUT("/meetings/:id/media-policy", webrtcController.UpdateMediaPolicy)
+			webrtc.POST("/meetings/:id/quality", webrtcController.SubmitQualityStats)
+			webrtc.GET("/meetings/:id/quality", webrtcController.GetMeetingQuality)
+			webrtc.GET("/meetings/:id/quality/poor", webrtcController.GetPoorConnections)
+			webrtc.GET("/meetings/:id/quality/peers/:peerId", webrtcController.GetPeerQuality)
 		}
 
 		// TEMPORARILY DISABLED FOR EMERGENCY WEBSOCKET FIX
@@ -205,22 +344,57 @@ func Setup(db *gorm.DB, cfg config.Config) *gin.Engine {
 		meetingsChat := v1.Group("/meetings/:id")
 		{
 			// Get messages (supports both auth and public users via sessionId query param)
-			meetingsChat.GET("/messages", chatController.GetMessages)
+			meetingsChat.GET("/messages", authMiddleware.OptionalAuth(), chatController.GetMessages)
 			
-			// Send message (supports both auth and public users via sessionId query param)
-			meetingsChat.POST("/messages", chatController.SendMessage)
+			// Send message; announcements need an authenticated host or co-host (supports both auth and public users via sessionId query param)
+			meetingsChat.POST("/messages", authMiddleware.OptionalAuth(), chatController.SendMessage)
 			
 			// Mark message as read (supports both auth and public users via sessionId query param)
-			meetingsChat.POST("/messages/:messageId/read", chatController.MarkMessageRead)
+			meetingsChat.POST("/messages/:messageId/read", authMiddleware.OptionalAuth(), chatController.MarkMessageRead)
+			
+			// Mark a message and everything before it as read in one call (supports both auth and public users via sessionId query param)
+			meetingsChat.POST("/messages/mark-read-up-to", authMiddleware.OptionalAuth(), chatController.MarkReadUpTo)
 			
 			// Toggle reaction (supports both auth and public users via sessionId query param)
-			meetingsChat.POST("/messages/:messageId/reactions", chatController.ToggleReaction)
+			meetingsChat.POST("/messages/:messageId/reactions", authMiddleware.OptionalAuth(), chatController.ToggleReaction)
 			
 			// Get unread count (supports both auth and public users via sessionId query param)
-			meetingsChat.GET("/messages/unread-count", chatController.GetUnreadCount)
+			meetingsChat.GET("/messages/unread-count", authMiddleware.OptionalAuth(), chatController.GetUnreadCount)
+			
+			// A thread: the message that started it and a page of its replies (supports both auth and public users via sessionId query param)
+			meetingsChat.GET("/messages/:messageId/thread", authMiddleware.OptionalAuth(), chatController.GetThread)
+			
+			// Full-text search over the meeting's chat (supports both auth and public users via sessionId query param)
+			meetingsChat.GET("/messages/search", authMiddleware.OptionalAuth(), chatController.SearchMessages)
 			
-			// Update message (authenticated users only)
+			// Download the chat as a JSON, Markdown, HTML or CSV transcript (host and participants; supports both auth and public users via sessionId query param)
+			meetingsChat.GET("/messages/export", authMiddleware.OptionalAuth(), chatController.ExportTranscript)
+			
+			// Update message; hosts and co-hosts may also delete other people's messages (authenticated users only)
 			meetingsChat.PUT("/messages/:messageId", authMiddleware.RequireAuth(), chatController.UpdateMessage)
+
+			// Review queue of messages the content filter flagged (hosts and co-hosts only)
+			meetingsChat.GET("/messages/flagged", authMiddleware.RequireAuth(), chatController.GetFlaggedMessages)
+			meetingsChat.POST("/messages/flagged/:flagId/review", authMiddleware.RequireAuth(), chatController.ReviewFlag)
+
+			// Pinned messages (supports both auth and public users via sessionId query param); pinning is for hosts and co-hosts only
+			meetingsChat.GET("/messages/pinned", authMiddleware.OptionalAuth(), chatController.GetPinnedMessages)
+			meetingsChat.POST("/messages/:messageId/pin", authMiddleware.RequireAuth(), chatController.PinMessage)
+			meetingsChat.DELETE("/messages/:messageId/pin", authMiddleware.RequireAuth(), chatController.UnpinMessage)
+
+			// Acknowledge an announcement (supports both auth and public users via sessionId query param), and who did (hosts and co-hosts only)
+			meetingsChat.POST("/messages/:messageId/acknowledge", authMiddleware.OptionalAuth(), chatController.AcknowledgeMessage)
+			meetingsChat.GET("/messages/:messageId/acknowledgements", authMiddleware.RequireAuth(), chatController.GetAcknowledgements)
+
+			// Upload a file to send with a message, and refresh an attachment's download URLs (supports both auth and public users via sessionId query param)
+			meetingsChat.POST("/attachments", authMiddleware.OptionalAuth(), attachmentController.UploadAttachment)
+			meetingsChat.GET("/attachments/:attachmentId", authMiddleware.OptionalAuth(), attachmentController.GetAttachment)
+		}
+
+		// Attachment downloads through signed URLs (local storage driver; the signature authenticates)
+		attachments := v1.Group("/attachments")
+		{
+			attachments.GET("/files/*key", attachmentController.ServeFile)
 		}
 
 		// WebSocket routes
@@ -256,5 +430,15 @@ func Setup(db *gorm.DB, cfg config.Config) *gin.Engine {
 		// }
 	}
 
-	return router
+	shutdown := func() {
+		webrtcService.Stop()
+		attachmentService.Stop()
+		if hubBroker != nil {
+			if err := hubBroker.Close(); err != nil {
+				log.Printf("Failed to leave the WebSocket hub cluster: %v", err)
+			}
+		}
+	}
+
+	return router, shutdown
 }
@@ -0,0 +1,439 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Registers the GIF decoder for thumbnails
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+const (
+	attachmentThumbnailSize = 320        // Thumbnails fit in a square of this many pixels
+	attachmentMaxPixels     = 40_000_000 // Larger images are stored without a thumbnail rather than decoded
+	attachmentPruneInterval = time.Hour  // How often unclaimed uploads are looked for
+)
+
+// AttachmentService handles chat file uploads. Files are validated by their
+// content rather than the name or declared type, stored through an
+// AttachmentStorage driver and only ever handed out through signed URLs.
+type AttachmentService struct {
+	db           *gorm.DB
+	storage      models.AttachmentStorage
+	roleService  *RoleService
+	maxSize      int64
+	allowedTypes map[string]bool
+	urlExpiry    time.Duration
+	unclaimedTTL time.Duration
+	stopPruning  chan struct{}
+}
+
+func NewAttachmentService(db *gorm.DB, storage models.AttachmentStorage, roleService *RoleService, cfg config.AttachmentConfig) *AttachmentService {
+	allowedTypes := make(map[string]bool, len(cfg.AllowedTypes))
+	for _, contentType := range cfg.AllowedTypes {
+		allowedTypes[strings.ToLower(contentType)] = true
+	}
+
+	return &AttachmentService{
+		db:           db,
+		storage:      storage,
+		roleService:  roleService,
+		maxSize:      cfg.MaxSize,
+		allowedTypes: allowedTypes,
+		urlExpiry:    cfg.URLExpiry,
+		unclaimedTTL: cfg.UnclaimedTTL,
+		stopPruning:  make(chan struct{}),
+	}
+}
+
+// StartPruning deletes uploads that were never sent in a message, once
+// they are older than the unclaimed TTL, every hour until Stop is called
+func (s *AttachmentService) StartPruning() {
+	if s.unclaimedTTL <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(attachmentPruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if pruned, err := s.PruneUnclaimed(); err != nil {
+					log.Printf("Failed to prune unclaimed attachments: %v", err)
+				} else if pruned > 0 {
+					log.Printf("Pruned %d unclaimed attachments", pruned)
+				}
+			case <-s.stopPruning:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops pruning unclaimed uploads
+func (s *AttachmentService) Stop() {
+	close(s.stopPruning)
+}
+
+// PruneUnclaimed deletes the uploads older than the unclaimed TTL that no
+// message claimed, with their files
+func (s *AttachmentService) PruneUnclaimed() (int64, error) {
+	var attachments []models.ChatAttachment
+	if err := s.db.Where("message_id IS NULL AND created_at < ?", time.Now().Add(-s.unclaimedTTL)).
+		Find(&attachments).Error; err != nil {
+		return 0, fmt.Errorf("failed to fetch unclaimed attachments: %w", err)
+	}
+
+	var pruned int64
+	for i := range attachments {
+		// A message may claim the upload meanwhile; it is then kept
+		result := s.db.Where("id = ? AND message_id IS NULL", attachments[i].ID).Delete(&models.ChatAttachment{})
+		if result.Error != nil {
+			return pruned, fmt.Errorf("failed to delete attachment: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		s.deleteFiles(&attachments[i])
+		pruned++
+	}
+	return pruned, nil
+}
+
+// MaxSize returns the largest accepted upload in bytes
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload validates and stores a file for a meeting's chat. The attachment
+// stays private to the uploader until a message claims it.
+func (s *AttachmentService) Upload(meetingID uuid.UUID, actor models.MeetingActor, fileName string, body io.Reader) (*models.ChatAttachment, error) {
+	if err := s.roleService.Authorize(meetingID, actor, models.PermissionChat); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, errors.New("attachment too large")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("attachment is empty")
+	}
+
+	contentType := sniffContentType(data)
+	if !s.allowedTypes[contentType] {
+		return nil, errors.New("unsupported attachment type")
+	}
+
+	attachment := &models.ChatAttachment{
+		ID:                   uuid.New(),
+		MeetingID:            meetingID,
+		UploaderUserID:       actor.UserID,
+		UploaderPublicUserID: actor.PublicUserID,
+		FileName:             sanitizeFileName(fileName),
+		ContentType:          contentType,
+		Size:                 int64(len(data)),
+	}
+	attachment.StorageKey = attachmentKey(attachment, "original")
+
+	var thumbnail []byte
+	if attachment.IsImage() {
+		var thumbnailType string
+		thumbnail, thumbnailType, attachment.Width, attachment.Height = makeThumbnail(data)
+		if thumbnail != nil {
+			attachment.ThumbnailKey = attachmentKey(attachment, "thumbnail")
+			attachment.ThumbnailType = thumbnailType
+		}
+	}
+
+	if err := s.storage.Put(attachment.StorageKey, bytes.NewReader(data), attachment.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if thumbnail != nil {
+		if err := s.storage.Put(attachment.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), attachment.ThumbnailType); err != nil {
+			s.deleteFiles(attachment)
+			return nil, fmt.Errorf("failed to store attachment thumbnail: %w", err)
+		}
+	}
+
+	if err := s.db.Create(attachment).Error; err != nil {
+		s.deleteFiles(attachment)
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	s.Sign(attachment)
+	return attachment, nil
+}
+
+// GetAttachment returns an attachment with freshly signed URLs. Sent
+// attachments are visible to every meeting member, unsent ones only to the
+// uploader.
+func (s *AttachmentService) GetAttachment(meetingID, attachmentID uuid.UUID, actor models.MeetingActor) (*models.ChatAttachment, error) {
+	if _, err := s.roleService.GetRole(meetingID, actor); err != nil {
+		return nil, err
+	}
+
+	var attachment models.ChatAttachment
+	if err := s.db.Where("id = ? AND meeting_id = ?", attachmentID, meetingID).First(&attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attachment not found")
+		}
+		return nil, fmt.Errorf("failed to fetch attachment: %w", err)
+	}
+	if attachment.MessageID == nil && !uploadedBy(&attachment, actor) {
+		return nil, errors.New("attachment not found")
+	}
+	if attachment.MessageID != nil {
+		// Attachments of private messages are as private as the message
+		var visible int64
+		if err := s.db.Model(&models.ChatMessage{}).Scopes(chatVisibilityScope(actor)).
+			Where("chat_messages.id = ?", *attachment.MessageID).
+			Count(&visible).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch attachment: %w", err)
+		}
+		if visible == 0 {
+			return nil, errors.New("attachment not found")
+		}
+	}
+
+	s.Sign(&attachment)
+	return &attachment, nil
+}
+
+// claim links an unsent attachment uploaded by actor to a message
+func (s *AttachmentService) claim(tx *gorm.DB, meetingID, attachmentID uuid.UUID, actor models.MeetingActor, messageID uuid.UUID) (*models.ChatAttachment, error) {
+	var attachment models.ChatAttachment
+	if err := tx.Where("id = ? AND meeting_id = ? AND message_id IS NULL", attachmentID, meetingID).First(&attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("attachment not found")
+		}
+		return nil, fmt.Errorf("failed to fetch attachment: %w", err)
+	}
+	if !uploadedBy(&attachment, actor) {
+		return nil, errors.New("attachment not found")
+	}
+
+	// The message_id guard loses cleanly against a concurrent claim
+	result := tx.Model(&models.ChatAttachment{}).
+		Where("id = ? AND message_id IS NULL", attachment.ID).
+		Update("message_id", messageID)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to attach file: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("attachment not found")
+	}
+	attachment.MessageID = &messageID
+	return &attachment, nil
+}
+
+// DeleteMessageAttachment removes the attachment of a deleted message along
+// with its files
+func (s *AttachmentService) DeleteMessageAttachment(messageID uuid.UUID) error {
+	var attachment models.ChatAttachment
+	if err := s.db.Where("message_id = ?", messageID).First(&attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch attachment: %w", err)
+	}
+
+	if err := s.db.Delete(&attachment).Error; err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	s.deleteFiles(&attachment)
+	return nil
+}
+
+// Sign fills in the attachment's download URLs. Images are served inline
+// so they can be shown in the chat; anything else downloads as a file.
+func (s *AttachmentService) Sign(attachment *models.ChatAttachment) {
+	download := models.AttachmentDownload{
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Inline:      attachment.IsImage(),
+	}
+	url, err := s.storage.SignedURL(attachment.StorageKey, download, s.urlExpiry)
+	if err != nil {
+		log.Printf("Failed to sign attachment %s: %v", attachment.ID, err)
+		return
+	}
+	attachment.URL = url
+	attachment.URLExpiresAt = time.Now().Add(s.urlExpiry)
+
+	switch {
+	case attachment.ThumbnailKey != "":
+		download.ContentType = attachment.ThumbnailType
+		download.Inline = true
+		if attachment.ThumbnailURL, err = s.storage.SignedURL(attachment.ThumbnailKey, download, s.urlExpiry); err != nil {
+			log.Printf("Failed to sign attachment thumbnail %s: %v", attachment.ID, err)
+		}
+	case attachment.IsImage():
+		// Small images are their own thumbnail
+		attachment.ThumbnailURL = url
+	}
+}
+
+// deleteFiles removes an attachment's stored objects. Failures are logged
+// rather than returned: the record is gone and nothing links to the files.
+func (s *AttachmentService) deleteFiles(attachment *models.ChatAttachment) {
+	for _, key := range []string{attachment.StorageKey, attachment.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.storage.Delete(key); err != nil {
+			log.Printf("Failed to delete attachment file %s: %v", key, err)
+		}
+	}
+}
+
+// uploadedBy reports whether actor uploaded the attachment
+func uploadedBy(attachment *models.ChatAttachment, actor models.MeetingActor) bool {
+	if actor.UserID != nil {
+		return attachment.UploaderUserID != nil && *attachment.UploaderUserID == *actor.UserID
+	}
+	return actor.PublicUserID != nil && attachment.UploaderPublicUserID != nil && *attachment.UploaderPublicUserID == *actor.PublicUserID
+}
+
+// attachmentKey is the storage key of one of an attachment's files
+func attachmentKey(attachment *models.ChatAttachment, variant string) string {
+	return path.Join("meetings", attachment.MeetingID.String(), attachment.ID.String(), variant)
+}
+
+// sniffContentType detects the MIME type from the file content, without
+// parameters such as the charset
+func sniffContentType(data []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// sanitizeFileName keeps the base name of an uploaded file, without control
+// characters and short enough for the database
+func sanitizeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == utf8.RuneError {
+			return -1
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	// Browsers may send a full Windows path
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "attachment"
+	}
+
+	if len(name) > 255 {
+		// Keep the extension and cut the rest on a character boundary
+		ext := path.Ext(name)
+		if len(ext) > 16 {
+			ext = ""
+		}
+		cut := 255 - len(ext)
+		for cut > 0 && !utf8.RuneStart(name[cut]) {
+			cut--
+		}
+		name = name[:cut] + ext
+	}
+	return name
+}
+
+// makeThumbnail reads an image's dimensions and, when it is larger than the
+// thumbnail box, returns a scaled-down copy. Images that cannot be decoded
+// are stored without either.
+func makeThumbnail(data []byte) ([]byte, string, int, int) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", 0, 0
+	}
+	width, height := config.Width, config.Height
+	if width <= attachmentThumbnailSize && height <= attachmentThumbnailSize || width*height > attachmentMaxPixels {
+		return nil, "", width, height
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", width, height
+	}
+
+	thumbWidth, thumbHeight := attachmentThumbnailSize, attachmentThumbnailSize
+	if width > height {
+		thumbHeight = max(1, height*attachmentThumbnailSize/width)
+	} else {
+		thumbWidth = max(1, width*attachmentThumbnailSize/height)
+	}
+	thumb := scaleDown(img, thumbWidth, thumbHeight)
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80}); err != nil {
+			return nil, "", width, height
+		}
+		return buf.Bytes(), "image/jpeg", width, height
+	}
+	// PNG keeps the transparency of PNG and GIF images
+	if err := png.Encode(&buf, thumb); err != nil {
+		return nil, "", width, height
+	}
+	return buf.Bytes(), "image/png", width, height
+}
+
+// scaleDown resizes src to width x height by averaging the source pixels
+// covered by each destination pixel
+func scaleDown(src image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcHeight/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcHeight/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcWidth/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcWidth/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func setupTestAttachments(t *testing.T) (*gorm.DB, *AttachmentService, *ChatService, string) {
+	db := setupTestChatDB(t)
+
+	root := t.TempDir()
+	storage, err := NewLocalAttachmentStorage(root, "http://localhost:8080/api/v1/attachments/files", "secret")
+	require.NoError(t, err)
+
+	roleService := NewRoleService(db, nil)
+	attachmentService := NewAttachmentService(db, storage, roleService, config.AttachmentConfig{
+		MaxSize:      64 << 10,
+		AllowedTypes: []string{"image/png", "text/plain"},
+		URLExpiry:    time.Minute,
+		UnclaimedTTL: time.Hour,
+	})
+	chatService := NewChatService(db, nil, nil, roleService)
+	chatService.SetAttachmentService(attachmentService)
+	return db, attachmentService, chatService, root
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	db, service, _, root := setupTestAttachments(t)
+	meeting, hostID := createTestMeeting(t, db)
+	host := models.MeetingActor{UserID: &hostID}
+
+	// The type comes from the content, not the name
+	photo, err := service.Upload(meeting.ID, host, `C:\Users\host\photo.txt`, bytes.NewReader(testPNG(t, 800, 400)))
+	require.NoError(t, err)
+	assert.Equal(t, "photo.txt", photo.FileName)
+	assert.Equal(t, "image/png", photo.ContentType)
+	assert.Equal(t, 800, photo.Width)
+	assert.Equal(t, 400, photo.Height)
+	assert.NotEmpty(t, photo.URL)
+	assert.NotEmpty(t, photo.ThumbnailURL)
+	assert.NotEqual(t, photo.URL, photo.ThumbnailURL)
+
+	thumbnail, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(photo.ThumbnailKey)))
+	require.NoError(t, err)
+	config, err := png.DecodeConfig(bytes.NewReader(thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, 320, config.Width)
+	assert.Equal(t, 160, config.Height)
+
+	// Small images are their own thumbnail
+	icon, err := service.Upload(meeting.ID, host, "icon.png", bytes.NewReader(testPNG(t, 16, 16)))
+	require.NoError(t, err)
+	assert.Empty(t, icon.ThumbnailKey)
+	assert.Equal(t, icon.URL, icon.ThumbnailURL)
+
+	_, err = service.Upload(meeting.ID, host, "page.html", strings.NewReader("<html><body>hi</body></html>"))
+	assert.EqualError(t, err, "unsupported attachment type")
+	_, err = service.Upload(meeting.ID, host, "big.txt", strings.NewReader(strings.Repeat("a", 64<<10+1)))
+	assert.EqualError(t, err, "attachment too large")
+	_, err = service.Upload(meeting.ID, host, "empty.txt", strings.NewReader(""))
+	assert.EqualError(t, err, "attachment is empty")
+
+	outsider := createNamedTestUser(t, db, "outsider")
+	_, err = service.Upload(meeting.ID, models.MeetingActor{UserID: &outsider.ID}, "notes.txt", strings.NewReader("notes"))
+	assert.EqualError(t, err, "not a participant")
+
+	var count int64
+	db.Model(&models.ChatAttachment{}).Count(&count)
+	assert.EqualValues(t, 2, count)
+}
+
+func TestChatService_SendAttachment(t *testing.T) {
+	db, service, chatService, root := setupTestAttachments(t)
+	meeting, hostID := createTestMeeting(t, db)
+	host := models.MeetingActor{UserID: &hostID}
+	attendee := addTestParticipant(t, db, meeting.ID, "attendee", models.RoleAttendee)
+	attendeeActor := models.MeetingActor{UserID: attendee.UserID}
+
+	photo, err := service.Upload(meeting.ID, host, "photo.png", bytes.NewReader(testPNG(t, 640, 640)))
+	require.NoError(t, err)
+
+	// Until it is sent the attachment is private to the uploader
+	_, err = service.GetAttachment(meeting.ID, photo.ID, attendeeActor)
+	assert.EqualError(t, err, "attachment not found")
+	_, err = chatService.SendMessage(attendee.UserID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, AttachmentID: &photo.ID,
+	})
+	assert.EqualError(t, err, "attachment not found")
+
+	message, err := chatService.SendMessage(&hostID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, AttachmentID: &photo.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.MessageTypeImage, message.MessageType)
+	require.NotNil(t, message.Attachment)
+	assert.Equal(t, photo.ID, message.Attachment.ID)
+	assert.NotEmpty(t, message.ToResponse().AttachmentURL)
+
+	sent, err := service.GetAttachment(meeting.ID, photo.ID, attendeeActor)
+	require.NoError(t, err)
+	assert.Equal(t, message.ID, *sent.MessageID)
+
+	// An attachment is sent once
+	_, err = chatService.SendMessage(&hostID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: "again", AttachmentID: &photo.ID,
+	})
+	assert.EqualError(t, err, "attachment not found")
+
+	// Deleting the message deletes the file
+	deleted := true
+	_, err = chatService.UpdateMessage(hostID, message.ID, &models.UpdateChatMessageRequest{IsDeleted: &deleted})
+	require.NoError(t, err)
+	_, err = service.GetAttachment(meeting.ID, photo.ID, host)
+	assert.EqualError(t, err, "attachment not found")
+	_, err = os.Stat(filepath.Join(root, filepath.FromSlash(photo.StorageKey)))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(root, filepath.FromSlash(photo.ThumbnailKey)))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = chatService.SendMessage(&hostID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, AttachmentID: ptrUUID(uuid.New()),
+	})
+	assert.EqualError(t, err, "attachment not found")
+}
+
+func TestAttachmentService_PruneUnclaimed(t *testing.T) {
+	db, service, chatService, root := setupTestAttachments(t)
+	meeting, hostID := createTestMeeting(t, db)
+	host := models.MeetingActor{UserID: &hostID}
+
+	upload := func(name string) *models.ChatAttachment {
+		attachment, err := service.Upload(meeting.ID, host, name, strings.NewReader("notes"))
+		require.NoError(t, err)
+		return attachment
+	}
+	sent, abandoned, recent := upload("sent.txt"), upload("abandoned.txt"), upload("recent.txt")
+	_, err := chatService.SendMessage(&hostID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, AttachmentID: &sent.ID,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&models.ChatAttachment{}).Where("id IN ?", []uuid.UUID{sent.ID, abandoned.ID}).
+		Update("created_at", time.Now().Add(-2*time.Hour)).Error)
+
+	// Only old uploads no message claimed go, with their files
+	pruned, err := service.PruneUnclaimed()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, pruned)
+	_, err = service.GetAttachment(meeting.ID, abandoned.ID, host)
+	assert.EqualError(t, err, "attachment not found")
+	_, err = os.Stat(filepath.Join(root, filepath.FromSlash(abandoned.StorageKey)))
+	assert.True(t, os.IsNotExist(err))
+
+	for _, kept := range []*models.ChatAttachment{sent, recent} {
+		_, err = service.GetAttachment(meeting.ID, kept.ID, host)
+		assert.NoError(t, err)
+		_, err = os.Stat(filepath.Join(root, filepath.FromSlash(kept.StorageKey)))
+		assert.NoError(t, err)
+	}
+}
+
+func ptrUUID(id uuid.UUID) *uuid.UUID {
+	return &id
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	assert.Equal(t, "report.pdf", sanitizeFileName("../../etc/report.pdf"))
+	assert.Equal(t, "report.pdf", sanitizeFileName(`C:\docs\report.pdf`))
+	assert.Equal(t, "evil.txt", sanitizeFileName("ev\x00il\n.txt"))
+	assert.Equal(t, "attachment", sanitizeFileName("  "))
+	assert.Equal(t, "attachment", sanitizeFileName("../"))
+
+	long := sanitizeFileName(strings.Repeat("é", 200) + ".pdf")
+	assert.LessOrEqual(t, len(long), 255)
+	assert.True(t, strings.HasSuffix(long, ".pdf"))
+	assert.True(t, strings.HasPrefix(long, "éé"))
+	assert.NotContains(t, long, "\uFFFD")
+}
@@ -3,13 +3,21 @@ package services
 import (
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 
 	"github.com/your-org/gomeet-backend/internal/models"
 )
 
+var errRefreshTokenReused = errors.New("refresh token reuse detected")
+
+// refreshReuseGrace is how long a rotated refresh token may still be
+// presented, e.g. by a second tab that refreshed at the same time
+const refreshReuseGrace = 30 * time.Second
+
 type AuthService struct {
 	db         *gorm.DB
 	jwtService *JWTService
@@ -21,6 +29,12 @@ type AuthResponse struct {
 	RefreshToken string              `json:"refreshToken"`
 }
 
+// ClientInfo identifies the device a session was opened from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
 func NewAuthService(db *gorm.DB, jwtService *JWTService) *AuthService {
 	return &AuthService{
 		db:         db,
@@ -28,7 +42,7 @@ func NewAuthService(db *gorm.DB, jwtService *JWTService) *AuthService {
 	}
 }
 
-func (s *AuthService) Register(req *models.RegisterRequest) (*AuthResponse, error) {
+func (s *AuthService) Register(req *models.RegisterRequest, client ClientInfo) (*AuthResponse, error) {
 	// Check if user already exists
 	var existingUser models.User
 	if err := s.db.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
@@ -53,9 +67,9 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*AuthResponse, erro
 	}
 
 	// Generate tokens
-	tokens, err := s.jwtService.GenerateTokenPair(user)
+	tokens, err := s.startSession(user, client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+		return nil, err
 	}
 
 	return &AuthResponse{
@@ -65,7 +79,7 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*AuthResponse, erro
 	}, nil
 }
 
-func (s *AuthService) Login(req *models.LoginRequest) (*AuthResponse, error) {
+func (s *AuthService) Login(req *models.LoginRequest, client ClientInfo) (*AuthResponse, error) {
 	// Find user by email
 	var user models.User
 	if err := s.db.Where("email = ?", req.Email).First(&user).Error; err != nil {
@@ -81,9 +95,9 @@ func (s *AuthService) Login(req *models.LoginRequest) (*AuthResponse, error) {
 	}
 
 	// Generate tokens
-	tokens, err := s.jwtService.GenerateTokenPair(&user)
+	tokens, err := s.startSession(&user, client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+		return nil, err
 	}
 
 	return &AuthResponse{
@@ -93,13 +107,37 @@ func (s *AuthService) Login(req *models.LoginRequest) (*AuthResponse, error) {
 	}, nil
 }
 
-func (s *AuthService) RefreshToken(refreshToken string) (*AuthResponse, error) {
+// RefreshToken rotates a refresh token. The presented token is revoked and
+// replaced by a new one in the same family. Presenting a token that was
+// already rotated means it leaked, so the whole family is revoked, unless
+// it was rotated within refreshReuseGrace.
+func (s *AuthService) RefreshToken(refreshToken string, client ClientInfo) (*AuthResponse, error) {
 	// Validate refresh token
 	claims, err := s.jwtService.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		return nil, errors.New("invalid refresh token")
 	}
 
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	var stored models.RefreshToken
+	if err := s.db.Where("id = ? AND user_id = ?", tokenID, claims.UserID).First(&stored).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid refresh token")
+		}
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	if stored.RevokedAt != nil {
+		return s.refreshRotatedToken(&stored)
+	}
+	if !stored.IsActive() {
+		return nil, errors.New("invalid refresh token")
+	}
+
 	// Find user
 	var user models.User
 	if err := s.db.Where("id = ?", claims.UserID).First(&user).Error; err != nil {
@@ -109,8 +147,79 @@ func (s *AuthService) RefreshToken(refreshToken string) (*AuthResponse, error) {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
-	// Generate new tokens
-	tokens, err := s.jwtService.GenerateTokenPair(&user)
+	// Rotate: revoke the presented token and issue its successor
+	var tokens *TokenPair
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		nextID := uuid.New()
+		now := time.Now()
+
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", stored.ID).
+			Updates(map[string]interface{}{
+				"revoked_at":     now,
+				"replaced_by_id": nextID,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to revoke refresh token: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			// Another request rotated the token first
+			return errRefreshTokenReused
+		}
+
+		tokens, err = s.issueRefreshToken(tx, &user, stored.FamilyID, nextID, stored.SessionStart, client)
+		return err
+	})
+	if errors.Is(err, errRefreshTokenReused) {
+		if err := s.db.Where("id = ?", stored.ID).First(&stored).Error; err != nil {
+			return nil, fmt.Errorf("failed to find refresh token: %w", err)
+		}
+		return s.refreshRotatedToken(&stored)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthResponse{
+		User:         user.ToResponse(),
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+// refreshRotatedToken handles a refresh token that was already revoked.
+// Shortly after its rotation the still active successor is signed again, so
+// concurrent refreshes all end up in the same session; after that the
+// token counts as leaked.
+func (s *AuthService) refreshRotatedToken(token *models.RefreshToken) (*AuthResponse, error) {
+	if token.ReplacedByID == nil {
+		return nil, errors.New("invalid refresh token")
+	}
+	if token.RevokedAt == nil || time.Since(*token.RevokedAt) > refreshReuseGrace {
+		return nil, s.handleRefreshTokenReuse(token)
+	}
+
+	var successor models.RefreshToken
+	if err := s.db.Where("id = ?", *token.ReplacedByID).First(&successor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, s.handleRefreshTokenReuse(token)
+		}
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+	if !successor.IsActive() {
+		// The successor was rotated or revoked in turn
+		return nil, s.handleRefreshTokenReuse(token)
+	}
+
+	var user models.User
+	if err := s.db.Where("id = ?", token.UserID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	tokens, err := s.jwtService.ReissueTokenPair(&user, successor.FamilyID, successor.ID, successor.ExpiresAt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
@@ -122,6 +231,125 @@ func (s *AuthService) RefreshToken(refreshToken string) (*AuthResponse, error) {
 	}, nil
 }
 
+// handleRefreshTokenReuse revokes the family of a token that was presented
+// after it had already been rotated
+func (s *AuthService) handleRefreshTokenReuse(token *models.RefreshToken) error {
+	if err := s.revokeFamily(token.UserID, token.FamilyID); err != nil {
+		return err
+	}
+	return errRefreshTokenReused
+}
+
+// startSession creates a new refresh token family and issues its first tokens
+func (s *AuthService) startSession(user *models.User, client ClientInfo) (*TokenPair, error) {
+	return s.issueRefreshToken(s.db, user, uuid.New(), uuid.New(), time.Now(), client)
+}
+
+// issueRefreshToken persists a refresh token record and signs the matching
+// token pair
+func (s *AuthService) issueRefreshToken(db *gorm.DB, user *models.User, familyID, tokenID uuid.UUID, sessionStart time.Time, client ClientInfo) (*TokenPair, error) {
+	tokens, err := s.jwtService.GenerateTokenPair(user, familyID, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	record := &models.RefreshToken{
+		ID:           tokenID,
+		UserID:       user.ID,
+		FamilyID:     familyID,
+		UserAgent:    client.UserAgent,
+		IPAddress:    client.IPAddress,
+		ExpiresAt:    tokens.RefreshExpiresAt,
+		SessionStart: sessionStart,
+	}
+	if err := db.Create(record).Error; err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes the session the refresh token belongs to
+func (s *AuthService) Logout(refreshToken string) error {
+	claims, err := s.jwtService.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return errors.New("invalid refresh token")
+	}
+	return s.revokeFamily(claims.UserID, claims.SessionID)
+}
+
+// GetSessions returns the active sessions of a user, most recently used first
+func (s *AuthService) GetSessions(userID string, currentSessionID uuid.UUID) ([]models.SessionResponse, error) {
+	var tokens []models.RefreshToken
+	if err := s.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	sessions := make([]models.SessionResponse, 0, len(tokens))
+	for _, token := range tokens {
+		sessions = append(sessions, token.ToSessionResponse(currentSessionID))
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes a single session of a user
+func (s *AuthService) RevokeSession(userID string, sessionID uuid.UUID) error {
+	var count int64
+	if err := s.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND family_id = ? AND revoked_at IS NULL", userID, sessionID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to find session: %w", err)
+	}
+	if count == 0 {
+		return errors.New("session not found")
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	return s.revokeFamily(userUUID, sessionID)
+}
+
+// RevokeAllSessions revokes every session of a user
+func (s *AuthService) RevokeAllSessions(userID string) error {
+	return s.revokeAllSessions(s.db, userID)
+}
+
+func (s *AuthService) revokeAllSessions(db *gorm.DB, userID string) error {
+	if err := db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+// IsSessionActive reports whether a refresh token family still has a token
+// that can be exchanged. Rotation revokes the old token and issues its
+// successor in one transaction, so a live session always has one.
+func (s *AuthService) IsSessionActive(userID, sessionID uuid.UUID) (bool, error) {
+	var count int64
+	if err := s.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND family_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, sessionID, time.Now()).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+	return count > 0, nil
+}
+
+// revokeFamily revokes every outstanding token of a refresh token family
+func (s *AuthService) revokeFamily(userID, familyID uuid.UUID) error {
+	if err := s.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND family_id = ? AND revoked_at IS NULL", userID, familyID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
 func (s *AuthService) GetUserByID(userID string) (*models.User, error) {
 	var user models.User
 	if err := s.db.Where("id = ?", userID).First(&user).Error; err != nil {
@@ -154,15 +382,16 @@ func (s *AuthService) UpdatePassword(userID string, req *models.UpdatePasswordRe
 		return fmt.Errorf("failed to hash new password: %w", err)
 	}
 
-	// Update password
-	if err := s.db.Model(&user).Update("password_hash", string(hashedPassword)).Error; err != nil {
-		return fmt.Errorf("failed to update password: %w", err)
-	}
-
-	return nil
+	// Update password and sign out every device
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("password_hash", string(hashedPassword)).Error; err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+		return s.revokeAllSessions(tx, userID)
+	})
 }
 
-func (s *AuthService) UpdateProfile(userID string, username, email string) (*models.UserResponse, error) {
+func (s *AuthService) UpdateProfile(userID string, username, email, timeZone string) (*models.UserResponse, error) {
 	var user models.User
 	if err := s.db.Where("id = ?", userID).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -187,6 +416,12 @@ func (s *AuthService) UpdateProfile(userID string, username, email string) (*mod
 	if email != "" {
 		updates["email"] = email
 	}
+	if timeZone != "" {
+		if _, err := models.LoadTimeZone(timeZone); err != nil {
+			return nil, err
+		}
+		updates["time_zone"] = timeZone
+	}
 
 	if err := s.db.Model(&user).Updates(updates).Error; err != nil {
 		return nil, fmt.Errorf("failed to update profile: %w", err)
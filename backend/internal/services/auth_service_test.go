@@ -0,0 +1,168 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// setupTestDB opens an in-memory sqlite database with the given tables
+func setupTestDB(t *testing.T, tables ...interface{}) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	// Every connection gets its own in-memory database, so keep just one
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(tables...))
+	return db
+}
+
+func setupTestAuthService(t *testing.T) *AuthService {
+	db := setupTestDB(t, &models.User{}, &models.RefreshToken{})
+
+	jwtService := NewJWTService(config.JWTConfig{
+		Secret:             "test-secret",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: time.Hour,
+	})
+	service := NewAuthService(db, jwtService)
+	jwtService.SetSessionChecker(service)
+	return service
+}
+
+func registerTestUser(t *testing.T, service *AuthService) *AuthResponse {
+	response, err := service.Register(&models.RegisterRequest{
+		Username: "alice",
+		Email:    "alice@example.com",
+		Password: "secret123",
+	}, ClientInfo{UserAgent: "test-agent", IPAddress: "127.0.0.1"})
+	require.NoError(t, err)
+	return response
+}
+
+func TestAuthService_RefreshTokenRotation(t *testing.T) {
+	service := setupTestAuthService(t)
+	registered := registerTestUser(t, service)
+
+	rotated, err := service.RefreshToken(registered.RefreshToken, ClientInfo{})
+	require.NoError(t, err)
+	assert.NotEqual(t, registered.RefreshToken, rotated.RefreshToken)
+
+	// The rotated token keeps working
+	_, err = service.RefreshToken(rotated.RefreshToken, ClientInfo{})
+	require.NoError(t, err)
+
+	// Refresh tokens cannot be used as access tokens
+	_, err = service.jwtService.ValidateAccessToken(rotated.RefreshToken)
+	assert.Error(t, err)
+}
+
+func TestAuthService_RefreshTokenReuseRevokesFamily(t *testing.T) {
+	service := setupTestAuthService(t)
+	registered := registerTestUser(t, service)
+
+	rotated, err := service.RefreshToken(registered.RefreshToken, ClientInfo{})
+	require.NoError(t, err)
+	require.NoError(t, service.db.Model(&models.RefreshToken{}).
+		Where("replaced_by_id IS NOT NULL").
+		Update("revoked_at", time.Now().Add(-2*refreshReuseGrace)).Error)
+
+	// Replaying the first token after the grace period is treated as theft
+	_, err = service.RefreshToken(registered.RefreshToken, ClientInfo{})
+	assert.ErrorIs(t, err, errRefreshTokenReused)
+
+	// ...and the legitimate successor is revoked with it
+	_, err = service.RefreshToken(rotated.RefreshToken, ClientInfo{})
+	assert.EqualError(t, err, "invalid refresh token")
+}
+
+func TestAuthService_RefreshTokenReuseGrace(t *testing.T) {
+	service := setupTestAuthService(t)
+	registered := registerTestUser(t, service)
+
+	// Two tabs refresh with the same token at once
+	first, err := service.RefreshToken(registered.RefreshToken, ClientInfo{})
+	require.NoError(t, err)
+	second, err := service.RefreshToken(registered.RefreshToken, ClientInfo{})
+	require.NoError(t, err)
+
+	// Both end up holding the same successor, and the session survives
+	firstClaims, err := service.jwtService.ValidateRefreshToken(first.RefreshToken)
+	require.NoError(t, err)
+	secondClaims, err := service.jwtService.ValidateRefreshToken(second.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, firstClaims.ID, secondClaims.ID)
+	_, err = service.jwtService.ValidateAccessToken(second.AccessToken)
+	require.NoError(t, err)
+
+	_, err = service.RefreshToken(second.RefreshToken, ClientInfo{})
+	require.NoError(t, err)
+	sessions, err := service.GetSessions(registered.User.ID.String(), uuid.Nil)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+}
+
+func TestAuthService_LogoutAndSessions(t *testing.T) {
+	service := setupTestAuthService(t)
+	registered := registerTestUser(t, service)
+
+	second, err := service.Login(&models.LoginRequest{
+		Email:    "alice@example.com",
+		Password: "secret123",
+	}, ClientInfo{UserAgent: "other-device"})
+	require.NoError(t, err)
+
+	userID := registered.User.ID.String()
+	sessions, err := service.GetSessions(userID, uuid.Nil)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	// Access tokens stop working with their session, not when they expire
+	_, err = service.jwtService.ValidateAccessToken(second.AccessToken)
+	require.NoError(t, err)
+	require.NoError(t, service.Logout(second.RefreshToken))
+	_, err = service.RefreshToken(second.RefreshToken, ClientInfo{})
+	assert.Error(t, err)
+	_, err = service.jwtService.ValidateAccessToken(second.AccessToken)
+	assert.EqualError(t, err, "session revoked")
+	_, err = service.jwtService.ValidateAccessToken(registered.AccessToken)
+	assert.NoError(t, err)
+
+	sessions, err = service.GetSessions(userID, uuid.Nil)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "test-agent", sessions[0].UserAgent)
+
+	require.NoError(t, service.RevokeSession(userID, sessions[0].ID))
+	assert.EqualError(t, service.RevokeSession(userID, sessions[0].ID), "session not found")
+	_, err = service.RefreshToken(registered.RefreshToken, ClientInfo{})
+	assert.Error(t, err)
+}
+
+func TestAuthService_UpdatePasswordRevokesSessions(t *testing.T) {
+	service := setupTestAuthService(t)
+	registered := registerTestUser(t, service)
+
+	err := service.UpdatePassword(registered.User.ID.String(), &models.UpdatePasswordRequest{
+		CurrentPassword: "secret123",
+		NewPassword:     "new-secret",
+	})
+	require.NoError(t, err)
+
+	_, err = service.RefreshToken(registered.RefreshToken, ClientInfo{})
+	assert.EqualError(t, err, "invalid refresh token")
+	_, err = service.jwtService.ValidateAccessToken(registered.AccessToken)
+	assert.Error(t, err)
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+const (
+	// calendarFeedLimit caps the number of upcoming meetings in a feed
+	calendarFeedLimit = 500
+
+	calendarProductID = "-//GoMeet//GoMeet Calendar//EN"
+	icalTimeFormat    = "20060102T150405Z"
+)
+
+// CalendarService exports meetings as iCalendar data and serves the
+// per-user subscription feeds
+type CalendarService struct {
+	db             *gorm.DB
+	meetingService *MeetingService
+	roleService    *RoleService
+	joinURLBase    string
+	feedURLBase    string
+}
+
+// NewCalendarService creates the service. Join links are joinURLBase plus
+// the meeting ID; feed URLs are feedURLBase plus the feed token.
+func NewCalendarService(db *gorm.DB, meetingService *MeetingService, roleService *RoleService, joinURLBase, feedURLBase string) *CalendarService {
+	return &CalendarService{
+		db:             db,
+		meetingService: meetingService,
+		roleService:    roleService,
+		joinURLBase:    strings.TrimSuffix(joinURLBase, "/"),
+		feedURLBase:    strings.TrimSuffix(feedURLBase, "/"),
+	}
+}
+
+// GetMeetingICS returns the meeting as an iCalendar VEVENT. The host and the
+// meeting's participants may export it.
+func (s *CalendarService) GetMeetingICS(meetingID uuid.UUID, userID uuid.UUID) ([]byte, error) {
+	if _, err := s.roleService.GetRole(meetingID, models.MeetingActor{UserID: &userID}); err != nil {
+		return nil, err
+	}
+
+	var meeting models.Meeting
+	if err := s.db.Preload("Participants.User").Preload("Host").First(&meeting, "id = ?", meetingID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("meeting not found")
+		}
+		return nil, fmt.Errorf("failed to fetch meeting: %w", err)
+	}
+
+	cal := newICalendar()
+	s.writeEvent(cal, &meeting, time.Now())
+	return cal.finish(), nil
+}
+
+// CreateFeed issues a new subscription feed URL for the user. Any previous
+// feed URL stops working.
+func (s *CalendarService) CreateFeed(userID uuid.UUID) (*models.CalendarFeed, string, error) {
+	token, err := generateFeedToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+
+	feed := &models.CalendarFeed{UserID: userID, TokenHash: hashFeedToken(token)}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.CalendarFeed{}).Error; err != nil {
+			return fmt.Errorf("failed to revoke calendar feed: %w", err)
+		}
+		if err := tx.Create(feed).Error; err != nil {
+			return fmt.Errorf("failed to create calendar feed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return feed, s.feedURLBase + "/" + token + ".ics", nil
+}
+
+// RevokeFeed stops the user's feed URL from working
+func (s *CalendarService) RevokeFeed(userID uuid.UUID) error {
+	result := s.db.Where("user_id = ?", userID).Delete(&models.CalendarFeed{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to revoke calendar feed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("calendar feed not found")
+	}
+	return nil
+}
+
+// RenderFeed returns the VCALENDAR served at a feed URL: the owner's
+// upcoming meetings plus the upcoming ones that were deleted, as cancelled
+// events so subscribed calendars drop them.
+func (s *CalendarService) RenderFeed(token string) ([]byte, error) {
+	token = strings.TrimSuffix(token, ".ics")
+
+	var feed models.CalendarFeed
+	if err := s.db.Where("token_hash = ?", hashFeedToken(token)).First(&feed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("calendar feed not found")
+		}
+		return nil, fmt.Errorf("failed to fetch calendar feed: %w", err)
+	}
+
+	// Meetings under way stay in the feed until their planned end
+	meetings, err := s.meetingService.GetInProgressMeetings(feed.UserID, models.MeetingListFilter{})
+	if err != nil {
+		return nil, err
+	}
+	upcoming, err := s.meetingService.GetUpcomingMeetings(feed.UserID, calendarFeedLimit, models.MeetingListFilter{})
+	if err != nil {
+		return nil, err
+	}
+	meetings = append(meetings, upcoming...)
+
+	var cancellations []models.MeetingCancellation
+	if err := s.db.Where("host_id = ? AND start_time > ?", feed.UserID, time.Now()).
+		Order("start_time ASC").
+		Find(&cancellations).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch cancelled meetings: %w", err)
+	}
+
+	// Attendee addresses need the participants' accounts
+	if len(meetings) > 0 {
+		ids := make([]uuid.UUID, len(meetings))
+		for i := range meetings {
+			ids[i] = meetings[i].ID
+		}
+		var participants []models.Participant
+		if err := s.db.Preload("User").Where("meeting_id IN ?", ids).Find(&participants).Error; err != nil {
+			return nil, fmt.Errorf("failed to fetch participants: %w", err)
+		}
+		byMeeting := make(map[uuid.UUID][]models.Participant)
+		for _, participant := range participants {
+			byMeeting[participant.MeetingID] = append(byMeeting[participant.MeetingID], participant)
+		}
+		for i := range meetings {
+			meetings[i].Participants = byMeeting[meetings[i].ID]
+		}
+	}
+
+	now := time.Now()
+	cal := newICalendar()
+	cal.line("X-WR-CALNAME", "GoMeet")
+	for i := range meetings {
+		s.writeEvent(cal, &meetings[i], now)
+	}
+	for i := range cancellations {
+		s.writeCancellation(cal, &cancellations[i], now)
+	}
+
+	s.db.Model(&feed).Update("last_accessed_at", now)
+	return cal.finish(), nil
+}
+
+// JoinURL returns the link participants open to join the meeting
+func (s *CalendarService) JoinURL(meetingID uuid.UUID) string {
+	return s.joinURLBase + "/" + meetingID.String()
+}
+
+func (s *CalendarService) writeEvent(cal *iCalendar, meeting *models.Meeting, now time.Time) {
+	joinURL := s.JoinURL(meeting.ID)
+
+	cal.line("BEGIN", "VEVENT")
+	cal.line("UID", eventUID(meeting.ID))
+	cal.line("DTSTAMP", formatICalTime(now))
+	cal.line("DTSTART", formatICalTime(meeting.StartTime))
+	cal.line("DTEND", formatICalTime(meeting.EndTime))
+	cal.line("SUMMARY", escapeICalText(meeting.Name))
+	cal.line("DESCRIPTION", escapeICalText("Join the meeting: "+joinURL))
+	cal.line("LOCATION", escapeICalText(joinURL))
+	cal.line("URL", joinURL)
+	cal.line("STATUS", "CONFIRMED")
+	cal.line("SEQUENCE", fmt.Sprint(calendarSequence(meeting.CreatedAt, meeting.UpdatedAt)))
+	cal.line("LAST-MODIFIED", formatICalTime(meeting.UpdatedAt))
+	if meeting.Host.ID != uuid.Nil {
+		cal.line("ORGANIZER;CN="+quoteICalParam(meeting.Host.Username), "mailto:"+meeting.Host.Email)
+	}
+	for _, participant := range meeting.Participants {
+		if participant.UserID != nil && *participant.UserID == meeting.HostID {
+			continue
+		}
+		// Invitees without an account have no email; a URN still
+		// identifies them
+		address := "urn:uuid:" + participant.ID.String()
+		name := participant.Name
+		if participant.User != nil {
+			if participant.User.Email != "" {
+				address = "mailto:" + participant.User.Email
+			}
+			if name == "" {
+				name = participant.User.Username
+			}
+		}
+		cal.line("ATTENDEE;CN="+quoteICalParam(name)+";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION", address)
+	}
+	cal.line("END", "VEVENT")
+}
+
+func (s *CalendarService) writeCancellation(cal *iCalendar, cancellation *models.MeetingCancellation, now time.Time) {
+	cal.line("BEGIN", "VEVENT")
+	cal.line("UID", eventUID(cancellation.MeetingID))
+	cal.line("DTSTAMP", formatICalTime(now))
+	cal.line("DTSTART", formatICalTime(cancellation.StartTime))
+	cal.line("DTEND", formatICalTime(cancellation.EndTime))
+	cal.line("SUMMARY", escapeICalText(cancellation.Name))
+	cal.line("STATUS", "CANCELLED")
+	cal.line("SEQUENCE", fmt.Sprint(cancellation.Sequence))
+	cal.line("LAST-MODIFIED", formatICalTime(cancellation.CancelledAt))
+	cal.line("END", "VEVENT")
+}
+
+// recordCancellations remembers the upcoming meetings among those about to
+// be deleted, so calendar feeds publish them as cancelled. Cancellations of
+// meetings that have since started are pruned.
+func recordCancellations(tx *gorm.DB, meetings []models.Meeting) error {
+	now := time.Now()
+	if err := tx.Where("start_time <= ?", now).Delete(&models.MeetingCancellation{}).Error; err != nil {
+		return fmt.Errorf("failed to prune meeting cancellations: %w", err)
+	}
+
+	for _, meeting := range meetings {
+		if !meeting.StartTime.After(now) || meeting.Status != models.MeetingStatusScheduled {
+			continue
+		}
+		cancellation := &models.MeetingCancellation{
+			MeetingID:   meeting.ID,
+			HostID:      meeting.HostID,
+			Name:        meeting.Name,
+			StartTime:   meeting.StartTime,
+			EndTime:     meeting.EndTime,
+			Sequence:    calendarSequence(meeting.CreatedAt, now),
+			CancelledAt: now,
+		}
+		if err := tx.Create(cancellation).Error; err != nil {
+			return fmt.Errorf("failed to record meeting cancellation: %w", err)
+		}
+	}
+	return nil
+}
+
+// calendarSequence derives the iCalendar SEQUENCE from the time since the
+// meeting was created, so every later revision gets a higher number
+func calendarSequence(createdAt, modifiedAt time.Time) int {
+	if !modifiedAt.After(createdAt) {
+		return 0
+	}
+	return int(modifiedAt.Sub(createdAt) / time.Second)
+}
+
+func eventUID(meetingID uuid.UUID) string {
+	return meetingID.String() + "@gomeet"
+}
+
+func generateFeedToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// iCalendar builds an RFC 5545 VCALENDAR with CRLF line endings and lines
+// folded at 75 octets
+type iCalendar struct {
+	b strings.Builder
+}
+
+func newICalendar() *iCalendar {
+	cal := &iCalendar{}
+	cal.line("BEGIN", "VCALENDAR")
+	cal.line("VERSION", "2.0")
+	cal.line("PRODID", calendarProductID)
+	cal.line("CALSCALE", "GREGORIAN")
+	cal.line("METHOD", "PUBLISH")
+	return cal
+}
+
+// line writes a content line; name may include parameters
+func (c *iCalendar) line(name, value string) {
+	line := name + ":" + value
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// Never split a UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		c.b.WriteString(line[:cut])
+		c.b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	c.b.WriteString(line)
+	c.b.WriteString("\r\n")
+}
+
+func (c *iCalendar) finish() []byte {
+	c.line("END", "VCALENDAR")
+	return []byte(c.b.String())
+}
+
+func formatICalTime(t time.Time) string {
+	return t.UTC().Format(icalTimeFormat)
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICalText(value string) string {
+	return icalTextEscaper.Replace(value)
+}
+
+// quoteICalParam quotes a parameter value; double quotes cannot be escaped
+// in parameters, so they are dropped
+func quoteICalParam(value string) string {
+	value = strings.NewReplacer(`"`, "", "\r", "", "\n", " ").Replace(value)
+	return `"` + value + `"`
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func newTestCalendarService(t *testing.T) (*gorm.DB, *MeetingService, *CalendarService) {
+	db := setupTestDB(t, &models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{},
+		&models.MeetingSeries{}, &models.CalendarFeed{}, &models.MeetingCancellation{})
+	roleService := NewRoleService(db, nil)
+	meetingService := NewMeetingService(db, nil, roleService)
+	calendarService := NewCalendarService(db, meetingService, roleService, "https://meet.example.com/meeting/", "https://api.example.com/calendar/feed")
+	return db, meetingService, calendarService
+}
+
+func TestCalendarService_GetMeetingICS(t *testing.T) {
+	db, meetingService, calendarService := newTestCalendarService(t)
+	host := createNamedTestUser(t, db, "host")
+	start := time.Date(2030, 3, 4, 15, 30, 0, 0, time.UTC)
+	meeting, err := meetingService.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:         "Design review; Q1, final",
+		StartTime:    start,
+		Participants: []models.CreateParticipantRequest{{Name: "Ana"}},
+	})
+	require.NoError(t, err)
+	member := addTestParticipant(t, db, meeting.ID, "bob", models.RoleAttendee)
+
+	data, err := calendarService.GetMeetingICS(meeting.ID, host.ID)
+	require.NoError(t, err)
+	ics := unfoldICal(string(data))
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "UID:"+meeting.ID.String()+"@gomeet\r\n")
+	assert.Contains(t, ics, "DTSTART:20300304T153000Z\r\n")
+	assert.Contains(t, ics, "DTEND:20300304T163000Z\r\n")
+	assert.Contains(t, ics, `SUMMARY:Design review\; Q1\, final`+"\r\n")
+	assert.Contains(t, ics, "URL:https://meet.example.com/meeting/"+meeting.ID.String()+"\r\n")
+	assert.Contains(t, ics, `ORGANIZER;CN="host":mailto:host@example.com`)
+	assert.Contains(t, ics, `ATTENDEE;CN="bob";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION:mailto:bob@example.com`)
+	assert.Contains(t, ics, `ATTENDEE;CN="Ana";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION:urn:uuid:`)
+	assert.Contains(t, ics, "STATUS:CONFIRMED\r\n")
+
+	// Participants may export the meeting, other users may not
+	_, err = calendarService.GetMeetingICS(meeting.ID, *member.UserID)
+	assert.NoError(t, err)
+	stranger := createNamedTestUser(t, db, "stranger")
+	_, err = calendarService.GetMeetingICS(meeting.ID, stranger.ID)
+	assert.EqualError(t, err, "not a participant")
+}
+
+func TestCalendarService_Feed(t *testing.T) {
+	db, meetingService, calendarService := newTestCalendarService(t)
+	host := createNamedTestUser(t, db, "host")
+	kept, err := meetingService.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "Kept", StartTime: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	deleted, err := meetingService.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "Dropped", StartTime: time.Now().Add(2 * time.Hour)})
+	require.NoError(t, err)
+
+	_, url, err := calendarService.CreateFeed(host.ID)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(url, "https://api.example.com/calendar/feed/"))
+	require.True(t, strings.HasSuffix(url, ".ics"))
+	token := strings.TrimPrefix(url, "https://api.example.com/calendar/feed/")
+
+	require.NoError(t, meetingService.DeleteMeeting(deleted.ID, host.ID, models.RecurrenceScopeThis))
+
+	data, err := calendarService.RenderFeed(token)
+	require.NoError(t, err)
+	events := strings.Split(unfoldICal(string(data)), "BEGIN:VEVENT")
+	require.Len(t, events, 3)
+	assert.Contains(t, events[1], "UID:"+kept.ID.String()+"@gomeet")
+	assert.Contains(t, events[1], "STATUS:CONFIRMED")
+	assert.Contains(t, events[2], "UID:"+deleted.ID.String()+"@gomeet")
+	assert.Contains(t, events[2], "STATUS:CANCELLED")
+
+	// Rotating the feed invalidates the old URL
+	_, _, err = calendarService.CreateFeed(host.ID)
+	require.NoError(t, err)
+	_, err = calendarService.RenderFeed(token)
+	assert.EqualError(t, err, "calendar feed not found")
+
+	require.NoError(t, calendarService.RevokeFeed(host.ID))
+	assert.EqualError(t, calendarService.RevokeFeed(host.ID), "calendar feed not found")
+}
+
+// unfoldICal joins folded content lines
+func unfoldICal(data string) string {
+	return strings.ReplaceAll(data, "\r\n ", "")
+}
+
+func TestICalendar_FoldsLongLines(t *testing.T) {
+	cal := &iCalendar{}
+	cal.line("DESCRIPTION", strings.Repeat("é", 100))
+
+	lines := strings.Split(strings.TrimSuffix(cal.b.String(), "\r\n"), "\r\n")
+	require.Greater(t, len(lines), 1)
+	unfolded := ""
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 75)
+		if i > 0 {
+			require.True(t, strings.HasPrefix(line, " "))
+			line = line[1:]
+		}
+		unfolded += line
+	}
+	assert.Equal(t, "DESCRIPTION:"+strings.Repeat("é", 100), unfolded)
+}
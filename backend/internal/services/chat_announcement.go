@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// AcknowledgeMessage records that a participant acknowledged an announcement
+// asking for it. Acknowledging twice is a no-op.
+func (s *ChatService) AcknowledgeMessage(userID *uuid.UUID, publicUserID *uuid.UUID, messageID uuid.UUID) error {
+	var message models.ChatMessage
+	if err := s.db.Scopes(chatVisibilityScope(models.MeetingActor{UserID: userID, PublicUserID: publicUserID})).
+		Where("id = ? AND is_deleted = ?", messageID, false).First(&message).Error; err != nil {
+		return fmt.Errorf("message not found")
+	}
+	if message.MessageType != models.MessageTypeAnnouncement || !message.RequiresAck {
+		return fmt.Errorf("message does not require acknowledgement")
+	}
+
+	// Check if already acknowledged
+	var existing models.ChatMessageAcknowledgement
+	query := s.db.Where("message_id = ?", messageID)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	} else {
+		query = query.Where("public_user_id = ?", *publicUserID)
+	}
+	if err := query.First(&existing).Error; err == nil {
+		return nil
+	}
+
+	acknowledgement := models.ChatMessageAcknowledgement{
+		MessageID:    messageID,
+		UserID:       userID,
+		PublicUserID: publicUserID,
+	}
+	if err := s.db.Create(&acknowledgement).Error; err != nil {
+		return fmt.Errorf("failed to acknowledge message: %w", err)
+	}
+
+	go s.broadcastAcknowledgement(&message, &acknowledgement)
+
+	return nil
+}
+
+// GetAcknowledgements tells a host or co-host who acknowledged an
+// announcement, and which active participants still have to
+func (s *ChatService) GetAcknowledgements(meetingID, messageID, actorID uuid.UUID) (*models.ChatAcknowledgementSummary, error) {
+	if err := s.authorizeModerator(meetingID, actorID); err != nil {
+		return nil, err
+	}
+
+	var message models.ChatMessage
+	if err := s.db.Where("id = ? AND meeting_id = ? AND is_deleted = ?", messageID, meetingID, false).
+		First(&message).Error; err != nil {
+		return nil, errors.New("message not found")
+	}
+	if message.MessageType != models.MessageTypeAnnouncement || !message.RequiresAck {
+		return nil, errors.New("message does not require acknowledgement")
+	}
+
+	var acknowledgements []models.ChatMessageAcknowledgement
+	if err := s.db.Preload("User").Preload("PublicUser").
+		Where("message_id = ?", messageID).
+		Order("acknowledged_at ASC").
+		Find(&acknowledgements).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch acknowledgements: %w", err)
+	}
+
+	var participants []models.Participant
+	if err := s.db.Where("meeting_id = ? AND is_active = ? AND lobby_status = ?", meetingID, true, models.LobbyStatusAdmitted).
+		Order("joined_at ASC").
+		Find(&participants).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch participants: %w", err)
+	}
+
+	summary := &models.ChatAcknowledgementSummary{
+		MessageID:    messageID,
+		Acknowledged: make([]models.ChatMessageAcknowledgementResponse, 0, len(acknowledgements)),
+		Pending:      []models.ChatPendingAcknowledgement{},
+	}
+	acknowledged := []models.MeetingActor{{UserID: message.UserID, PublicUserID: message.PublicUserID}}
+	for i := range acknowledgements {
+		summary.Acknowledged = append(summary.Acknowledged, acknowledgements[i].ToResponse())
+		acknowledged = append(acknowledged, models.MeetingActor{UserID: acknowledgements[i].UserID, PublicUserID: acknowledgements[i].PublicUserID})
+	}
+	for _, participant := range participants {
+		actor := models.MeetingActor{UserID: participant.UserID, PublicUserID: participant.PublicUserID}
+		if containsActor(acknowledged, actor) {
+			continue
+		}
+		summary.Pending = append(summary.Pending, models.ChatPendingAcknowledgement{
+			ParticipantID: participant.ID,
+			UserID:        participant.UserID,
+			PublicUserID:  participant.PublicUserID,
+			Name:          participant.Name,
+		})
+	}
+	return summary, nil
+}
+
+// checkAnnouncement allows announcements from hosts and co-hosts only, sent
+// to the whole meeting
+func (s *ChatService) checkAnnouncement(req *models.CreateChatMessageRequest, sender models.MeetingActor) error {
+	var meeting models.Meeting
+	if err := s.db.Select("id, host_id").Where("id = ?", req.MeetingID).First(&meeting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("meeting not found")
+		}
+		return fmt.Errorf("failed to fetch meeting: %w", err)
+	}
+	if !s.isChatModerator(&meeting, sender) {
+		return errors.New("announcements restricted to hosts")
+	}
+	if req.ReplyToID != nil || len(req.RecipientIDs) > 0 {
+		return errors.New("announcements must be meeting-wide")
+	}
+	return nil
+}
+
+// containsActor reports whether actor is one of actors
+func containsActor(actors []models.MeetingActor, actor models.MeetingActor) bool {
+	for _, a := range actors {
+		if sameActor(a, actor) {
+			return true
+		}
+	}
+	return false
+}
+
+// broadcastAcknowledgement tells the meeting's hosts and co-hosts that an
+// announcement was acknowledged
+func (s *ChatService) broadcastAcknowledgement(message *models.ChatMessage, acknowledgement *models.ChatMessageAcknowledgement) {
+	if s.webSocketService == nil {
+		return
+	}
+
+	var count int64
+	if err := s.db.Model(&models.ChatMessageAcknowledgement{}).
+		Where("message_id = ?", message.ID).
+		Count(&count).Error; err != nil {
+		return
+	}
+
+	s.webSocketService.SendMessageToModerators(message.MeetingID.String(), models.SignalingMessage{
+		Type:      models.SignalingTypeChatAcknowledged,
+		MeetingID: message.MeetingID.String(),
+		Data: models.ChatAcknowledgedPayload{
+			MessageID:         message.ID,
+			UserID:            acknowledgement.UserID,
+			PublicUserID:      acknowledgement.PublicUserID,
+			AcknowledgedAt:    acknowledgement.AcknowledgedAt,
+			AcknowledgedCount: int(count),
+		},
+		Timestamp: time.Now(),
+	})
+}
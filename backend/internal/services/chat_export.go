@@ -0,0 +1,463 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// chatExportBatchSize is how many messages a transcript export loads at a
+// time; each batch is written out before the next one is fetched
+const chatExportBatchSize = 200
+
+// transcriptTimeLayout is how Markdown, HTML and CSV transcripts show times
+const transcriptTimeLayout = "2006-01-02 15:04:05 MST"
+
+// PrepareTranscript checks that actor may export the meeting's chat, which
+// the host and the meeting's participants may, and resolves the time zone
+// the transcript is shown in: timeZone when given, else the user's
+// preference, else UTC. Nothing is read from the history until the
+// transcript is written.
+func (s *ChatService) PrepareTranscript(meetingID uuid.UUID, actor models.MeetingActor, format models.ChatExportFormat, timeZone string) (*models.ChatTranscript, error) {
+	if !format.IsValid() {
+		return nil, errors.New("invalid export format")
+	}
+	if err := s.checkReader(meetingID, actor); err != nil {
+		return nil, err
+	}
+
+	if timeZone == "" && actor.UserID != nil {
+		var user models.User
+		if err := s.db.Select("id, time_zone").Where("id = ?", *actor.UserID).First(&user).Error; err == nil {
+			timeZone = user.TimeZone
+		}
+	}
+	location, err := models.LoadTimeZone(timeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	var meeting models.Meeting
+	if err := s.db.Select("id, name").Where("id = ?", meetingID).First(&meeting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("meeting not found")
+		}
+		return nil, fmt.Errorf("failed to fetch meeting: %w", err)
+	}
+
+	return &models.ChatTranscript{
+		MeetingID:  meetingID,
+		Title:      meeting.Name,
+		Format:     format,
+		Location:   location,
+		Viewer:     actor,
+		ExportedAt: time.Now(),
+	}, nil
+}
+
+// WriteTranscript writes the messages of a prepared transcript to w, oldest
+// first, replies included. Messages are loaded in batches and w is flushed
+// after each one when it supports it, so large meetings are streamed rather
+// than held in memory.
+func (s *ChatService) WriteTranscript(w io.Writer, transcript *models.ChatTranscript) error {
+	writer := newTranscriptWriter(w, transcript)
+	if err := writer.begin(); err != nil {
+		return err
+	}
+
+	var cursor *models.ChatCursor
+	for {
+		var messages []models.ChatMessage
+		if err := s.db.Scopes(transcriptPreloads, chatVisibilityScope(transcript.Viewer), timelineScope, keysetScope(cursor, models.ChatHistoryNewer)).
+			Where("chat_messages.meeting_id = ?", transcript.MeetingID).
+			Limit(chatExportBatchSize).
+			Find(&messages).Error; err != nil {
+			return fmt.Errorf("failed to fetch messages: %w", err)
+		}
+
+		for i := range messages {
+			s.signAttachments(&messages[i])
+			if err := writer.write(transcriptEntry(&messages[i], transcript.Location)); err != nil {
+				return err
+			}
+		}
+		if err := writer.flush(); err != nil {
+			return err
+		}
+
+		if len(messages) < chatExportBatchSize {
+			break
+		}
+		last := models.CursorOf(&messages[len(messages)-1])
+		cursor = &last
+	}
+
+	return writer.end()
+}
+
+// transcriptPreloads loads what a transcript shows of a message: who sent
+// it, who it answered and who it was addressed to
+func transcriptPreloads(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").
+		Preload("PublicUser").
+		Preload("ReplyTo.User").
+		Preload("ReplyTo.PublicUser").
+		Preload("Reactions").
+		Preload("Attachment").
+		Preload("Recipients")
+}
+
+// transcriptEntry flattens a message for a transcript shown in loc
+func transcriptEntry(message *models.ChatMessage, loc *time.Location) models.ChatTranscriptEntry {
+	entry := models.ChatTranscriptEntry{
+		ID:          message.ID,
+		SentAt:      message.CreatedAt.In(loc),
+		Sender:      senderName(message),
+		MessageType: message.MessageType,
+		Content:     message.Content,
+		ReplyToID:   message.ReplyToID,
+		Removed:     message.IsDeleted,
+	}
+	if message.ReplyTo != nil {
+		entry.ReplyToSender = senderName(message.ReplyTo)
+	}
+	if message.IsEdited && message.EditedAt != nil {
+		editedAt := message.EditedAt.In(loc)
+		entry.EditedAt = &editedAt
+	}
+	for _, recipient := range message.Recipients {
+		entry.Recipients = append(entry.Recipients, recipient.Name)
+	}
+
+	// Reactions are counted per emoji, in the order they were first used
+	counts := make(map[string]int)
+	for _, reaction := range message.Reactions {
+		if counts[reaction.Reaction] == 0 {
+			entry.Reactions = append(entry.Reactions, models.ChatTranscriptReaction{Reaction: reaction.Reaction})
+		}
+		counts[reaction.Reaction]++
+	}
+	for i := range entry.Reactions {
+		entry.Reactions[i].Count = counts[entry.Reactions[i].Reaction]
+	}
+
+	if message.Attachment != nil && !message.IsDeleted {
+		entry.Attachment = &models.ChatTranscriptAttachment{
+			Name:        message.Attachment.FileName,
+			ContentType: message.Attachment.ContentType,
+			URL:         message.Attachment.URL,
+		}
+	}
+	return entry
+}
+
+// senderName returns the display name of a message's sender
+func senderName(message *models.ChatMessage) string {
+	switch {
+	case message.User != nil:
+		return message.User.Username
+	case message.PublicUser != nil:
+		return message.PublicUser.Name
+	case message.MessageType == models.MessageTypeSystem:
+		return "System"
+	default:
+		return "Unknown"
+	}
+}
+
+// transcriptWriter renders transcript entries in one export format
+type transcriptWriter interface {
+	begin() error
+	write(entry models.ChatTranscriptEntry) error
+	flush() error
+	end() error
+}
+
+func newTranscriptWriter(w io.Writer, transcript *models.ChatTranscript) transcriptWriter {
+	switch transcript.Format {
+	case models.ChatExportMarkdown:
+		return &markdownTranscriptWriter{w: w, transcript: transcript}
+	case models.ChatExportHTML:
+		return &htmlTranscriptWriter{w: w, transcript: transcript}
+	case models.ChatExportCSV:
+		return &csvTranscriptWriter{w: w, csv: csv.NewWriter(w)}
+	default:
+		return &jsonTranscriptWriter{w: w, transcript: transcript}
+	}
+}
+
+// flushWriter flushes w if it buffers, like an HTTP response does
+func flushWriter(w io.Writer) {
+	if flusher, ok := w.(interface{ Flush() }); ok {
+		flusher.Flush()
+	}
+}
+
+// jsonTranscriptWriter writes a single JSON document whose messages array
+// is filled in one entry at a time
+type jsonTranscriptWriter struct {
+	w          io.Writer
+	transcript *models.ChatTranscript
+	written    int
+}
+
+func (t *jsonTranscriptWriter) begin() error {
+	header, err := json.Marshal(struct {
+		MeetingID  uuid.UUID `json:"meetingId"`
+		Title      string    `json:"title"`
+		TimeZone   string    `json:"timeZone"`
+		ExportedAt time.Time `json:"exportedAt"`
+	}{t.transcript.MeetingID, t.transcript.Title, t.transcript.Location.String(), t.transcript.ExportedAt.In(t.transcript.Location)})
+	if err != nil {
+		return err
+	}
+	// The header object is reopened to append the messages to it
+	_, err = fmt.Fprintf(t.w, "%s,\"messages\":[", header[:len(header)-1])
+	return err
+}
+
+func (t *jsonTranscriptWriter) write(entry models.ChatTranscriptEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if t.written > 0 {
+		if _, err := io.WriteString(t.w, ","); err != nil {
+			return err
+		}
+	}
+	t.written++
+	_, err = t.w.Write(data)
+	return err
+}
+
+func (t *jsonTranscriptWriter) flush() error {
+	flushWriter(t.w)
+	return nil
+}
+
+func (t *jsonTranscriptWriter) end() error {
+	_, err := io.WriteString(t.w, "]}\n")
+	return err
+}
+
+// markdownEscaper keeps names and message text from being read as Markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+type markdownTranscriptWriter struct {
+	w          io.Writer
+	transcript *models.ChatTranscript
+}
+
+func (t *markdownTranscriptWriter) begin() error {
+	_, err := fmt.Fprintf(t.w, "# Chat transcript: %s\n\nExported %s. Times are in %s.\n\n",
+		markdownEscaper.Replace(t.transcript.Title),
+		t.transcript.ExportedAt.In(t.transcript.Location).Format(transcriptTimeLayout),
+		t.transcript.Location)
+	return err
+}
+
+func (t *markdownTranscriptWriter) write(entry models.ChatTranscriptEntry) error {
+	var b strings.Builder
+
+	// Lines within an entry end in two spaces, Markdown's hard line break
+	fmt.Fprintf(&b, "**%s** · %s", markdownEscaper.Replace(entry.Sender), entry.SentAt.Format(transcriptTimeLayout))
+	if entry.MessageType == models.MessageTypeAnnouncement {
+		b.WriteString(" · announcement")
+	}
+	if entry.EditedAt != nil {
+		b.WriteString(" · edited")
+	}
+	if len(entry.Recipients) > 0 {
+		fmt.Fprintf(&b, " · private to %s", markdownEscaper.Replace(strings.Join(entry.Recipients, ", ")))
+	}
+	b.WriteString("  \n")
+	if entry.ReplyToID != nil {
+		fmt.Fprintf(&b, "↳ in reply to %s  \n", markdownEscaper.Replace(entry.ReplyToSender))
+	}
+
+	switch {
+	case entry.Removed:
+		b.WriteString("_Removed by a moderator_  \n")
+	case entry.Content != "":
+		for _, line := range strings.Split(entry.Content, "\n") {
+			b.WriteString(markdownEscaper.Replace(line))
+			b.WriteString("  \n")
+		}
+	}
+	if entry.Attachment != nil {
+		fmt.Fprintf(&b, "📎 [%s](<%s>)  \n", markdownEscaper.Replace(entry.Attachment.Name), entry.Attachment.URL)
+	}
+	if len(entry.Reactions) > 0 {
+		reactions := make([]string, 0, len(entry.Reactions))
+		for _, reaction := range entry.Reactions {
+			reactions = append(reactions, reaction.Reaction+" "+strconv.Itoa(reaction.Count))
+		}
+		b.WriteString(strings.Join(reactions, " · "))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(t.w, b.String())
+	return err
+}
+
+func (t *markdownTranscriptWriter) flush() error {
+	flushWriter(t.w)
+	return nil
+}
+
+func (t *markdownTranscriptWriter) end() error {
+	return nil
+}
+
+// transcriptHTML renders a self-contained page: the styles are inlined and
+// nothing is loaded from elsewhere, attachments aside
+var transcriptHTML = template.Must(template.New("transcript").Funcs(template.FuncMap{
+	"clock": func(t time.Time) string { return t.Format(transcriptTimeLayout) },
+	"iso":   func(t time.Time) string { return t.Format(time.RFC3339) },
+	"join":  func(values []string) string { return strings.Join(values, ", ") },
+}).Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Chat transcript: {{.Title}}</title>
+<style>
+body{font-family:system-ui,-apple-system,"Segoe UI",sans-serif;max-width:48rem;margin:2rem auto;padding:0 1rem;color:#1f2328}
+h1{font-size:1.5rem;margin-bottom:.25rem}
+.meta{color:#656d76;margin-top:0}
+ol{list-style:none;padding:0}
+li{padding:.75rem 0;border-bottom:1px solid #d0d7de}
+li.announcement{background:#fff8c5;padding:.75rem}
+.head{font-size:.875rem;color:#656d76}
+.sender{font-weight:600;color:#1f2328}
+.content{white-space:pre-wrap;margin:.25rem 0}
+.removed .content{font-style:italic;color:#656d76}
+.reply a{color:#656d76;font-size:.875rem}
+.reactions span{display:inline-block;border:1px solid #d0d7de;border-radius:1rem;padding:0 .5rem;margin-right:.25rem;font-size:.875rem}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Exported {{clock .ExportedAt}}. Times are in {{.TimeZone}}.</p>
+<ol>
+{{end}}
+{{define "entry"}}<li id="m-{{.ID}}" class="{{if eq .MessageType "announcement"}}announcement{{end}}{{if .Removed}} removed{{end}}">
+<div class="head"><span class="sender">{{.Sender}}</span> · <time datetime="{{iso .SentAt}}">{{clock .SentAt}}</time>{{if .EditedAt}} · edited{{end}}{{if .Recipients}} · private to {{join .Recipients}}{{end}}</div>
+{{if .ReplyToID}}<div class="reply"><a href="#m-{{.ReplyToID}}">↳ in reply to {{.ReplyToSender}}</a></div>
+{{end}}{{if .Removed}}<p class="content">Removed by a moderator</p>
+{{else if .Content}}<p class="content">{{.Content}}</p>
+{{end}}{{with .Attachment}}<p class="attachment">📎 <a href="{{.URL}}">{{.Name}}</a></p>
+{{end}}{{if .Reactions}}<p class="reactions">{{range .Reactions}}<span>{{.Reaction}} {{.Count}}</span>{{end}}</p>
+{{end}}</li>
+{{end}}
+{{define "footer"}}</ol>
+</body>
+</html>
+{{end}}`))
+
+type htmlTranscriptWriter struct {
+	w          io.Writer
+	transcript *models.ChatTranscript
+}
+
+func (t *htmlTranscriptWriter) begin() error {
+	return transcriptHTML.ExecuteTemplate(t.w, "header", struct {
+		Title      string
+		TimeZone   string
+		ExportedAt time.Time
+	}{t.transcript.Title, t.transcript.Location.String(), t.transcript.ExportedAt.In(t.transcript.Location)})
+}
+
+func (t *htmlTranscriptWriter) write(entry models.ChatTranscriptEntry) error {
+	return transcriptHTML.ExecuteTemplate(t.w, "entry", entry)
+}
+
+func (t *htmlTranscriptWriter) flush() error {
+	flushWriter(t.w)
+	return nil
+}
+
+func (t *htmlTranscriptWriter) end() error {
+	return transcriptHTML.ExecuteTemplate(t.w, "footer", nil)
+}
+
+type csvTranscriptWriter struct {
+	w   io.Writer
+	csv *csv.Writer
+}
+
+func (t *csvTranscriptWriter) begin() error {
+	return t.csv.Write([]string{
+		"id", "sent_at", "sender", "message_type", "content", "reply_to_id", "reply_to_sender",
+		"private_to", "edited_at", "removed", "reactions", "attachment_name", "attachment_url",
+	})
+}
+
+func (t *csvTranscriptWriter) write(entry models.ChatTranscriptEntry) error {
+	var replyToID, editedAt string
+	if entry.ReplyToID != nil {
+		replyToID = entry.ReplyToID.String()
+	}
+	if entry.EditedAt != nil {
+		editedAt = entry.EditedAt.Format(transcriptTimeLayout)
+	}
+	reactions := make([]string, 0, len(entry.Reactions))
+	for _, reaction := range entry.Reactions {
+		reactions = append(reactions, reaction.Reaction+" "+strconv.Itoa(reaction.Count))
+	}
+	var attachmentName, attachmentURL string
+	if entry.Attachment != nil {
+		attachmentName, attachmentURL = entry.Attachment.Name, entry.Attachment.URL
+	}
+
+	return t.csv.Write([]string{
+		entry.ID.String(),
+		entry.SentAt.Format(transcriptTimeLayout),
+		csvText(entry.Sender),
+		string(entry.MessageType),
+		csvText(entry.Content),
+		replyToID,
+		csvText(entry.ReplyToSender),
+		csvText(strings.Join(entry.Recipients, ", ")),
+		editedAt,
+		strconv.FormatBool(entry.Removed),
+		strings.Join(reactions, " "),
+		csvText(attachmentName),
+		attachmentURL,
+	})
+}
+
+func (t *csvTranscriptWriter) flush() error {
+	t.csv.Flush()
+	flushWriter(t.w)
+	return t.csv.Error()
+}
+
+func (t *csvTranscriptWriter) end() error {
+	t.csv.Flush()
+	return t.csv.Error()
+}
+
+// csvText keeps spreadsheets from running user text as a formula
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
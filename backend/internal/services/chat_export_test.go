@@ -0,0 +1,112 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestChatService_ExportTranscript(t *testing.T) {
+	db := setupTestChatDB(t)
+	service := NewChatService(db, nil, nil, NewRoleService(db, nil))
+
+	meeting, hostID := createTestMeeting(t, db)
+	guest := addTestParticipant(t, db, meeting.ID, "guest", models.RoleAttendee)
+	other := addTestParticipant(t, db, meeting.ID, "other", models.RoleAttendee)
+	outsider := createNamedTestUser(t, db, "outsider")
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", *guest.UserID).Update("time_zone", "Asia/Tokyo").Error)
+
+	send := func(userID uuid.UUID, req models.CreateChatMessageRequest) *models.ChatMessage {
+		req.MeetingID = meeting.ID
+		req.MessageType = models.MessageTypeText
+		message, err := service.SendMessage(&userID, nil, &req)
+		require.NoError(t, err)
+		return message
+	}
+	hello := send(hostID, models.CreateChatMessageRequest{Content: "=1+1 *hello*"})
+	send(*guest.UserID, models.CreateChatMessageRequest{Content: "hi <b>host</b>", ReplyToID: &hello.ID})
+	send(hostID, models.CreateChatMessageRequest{Content: "just for other", RecipientIDs: []uuid.UUID{other.ID}})
+	_, err := service.ToggleReaction(guest.UserID, nil, &models.CreateChatMessageReactionRequest{MessageID: hello.ID, Reaction: "👍"})
+	require.NoError(t, err)
+	edited := "=1+1 *hello* all"
+	_, err = service.UpdateMessage(hostID, hello.ID, &models.UpdateChatMessageRequest{Content: &edited})
+	require.NoError(t, err)
+
+	_, err = service.PrepareTranscript(meeting.ID, models.MeetingActor{UserID: &outsider.ID}, models.ChatExportJSON, "")
+	assert.EqualError(t, err, "not a participant")
+	_, err = service.PrepareTranscript(meeting.ID, models.MeetingActor{UserID: &hostID}, models.ChatExportJSON, "Mars/Olympus")
+	assert.EqualError(t, err, "invalid time zone")
+
+	export := func(format models.ChatExportFormat, timeZone string) string {
+		transcript, err := service.PrepareTranscript(meeting.ID, models.MeetingActor{UserID: guest.UserID}, format, timeZone)
+		require.NoError(t, err)
+		var out bytes.Buffer
+		require.NoError(t, service.WriteTranscript(&out, transcript))
+		return out.String()
+	}
+
+	// The guest's own time zone applies, and the private message is left out
+	var document struct {
+		TimeZone string                       `json:"timeZone"`
+		Messages []models.ChatTranscriptEntry `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(export(models.ChatExportJSON, "")), &document))
+	assert.Equal(t, "Asia/Tokyo", document.TimeZone)
+	require.Len(t, document.Messages, 2)
+	first, reply := document.Messages[0], document.Messages[1]
+	assert.Equal(t, "host", first.Sender)
+	assert.NotNil(t, first.EditedAt)
+	assert.Equal(t, []models.ChatTranscriptReaction{{Reaction: "👍", Count: 1}}, first.Reactions)
+	_, offset := first.SentAt.Zone()
+	assert.Equal(t, 9*60*60, offset)
+	assert.Equal(t, "guest", reply.Sender)
+	assert.Equal(t, "host", reply.ReplyToSender)
+
+	markdown := export(models.ChatExportMarkdown, "UTC")
+	assert.Contains(t, markdown, "**host** · ")
+	assert.Contains(t, markdown, `=1+1 \*hello\* all`)
+	assert.Contains(t, markdown, "↳ in reply to host")
+	assert.Contains(t, markdown, "👍 1")
+	assert.NotContains(t, markdown, "just for other")
+
+	page := export(models.ChatExportHTML, "")
+	assert.Contains(t, page, "<!DOCTYPE html>")
+	assert.Contains(t, page, "hi &lt;b&gt;host&lt;/b&gt;")
+	assert.Contains(t, page, `href="#m-`+hello.ID.String()+`"`)
+	assert.Contains(t, page, "</html>")
+
+	rows, err := csv.NewReader(bytes.NewBufferString(export(models.ChatExportCSV, ""))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "content", rows[0][4])
+	assert.Equal(t, "'=1+1 *hello* all", rows[1][4])
+	assert.Equal(t, hello.ID.String(), rows[2][5])
+}
+
+func TestChatService_ExportTranscriptInBatches(t *testing.T) {
+	db := setupTestChatDB(t)
+	service := NewChatService(db, nil, nil, NewRoleService(db, nil))
+	meeting, hostID := createTestMeeting(t, db)
+	createTestChatMessages(t, db, meeting.ID, hostID, chatExportBatchSize+5)
+
+	transcript, err := service.PrepareTranscript(meeting.ID, models.MeetingActor{UserID: &hostID}, models.ChatExportJSON, "")
+	require.NoError(t, err)
+	var out bytes.Buffer
+	require.NoError(t, service.WriteTranscript(&out, transcript))
+
+	var document struct {
+		Messages []models.ChatTranscriptEntry `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &document))
+	require.Len(t, document.Messages, chatExportBatchSize+5)
+	for i := 1; i < len(document.Messages); i++ {
+		assert.False(t, document.Messages[i].SentAt.Before(document.Messages[i-1].SentAt))
+	}
+}
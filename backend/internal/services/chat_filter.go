@@ -0,0 +1,151 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// NewChatContentFilter builds the content filter described by the config:
+// the configured word list, followed by the rules of the rules file. It
+// returns nil when neither is set.
+func NewChatContentFilter(cfg config.ChatConfig) (models.ChatContentFilter, error) {
+	var rules []models.ChatFilterRule
+	if len(cfg.FilterWords) > 0 {
+		rules = append(rules, models.ChatFilterRule{
+			Name:   "words",
+			Words:  cfg.FilterWords,
+			Action: models.ChatFilterAction(cfg.FilterAction),
+		})
+	}
+	if cfg.FilterRulesFile != "" {
+		data, err := os.ReadFile(cfg.FilterRulesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chat filter rules: %w", err)
+		}
+		var fileRules []models.ChatFilterRule
+		if err := json.Unmarshal(data, &fileRules); err != nil {
+			return nil, fmt.Errorf("failed to parse chat filter rules: %w", err)
+		}
+		rules = append(rules, fileRules...)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	filter, err := NewRuleChatFilter(rules)
+	if err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// RuleChatFilter is a content filter made of word list and regular
+// expression rules. A blocking rule refuses the message outright; masking
+// rules apply one after the other and flagging rules are all reported.
+type RuleChatFilter struct {
+	rules []chatFilterRule
+}
+
+type chatFilterRule struct {
+	name    string
+	pattern *regexp.Regexp
+	action  models.ChatFilterAction
+}
+
+func NewRuleChatFilter(rules []models.ChatFilterRule) (*RuleChatFilter, error) {
+	filter := &RuleChatFilter{}
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+		if !rule.Action.IsValid() {
+			return nil, fmt.Errorf("chat filter rule %q: unknown action %q", name, rule.Action)
+		}
+
+		var source string
+		switch {
+		case len(rule.Words) > 0 && rule.Pattern == "":
+			source = wordListPattern(rule.Words)
+		case len(rule.Words) == 0 && rule.Pattern != "":
+			source = rule.Pattern
+		default:
+			return nil, fmt.Errorf("chat filter rule %q: set either words or a pattern", name)
+		}
+		if source == "" {
+			return nil, fmt.Errorf("chat filter rule %q: word list is empty", name)
+		}
+
+		pattern, err := regexp.Compile(source)
+		if err != nil {
+			return nil, fmt.Errorf("chat filter rule %q: %w", name, err)
+		}
+		filter.rules = append(filter.rules, chatFilterRule{name: name, pattern: pattern, action: rule.Action})
+	}
+	if len(filter.rules) == 0 {
+		return nil, errors.New("chat filter has no rules")
+	}
+	return filter, nil
+}
+
+// Check runs every rule against the message text
+func (f *RuleChatFilter) Check(content string) models.ChatFilterResult {
+	result := models.ChatFilterResult{Content: content}
+	for _, rule := range f.rules {
+		if !rule.pattern.MatchString(content) {
+			continue
+		}
+		switch rule.action {
+		case models.ChatFilterBlock:
+			return models.ChatFilterResult{Content: content, Blocked: true}
+		case models.ChatFilterMask:
+			result.Content = rule.pattern.ReplaceAllStringFunc(result.Content, maskText)
+		case models.ChatFilterFlag:
+			result.Flags = append(result.Flags, rule.name)
+		}
+	}
+	return result
+}
+
+// wordListPattern matches any of words as a whole word, ignoring case. A
+// word boundary is only required next to ASCII letters and digits, as that
+// is all \b knows about, so entries such as "f*ck" still match.
+func wordListPattern(words []string) string {
+	alternatives := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		alternative := regexp.QuoteMeta(word)
+		if first, _ := utf8.DecodeRuneInString(word); isWordRune(first) {
+			alternative = `\b` + alternative
+		}
+		if last, _ := utf8.DecodeLastRuneInString(word); isWordRune(last) {
+			alternative += `\b`
+		}
+		alternatives = append(alternatives, alternative)
+	}
+	if len(alternatives) == 0 {
+		return ""
+	}
+	return `(?i)(?:` + strings.Join(alternatives, "|") + `)`
+}
+
+func isWordRune(r rune) bool {
+	return r < unicode.MaxASCII && (r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// maskText replaces every character of text with an asterisk
+func maskText(text string) string {
+	return strings.Repeat("*", utf8.RuneCountInString(text))
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// chatSearchHeadlineOptions configures ts_headline. Matches are delimited by
+// private-use characters rather than markup so the snippet can be escaped
+// before the <mark> elements are put in.
+const chatSearchHeadlineOptions = "StartSel=\uE000, StopSel=\uE001, MaxWords=24, MinWords=8, MaxFragments=2, FragmentDelimiter=\" … \""
+
+var chatSnippetMarks = strings.NewReplacer("\uE000", "<mark>", "\uE001", "</mark>")
+
+// GetMessages returns a page of a meeting's main timeline, oldest message
+// first. Pages are keyed on (created_at, id), so they stay stable while new
+// messages arrive. Replies are left out; they are paged through GetThread.
+// Messages a moderator removed stay as empty tombstones.
+func (s *ChatService) GetMessages(meetingID uuid.UUID, query models.ChatHistoryQuery) ([]models.ChatMessage, models.ChatCursorPagination, error) {
+	if query.Around != nil {
+		// Around a reply, the timeline centres on the message that started its thread
+		var target models.ChatMessage
+		if err := s.db.Select("id, reply_to_id").Scopes(chatVisibilityScope(query.Viewer), timelineScope).
+			Where("id = ? AND meeting_id = ?", *query.Around, meetingID).
+			First(&target).Error; err != nil {
+			return nil, models.ChatCursorPagination{Limit: query.Limit}, errors.New("message not found")
+		}
+		if target.ReplyToID != nil {
+			query.Around = target.ReplyToID
+		}
+	}
+	return s.history(meetingID, nil, query)
+}
+
+// history pages through the main timeline, or through the replies of thread
+// when it is set
+func (s *ChatService) history(meetingID uuid.UUID, thread *uuid.UUID, query models.ChatHistoryQuery) ([]models.ChatMessage, models.ChatCursorPagination, error) {
+	pagination := models.ChatCursorPagination{Limit: query.Limit}
+
+	var messages []models.ChatMessage
+	var err error
+	switch {
+	case query.Around != nil:
+		var target models.ChatMessage
+		if err := s.db.Scopes(chatPagePreloads, threadScope(thread), chatVisibilityScope(query.Viewer), timelineScope).
+			Where("chat_messages.id = ? AND chat_messages.meeting_id = ?", *query.Around, meetingID).
+			First(&target).Error; err != nil {
+			return nil, pagination, errors.New("message not found")
+		}
+
+		// The target takes one slot; the rest is split between both sides
+		cursor := models.CursorOf(&target)
+		olderLimit := (query.Limit - 1) / 2
+		older, hasOlder, err := s.fetchHistory(meetingID, thread, query.Viewer, &cursor, models.ChatHistoryOlder, olderLimit)
+		if err != nil {
+			return nil, pagination, err
+		}
+		newer, hasNewer, err := s.fetchHistory(meetingID, thread, query.Viewer, &cursor, models.ChatHistoryNewer, query.Limit-1-olderLimit)
+		if err != nil {
+			return nil, pagination, err
+		}
+		slices.Reverse(older)
+		messages = append(append(older, target), newer...)
+		pagination.HasOlder, pagination.HasNewer = hasOlder, hasNewer
+
+	case query.Direction == models.ChatHistoryNewer:
+		messages, pagination.HasNewer, err = s.fetchHistory(meetingID, thread, query.Viewer, query.Cursor, models.ChatHistoryNewer, query.Limit)
+		if err != nil {
+			return nil, pagination, err
+		}
+		pagination.HasOlder = query.Cursor != nil
+
+	default:
+		messages, pagination.HasOlder, err = s.fetchHistory(meetingID, thread, query.Viewer, query.Cursor, models.ChatHistoryOlder, query.Limit)
+		if err != nil {
+			return nil, pagination, err
+		}
+		slices.Reverse(messages)
+		pagination.HasNewer = query.Cursor != nil
+	}
+
+	listed := make([]*models.ChatMessage, len(messages))
+	for i := range messages {
+		s.signAttachments(&messages[i])
+		listed[i] = &messages[i]
+	}
+	if err := s.countSeenBy(listed...); err != nil {
+		return nil, pagination, err
+	}
+	if err := s.summarizeReactions(query.Viewer, listed...); err != nil {
+		return nil, pagination, err
+	}
+
+	if len(messages) > 0 {
+		pagination.OlderCursor = models.CursorOf(&messages[0]).Encode()
+		pagination.NewerCursor = models.CursorOf(&messages[len(messages)-1]).Encode()
+	}
+	return messages, pagination, nil
+}
+
+// checkReader makes sure actor may read a meeting's chat outside of the
+// live timeline: the host and admitted participants may
+func (s *ChatService) checkReader(meetingID uuid.UUID, actor models.MeetingActor) error {
+	if s.roleService == nil {
+		return errors.New("permission denied")
+	}
+	_, err := s.roleService.GetRole(meetingID, actor)
+	return err
+}
+
+// fetchHistory loads up to limit messages beyond the cursor, nearest first,
+// and reports whether there are more
+func (s *ChatService) fetchHistory(meetingID uuid.UUID, thread *uuid.UUID, viewer models.MeetingActor, cursor *models.ChatCursor, direction models.ChatHistoryDirection, limit int) ([]models.ChatMessage, bool, error) {
+	var messages []models.ChatMessage
+	if err := s.db.Scopes(chatPagePreloads, threadScope(thread), chatVisibilityScope(viewer), timelineScope, keysetScope(cursor, direction)).
+		Where("chat_messages.meeting_id = ?", meetingID).
+		Limit(limit + 1).
+		Find(&messages).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to fetch messages: %w", err)
+	}
+
+	if len(messages) > limit {
+		return messages[:limit], true, nil
+	}
+	return messages, false, nil
+}
+
+// SearchMessages runs a full-text search over a meeting's chat, newest match
+// first. The query uses web search syntax: quoted phrases, "or" and -term.
+// Only the host and the meeting's participants may search.
+func (s *ChatService) SearchMessages(meetingID uuid.UUID, query models.ChatSearchQuery) ([]models.ChatSearchResult, models.ChatCursorPagination, error) {
+	pagination := models.ChatCursorPagination{Limit: query.Limit}
+
+	terms := strings.TrimSpace(query.Query)
+	if terms == "" {
+		return nil, pagination, errors.New("search query required")
+	}
+	if err := s.checkReader(meetingID, query.Viewer); err != nil {
+		return nil, pagination, err
+	}
+
+	// The 'simple' configuration must match the one chat_messages.search_vector
+	// is generated with, or the index is not used
+	db := s.db.Model(&models.ChatMessage{}).
+		Select("chat_messages.id, chat_messages.created_at, "+
+			"ts_headline('simple', chat_messages.content, websearch_to_tsquery('simple', ?), ?) AS snippet, "+
+			"ts_rank(chat_messages.search_vector, websearch_to_tsquery('simple', ?)) AS rank",
+			terms, chatSearchHeadlineOptions, terms).
+		Scopes(chatVisibilityScope(query.Viewer)).
+		Where("chat_messages.meeting_id = ? AND chat_messages.is_deleted = ?", meetingID, false).
+		Where("chat_messages.search_vector @@ websearch_to_tsquery('simple', ?)", terms)
+	if query.SenderUserID != nil {
+		db = db.Where("chat_messages.user_id = ?", *query.SenderUserID)
+	}
+	if query.SenderPublicUserID != nil {
+		db = db.Where("chat_messages.public_user_id = ?", *query.SenderPublicUserID)
+	}
+	if len(query.MessageTypes) > 0 {
+		db = db.Where("chat_messages.message_type IN ?", query.MessageTypes)
+	}
+
+	var hits []struct {
+		ID        uuid.UUID
+		CreatedAt time.Time
+		Snippet   string
+		Rank      float64
+	}
+	if err := db.Scopes(keysetScope(query.Cursor, models.ChatHistoryOlder)).
+		Limit(query.Limit + 1).
+		Scan(&hits).Error; err != nil {
+		return nil, pagination, fmt.Errorf("failed to search messages: %w", err)
+	}
+	if len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+		pagination.HasOlder = true
+	}
+	pagination.HasNewer = query.Cursor != nil
+	if len(hits) == 0 {
+		return []models.ChatSearchResult{}, pagination, nil
+	}
+
+	ids := make([]uuid.UUID, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	var messages []models.ChatMessage
+	if err := s.db.Scopes(chatPagePreloads).Where("id IN ?", ids).Find(&messages).Error; err != nil {
+		return nil, pagination, fmt.Errorf("failed to load messages: %w", err)
+	}
+	byID := make(map[uuid.UUID]*models.ChatMessage, len(messages))
+	listed := make([]*models.ChatMessage, len(messages))
+	for i := range messages {
+		s.signAttachments(&messages[i])
+		byID[messages[i].ID] = &messages[i]
+		listed[i] = &messages[i]
+	}
+	if err := s.countSeenBy(listed...); err != nil {
+		return nil, pagination, err
+	}
+	if err := s.summarizeReactions(query.Viewer, listed...); err != nil {
+		return nil, pagination, err
+	}
+
+	results := make([]models.ChatSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		message, ok := byID[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, models.ChatSearchResult{
+			Message: message.ToResponse(),
+			Snippet: highlightSnippet(hit.Snippet),
+			Rank:    hit.Rank,
+		})
+	}
+
+	pagination.NewerCursor = models.ChatCursor{CreatedAt: hits[0].CreatedAt, ID: hits[0].ID}.Encode()
+	last := hits[len(hits)-1]
+	pagination.OlderCursor = models.ChatCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	return results, pagination, nil
+}
+
+// highlightSnippet escapes a ts_headline excerpt and turns its match
+// delimiters into <mark> elements
+func highlightSnippet(raw string) string {
+	return chatSnippetMarks.Replace(html.EscapeString(raw))
+}
+
+// chatPagePreloads loads what a message list shows. Replies are left out,
+// and reactions are summarized instead of loaded.
+func chatPagePreloads(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").
+		Preload("PublicUser").
+		Preload("ReplyTo").
+		Preload("Attachment").
+		Preload("Recipients").
+		Preload("Acknowledgements")
+}
+
+// threadScope selects the replies of thread, or the main timeline when
+// thread is nil
+func threadScope(thread *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if thread == nil {
+			return db.Where("chat_messages.reply_to_id IS NULL")
+		}
+		return db.Where("chat_messages.reply_to_id = ?", *thread)
+	}
+}
+
+// keysetScope selects the messages strictly beyond cursor in direction,
+// nearest first. Without a cursor it starts from the newest message when
+// going older and from the first one when going newer.
+func keysetScope(cursor *models.ChatCursor, direction models.ChatHistoryDirection) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if direction == models.ChatHistoryNewer {
+			if cursor != nil {
+				db = db.Where("(chat_messages.created_at > ? OR (chat_messages.created_at = ? AND chat_messages.id > ?))",
+					cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+			}
+			return db.Order("chat_messages.created_at ASC, chat_messages.id ASC")
+		}
+
+		if cursor != nil {
+			db = db.Where("(chat_messages.created_at < ? OR (chat_messages.created_at = ? AND chat_messages.id < ?))",
+				cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+		}
+		return db.Order("chat_messages.created_at DESC, chat_messages.id DESC")
+	}
+}
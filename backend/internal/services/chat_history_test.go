@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func setupTestChatDB(t *testing.T, tables ...interface{}) *gorm.DB {
+	return setupTestDB(t, append([]interface{}{&models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{},
+		&models.ChatMessage{}, &models.ChatReadCursor{}, &models.ChatMessageReaction{}, &models.ChatAttachment{}, &models.ChatMessageRecipient{},
+		&models.ChatMessageFlag{}, &models.ModerationLog{}, &models.ChatMessageAcknowledgement{}}, tables...)...)
+}
+
+// createTestChatMessages posts messages "0" to "n-1" a second apart, except
+// that messages 2 to 4 share a timestamp
+func createTestChatMessages(t *testing.T, db *gorm.DB, meetingID, userID uuid.UUID, n int) []models.ChatMessage {
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	messages := make([]models.ChatMessage, n)
+	for i := range messages {
+		offset := i
+		if i >= 2 && i <= 4 {
+			offset = 2
+		}
+		messages[i] = models.ChatMessage{
+			MeetingID:   meetingID,
+			UserID:      &userID,
+			MessageType: models.MessageTypeText,
+			Content:     fmt.Sprint(i),
+			CreatedAt:   base.Add(time.Duration(offset) * time.Second),
+		}
+		require.NoError(t, db.Create(&messages[i]).Error)
+	}
+	return messages
+}
+
+func messageContents(messages []models.ChatMessage) []string {
+	contents := make([]string, len(messages))
+	for i, message := range messages {
+		contents[i] = message.Content
+	}
+	return contents
+}
+
+func TestChatService_GetMessagesPaging(t *testing.T) {
+	db := setupTestChatDB(t)
+	service := NewChatService(db, nil, nil, nil)
+	meeting, hostID := createTestMeeting(t, db)
+	createTestChatMessages(t, db, meeting.ID, hostID, 7)
+
+	// Walking back from the latest message visits every message once, ties
+	// included
+	var seen []string
+	var cursor *models.ChatCursor
+	for {
+		page, pagination, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Cursor: cursor, Limit: 3})
+		require.NoError(t, err)
+		seen = append(messageContents(page), seen...)
+		assert.Equal(t, cursor != nil, pagination.HasNewer)
+		if !pagination.HasOlder {
+			break
+		}
+		cursor, err = models.DecodeChatCursor(pagination.OlderCursor)
+		require.NoError(t, err)
+	}
+	assert.Len(t, seen, 7)
+	assert.ElementsMatch(t, []string{"0", "1", "2", "3", "4", "5", "6"}, seen)
+
+	// Pages do not shift when messages arrive while scrolling back
+	latest, pagination, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5", "6"}, messageContents(latest))
+	require.NoError(t, db.Create(&models.ChatMessage{MeetingID: meeting.ID, UserID: &hostID, MessageType: models.MessageTypeText, Content: "7"}).Error)
+	cursor, err = models.DecodeChatCursor(pagination.OlderCursor)
+	require.NoError(t, err)
+	older, _, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Cursor: cursor, Limit: 2})
+	require.NoError(t, err)
+	assert.NotContains(t, messageContents(older), "5")
+	assert.Len(t, older, 2)
+
+	// Going newer from the last page picks up the new message
+	cursor, err = models.DecodeChatCursor(pagination.NewerCursor)
+	require.NoError(t, err)
+	newer, pagination, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Cursor: cursor, Direction: models.ChatHistoryNewer, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"7"}, messageContents(newer))
+	assert.True(t, pagination.HasOlder)
+	assert.False(t, pagination.HasNewer)
+
+	// Without a cursor, newer pages start from the first message
+	first, pagination, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Direction: models.ChatHistoryNewer, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0", "1"}, messageContents(first))
+	assert.False(t, pagination.HasOlder)
+	assert.True(t, pagination.HasNewer)
+}
+
+func TestChatService_GetMessagesAround(t *testing.T) {
+	db := setupTestChatDB(t)
+	service := NewChatService(db, nil, nil, nil)
+	meeting, hostID := createTestMeeting(t, db)
+	messages := createTestChatMessages(t, db, meeting.ID, hostID, 10)
+
+	page, pagination, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Around: &messages[6].ID, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, page, 5)
+	assert.Equal(t, messages[6].ID, page[2].ID)
+	assert.True(t, pagination.HasOlder)
+	assert.True(t, pagination.HasNewer)
+
+	page, pagination, err = service.GetMessages(meeting.ID, models.ChatHistoryQuery{Around: &messages[9].ID, Limit: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"7", "8", "9"}, messageContents(page))
+	assert.True(t, pagination.HasOlder)
+	assert.False(t, pagination.HasNewer)
+
+	// Deleted messages are neither listed nor reachable
+	require.NoError(t, db.Model(&messages[8]).Update("is_deleted", true).Error)
+	page, _, err = service.GetMessages(meeting.ID, models.ChatHistoryQuery{Around: &messages[9].ID, Limit: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"6", "7", "9"}, messageContents(page))
+	_, _, err = service.GetMessages(meeting.ID, models.ChatHistoryQuery{Around: &messages[8].ID, Limit: 5})
+	assert.EqualError(t, err, "message not found")
+
+	other := &models.Meeting{Name: "Other", StartTime: time.Now(), HostID: hostID}
+	require.NoError(t, db.Create(other).Error)
+	_, _, err = service.GetMessages(other.ID, models.ChatHistoryQuery{Around: &messages[6].ID, Limit: 5})
+	assert.EqualError(t, err, "message not found")
+}
+
+func TestChatCursor_RoundTrip(t *testing.T) {
+	cursor := models.ChatCursor{
+		CreatedAt: time.Date(2030, 1, 2, 3, 4, 5, 123456789, time.FixedZone("", 2*60*60)),
+		ID:        uuid.New(),
+	}
+	decoded, err := models.DecodeChatCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+
+	for _, raw := range []string{"", "not base64!", "bm8gc2VwYXJhdG9y", cursor.Encode()[:10]} {
+		_, err := models.DecodeChatCursor(raw)
+		assert.EqualError(t, err, "invalid cursor", raw)
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	assert.Equal(t, `see the <mark>roadmap</mark> &lt;b&gt;draft&lt;/b&gt; … next <mark>roadmap</mark>`,
+		highlightSnippet("see the \uE000roadmap\uE001 <b>draft</b> … next \uE000roadmap\uE001"))
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// SetContentFilter enables checking message text with filter before it is
+// stored
+func (s *ChatService) SetContentFilter(filter models.ChatContentFilter) {
+	s.contentFilter = filter
+}
+
+// GetFlaggedMessages returns the meeting's flags with the given status,
+// oldest first, for a host or co-host to review. The status defaults to
+// pending.
+func (s *ChatService) GetFlaggedMessages(meetingID uuid.UUID, actorID uuid.UUID, status models.ChatFlagStatus) ([]models.ChatMessageFlag, error) {
+	if err := s.authorizeModerator(meetingID, actorID); err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		status = models.ChatFlagPending
+	}
+	var flags []models.ChatMessageFlag
+	if err := s.db.Preload("Message", chatPagePreloads).
+		Where("meeting_id = ? AND status = ?", meetingID, status).
+		Order("created_at ASC").
+		Find(&flags).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch flagged messages: %w", err)
+	}
+	for i := range flags {
+		s.signAttachments(&flags[i].Message)
+	}
+	return flags, nil
+}
+
+// ReviewFlag settles a flag. Dismissing it keeps the message; removing it
+// deletes the message as a moderator, which settles its other flags too.
+func (s *ChatService) ReviewFlag(meetingID uuid.UUID, flagID uuid.UUID, actorID uuid.UUID, req *models.ReviewChatFlagRequest) (*models.ChatMessageFlag, error) {
+	if err := s.authorizeModerator(meetingID, actorID); err != nil {
+		return nil, err
+	}
+
+	var flag models.ChatMessageFlag
+	if err := s.db.Where("id = ? AND meeting_id = ?", flagID, meetingID).First(&flag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("flag not found")
+		}
+		return nil, fmt.Errorf("failed to fetch flag: %w", err)
+	}
+	if flag.Status != models.ChatFlagPending {
+		return nil, errors.New("flag already reviewed")
+	}
+
+	switch req.Action {
+	case "remove":
+		deleted := true
+		if _, err := s.UpdateMessage(actorID, flag.MessageID, &models.UpdateChatMessageRequest{IsDeleted: &deleted, Reason: req.Reason}); err != nil {
+			return nil, err
+		}
+	case "dismiss":
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&flag).Updates(map[string]interface{}{
+				"status":         models.ChatFlagDismissed,
+				"reviewed_by_id": actorID,
+				"reviewed_at":    time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+			return tx.Create(&models.ModerationLog{
+				MeetingID:       meetingID,
+				ActorID:         actorID,
+				Action:          models.ModerationActionDismissFlag,
+				TargetMessageID: &flag.MessageID,
+				Reason:          req.Reason,
+				Details:         flag.Rule,
+			}).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to dismiss flag: %w", err)
+		}
+	default:
+		return nil, errors.New("invalid review action")
+	}
+
+	var reviewed models.ChatMessageFlag
+	if err := s.db.Preload("Message", chatPagePreloads).First(&reviewed, "id = ?", flagID).Error; err != nil {
+		return nil, fmt.Errorf("failed to reload flag: %w", err)
+	}
+	s.signAttachments(&reviewed.Message)
+	return &reviewed, nil
+}
+
+// filterContent runs the content filter over a message's text, returning
+// the text to store and the flagging rules it matched
+func (s *ChatService) filterContent(content string) (string, []string, error) {
+	if s.contentFilter == nil || content == "" {
+		return content, nil, nil
+	}
+	result := s.contentFilter.Check(content)
+	if result.Blocked {
+		return "", nil, errors.New("message blocked by content filter")
+	}
+	return result.Content, result.Flags, nil
+}
+
+// checkChatMute rejects chat actions from a participant a moderator muted
+// in chat
+func (s *ChatService) checkChatMute(meetingID uuid.UUID, actor models.MeetingActor) error {
+	query := participantQuery(s.db, meetingID, actor)
+	if query == nil {
+		return nil
+	}
+	var participant models.Participant
+	err := query.Select("is_chat_muted, chat_muted_until").
+		Where("lobby_status = ?", models.LobbyStatusAdmitted).
+		Order("joined_at DESC").
+		First(&participant).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to fetch participant: %w", err)
+	}
+	if participant.ChatMuted(time.Now()) {
+		return errors.New("muted in chat")
+	}
+	return nil
+}
+
+// checkSlowMode rejects a message sent sooner after the sender's previous
+// one than the meeting's slow mode allows. Hosts and co-hosts are exempt.
+func (s *ChatService) checkSlowMode(meetingID uuid.UUID, sender models.MeetingActor) error {
+	var meeting models.Meeting
+	if err := s.db.Select("id, host_id, chat_slow_mode").Where("id = ?", meetingID).First(&meeting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("meeting not found")
+		}
+		return fmt.Errorf("failed to fetch meeting: %w", err)
+	}
+	if meeting.ChatSlowMode <= 0 || s.isChatModerator(&meeting, sender) {
+		return nil
+	}
+
+	query := s.db.Model(&models.ChatMessage{}).
+		Where("meeting_id = ? AND created_at > ?", meetingID, time.Now().Add(-time.Duration(meeting.ChatSlowMode)*time.Second))
+	switch {
+	case sender.UserID != nil:
+		query = query.Where("user_id = ?", *sender.UserID)
+	case sender.PublicUserID != nil:
+		query = query.Where("public_user_id = ?", *sender.PublicUserID)
+	default:
+		return nil
+	}
+
+	var recent int64
+	if err := query.Count(&recent).Error; err != nil {
+		return fmt.Errorf("failed to check slow mode: %w", err)
+	}
+	if recent > 0 {
+		return errors.New("slow mode")
+	}
+	return nil
+}
+
+// checkMessageModerator checks that actorID may remove someone else's
+// message: a host or co-host holding a higher role than its author
+func (s *ChatService) checkMessageModerator(message *models.ChatMessage, actorID uuid.UUID) error {
+	if s.roleService == nil {
+		return errors.New("unauthorized")
+	}
+	actorRole, err := s.roleService.GetRole(message.MeetingID, models.MeetingActor{UserID: &actorID})
+	if err != nil || !actorRole.Can(models.PermissionModerate) {
+		return errors.New("unauthorized")
+	}
+	// Authors who left the meeting hold no role any more
+	authorRole, err := s.roleService.GetRole(message.MeetingID, models.MeetingActor{UserID: message.UserID, PublicUserID: message.PublicUserID})
+	if err == nil && !actorRole.Outranks(authorRole) {
+		return errors.New("permission denied")
+	}
+	return nil
+}
+
+func (s *ChatService) authorizeModerator(meetingID uuid.UUID, actorID uuid.UUID) error {
+	if s.roleService == nil {
+		return errors.New("permission denied")
+	}
+	return s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &actorID}, models.PermissionModerate)
+}
+
+// flagMessage queues a message for review, once per flagging rule it matched
+func flagMessage(tx *gorm.DB, message *models.ChatMessage, rules []string) error {
+	for _, rule := range rules {
+		flag := &models.ChatMessageFlag{MeetingID: message.MeetingID, MessageID: message.ID, Rule: rule}
+		if err := tx.Create(flag).Error; err != nil {
+			return fmt.Errorf("failed to flag message: %w", err)
+		}
+	}
+	return nil
+}
+
+// settleFlags closes the pending flags of a deleted message
+func settleFlags(tx *gorm.DB, messageID uuid.UUID, reviewerID uuid.UUID) error {
+	if err := tx.Model(&models.ChatMessageFlag{}).
+		Where("message_id = ? AND status = ?", messageID, models.ChatFlagPending).
+		Updates(map[string]interface{}{
+			"status":         models.ChatFlagRemoved,
+			"reviewed_by_id": reviewerID,
+			"reviewed_at":    time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to settle flags: %w", err)
+	}
+	return nil
+}
+
+// timelineScope keeps the messages the history shows: those not deleted,
+// and the tombstones of those a moderator removed
+func timelineScope(db *gorm.DB) *gorm.DB {
+	return db.Where("(chat_messages.is_deleted = ? OR chat_messages.deleted_by_id IS NOT NULL)", false)
+}
+
+// broadcastFlag tells the meeting's hosts and co-hosts that a message was
+// queued for review
+func (s *ChatService) broadcastFlag(message *models.ChatMessage, rules []string) {
+	if s.webSocketService == nil {
+		return
+	}
+
+	s.webSocketService.SendMessageToModerators(message.MeetingID.String(), models.SignalingMessage{
+		Type:      models.SignalingTypeChatMessageFlagged,
+		MeetingID: message.MeetingID.String(),
+		Data: models.ChatMessageFlaggedPayload{
+			MessageID: message.ID,
+			Rules:     rules,
+		},
+		Timestamp: time.Now(),
+	})
+}
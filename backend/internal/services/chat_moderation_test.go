@@ -0,0 +1,160 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestRuleChatFilter(t *testing.T) {
+	filter, err := NewRuleChatFilter([]models.ChatFilterRule{
+		{Name: "profanity", Words: []string{"darn", "f*ck"}, Action: models.ChatFilterMask},
+		{Name: "links", Pattern: `https?://\S+`, Action: models.ChatFilterFlag},
+		{Name: "slurs", Words: []string{"vile"}, Action: models.ChatFilterBlock},
+	})
+	require.NoError(t, err)
+
+	// Words match whole and ignoring case
+	result := filter.Check("Darn it, darning socks is F*CK hard")
+	assert.Equal(t, "**** it, darning socks is **** hard", result.Content)
+	assert.False(t, result.Blocked)
+	assert.Empty(t, result.Flags)
+
+	result = filter.Check("darn, see https://example.com")
+	assert.Equal(t, "****, see https://example.com", result.Content)
+	assert.Equal(t, []string{"links"}, result.Flags)
+
+	// Blocking wins over the rules before it
+	result = filter.Check("darn VILE")
+	assert.True(t, result.Blocked)
+
+	_, err = NewRuleChatFilter([]models.ChatFilterRule{{Words: []string{"x"}, Action: "shout"}})
+	assert.Error(t, err)
+	_, err = NewRuleChatFilter([]models.ChatFilterRule{{Words: []string{"x"}, Pattern: "x", Action: models.ChatFilterFlag}})
+	assert.Error(t, err)
+	_, err = NewRuleChatFilter([]models.ChatFilterRule{{Pattern: "(", Action: models.ChatFilterFlag}})
+	assert.Error(t, err)
+}
+
+func TestChatService_Moderation(t *testing.T) {
+	db := setupTestChatDB(t)
+	roleService := NewRoleService(db, nil)
+	service := NewChatService(db, nil, nil, roleService)
+	moderation := NewModerationService(db, nil, roleService)
+	filter, err := NewRuleChatFilter([]models.ChatFilterRule{
+		{Name: "profanity", Words: []string{"darn"}, Action: models.ChatFilterMask},
+		{Name: "links", Pattern: `https?://\S+`, Action: models.ChatFilterFlag},
+		{Name: "threats", Words: []string{"hack you"}, Action: models.ChatFilterBlock},
+	})
+	require.NoError(t, err)
+	service.SetContentFilter(filter)
+
+	meeting, hostID := createTestMeeting(t, db)
+	cohost := addTestParticipant(t, db, meeting.ID, "cohost", models.RoleCoHost)
+	guest := addTestParticipant(t, db, meeting.ID, "guest", models.RoleAttendee)
+	send := func(userID uuid.UUID, content string) (*models.ChatMessage, error) {
+		return service.SendMessage(&userID, nil, &models.CreateChatMessageRequest{
+			MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: content,
+		})
+	}
+	deleteAs := func(userID uuid.UUID, messageID uuid.UUID) (*models.ChatMessage, error) {
+		deleted := true
+		return service.UpdateMessage(userID, messageID, &models.UpdateChatMessageRequest{IsDeleted: &deleted, Reason: "off topic"})
+	}
+
+	// The content filter masks, flags and blocks
+	masked, err := send(*guest.UserID, "darn")
+	require.NoError(t, err)
+	assert.Equal(t, "****", masked.Content)
+	_, err = send(*guest.UserID, "I will hack you")
+	assert.EqualError(t, err, "message blocked by content filter")
+	spam, err := send(*guest.UserID, "buy at http://spam.example")
+	require.NoError(t, err)
+
+	_, err = service.GetFlaggedMessages(meeting.ID, *guest.UserID, "")
+	assert.EqualError(t, err, "permission denied")
+	flags, err := service.GetFlaggedMessages(meeting.ID, *cohost.UserID, "")
+	require.NoError(t, err)
+	require.Len(t, flags, 1)
+	assert.Equal(t, spam.ID, flags[0].MessageID)
+	assert.Equal(t, "links", flags[0].Rule)
+
+	// Removing a flagged message leaves a tombstone in the history
+	reviewed, err := service.ReviewFlag(meeting.ID, flags[0].ID, *cohost.UserID, &models.ReviewChatFlagRequest{Action: "remove"})
+	require.NoError(t, err)
+	assert.Equal(t, models.ChatFlagRemoved, reviewed.Status)
+	assert.True(t, reviewed.Message.IsDeleted)
+	assert.Empty(t, reviewed.Message.Content)
+	_, err = service.ReviewFlag(meeting.ID, flags[0].ID, *cohost.UserID, &models.ReviewChatFlagRequest{Action: "dismiss"})
+	assert.EqualError(t, err, "flag already reviewed")
+
+	// Authors' own deletions vanish; moderators' stay as tombstones
+	own, err := send(*guest.UserID, "oops")
+	require.NoError(t, err)
+	_, err = deleteAs(*guest.UserID, own.ID)
+	require.NoError(t, err)
+	messages, _, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, spam.ID, messages[1].ID)
+	require.NotNil(t, messages[1].DeletedByID)
+	assert.Equal(t, *cohost.UserID, *messages[1].DeletedByID)
+
+	// Attendees cannot delete other people's messages, nor a co-host the host's
+	hostMessage, err := send(hostID, "welcome")
+	require.NoError(t, err)
+	_, err = deleteAs(*guest.UserID, hostMessage.ID)
+	assert.EqualError(t, err, "unauthorized")
+	_, err = deleteAs(*cohost.UserID, hostMessage.ID)
+	assert.EqualError(t, err, "permission denied")
+
+	var logged int64
+	require.NoError(t, db.Model(&models.ModerationLog{}).
+		Where("action = ? AND target_message_id = ?", models.ModerationActionDeleteMessage, spam.ID).
+		Count(&logged).Error)
+	assert.Equal(t, int64(1), logged)
+
+	// Slow mode holds attendees back, but not moderators
+	_, err = moderation.SetChatSlowMode(meeting.ID, *guest.UserID, 30)
+	assert.EqualError(t, err, "permission denied")
+	updated, err := moderation.SetChatSlowMode(meeting.ID, hostID, 30)
+	require.NoError(t, err)
+	assert.Equal(t, 30, updated.ChatSlowMode)
+	_, err = send(*guest.UserID, "again")
+	assert.EqualError(t, err, "slow mode")
+	_, err = send(*cohost.UserID, "one")
+	require.NoError(t, err)
+	_, err = send(*cohost.UserID, "two")
+	require.NoError(t, err)
+	_, err = moderation.SetChatSlowMode(meeting.ID, hostID, 0)
+	require.NoError(t, err)
+
+	// A chat mute stops messages and reactions until it is lifted or ends
+	_, err = moderation.MuteParticipantChat(meeting.ID, hostID, guest.ID, &models.ChatMuteRequest{Reason: "spam"})
+	require.NoError(t, err)
+	_, err = send(*guest.UserID, "let me talk")
+	assert.EqualError(t, err, "muted in chat")
+	_, err = service.ToggleReaction(guest.UserID, nil, &models.CreateChatMessageReactionRequest{MessageID: hostMessage.ID, Reaction: "👍"})
+	assert.EqualError(t, err, "muted in chat")
+
+	_, err = moderation.UnmuteParticipantChat(meeting.ID, hostID, guest.ID)
+	require.NoError(t, err)
+	_, err = send(*guest.UserID, "thanks")
+	require.NoError(t, err)
+	_, err = moderation.UnmuteParticipantChat(meeting.ID, hostID, guest.ID)
+	assert.EqualError(t, err, "participant not muted in chat")
+
+	_, err = moderation.MuteParticipantChat(meeting.ID, hostID, guest.ID, &models.ChatMuteRequest{Duration: 60})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(guest).Update("chat_muted_until", time.Now().Add(-time.Second)).Error)
+	_, err = send(*guest.UserID, "back")
+	assert.NoError(t, err)
+
+	_, err = moderation.MuteParticipantChat(meeting.ID, *cohost.UserID, cohost.ID, &models.ChatMuteRequest{})
+	assert.EqualError(t, err, "cannot moderate yourself")
+}
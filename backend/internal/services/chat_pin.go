@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// PinMessage pins a meeting-wide message for everyone in the meeting. Only
+// hosts and co-hosts pin, and a meeting keeps at most
+// models.MaxPinnedMessages pinned.
+func (s *ChatService) PinMessage(meetingID, messageID, actorID uuid.UUID) (*models.ChatMessage, error) {
+	if err := s.authorizeModerator(meetingID, actorID); err != nil {
+		return nil, err
+	}
+
+	var message models.ChatMessage
+	if err := s.db.Select("id, is_private, is_pinned").
+		Where("id = ? AND meeting_id = ? AND is_deleted = ?", messageID, meetingID, false).
+		First(&message).Error; err != nil {
+		return nil, errors.New("message not found")
+	}
+	if message.IsPrivate {
+		return nil, errors.New("private messages cannot be pinned")
+	}
+	if message.IsPinned {
+		return nil, errors.New("message already pinned")
+	}
+
+	// Pins of a meeting take turns on its row, so two hosts pinning at once
+	// cannot both take the last slot
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var meeting models.Meeting
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			Where("id = ?", meetingID).First(&meeting).Error; err != nil {
+			return errors.New("meeting not found")
+		}
+
+		var pinned int64
+		if err := tx.Model(&models.ChatMessage{}).
+			Where("meeting_id = ? AND is_pinned = ?", meetingID, true).
+			Count(&pinned).Error; err != nil {
+			return fmt.Errorf("failed to pin message: %w", err)
+		}
+		if pinned >= models.MaxPinnedMessages {
+			return errors.New("pin limit reached")
+		}
+
+		result := tx.Model(&models.ChatMessage{}).
+			Where("id = ? AND is_pinned = ?", messageID, false).
+			UpdateColumns(map[string]interface{}{
+				"is_pinned":    true,
+				"pinned_at":    time.Now(),
+				"pinned_by_id": actorID,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to pin message: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("message already pinned")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadMessageRelationships(&message); err != nil {
+		return nil, fmt.Errorf("failed to load message relationships: %w", err)
+	}
+	s.signAttachments(&message)
+
+	go s.broadcastPin(&message)
+
+	return &message, nil
+}
+
+// UnpinMessage takes a message off the meeting's pinned list
+func (s *ChatService) UnpinMessage(meetingID, messageID, actorID uuid.UUID) error {
+	if err := s.authorizeModerator(meetingID, actorID); err != nil {
+		return err
+	}
+
+	result := s.db.Model(&models.ChatMessage{}).
+		Where("id = ? AND meeting_id = ? AND is_pinned = ?", messageID, meetingID, true).
+		UpdateColumns(unpinColumns())
+	if result.Error != nil {
+		return fmt.Errorf("failed to unpin message: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("message not pinned")
+	}
+
+	go s.broadcastUnpin(meetingID, messageID)
+
+	return nil
+}
+
+// GetPinnedMessages returns a meeting's pinned messages, most recently
+// pinned first, to the host and the meeting's participants
+func (s *ChatService) GetPinnedMessages(meetingID uuid.UUID, viewer models.MeetingActor) ([]models.ChatMessage, error) {
+	if err := s.checkReader(meetingID, viewer); err != nil {
+		return nil, err
+	}
+	return s.pinnedMessages(meetingID, viewer)
+}
+
+// pinnedMessages loads the pinned messages viewer can see, without checking
+// that viewer belongs to the meeting
+func (s *ChatService) pinnedMessages(meetingID uuid.UUID, viewer models.MeetingActor) ([]models.ChatMessage, error) {
+	var messages []models.ChatMessage
+	if err := s.db.Scopes(chatPagePreloads, chatVisibilityScope(viewer)).
+		Where("chat_messages.meeting_id = ? AND chat_messages.is_pinned = ? AND chat_messages.is_deleted = ?", meetingID, true, false).
+		Order("chat_messages.pinned_at DESC").
+		Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch pinned messages: %w", err)
+	}
+	listed := make([]*models.ChatMessage, len(messages))
+	for i := range messages {
+		s.signAttachments(&messages[i])
+		listed[i] = &messages[i]
+	}
+	if err := s.countSeenBy(listed...); err != nil {
+		return nil, err
+	}
+	if err := s.summarizeReactions(viewer, listed...); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// unpinColumns clears a message's pin; UpdateColumns leaves updated_at
+// alone, as the message itself did not change
+func unpinColumns() map[string]interface{} {
+	return map[string]interface{}{
+		"is_pinned":    false,
+		"pinned_at":    nil,
+		"pinned_by_id": nil,
+	}
+}
+
+// broadcastPin tells the meeting that a message was pinned
+func (s *ChatService) broadcastPin(message *models.ChatMessage) {
+	if s.webSocketService == nil {
+		return
+	}
+
+	response := message.ToResponse()
+	s.webSocketService.SendMessageToMeeting(message.MeetingID.String(), models.SignalingMessage{
+		Type:      models.SignalingTypeChatPin,
+		MeetingID: message.MeetingID.String(),
+		Data: models.ChatPinPayload{
+			MessageID:  message.ID,
+			PinnedByID: message.PinnedByID,
+			PinnedAt:   message.PinnedAt,
+			Message:    &response,
+		},
+		Timestamp: time.Now(),
+	})
+}
+
+// broadcastUnpin tells the meeting that a message was unpinned, or deleted
+// while pinned
+func (s *ChatService) broadcastUnpin(meetingID, messageID uuid.UUID) {
+	if s.webSocketService == nil {
+		return
+	}
+
+	s.webSocketService.SendMessageToMeeting(meetingID.String(), models.SignalingMessage{
+		Type:      models.SignalingTypeChatUnpin,
+		MeetingID: meetingID.String(),
+		Data:      models.ChatPinPayload{MessageID: messageID},
+		Timestamp: time.Now(),
+	})
+}
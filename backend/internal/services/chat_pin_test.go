@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestChatService_PinnedMessages(t *testing.T) {
+	db := setupTestChatDB(t)
+	service := NewChatService(db, nil, nil, NewRoleService(db, nil))
+
+	meeting, hostID := createTestMeeting(t, db)
+	guest := addTestParticipant(t, db, meeting.ID, "guest", models.RoleAttendee)
+	send := func(content string) *models.ChatMessage {
+		message, err := service.SendMessage(guest.UserID, nil, &models.CreateChatMessageRequest{
+			MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: content,
+		})
+		require.NoError(t, err)
+		return message
+	}
+
+	first := send("agenda")
+	_, err := service.PinMessage(meeting.ID, first.ID, *guest.UserID)
+	assert.EqualError(t, err, "permission denied")
+
+	pinned, err := service.PinMessage(meeting.ID, first.ID, hostID)
+	require.NoError(t, err)
+	assert.True(t, pinned.IsPinned)
+	require.NotNil(t, pinned.PinnedByID)
+	assert.Equal(t, hostID, *pinned.PinnedByID)
+	_, err = service.PinMessage(meeting.ID, first.ID, hostID)
+	assert.EqualError(t, err, "message already pinned")
+
+	// The meeting keeps a bounded number of pins
+	for i := 1; i < models.MaxPinnedMessages; i++ {
+		_, err := service.PinMessage(meeting.ID, send("note").ID, hostID)
+		require.NoError(t, err)
+	}
+	extra := send("one too many")
+	_, err = service.PinMessage(meeting.ID, extra.ID, hostID)
+	assert.EqualError(t, err, "pin limit reached")
+
+	messages, err := service.GetPinnedMessages(meeting.ID, models.MeetingActor{UserID: guest.UserID})
+	require.NoError(t, err)
+	require.Len(t, messages, models.MaxPinnedMessages)
+	_, err = service.GetPinnedMessages(meeting.ID, models.MeetingActor{})
+	assert.EqualError(t, err, "not a participant")
+
+	// Unpinning, or deleting a pinned message, frees a slot
+	require.NoError(t, service.UnpinMessage(meeting.ID, first.ID, hostID))
+	assert.EqualError(t, service.UnpinMessage(meeting.ID, first.ID, hostID), "message not pinned")
+	deleted := true
+	_, err = service.UpdateMessage(*guest.UserID, messages[0].ID, &models.UpdateChatMessageRequest{IsDeleted: &deleted})
+	require.NoError(t, err)
+
+	messages, err = service.GetPinnedMessages(meeting.ID, models.MeetingActor{UserID: guest.UserID})
+	require.NoError(t, err)
+	assert.Len(t, messages, models.MaxPinnedMessages-2)
+	_, err = service.PinMessage(meeting.ID, extra.ID, hostID)
+	assert.NoError(t, err)
+
+	private, err := service.SendMessage(&hostID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: "psst", RecipientIDs: []uuid.UUID{guest.ID},
+	})
+	require.NoError(t, err)
+	_, err = service.PinMessage(meeting.ID, private.ID, hostID)
+	assert.EqualError(t, err, "private messages cannot be pinned")
+}
+
+func TestChatService_Announcements(t *testing.T) {
+	db := setupTestChatDB(t)
+	service := NewChatService(db, nil, nil, NewRoleService(db, nil))
+
+	meeting, hostID := createTestMeeting(t, db)
+	cohost := addTestParticipant(t, db, meeting.ID, "cohost", models.RoleCoHost)
+	alice := addTestParticipant(t, db, meeting.ID, "alice", models.RoleAttendee)
+	bob := addTestParticipant(t, db, meeting.ID, "bob", models.RoleAttendee)
+	announce := func(userID uuid.UUID, requiresAck bool) (*models.ChatMessage, error) {
+		return service.SendMessage(&userID, nil, &models.CreateChatMessageRequest{
+			MeetingID: meeting.ID, MessageType: models.MessageTypeAnnouncement, Content: "Break in 5", RequiresAck: requiresAck,
+		})
+	}
+
+	_, err := announce(*alice.UserID, false)
+	assert.EqualError(t, err, "announcements restricted to hosts")
+	_, err = service.SendMessage(&hostID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeAnnouncement, Content: "psst", RecipientIDs: []uuid.UUID{alice.ID},
+	})
+	assert.EqualError(t, err, "announcements must be meeting-wide")
+
+	plain, err := announce(*cohost.UserID, false)
+	require.NoError(t, err)
+	assert.Equal(t, models.MessageTypeAnnouncement, plain.MessageType)
+	assert.EqualError(t, service.AcknowledgeMessage(alice.UserID, nil, plain.ID), "message does not require acknowledgement")
+
+	announcement, err := announce(hostID, true)
+	require.NoError(t, err)
+	assert.True(t, announcement.RequiresAck)
+
+	require.NoError(t, service.AcknowledgeMessage(alice.UserID, nil, announcement.ID))
+	require.NoError(t, service.AcknowledgeMessage(alice.UserID, nil, announcement.ID))
+
+	_, err = service.GetAcknowledgements(meeting.ID, announcement.ID, *alice.UserID)
+	assert.EqualError(t, err, "permission denied")
+	summary, err := service.GetAcknowledgements(meeting.ID, announcement.ID, hostID)
+	require.NoError(t, err)
+	require.Len(t, summary.Acknowledged, 1)
+	assert.Equal(t, *alice.UserID, *summary.Acknowledged[0].UserID)
+	pending := make([]string, 0, len(summary.Pending))
+	for _, p := range summary.Pending {
+		pending = append(pending, p.Name)
+	}
+	assert.ElementsMatch(t, []string{cohost.Name, bob.Name}, pending)
+
+	// Readers see their own acknowledgement in the history
+	messages, _, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Len(t, messages[1].Acknowledgements, 1)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// chatVisibilityScope keeps the messages viewer may read: everything sent
+// meeting-wide, plus the private messages viewer sent or received. A viewer
+// who is nobody only sees meeting-wide messages.
+func chatVisibilityScope(viewer models.MeetingActor) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch {
+		case viewer.UserID != nil:
+			return db.Where("(chat_messages.is_private = ? OR chat_messages.user_id = ? OR EXISTS "+
+				"(SELECT 1 FROM chat_message_recipients r WHERE r.message_id = chat_messages.id AND r.user_id = ?))",
+				false, *viewer.UserID, *viewer.UserID)
+		case viewer.PublicUserID != nil:
+			return db.Where("(chat_messages.is_private = ? OR chat_messages.public_user_id = ? OR EXISTS "+
+				"(SELECT 1 FROM chat_message_recipients r WHERE r.message_id = chat_messages.id AND r.public_user_id = ?))",
+				false, *viewer.PublicUserID, *viewer.PublicUserID)
+		default:
+			return db.Where("chat_messages.is_private = ?", false)
+		}
+	}
+}
+
+// resolveRecipients checks a private message's recipients against the
+// meeting's private chat policy and returns them as recipient records.
+// Recipients are participant IDs of the meeting; the sender is dropped if
+// listed.
+func (s *ChatService) resolveRecipients(meetingID uuid.UUID, sender models.MeetingActor, participantIDs []uuid.UUID) ([]models.ChatMessageRecipient, error) {
+	var meeting models.Meeting
+	if err := s.db.Select("id, host_id, private_chat").Where("id = ?", meetingID).First(&meeting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("meeting not found")
+		}
+		return nil, fmt.Errorf("failed to fetch meeting: %w", err)
+	}
+	if meeting.PrivateChat == models.PrivateChatOff {
+		return nil, errors.New("private chat disabled")
+	}
+
+	ids := make([]uuid.UUID, 0, len(participantIDs))
+	seen := make(map[uuid.UUID]bool, len(participantIDs))
+	for _, id := range participantIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > models.MaxChatRecipients {
+		return nil, errors.New("too many recipients")
+	}
+
+	var participants []models.Participant
+	if err := s.db.Where("id IN ? AND meeting_id = ? AND lobby_status = ?", ids, meetingID, models.LobbyStatusAdmitted).
+		Find(&participants).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch recipients: %w", err)
+	}
+	if len(participants) != len(ids) {
+		return nil, errors.New("recipient not found")
+	}
+
+	recipients := make([]models.ChatMessageRecipient, 0, len(participants))
+	for _, participant := range participants {
+		actor := models.MeetingActor{UserID: participant.UserID, PublicUserID: participant.PublicUserID}
+		if sameActor(actor, sender) {
+			continue
+		}
+		recipients = append(recipients, models.ChatMessageRecipient{
+			ParticipantID: participant.ID,
+			UserID:        participant.UserID,
+			PublicUserID:  participant.PublicUserID,
+			Name:          participant.Name,
+		})
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("recipient not found")
+	}
+
+	// In host-only mode a private conversation needs a host or co-host on
+	// one side: they may write to anyone, everyone else only to them
+	if meeting.PrivateChat == models.PrivateChatHostOnly && !s.isChatModerator(&meeting, sender) {
+		for _, participant := range participants {
+			if participant.UserID != nil && *participant.UserID == meeting.HostID {
+				continue
+			}
+			if !participant.Role.Can(models.PermissionModerate) {
+				return nil, errors.New("private chat restricted to hosts")
+			}
+		}
+	}
+	return recipients, nil
+}
+
+// isChatModerator reports whether actor hosts or co-hosts the meeting
+func (s *ChatService) isChatModerator(meeting *models.Meeting, actor models.MeetingActor) bool {
+	if actor.UserID != nil && *actor.UserID == meeting.HostID {
+		return true
+	}
+	if s.roleService == nil {
+		return false
+	}
+	role, err := s.roleService.GetRole(meeting.ID, actor)
+	return err == nil && role.Can(models.PermissionModerate)
+}
+
+// publish delivers a chat event about message: to the whole meeting, or for
+// a private message only to the connections of its sender and recipients
+func (s *ChatService) publish(message *models.ChatMessage, wsMessage models.SignalingMessage) {
+	meetingID := message.MeetingID.String()
+	if !message.IsPrivate {
+		s.webSocketService.SendMessageToMeeting(meetingID, wsMessage)
+		return
+	}
+
+	recipients := message.Recipients
+	if recipients == nil {
+		if err := s.db.Where("message_id = ?", message.ID).Find(&recipients).Error; err != nil {
+			return
+		}
+	}
+	audience := []models.MeetingActor{{UserID: message.UserID, PublicUserID: message.PublicUserID}}
+	for _, recipient := range recipients {
+		audience = append(audience, recipient.Actor())
+	}
+	s.webSocketService.SendMessageToActors(meetingID, audience, wsMessage)
+}
+
+// sameActor reports whether a and b are the same user or public user
+func sameActor(a, b models.MeetingActor) bool {
+	if a.UserID != nil || b.UserID != nil {
+		return a.UserID != nil && b.UserID != nil && *a.UserID == *b.UserID
+	}
+	return a.PublicUserID != nil && b.PublicUserID != nil && *a.PublicUserID == *b.PublicUserID
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestChatService_PrivateMessages(t *testing.T) {
+	db := setupTestChatDB(t)
+	service := NewChatService(db, nil, nil, NewRoleService(db, nil))
+	meeting, hostID := createTestMeeting(t, db)
+	alice := addTestParticipant(t, db, meeting.ID, "alice", models.RoleAttendee)
+	bob := addTestParticipant(t, db, meeting.ID, "bob", models.RoleAttendee)
+	carol := addTestParticipant(t, db, meeting.ID, "carol", models.RoleAttendee)
+
+	send := func(userID uuid.UUID, content string, recipients ...uuid.UUID) (*models.ChatMessage, error) {
+		return service.SendMessage(&userID, nil, &models.CreateChatMessageRequest{
+			MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: content, RecipientIDs: recipients,
+		})
+	}
+	timeline := func(viewer *uuid.UUID) []string {
+		messages, _, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Limit: 10, Viewer: models.MeetingActor{UserID: viewer}})
+		require.NoError(t, err)
+		return messageContents(messages)
+	}
+
+	_, err := send(hostID, "welcome")
+	require.NoError(t, err)
+	// Listing the sender among the recipients is harmless
+	whisper, err := send(*alice.UserID, "psst", bob.ID, alice.ID, bob.ID)
+	require.NoError(t, err)
+	assert.True(t, whisper.IsPrivate)
+	require.Len(t, whisper.Recipients, 1)
+	assert.Equal(t, *bob.UserID, *whisper.Recipients[0].UserID)
+
+	// Only the sender and the recipients see a private message
+	assert.Equal(t, []string{"welcome", "psst"}, timeline(alice.UserID))
+	assert.Equal(t, []string{"welcome", "psst"}, timeline(bob.UserID))
+	assert.Equal(t, []string{"welcome"}, timeline(carol.UserID))
+	assert.Equal(t, []string{"welcome"}, timeline(&hostID))
+	assert.Equal(t, []string{"welcome"}, timeline(nil))
+
+	unread, err := service.GetUnreadCount(carol.UserID, nil, meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, unread)
+	unread, err = service.GetUnreadCount(bob.UserID, nil, meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, unread)
+	assert.EqualError(t, service.MarkMessageRead(carol.UserID, nil, whisper.ID), "message not found")
+
+	_, err = service.SendMessage(carol.UserID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: "me too", ReplyToID: &whisper.ID,
+	})
+	assert.EqualError(t, err, "reply to message not found")
+	_, err = service.SendMessage(bob.UserID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: "ok", ReplyToID: &whisper.ID,
+	})
+	assert.EqualError(t, err, "private messages cannot be threaded")
+
+	_, err = send(*alice.UserID, "who?", uuid.New())
+	assert.EqualError(t, err, "recipient not found")
+	_, err = send(*alice.UserID, "me", alice.ID)
+	assert.EqualError(t, err, "recipient not found")
+	tooMany := make([]uuid.UUID, models.MaxChatRecipients+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+	_, err = send(*alice.UserID, "all", tooMany...)
+	assert.EqualError(t, err, "too many recipients")
+
+	// Host-only: attendees may only write to the host and co-hosts
+	require.NoError(t, db.Model(meeting).Update("private_chat", models.PrivateChatHostOnly).Error)
+	cohost := addTestParticipant(t, db, meeting.ID, "cohost", models.RoleCoHost)
+	_, err = send(*alice.UserID, "psst", bob.ID)
+	assert.EqualError(t, err, "private chat restricted to hosts")
+	_, err = send(*alice.UserID, "question", cohost.ID)
+	assert.NoError(t, err)
+	_, err = send(*cohost.UserID, "answer", alice.ID, bob.ID)
+	assert.NoError(t, err)
+
+	require.NoError(t, db.Model(meeting).Update("private_chat", models.PrivateChatOff).Error)
+	_, err = send(*cohost.UserID, "answer", alice.ID)
+	assert.EqualError(t, err, "private chat disabled")
+}
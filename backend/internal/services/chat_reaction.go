@@ -0,0 +1,415 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// customEmojiPattern is the shortcode form custom emoji names take
+var customEmojiPattern = regexp.MustCompile(`^:[A-Za-z0-9_+-]+:$`)
+
+// EmojiSet decides which reactions are accepted: any Unicode emoji along
+// with the deployment's custom emoji, or the custom emoji alone. A nil set
+// accepts Unicode emoji only.
+type EmojiSet struct {
+	custom     map[string]bool
+	customOnly bool
+}
+
+// NewEmojiSet builds the emoji set described by the config. Custom emoji
+// are either shortcodes, like ":partyparrot:", or Unicode emoji, so that a
+// deployment can offer a fixed palette. It returns nil when the config
+// accepts any Unicode emoji and nothing else.
+func NewEmojiSet(cfg config.ChatConfig) (*EmojiSet, error) {
+	if len(cfg.CustomEmoji) == 0 {
+		if cfg.CustomEmojiOnly {
+			return nil, errors.New("custom emoji only, but no custom emoji configured")
+		}
+		return nil, nil
+	}
+
+	set := &EmojiSet{custom: make(map[string]bool), customOnly: cfg.CustomEmojiOnly}
+	for _, name := range cfg.CustomEmoji {
+		if len(name) > models.MaxReactionLength || !(customEmojiPattern.MatchString(name) || isEmoji(name)) {
+			return nil, fmt.Errorf("invalid custom emoji %q", name)
+		}
+		set.custom[name] = true
+	}
+	return set, nil
+}
+
+// Accepts reports whether reaction may be used as a reaction
+func (e *EmojiSet) Accepts(reaction string) bool {
+	if len(reaction) > models.MaxReactionLength {
+		return false
+	}
+	if e == nil {
+		return isEmoji(reaction)
+	}
+	if e.custom[reaction] {
+		return true
+	}
+	return !e.customOnly && isEmoji(reaction)
+}
+
+// SetEmojiSet restricts reactions to set
+func (s *ChatService) SetEmojiSet(set *EmojiSet) {
+	s.emojiSet = set
+}
+
+const (
+	zeroWidthJoiner     = '‍'
+	variationSelector16 = '️' // Asks for the emoji rather than the text presentation
+	combiningKeycap     = '⃣'
+	blackFlag           = '\U0001f3f4'
+	cancelTag           = '\U000e007f'
+)
+
+// emojiTable approximates the Emoji property of Unicode's emoji data: the
+// code points an emoji can be built on. Regional indicators and keycap
+// bases are left out, as they only make emoji in sequences.
+var emojiTable = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00a9, Hi: 0x00ae, Stride: 5},
+		{Lo: 0x203c, Hi: 0x2049, Stride: 13},
+		{Lo: 0x2122, Hi: 0x2139, Stride: 23},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21a9, Hi: 0x21aa, Stride: 1},
+		{Lo: 0x231a, Hi: 0x231b, Stride: 1},
+		{Lo: 0x2328, Hi: 0x23cf, Stride: 167},
+		{Lo: 0x23e9, Hi: 0x23f3, Stride: 1},
+		{Lo: 0x23f8, Hi: 0x23fa, Stride: 1},
+		{Lo: 0x24c2, Hi: 0x24c2, Stride: 1},
+		{Lo: 0x25aa, Hi: 0x25ab, Stride: 1},
+		{Lo: 0x25b6, Hi: 0x25c0, Stride: 10},
+		{Lo: 0x25fb, Hi: 0x25fe, Stride: 1},
+		{Lo: 0x2600, Hi: 0x27bf, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2b05, Hi: 0x2b07, Stride: 1},
+		{Lo: 0x2b1b, Hi: 0x2b1c, Stride: 1},
+		{Lo: 0x2b50, Hi: 0x2b55, Stride: 5},
+		{Lo: 0x3030, Hi: 0x303d, Stride: 13},
+		{Lo: 0x3297, Hi: 0x3299, Stride: 2},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f004, Hi: 0x1f0cf, Stride: 203},
+		{Lo: 0x1f170, Hi: 0x1f171, Stride: 1},
+		{Lo: 0x1f17e, Hi: 0x1f17f, Stride: 1},
+		{Lo: 0x1f18e, Hi: 0x1f18e, Stride: 1},
+		{Lo: 0x1f191, Hi: 0x1f19a, Stride: 1},
+		{Lo: 0x1f201, Hi: 0x1f202, Stride: 1},
+		{Lo: 0x1f21a, Hi: 0x1f22f, Stride: 21},
+		{Lo: 0x1f232, Hi: 0x1f23a, Stride: 1},
+		{Lo: 0x1f250, Hi: 0x1f251, Stride: 1},
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f680, Hi: 0x1f6ff, Stride: 1},
+		{Lo: 0x1f7e0, Hi: 0x1f7eb, Stride: 1},
+		{Lo: 0x1f7f0, Hi: 0x1f7f0, Stride: 1},
+		{Lo: 0x1f90c, Hi: 0x1f9ff, Stride: 1},
+		{Lo: 0x1fa70, Hi: 0x1faff, Stride: 1},
+	},
+	LatinOffset: 1,
+}
+
+// isEmoji reports whether s is a single emoji: one emoji element, or
+// several joined with zero width joiners, like 👩🏽‍💻 or 🏳️‍🌈
+func isEmoji(s string) bool {
+	runes := []rune(s)
+	for i := 0; ; i++ {
+		n := emojiElement(runes[i:])
+		if n == 0 {
+			return false
+		}
+		i += n
+		if i == len(runes) {
+			return true
+		}
+		if runes[i] != zeroWidthJoiner {
+			return false
+		}
+	}
+}
+
+// emojiElement returns the length of the emoji element runes start with,
+// or 0 when they start with none. An element is a flag, a keycap, or an
+// emoji with an optional presentation selector or skin tone; subdivision
+// flags, like Scotland's, follow the black flag with tags.
+func emojiElement(runes []rune) int {
+	if len(runes) == 0 {
+		return 0
+	}
+
+	switch r := runes[0]; {
+	case isRegionalIndicator(r):
+		if len(runes) > 1 && isRegionalIndicator(runes[1]) {
+			return 2
+		}
+		return 0
+	case r == '#' || r == '*' || (r >= '0' && r <= '9'):
+		n := 1
+		if n < len(runes) && runes[n] == variationSelector16 {
+			n++
+		}
+		if n < len(runes) && runes[n] == combiningKeycap {
+			return n + 1
+		}
+		return 0
+	case !unicode.Is(emojiTable, r):
+		return 0
+	}
+
+	n := 1
+	if n < len(runes) && (runes[n] == variationSelector16 || isSkinTone(runes[n])) {
+		n++
+	}
+	if runes[0] == blackFlag && n == 1 {
+		tags := n
+		for n < len(runes) && runes[n] >= 0xe0020 && runes[n] <= 0xe007e {
+			n++
+		}
+		if n > tags {
+			if n == len(runes) || runes[n] != cancelTag {
+				return 0
+			}
+			n++
+		}
+	}
+	return n
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+func isSkinTone(r rune) bool {
+	return r >= 0x1f3fb && r <= 0x1f3ff
+}
+
+// ToggleReaction adds or removes the actor's reaction to a message. It
+// returns the message's summary for the emoji afterwards; ReactedByMe tells
+// whether the reaction was added.
+func (s *ChatService) ToggleReaction(userID *uuid.UUID, publicUserID *uuid.UUID, req *models.CreateChatMessageReactionRequest) (*models.ChatReactionSummary, error) {
+	actor := models.MeetingActor{UserID: userID, PublicUserID: publicUserID}
+	if !s.emojiSet.Accepts(req.Reaction) {
+		return nil, errors.New("invalid reaction")
+	}
+
+	var message models.ChatMessage
+	if err := s.db.Scopes(chatVisibilityScope(actor)).
+		Where("id = ? AND is_deleted = ?", req.MessageID, false).First(&message).Error; err != nil {
+		return nil, errors.New("message not found")
+	}
+
+	if err := s.checkChatPermission(message.MeetingID, userID, publicUserID); err != nil {
+		return nil, err
+	}
+
+	// Reactions to a message take turns on its row, so two new emoji at once
+	// cannot both take the last slot
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var locked models.ChatMessage
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			Where("id = ? AND is_deleted = ?", req.MessageID, false).First(&locked).Error; err != nil {
+			return errors.New("message not found")
+		}
+
+		var existing models.ChatMessageReaction
+		err := tx.Scopes(reactorScope(actor)).
+			Where("message_id = ? AND reaction = ?", req.MessageID, req.Reaction).
+			First(&existing).Error
+		switch {
+		case err == nil:
+			if err := tx.Delete(&existing).Error; err != nil {
+				return fmt.Errorf("failed to remove reaction: %w", err)
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			var used []string
+			if err := tx.Model(&models.ChatMessageReaction{}).
+				Where("message_id = ?", req.MessageID).
+				Distinct().Pluck("reaction", &used).Error; err != nil {
+				return fmt.Errorf("failed to fetch reactions: %w", err)
+			}
+			if !slices.Contains(used, req.Reaction) && len(used) >= models.MaxDistinctReactions {
+				return errors.New("reaction limit reached")
+			}
+
+			reaction := models.ChatMessageReaction{
+				MessageID:    req.MessageID,
+				UserID:       userID,
+				PublicUserID: publicUserID,
+				Reaction:     req.Reaction,
+			}
+			if err := tx.Create(&reaction).Error; err != nil {
+				return fmt.Errorf("failed to add reaction: %w", err)
+			}
+		default:
+			return fmt.Errorf("failed to fetch reaction: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.summarizeReactions(actor, &message); err != nil {
+		return nil, err
+	}
+	summary := models.ChatReactionSummary{Emoji: req.Reaction, SampleUsers: []models.ChatReactionUser{}}
+	for _, candidate := range message.ReactionSummaries {
+		if candidate.Emoji == req.Reaction {
+			summary = candidate
+		}
+	}
+
+	go s.broadcastReaction(&message, actor, summary)
+
+	return &summary, nil
+}
+
+// summarizeReactions aggregates each message's reactions per emoji, in the
+// order the emoji were first used, marking those viewer reacted with
+func (s *ChatService) summarizeReactions(viewer models.MeetingActor, messages ...*models.ChatMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+
+	var counts []struct {
+		MessageID uuid.UUID
+		Reaction  string
+		Count     int
+	}
+	if err := s.db.Model(&models.ChatMessageReaction{}).
+		Select("message_id, reaction, COUNT(*) AS count").
+		Where("message_id IN ?", ids).
+		Group("message_id, reaction").
+		Order("MIN(created_at), reaction").
+		Scan(&counts).Error; err != nil {
+		return fmt.Errorf("failed to count reactions: %w", err)
+	}
+
+	// Only the first few reactors of each emoji are loaded
+	ranked := s.db.Model(&models.ChatMessageReaction{}).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY message_id, reaction ORDER BY created_at, id) AS position").
+		Where("message_id IN ?", ids)
+	var samples []models.ChatMessageReaction
+	if err := s.db.Table("(?) AS chat_message_reactions", ranked).
+		Where("position <= ?", models.MaxReactionSampleUsers).
+		Order("created_at, id").
+		Preload("User").
+		Preload("PublicUser").
+		Find(&samples).Error; err != nil {
+		return fmt.Errorf("failed to fetch reactions: %w", err)
+	}
+
+	type key struct {
+		messageID uuid.UUID
+		reaction  string
+	}
+	mine := make(map[key]bool)
+	if viewer.UserID != nil || viewer.PublicUserID != nil {
+		var own []models.ChatMessageReaction
+		if err := s.db.Select("message_id, reaction").Scopes(reactorScope(viewer)).
+			Where("message_id IN ?", ids).
+			Find(&own).Error; err != nil {
+			return fmt.Errorf("failed to fetch reactions: %w", err)
+		}
+		for _, reaction := range own {
+			mine[key{reaction.MessageID, reaction.Reaction}] = true
+		}
+	}
+
+	sampled := make(map[key][]models.ChatReactionUser)
+	for _, reaction := range samples {
+		k := key{reaction.MessageID, reaction.Reaction}
+		sampled[k] = append(sampled[k], reactionUser(&reaction))
+	}
+
+	byMessage := make(map[uuid.UUID][]models.ChatReactionSummary, len(messages))
+	for _, count := range counts {
+		k := key{count.MessageID, count.Reaction}
+		users := sampled[k]
+		if users == nil {
+			users = []models.ChatReactionUser{}
+		}
+		byMessage[count.MessageID] = append(byMessage[count.MessageID], models.ChatReactionSummary{
+			Emoji:       count.Reaction,
+			Count:       count.Count,
+			ReactedByMe: mine[k],
+			SampleUsers: users,
+		})
+	}
+	for _, message := range messages {
+		message.ReactionSummaries = byMessage[message.ID]
+	}
+	return nil
+}
+
+// reactorScope selects the reactions actor made
+func reactorScope(actor models.MeetingActor) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if actor.UserID != nil {
+			return db.Where("user_id = ?", *actor.UserID)
+		}
+		return db.Where("public_user_id = ?", *actor.PublicUserID)
+	}
+}
+
+// reactionUser names the reactor behind a reaction
+func reactionUser(reaction *models.ChatMessageReaction) models.ChatReactionUser {
+	user := models.ChatReactionUser{UserID: reaction.UserID, PublicUserID: reaction.PublicUserID}
+	switch {
+	case reaction.User != nil:
+		user.Name = reaction.User.Username
+	case reaction.PublicUser != nil:
+		user.Name = reaction.PublicUser.Name
+	}
+	return user
+}
+
+// broadcastReaction tells those who can see a message that actor added or
+// removed a reaction, with the emoji's new count and sample reactors.
+// Whether it is the receiver's own reaction is for clients to work out from
+// the reactor.
+func (s *ChatService) broadcastReaction(message *models.ChatMessage, actor models.MeetingActor, summary models.ChatReactionSummary) {
+	if s.webSocketService == nil {
+		return
+	}
+
+	action := "removed"
+	if summary.ReactedByMe {
+		action = "added"
+	}
+	// Create WebSocket payload that matches frontend expectations
+	payload := map[string]interface{}{
+		"message":      map[string]interface{}{"id": message.ID.String()},
+		"reaction":     summary.Emoji,
+		"action":       action,
+		"count":        summary.Count,
+		"sampleUsers":  summary.SampleUsers,
+		"userId":       actor.UserID,
+		"publicUserId": actor.PublicUserID,
+	}
+
+	wsMessage := models.SignalingMessage{
+		Type:      models.SignalingTypeChatReaction,
+		MeetingID: message.MeetingID.String(),
+		Data:      payload,
+		Timestamp: time.Now(),
+	}
+	s.publish(message, wsMessage)
+}
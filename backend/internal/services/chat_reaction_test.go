@@ -0,0 +1,105 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestEmojiSet(t *testing.T) {
+	var unicodeOnly *EmojiSet
+	for _, emoji := range []string{
+		"👍", "👍🏽", "❤️", "❤", "🇫🇷", "1️⃣", "#⃣", "👩🏽‍💻", "🏳️‍🌈", "👨‍👩‍👧‍👦",
+		"👩🏻‍❤️‍💋‍👨🏼", "🏴󠁧󠁢󠁳󠁣󠁴󠁿", "©️", "🫠",
+	} {
+		assert.True(t, unicodeOnly.Accepts(emoji), emoji)
+	}
+	for _, text := range []string{
+		"", "a", "ok", "👍👍", "👍 ", "1", "🇫", "👍‍", "‍👍", "🏴󠁧󠁢", ":partyparrot:", "<b>",
+	} {
+		assert.False(t, unicodeOnly.Accepts(text), text)
+	}
+
+	set, err := NewEmojiSet(config.ChatConfig{CustomEmoji: []string{":partyparrot:", ":+1:"}})
+	require.NoError(t, err)
+	assert.True(t, set.Accepts(":partyparrot:"))
+	assert.True(t, set.Accepts("🎉"))
+	assert.False(t, set.Accepts(":shipit:"))
+
+	set, err = NewEmojiSet(config.ChatConfig{CustomEmoji: []string{"👍", "🎉", ":partyparrot:"}, CustomEmojiOnly: true})
+	require.NoError(t, err)
+	assert.True(t, set.Accepts("🎉"))
+	assert.False(t, set.Accepts("❤️"))
+
+	_, err = NewEmojiSet(config.ChatConfig{CustomEmoji: []string{"party parrot"}})
+	assert.Error(t, err)
+	_, err = NewEmojiSet(config.ChatConfig{CustomEmojiOnly: true})
+	assert.Error(t, err)
+}
+
+func TestChatService_Reactions(t *testing.T) {
+	db := setupTestChatDB(t)
+	service := NewChatService(db, nil, nil, NewRoleService(db, nil))
+	meeting, hostID := createTestMeeting(t, db)
+	participants := make([]*models.Participant, 4)
+	for i := range participants {
+		participants[i] = addTestParticipant(t, db, meeting.ID, fmt.Sprintf("guest%d", i), models.RoleAttendee)
+	}
+	message := createTestChatMessages(t, db, meeting.ID, hostID, 1)[0]
+	react := func(participant *models.Participant, emoji string) (*models.ChatReactionSummary, error) {
+		return service.ToggleReaction(participant.UserID, nil, &models.CreateChatMessageReactionRequest{MessageID: message.ID, Reaction: emoji})
+	}
+
+	_, err := react(participants[0], "nice")
+	assert.EqualError(t, err, "invalid reaction")
+
+	// Summaries count every reactor but name only the first few
+	for _, participant := range participants {
+		summary, err := react(participant, "👍🏽")
+		require.NoError(t, err)
+		assert.True(t, summary.ReactedByMe)
+	}
+	summary, err := react(participants[1], "🎉")
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Count)
+
+	messages, _, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Limit: 10, Viewer: models.MeetingActor{UserID: participants[1].UserID}})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	reactions := messages[0].ToResponse().Reactions
+	require.Len(t, reactions, 2)
+	assert.Equal(t, "👍🏽", reactions[0].Emoji)
+	assert.Equal(t, 4, reactions[0].Count)
+	assert.True(t, reactions[0].ReactedByMe)
+	require.Len(t, reactions[0].SampleUsers, models.MaxReactionSampleUsers)
+	assert.Equal(t, "guest0", reactions[0].SampleUsers[0].Name)
+	assert.Equal(t, "🎉", reactions[1].Emoji)
+
+	messages, _, err = service.GetMessages(meeting.ID, models.ChatHistoryQuery{Limit: 10, Viewer: models.MeetingActor{UserID: participants[0].UserID}})
+	require.NoError(t, err)
+	assert.False(t, messages[0].ReactionSummaries[1].ReactedByMe)
+
+	// Toggling again removes the reaction
+	summary, err = react(participants[1], "🎉")
+	require.NoError(t, err)
+	assert.False(t, summary.ReactedByMe)
+	assert.Equal(t, 0, summary.Count)
+	assert.Empty(t, summary.SampleUsers)
+
+	// A message collects a bounded number of different emoji, though anyone
+	// can still join in on one it has
+	palette := []rune("😀😁😂🤣😃😄😅😆😉😊😋😎😍😘🥰😗😙😚🙂🤗🤩")
+	for _, emoji := range palette[:models.MaxDistinctReactions-1] {
+		_, err := react(participants[2], string(emoji))
+		require.NoError(t, err)
+	}
+	_, err = react(participants[3], string(palette[models.MaxDistinctReactions]))
+	assert.EqualError(t, err, "reaction limit reached")
+	_, err = react(participants[3], string(palette[0]))
+	assert.NoError(t, err)
+}
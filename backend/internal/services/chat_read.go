@@ -0,0 +1,223 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// MarkReadUpTo moves the reader's cursor up to a message, marking it and
+// everything before it as read: in the main timeline, or in the thread when
+// the message is a reply. Cursors only move forward, so marking an older
+// message changes nothing.
+func (s *ChatService) MarkReadUpTo(meetingID uuid.UUID, actor models.MeetingActor, messageID uuid.UUID) (*models.ChatReadCursor, error) {
+	if actor.UserID == nil && actor.PublicUserID == nil {
+		return nil, errors.New("message not found")
+	}
+
+	var message models.ChatMessage
+	if err := s.db.Select("id, meeting_id, reply_to_id, created_at").
+		Scopes(chatVisibilityScope(actor)).
+		Where("id = ? AND meeting_id = ? AND is_deleted = ?", messageID, meetingID, false).
+		First(&message).Error; err != nil {
+		return nil, errors.New("message not found")
+	}
+
+	var cursor *models.ChatReadCursor
+	var moved bool
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		cursor, moved, err = advanceReadCursor(tx, actor, &message)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if moved {
+		go s.broadcastReadCursor(cursor)
+	}
+	return cursor, nil
+}
+
+// GetReadCursor returns the reader's cursor for a meeting's main timeline,
+// or for a thread, or nil when they have not read any of it
+func (s *ChatService) GetReadCursor(meetingID uuid.UUID, thread *uuid.UUID, actor models.MeetingActor) (*models.ChatReadCursor, error) {
+	if actor.UserID == nil && actor.PublicUserID == nil {
+		return nil, nil
+	}
+	var cursor models.ChatReadCursor
+	err := readCursorQuery(s.db, meetingID, thread, actor).First(&cursor).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to fetch read cursor: %w", err)
+	}
+	return &cursor, nil
+}
+
+// advanceReadCursor moves the reader's cursor for message's timeline or
+// thread up to message, creating the cursor on first read. It reports
+// whether the cursor moved.
+func advanceReadCursor(tx *gorm.DB, actor models.MeetingActor, message *models.ChatMessage) (*models.ChatReadCursor, bool, error) {
+	var cursor models.ChatReadCursor
+	err := readCursorQuery(tx, message.MeetingID, message.ReplyToID, actor).First(&cursor).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		cursor = models.ChatReadCursor{
+			MeetingID:         message.MeetingID,
+			ThreadID:          message.ReplyToID,
+			UserID:            actor.UserID,
+			PublicUserID:      actor.PublicUserID,
+			LastReadMessageID: message.ID,
+			LastReadAt:        message.CreatedAt,
+		}
+		if err := tx.Create(&cursor).Error; err != nil {
+			return nil, false, fmt.Errorf("failed to mark messages as read: %w", err)
+		}
+		return &cursor, true, nil
+	case err != nil:
+		return nil, false, fmt.Errorf("failed to fetch read cursor: %w", err)
+	}
+
+	if cursor.Position().Covers(message) {
+		return &cursor, false, nil
+	}
+
+	// The position is checked again in the update, so a concurrent mark
+	// further ahead is not undone
+	result := tx.Model(&cursor).
+		Where("(last_read_at < ? OR (last_read_at = ? AND last_read_message_id < ?))", message.CreatedAt, message.CreatedAt, message.ID).
+		Updates(map[string]interface{}{
+			"last_read_message_id": message.ID,
+			"last_read_at":         message.CreatedAt,
+		})
+	if result.Error != nil {
+		return nil, false, fmt.Errorf("failed to mark messages as read: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return &cursor, false, nil
+	}
+	cursor.LastReadMessageID = message.ID
+	cursor.LastReadAt = message.CreatedAt
+	return &cursor, true, nil
+}
+
+// readCursorQuery selects the reader's cursor for a meeting's main timeline,
+// or for thread when it is set
+func readCursorQuery(db *gorm.DB, meetingID uuid.UUID, thread *uuid.UUID, actor models.MeetingActor) *gorm.DB {
+	query := db.Where("meeting_id = ?", meetingID)
+	if thread == nil {
+		query = query.Where("thread_id IS NULL")
+	} else {
+		query = query.Where("thread_id = ?", *thread)
+	}
+	if actor.UserID != nil {
+		return query.Where("user_id = ?", *actor.UserID)
+	}
+	return query.Where("public_user_id = ?", *actor.PublicUserID)
+}
+
+// unreadScope keeps the messages the reader's cursors have not passed: main
+// timeline messages after their timeline cursor, and replies after their
+// cursor for the thread
+func unreadScope(actor models.MeetingActor) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		readerColumn, reader := "c.user_id", actor.UserID
+		if actor.UserID == nil {
+			readerColumn, reader = "c.public_user_id", actor.PublicUserID
+		}
+		return db.Where("NOT EXISTS (SELECT 1 FROM chat_read_cursors c WHERE c.meeting_id = chat_messages.meeting_id AND "+readerColumn+" = ? AND "+
+			"(c.thread_id = chat_messages.reply_to_id OR (c.thread_id IS NULL AND chat_messages.reply_to_id IS NULL)) AND "+
+			"(c.last_read_at > chat_messages.created_at OR (c.last_read_at = chat_messages.created_at AND c.last_read_message_id >= chat_messages.id)))",
+			*reader)
+	}
+}
+
+// countSeenBy fills in how many readers have seen each message: those whose
+// cursor for the message's timeline or thread has passed it, the sender
+// aside. Only recipients count towards a private message. The messages must
+// belong to one meeting and have their recipients loaded.
+func (s *ChatService) countSeenBy(messages ...*models.ChatMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var threads []uuid.UUID
+	for _, message := range messages {
+		if message.ReplyToID != nil {
+			threads = append(threads, *message.ReplyToID)
+		}
+	}
+	query := s.db.Where("meeting_id = ?", messages[0].MeetingID)
+	if len(threads) > 0 {
+		query = query.Where("(thread_id IS NULL OR thread_id IN ?)", threads)
+	} else {
+		query = query.Where("thread_id IS NULL")
+	}
+	var cursors []models.ChatReadCursor
+	if err := query.Find(&cursors).Error; err != nil {
+		return fmt.Errorf("failed to fetch read cursors: %w", err)
+	}
+
+	// The main timeline's cursors are filed under the nil UUID
+	byThread := make(map[uuid.UUID][]*models.ChatReadCursor)
+	for i := range cursors {
+		thread := uuid.Nil
+		if cursors[i].ThreadID != nil {
+			thread = *cursors[i].ThreadID
+		}
+		byThread[thread] = append(byThread[thread], &cursors[i])
+	}
+
+	for _, message := range messages {
+		thread := uuid.Nil
+		if message.ReplyToID != nil {
+			thread = *message.ReplyToID
+		}
+		var audience []models.MeetingActor
+		for _, recipient := range message.Recipients {
+			audience = append(audience, recipient.Actor())
+		}
+		sender := models.MeetingActor{UserID: message.UserID, PublicUserID: message.PublicUserID}
+
+		message.SeenBy = 0
+		for _, cursor := range byThread[thread] {
+			reader := cursor.Actor()
+			if sameActor(reader, sender) || !cursor.Position().Covers(message) {
+				continue
+			}
+			if message.IsPrivate && !containsActor(audience, reader) {
+				continue
+			}
+			message.SeenBy++
+		}
+	}
+	return nil
+}
+
+// broadcastReadCursor tells the meeting that a reader's cursor moved
+func (s *ChatService) broadcastReadCursor(cursor *models.ChatReadCursor) {
+	if s.webSocketService == nil {
+		return
+	}
+
+	s.webSocketService.SendMessageToMeeting(cursor.MeetingID.String(), models.SignalingMessage{
+		Type:      models.SignalingTypeChatReadCursor,
+		MeetingID: cursor.MeetingID.String(),
+		Data: models.ChatReadCursorPayload{
+			ThreadID:          cursor.ThreadID,
+			UserID:            cursor.UserID,
+			PublicUserID:      cursor.PublicUserID,
+			LastReadMessageID: cursor.LastReadMessageID,
+			LastReadAt:        cursor.LastReadAt,
+		},
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestChatService_ReadCursors(t *testing.T) {
+	db := setupTestChatDB(t)
+	service := NewChatService(db, nil, nil, NewRoleService(db, nil))
+	meeting, hostID := createTestMeeting(t, db)
+	alice := addTestParticipant(t, db, meeting.ID, "alice", models.RoleAttendee)
+	bob := addTestParticipant(t, db, meeting.ID, "bob", models.RoleAttendee)
+	messages := createTestChatMessages(t, db, meeting.ID, hostID, 6)
+	asAlice := models.MeetingActor{UserID: alice.UserID}
+	asBob := models.MeetingActor{UserID: bob.UserID}
+
+	unread := func(actor models.MeetingActor) int {
+		count, err := service.GetUnreadCount(actor.UserID, actor.PublicUserID, meeting.ID)
+		require.NoError(t, err)
+		return count
+	}
+	seenBy := func(viewer models.MeetingActor) map[uuid.UUID]int {
+		page, _, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Limit: 20, Viewer: viewer})
+		require.NoError(t, err)
+		counts := make(map[uuid.UUID]int)
+		for _, message := range page {
+			counts[message.ID] = message.SeenBy
+		}
+		return counts
+	}
+
+	// Marking one message reads everything before it too
+	assert.Equal(t, 6, unread(asAlice))
+	cursor, err := service.MarkReadUpTo(meeting.ID, asAlice, messages[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, messages[1].ID, cursor.LastReadMessageID)
+	assert.Equal(t, 4, unread(asAlice))
+
+	_, err = service.MarkReadUpTo(meeting.ID, asAlice, messages[5].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, unread(asAlice))
+
+	// Cursors never move back
+	cursor, err = service.MarkReadUpTo(meeting.ID, asAlice, messages[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, messages[5].ID, cursor.LastReadMessageID)
+	cursor, err = service.GetReadCursor(meeting.ID, nil, asAlice)
+	require.NoError(t, err)
+	assert.Equal(t, messages[5].ID, cursor.LastReadMessageID)
+
+	_, err = service.MarkReadUpTo(meeting.ID, asAlice, uuid.New())
+	assert.EqualError(t, err, "message not found")
+	cursor, err = service.GetReadCursor(meeting.ID, nil, asBob)
+	require.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	// Seen-by counts readers whose cursor has passed, never the sender
+	_, err = service.MarkReadUpTo(meeting.ID, models.MeetingActor{UserID: &hostID}, messages[5].ID)
+	require.NoError(t, err)
+	require.NoError(t, service.MarkMessageRead(bob.UserID, nil, messages[1].ID))
+	counts := seenBy(asAlice)
+	assert.Equal(t, 2, counts[messages[0].ID])
+	assert.Equal(t, 2, counts[messages[1].ID])
+	assert.Equal(t, 1, counts[messages[2].ID])
+	assert.Equal(t, 1, counts[messages[5].ID])
+
+	// Replies are read with a cursor of their own
+	reply, err := service.SendMessage(bob.UserID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: "agreed", ReplyToID: &messages[5].ID,
+	})
+	require.NoError(t, err)
+	threads, err := service.GetThreadUnreadCounts(alice.UserID, nil, meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]int{messages[5].ID: 1}, threads)
+	assert.Equal(t, 0, unread(asAlice))
+
+	_, err = service.MarkReadUpTo(meeting.ID, asAlice, reply.ID)
+	require.NoError(t, err)
+	threads, err = service.GetThreadUnreadCounts(alice.UserID, nil, meeting.ID)
+	require.NoError(t, err)
+	assert.Empty(t, threads)
+	cursor, err = service.GetReadCursor(meeting.ID, &messages[5].ID, asAlice)
+	require.NoError(t, err)
+	assert.Equal(t, reply.ID, cursor.LastReadMessageID)
+	cursor, err = service.GetReadCursor(meeting.ID, nil, asAlice)
+	require.NoError(t, err)
+	assert.Equal(t, messages[5].ID, cursor.LastReadMessageID)
+
+	// Only recipients count towards a private message
+	whisper, err := service.SendMessage(&hostID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: "psst", RecipientIDs: []uuid.UUID{bob.ID},
+	})
+	require.NoError(t, err)
+	after, err := service.SendMessage(&hostID, nil, &models.CreateChatMessageRequest{
+		MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: "all",
+	})
+	require.NoError(t, err)
+	_, err = service.MarkReadUpTo(meeting.ID, asAlice, after.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, seenBy(asBob)[whisper.ID])
+	_, err = service.MarkReadUpTo(meeting.ID, asBob, whisper.ID)
+	require.NoError(t, err)
+	counts = seenBy(asBob)
+	assert.Equal(t, 1, counts[whisper.ID])
+	assert.Equal(t, 1, counts[after.ID])
+}
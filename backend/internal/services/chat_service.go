@@ -15,124 +15,144 @@ type ChatService struct {
 	db                *gorm.DB
 	webSocketService  *WebSocketService
 	publicUserService *PublicUserService
+	roleService       *RoleService
+	attachmentService *AttachmentService
+	contentFilter     models.ChatContentFilter
+	emojiSet          *EmojiSet
 }
 
-func NewChatService(db *gorm.DB, webSocketService *WebSocketService, publicUserService *PublicUserService) *ChatService {
+func NewChatService(db *gorm.DB, webSocketService *WebSocketService, publicUserService *PublicUserService, roleService *RoleService) *ChatService {
 	return &ChatService{
 		db:                db,
 		webSocketService:  webSocketService,
 		publicUserService: publicUserService,
+		roleService:       roleService,
 	}
 }
 
-// GetMessages retrieves paginated chat messages for a meeting
-func (s *ChatService) GetMessages(meetingID uuid.UUID, page, limit int, before, after string) ([]models.ChatMessage, models.PaginationInfo, error) {
-	var messages []models.ChatMessage
-	var total int64
-
-	query := s.db.Where("meeting_id = ? AND is_deleted = ?", meetingID, false)
+// SetAttachmentService enables sending files with chat messages
+func (s *ChatService) SetAttachmentService(attachmentService *AttachmentService) {
+	s.attachmentService = attachmentService
+}
 
-	// Apply time filters
-	if before != "" {
-		beforeTime, err := time.Parse(time.RFC3339, before)
-		if err == nil {
-			query = query.Where("created_at < ?", beforeTime)
-		}
+// SendMessage creates and sends a new chat message
+func (s *ChatService) SendMessage(userID *uuid.UUID, publicUserID *uuid.UUID, req *models.CreateChatMessageRequest) (*models.ChatMessage, error) {
+	// Validate content; a file may be sent without text
+	if strings.TrimSpace(req.Content) == "" && req.AttachmentID == nil {
+		return nil, fmt.Errorf("message content cannot be empty")
 	}
-	if after != "" {
-		afterTime, err := time.Parse(time.RFC3339, after)
-		if err == nil {
-			query = query.Where("created_at > ?", afterTime)
-		}
+	if req.AttachmentID != nil && s.attachmentService == nil {
+		return nil, fmt.Errorf("attachment not found")
 	}
 
-	// Count total messages
-	if err := query.Model(&models.ChatMessage{}).Count(&total).Error; err != nil {
-		return nil, models.PaginationInfo{}, err
+	if err := s.checkChatPermission(req.MeetingID, userID, publicUserID); err != nil {
+		return nil, err
 	}
 
-	// Apply pagination
-	offset := (page - 1) * limit
-	if err := query.
-		Preload("User").
-		Preload("PublicUser").
-		Preload("ReplyTo").
-		Preload("Replies", "is_deleted = ?", false).
-		Preload("Replies.User").
-		Preload("Replies.PublicUser").
-		Preload("ReadStatus").
-		Preload("ReadStatus.User").
-		Preload("ReadStatus.PublicUser").
-		Preload("Reactions").
-		Preload("Reactions.User").
-		Preload("Reactions.PublicUser").
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&messages).Error; err != nil {
-		return nil, models.PaginationInfo{}, err
-	}
-
-	// Reverse order to show oldest first
-	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
-		messages[i], messages[j] = messages[j], messages[i]
-	}
-
-	pagination := models.PaginationInfo{
-		Page:       page,
-		Limit:      limit,
-		Total:      int(total),
-		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
-	}
-
-	return messages, pagination, nil
-}
-
-// SendMessage creates and sends a new chat message
-func (s *ChatService) SendMessage(userID *uuid.UUID, publicUserID *uuid.UUID, req *models.CreateChatMessageRequest) (*models.ChatMessage, error) {
-	// Validate content
-	if strings.TrimSpace(req.Content) == "" {
-		return nil, fmt.Errorf("message content cannot be empty")
+	sender := models.MeetingActor{UserID: userID, PublicUserID: publicUserID}
+	if err := s.checkSlowMode(req.MeetingID, sender); err != nil {
+		return nil, err
+	}
+	if req.MessageType == models.MessageTypeAnnouncement {
+		if err := s.checkAnnouncement(req, sender); err != nil {
+			return nil, err
+		}
 	}
 
 	// Sanitize content (basic XSS prevention)
 	req.Content = strings.TrimSpace(req.Content)
+	content, flags, err := s.filterContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	req.Content = content
 
 	// Create message
 	message := models.ChatMessage{
+		ID:             uuid.New(),
 		MeetingID:      req.MeetingID,
 		UserID:         userID,
 		PublicUserID:   publicUserID,
 		MessageType:    req.MessageType,
 		Content:        req.Content,
 		ReplyToID:      req.ReplyToID,
-		AttachmentURL:  req.AttachmentURL,
-		AttachmentType: req.AttachmentType,
-		AttachmentName: req.AttachmentName,
+		RequiresAck:    req.RequiresAck && req.MessageType == models.MessageTypeAnnouncement,
 		MessageStatus:  models.MessageStatusSent,
 	}
 
 	// Validate reply to exists if specified
 	if req.ReplyToID != nil {
 		var replyTo models.ChatMessage
-		if err := s.db.Where("id = ? AND meeting_id = ? AND is_deleted = ?",
-			*req.ReplyToID, req.MeetingID, false).First(&replyTo).Error; err != nil {
+		if err := s.db.Scopes(chatVisibilityScope(sender)).
+			Where("id = ? AND meeting_id = ? AND is_deleted = ?", *req.ReplyToID, req.MeetingID, false).
+			First(&replyTo).Error; err != nil {
 			return nil, fmt.Errorf("reply to message not found")
 		}
+		// Threads are meeting-wide; private conversations stay flat
+		if replyTo.IsPrivate || len(req.RecipientIDs) > 0 {
+			return nil, fmt.Errorf("private messages cannot be threaded")
+		}
+		threadID := threadRoot(&replyTo)
+		message.ReplyToID = &threadID
 	}
 
-	// Save message
-	if err := s.db.Create(&message).Error; err != nil {
-		return nil, fmt.Errorf("failed to create message: %w", err)
+	if len(req.RecipientIDs) > 0 {
+		recipients, err := s.resolveRecipients(req.MeetingID, sender, req.RecipientIDs)
+		if err != nil {
+			return nil, err
+		}
+		message.IsPrivate = true
+		message.Recipients = recipients
+	}
+
+	// Save message, claiming the attachment in the same transaction
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if req.AttachmentID != nil {
+			attachment, err := s.attachmentService.claim(tx, req.MeetingID, *req.AttachmentID, sender, message.ID)
+			if err != nil {
+				return err
+			}
+			// The message type follows the file, whatever the client said;
+			// announcements stay announcements
+			if message.MessageType != models.MessageTypeAnnouncement {
+				message.MessageType = models.MessageTypeFile
+				if attachment.IsImage() {
+					message.MessageType = models.MessageTypeImage
+				}
+			}
+			message.AttachmentType = attachment.ContentType
+			message.AttachmentName = attachment.FileName
+		}
+
+		if err := tx.Create(&message).Error; err != nil {
+			return fmt.Errorf("failed to create message: %w", err)
+		}
+		if err := flagMessage(tx, &message, flags); err != nil {
+			return err
+		}
+		if message.ReplyToID != nil {
+			return addThreadReply(tx, &message)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Load relationships
 	if err := s.loadMessageRelationships(&message); err != nil {
 		return nil, fmt.Errorf("failed to load message relationships: %w", err)
 	}
+	s.signAttachments(&message)
 
 	// Broadcast via WebSocket
 	go s.broadcastMessage(&message)
+	if message.ReplyToID != nil {
+		go s.broadcastThreadUpdate(&message, false)
+	}
+	if len(flags) > 0 {
+		go s.broadcastFlag(&message, flags)
+	}
 
 	return &message, nil
 }
@@ -146,18 +166,33 @@ func (s *ChatService) UpdateMessage(userID uuid.UUID, messageID uuid.UUID, req *
 		return nil, fmt.Errorf("message not found")
 	}
 
-	// Check ownership
-	if userID != *message.UserID {
-		return nil, fmt.Errorf("unauthorized")
+	// Check ownership; hosts and co-hosts may also remove other people's
+	// messages, which leaves a tombstone in the history
+	deleted := req.IsDeleted != nil && *req.IsDeleted
+	moderated := false
+	if message.UserID == nil || userID != *message.UserID {
+		if req.Content != nil || !deleted {
+			return nil, fmt.Errorf("unauthorized")
+		}
+		if err := s.checkMessageModerator(&message, userID); err != nil {
+			return nil, err
+		}
+		moderated = true
 	}
 
 	// Update fields
 	updates := make(map[string]interface{})
+	var flags []string
 	if req.Content != nil {
 		content := strings.TrimSpace(*req.Content)
 		if content == "" {
 			return nil, fmt.Errorf("message content cannot be empty")
 		}
+		content, matched, err := s.filterContent(content)
+		if err != nil {
+			return nil, err
+		}
+		flags = matched
 		updates["content"] = content
 		updates["is_edited"] = true
 		updates["edited_at"] = time.Now()
@@ -169,10 +204,62 @@ func (s *ChatService) UpdateMessage(userID uuid.UUID, messageID uuid.UUID, req *
 			updates["deleted_at"] = time.Now()
 		}
 	}
+	// A deleted message leaves the pinned list
+	unpinned := deleted && message.IsPinned
+	if unpinned {
+		for column, value := range unpinColumns() {
+			updates[column] = value
+		}
+	}
+	if moderated {
+		// The tombstone keeps who removed the message, not what it said
+		updates["deleted_by_id"] = userID
+		updates["content"] = ""
+		updates["attachment_url"] = ""
+		updates["attachment_type"] = ""
+		updates["attachment_name"] = ""
+	}
 
-	// Apply updates
-	if err := s.db.Model(&message).Updates(updates).Error; err != nil {
-		return nil, fmt.Errorf("failed to update message: %w", err)
+	// Apply updates; a deleted reply no longer counts towards its thread
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&message).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update message: %w", err)
+		}
+		if err := flagMessage(tx, &message, flags); err != nil {
+			return err
+		}
+		if deleted {
+			if err := settleFlags(tx, message.ID, userID); err != nil {
+				return err
+			}
+		}
+		if moderated {
+			if err := tx.Create(&models.ModerationLog{
+				MeetingID:          message.MeetingID,
+				ActorID:            userID,
+				Action:             models.ModerationActionDeleteMessage,
+				TargetUserID:       message.UserID,
+				TargetPublicUserID: message.PublicUserID,
+				TargetMessageID:    &message.ID,
+				Reason:             req.Reason,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to record moderation action: %w", err)
+			}
+		}
+		if deleted && message.ReplyToID != nil {
+			return removeThreadReply(tx, &message)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A deleted message's file goes with it
+	if deleted && s.attachmentService != nil {
+		if err := s.attachmentService.DeleteMessageAttachment(messageID); err != nil {
+			return nil, err
+		}
 	}
 
 	// Reload message with relationships
@@ -180,128 +267,70 @@ func (s *ChatService) UpdateMessage(userID uuid.UUID, messageID uuid.UUID, req *
 		Preload("User").
 		Preload("PublicUser").
 		Preload("ReplyTo").
-		Preload("Replies").
-		Preload("ReadStatus").
-		Preload("Reactions").
+		Preload("Attachment").
+		Preload("Recipients").
+		Preload("Acknowledgements").
 		First(&message).Error; err != nil {
 		return nil, fmt.Errorf("failed to reload message: %w", err)
 	}
+	s.signAttachments(&message)
+	// The message is broadcast, so its reactions are summarized for no one
+	// in particular
+	if err := s.summarizeReactions(models.MeetingActor{}, &message); err != nil {
+		return nil, err
+	}
 
 	// Broadcast update via WebSocket
-	go s.broadcastMessageUpdate(&message, req.IsDeleted != nil && *req.IsDeleted)
+	go s.broadcastMessageUpdate(&message, deleted)
+	if deleted && message.ReplyToID != nil {
+		go s.broadcastThreadUpdate(&message, true)
+	}
+	if unpinned {
+		go s.broadcastUnpin(message.MeetingID, message.ID)
+	}
+	if len(flags) > 0 {
+		go s.broadcastFlag(&message, flags)
+	}
 
 	return &message, nil
 }
 
-// MarkMessageRead marks a message as read for a user
+// MarkMessageRead marks a message as read for a user, along with
+// everything before it in its timeline or thread. It moves the same cursor
+// as MarkReadUpTo, for clients that mark messages one at a time.
 func (s *ChatService) MarkMessageRead(userID *uuid.UUID, publicUserID *uuid.UUID, messageID uuid.UUID) error {
-	// Verify message exists
 	var message models.ChatMessage
-	if err := s.db.Where("id = ? AND is_deleted = ?", messageID, false).First(&message).Error; err != nil {
+	if err := s.db.Select("id, meeting_id").Where("id = ?", messageID).First(&message).Error; err != nil {
 		return fmt.Errorf("message not found")
 	}
 
-	// Check if already read
-	var existingStatus models.ChatMessageReadStatus
-	query := s.db.Where("message_id = ?", messageID)
-	if userID != nil {
-		query = query.Where("user_id = ?", *userID)
-	} else {
-		query = query.Where("public_user_id = ?", *publicUserID)
-	}
-
-	if err := query.First(&existingStatus).Error; err == nil {
-		return nil // Already marked as read
-	}
-
-	// Create read status
-	readStatus := models.ChatMessageReadStatus{
-		MessageID:    messageID,
-		UserID:       userID,
-		PublicUserID: publicUserID,
-	}
-
-	if err := s.db.Create(&readStatus).Error; err != nil {
-		return fmt.Errorf("failed to mark message as read: %w", err)
-	}
-
-	// Update message status if all participants have read it
-	go s.updateMessageStatus(&message)
-
-	// Broadcast read status via WebSocket
-	go s.broadcastReadStatus(&readStatus)
-
-	return nil
+	_, err := s.MarkReadUpTo(message.MeetingID, models.MeetingActor{UserID: userID, PublicUserID: publicUserID}, messageID)
+	return err
 }
 
-// ToggleReaction adds or removes a reaction to a message
-func (s *ChatService) ToggleReaction(userID *uuid.UUID, publicUserID *uuid.UUID, req *models.CreateChatMessageReactionRequest) (*models.ChatMessageReaction, error) {
-	// Verify message exists
-	var message models.ChatMessage
-	if err := s.db.Where("id = ? AND is_deleted = ?", req.MessageID, false).First(&message).Error; err != nil {
-		return nil, fmt.Errorf("message not found")
-	}
-
-	// Check if reaction already exists
-	var existingReaction models.ChatMessageReaction
-	query := s.db.Where("message_id = ? AND reaction = ?", req.MessageID, req.Reaction)
-	if userID != nil {
-		query = query.Where("user_id = ?", *userID)
-	} else {
-		query = query.Where("public_user_id = ?", *publicUserID)
-	}
-
-	if err := query.First(&existingReaction).Error; err == nil {
-		// Remove existing reaction
-		if err := s.db.Delete(&existingReaction).Error; err != nil {
-			return nil, fmt.Errorf("failed to remove reaction: %w", err)
-		}
-
-		// Broadcast reaction removal
-		go s.broadcastReactionRemoval(&existingReaction)
-		return nil, nil
-	}
-
-	// Add new reaction
-	reaction := models.ChatMessageReaction{
-		MessageID:    req.MessageID,
-		UserID:       userID,
-		PublicUserID: publicUserID,
-		Reaction:     req.Reaction,
-	}
-
-	if err := s.db.Create(&reaction).Error; err != nil {
-		return nil, fmt.Errorf("failed to add reaction: %w", err)
-	}
-
-	// Load relationships
-	if err := s.db.Preload("User").Preload("PublicUser").First(&reaction).Error; err != nil {
-		return nil, fmt.Errorf("failed to load reaction relationships: %w", err)
+// GetUnreadCount returns the number of unread messages of a meeting's main
+// timeline for a user; unread replies are counted per thread by
+// GetThreadUnreadCounts
+func (s *ChatService) GetUnreadCount(userID *uuid.UUID, publicUserID *uuid.UUID, meetingID uuid.UUID) (int, error) {
+	var count int64
+	if err := s.unreadMessages(userID, publicUserID, meetingID).
+		Where("reply_to_id IS NULL").
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to get unread count: %w", err)
 	}
 
-	// Broadcast reaction addition
-	go s.broadcastReactionAddition(&reaction)
-
-	return &reaction, nil
+	return int(count), nil
 }
 
-// GetUnreadCount returns the number of unread messages for a user in a meeting
-func (s *ChatService) GetUnreadCount(userID *uuid.UUID, publicUserID *uuid.UUID, meetingID uuid.UUID) (int, error) {
-	var count int64
+// unreadMessages selects a meeting's messages, replies included, that the
+// user's read cursors have not passed and that they did not send
+func (s *ChatService) unreadMessages(userID *uuid.UUID, publicUserID *uuid.UUID, meetingID uuid.UUID) *gorm.DB {
+	actor := models.MeetingActor{UserID: userID, PublicUserID: publicUserID}
 
-	// Subquery to get messages the user has already read
-	readMessagesQuery := s.db.Model(&models.ChatMessageReadStatus{}).
-		Select("message_id")
-	if userID != nil {
-		readMessagesQuery = readMessagesQuery.Where("user_id = ?", *userID)
-	} else {
-		readMessagesQuery = readMessagesQuery.Where("public_user_id = ?", *publicUserID)
-	}
-
-	// Count messages that are not deleted and not read by the user
+	// Messages the user can see that are not deleted and not read by them
 	query := s.db.Model(&models.ChatMessage{}).
-		Where("meeting_id = ? AND is_deleted = ?", meetingID, false).
-		Where("id NOT IN (?)", readMessagesQuery)
+		Scopes(chatVisibilityScope(actor), unreadScope(actor)).
+		Where("meeting_id = ? AND is_deleted = ?", meetingID, false)
 
 	// Exclude user's own messages
 	if userID != nil {
@@ -310,11 +339,7 @@ func (s *ChatService) GetUnreadCount(userID *uuid.UUID, publicUserID *uuid.UUID,
 		query = query.Where("public_user_id != ? OR public_user_id IS NULL", *publicUserID)
 	}
 
-	if err := query.Count(&count).Error; err != nil {
-		return 0, fmt.Errorf("failed to get unread count: %w", err)
-	}
-
-	return int(count), nil
+	return query
 }
 
 // HasMeetingAccess checks if a user has access to a meeting
@@ -335,6 +360,26 @@ func (s *ChatService) HasMeetingAccess(userID uuid.UUID, meetingID uuid.UUID) bo
 	return false
 }
 
+// checkChatPermission rejects chat actions from participants whose meeting
+// role does not allow chatting (e.g. viewers), or whom a moderator muted in
+// chat
+func (s *ChatService) checkChatPermission(meetingID uuid.UUID, userID *uuid.UUID, publicUserID *uuid.UUID) error {
+	if s.roleService == nil {
+		return nil
+	}
+
+	role, err := s.roleService.GetRole(meetingID, models.MeetingActor{UserID: userID, PublicUserID: publicUserID})
+	if err != nil {
+		// Access itself is checked separately; guests without a participant
+		// record keep the default attendee permissions
+		return nil
+	}
+	if !role.Can(models.PermissionChat) {
+		return fmt.Errorf("permission denied")
+	}
+	return s.checkChatMute(meetingID, models.MeetingActor{UserID: userID, PublicUserID: publicUserID})
+}
+
 // GetPublicUserBySessionID retrieves a public user by session ID
 func (s *ChatService) GetPublicUserBySessionID(sessionID string) (models.PublicUser, error) {
 	var publicUser models.PublicUser
@@ -351,19 +396,29 @@ func (s *ChatService) loadMessageRelationships(message *models.ChatMessage) erro
 		Preload("User").
 		Preload("PublicUser").
 		Preload("ReplyTo").
-		Preload("Replies", "is_deleted = ?", false).
-		Preload("Replies.User").
-		Preload("Replies.PublicUser").
-		Preload("ReadStatus").
-		Preload("ReadStatus.User").
-		Preload("ReadStatus.PublicUser").
-		Preload("Reactions").
-		Preload("Reactions.User").
-		Preload("Reactions.PublicUser").
+		Preload("Attachment").
+		Preload("Recipients").
+		Preload("Acknowledgements").
 		First(message).Error
 }
 
+// signAttachments fills in the download URLs of the messages' attachments
+func (s *ChatService) signAttachments(messages ...*models.ChatMessage) {
+	if s.attachmentService == nil {
+		return
+	}
+	for _, message := range messages {
+		if message.Attachment != nil {
+			s.attachmentService.Sign(message.Attachment)
+		}
+	}
+}
+
 func (s *ChatService) broadcastMessage(message *models.ChatMessage) {
+	if s.webSocketService == nil {
+		return
+	}
+
 	// Create WebSocket payload that matches frontend expectations
 	payload := map[string]interface{}{
 		"message": message.ToResponse(),
@@ -377,10 +432,14 @@ func (s *ChatService) broadcastMessage(message *models.ChatMessage) {
 		Timestamp: time.Now(),
 	}
 
-	s.webSocketService.SendMessageToMeeting(message.MeetingID.String(), wsMessage)
+	s.publish(message, wsMessage)
 }
 
 func (s *ChatService) broadcastMessageUpdate(message *models.ChatMessage, isDeleted bool) {
+	if s.webSocketService == nil {
+		return
+	}
+
 	messageType := models.SignalingTypeChatMessageEdit
 	if isDeleted {
 		messageType = models.SignalingTypeChatMessageDelete
@@ -398,91 +457,6 @@ func (s *ChatService) broadcastMessageUpdate(message *models.ChatMessage, isDele
 		Timestamp: time.Now(),
 	}
 
-	s.webSocketService.SendMessageToMeeting(message.MeetingID.String(), wsMessage)
-}
-
-func (s *ChatService) broadcastReadStatus(readStatus *models.ChatMessageReadStatus) {
-	// Get meeting ID from message
-	var message models.ChatMessage
-	if err := s.db.Select("meeting_id").Where("id = ?", readStatus.MessageID).First(&message).Error; err == nil {
-		// Create WebSocket payload that matches frontend expectations
-		payload := map[string]interface{}{
-			"messageId":   readStatus.MessageID.String(),
-			"userId":      readStatus.UserID,
-			"publicUserId": readStatus.PublicUserID,
-			"readAt":      readStatus.ReadAt,
-		}
-
-		wsMessage := models.SignalingMessage{
-			Type:      models.SignalingTypeChatReadStatus,
-			MeetingID: message.MeetingID.String(),
-			Data:      payload,
-			Timestamp: time.Now(),
-		}
-
-		s.webSocketService.SendMessageToMeeting(message.MeetingID.String(), wsMessage)
-	}
-}
-
-func (s *ChatService) broadcastReactionAddition(reaction *models.ChatMessageReaction) {
-	// Get meeting ID from message
-	var message models.ChatMessage
-	if err := s.db.Select("meeting_id").Where("id = ?", reaction.MessageID).First(&message).Error; err == nil {
-		// Create WebSocket payload that matches frontend expectations
-		payload := map[string]interface{}{
-			"message":   map[string]interface{}{"id": reaction.MessageID.String()},
-			"reaction":  reaction.Reaction,
-			"userId":    reaction.UserID,
-			"publicUserId": reaction.PublicUserID,
-		}
-
-		wsMessage := models.SignalingMessage{
-			Type:      models.SignalingTypeChatReaction,
-			MeetingID: message.MeetingID.String(),
-			Data:      payload,
-			Timestamp: time.Now(),
-		}
-		s.webSocketService.SendMessageToMeeting(message.MeetingID.String(), wsMessage)
-	}
-}
-
-func (s *ChatService) broadcastReactionRemoval(reaction *models.ChatMessageReaction) {
-	// Get meeting ID from message
-	var message models.ChatMessage
-	if err := s.db.Select("meeting_id").Where("id = ?", reaction.MessageID).First(&message).Error; err == nil {
-		// Create WebSocket payload that matches frontend expectations
-		payload := map[string]interface{}{
-			"message":   map[string]interface{}{"id": reaction.MessageID.String()},
-			"reaction":  reaction.Reaction,
-			"userId":    reaction.UserID,
-			"publicUserId": reaction.PublicUserID,
-		}
-
-		wsMessage := models.SignalingMessage{
-			Type:      models.SignalingTypeChatReaction,
-			MeetingID: message.MeetingID.String(),
-			Data:      payload,
-			Timestamp: time.Now(),
-		}
-		s.webSocketService.SendMessageToMeeting(message.MeetingID.String(), wsMessage)
-	}
+	s.publish(message, wsMessage)
 }
 
-func (s *ChatService) updateMessageStatus(message *models.ChatMessage) {
-	// Get all active participants in the meeting
-	var participantCount int64
-	s.db.Model(&models.Participant{}).
-		Where("meeting_id = ? AND is_active = ?", message.MeetingID, true).
-		Count(&participantCount)
-
-	// Get read count for this message
-	var readCount int64
-	s.db.Model(&models.ChatMessageReadStatus{}).
-		Where("message_id = ?", message.ID).
-		Count(&readCount)
-
-	// If all participants have read the message, update status
-	if int(readCount) >= int(participantCount) {
-		s.db.Model(message).Update("message_status", models.MessageStatusRead)
-	}
-}
\ No newline at end of file
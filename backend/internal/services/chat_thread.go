@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// GetThread returns the message that started a thread with a page of its
+// replies, paged like the main timeline. Asked for a reply, it returns the
+// thread the reply belongs to. Only the host and the meeting's participants
+// may read threads.
+func (s *ChatService) GetThread(meetingID, messageID uuid.UUID, actor models.MeetingActor, query models.ChatHistoryQuery) (*models.ChatThread, error) {
+	if err := s.checkReader(meetingID, actor); err != nil {
+		return nil, err
+	}
+	query.Viewer = actor
+	var target models.ChatMessage
+	if err := s.db.Select("id, reply_to_id").Scopes(chatVisibilityScope(actor), timelineScope).
+		Where("id = ? AND meeting_id = ?", messageID, meetingID).
+		First(&target).Error; err != nil {
+		return nil, errors.New("message not found")
+	}
+	if target.ReplyToID != nil {
+		messageID = *target.ReplyToID
+	}
+
+	var root models.ChatMessage
+	if err := s.db.Scopes(chatPagePreloads, timelineScope).
+		Where("chat_messages.id = ? AND chat_messages.meeting_id = ?", messageID, meetingID).
+		First(&root).Error; err != nil {
+		return nil, errors.New("message not found")
+	}
+	s.signAttachments(&root)
+	if err := s.countSeenBy(&root); err != nil {
+		return nil, err
+	}
+	if err := s.summarizeReactions(actor, &root); err != nil {
+		return nil, err
+	}
+
+	replies, pagination, err := s.history(meetingID, &root.ID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := &models.ChatThread{Root: root, Replies: replies, Pagination: pagination}
+	if actor.UserID != nil || actor.PublicUserID != nil {
+		var unread int64
+		if err := s.unreadMessages(actor.UserID, actor.PublicUserID, meetingID).
+			Where("reply_to_id = ?", root.ID).
+			Count(&unread).Error; err != nil {
+			return nil, fmt.Errorf("failed to get unread count: %w", err)
+		}
+		thread.UnreadCount = int(unread)
+	}
+	return thread, nil
+}
+
+// GetThreadUnreadCounts returns the number of unread replies in each of a
+// meeting's threads, leaving out threads without any
+func (s *ChatService) GetThreadUnreadCounts(userID *uuid.UUID, publicUserID *uuid.UUID, meetingID uuid.UUID) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		ReplyToID uuid.UUID
+		Unread    int
+	}
+	if err := s.unreadMessages(userID, publicUserID, meetingID).
+		Select("reply_to_id, COUNT(*) AS unread").
+		Where("reply_to_id IS NOT NULL").
+		Group("reply_to_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get thread unread counts: %w", err)
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.ReplyToID] = row.Unread
+	}
+	return counts, nil
+}
+
+// threadRoot resolves the message a reply to replyTo belongs under: threads
+// are one level deep, so replying to a reply joins its thread
+func threadRoot(replyTo *models.ChatMessage) uuid.UUID {
+	if replyTo.ReplyToID != nil {
+		return *replyTo.ReplyToID
+	}
+	return replyTo.ID
+}
+
+// addThreadReply counts a new reply on the message that started its thread
+func addThreadReply(tx *gorm.DB, reply *models.ChatMessage) error {
+	// UpdateColumns leaves updated_at alone: the root itself did not change
+	if err := tx.Model(&models.ChatMessage{}).
+		Where("id = ?", *reply.ReplyToID).
+		UpdateColumns(map[string]interface{}{
+			"reply_count":   gorm.Expr("reply_count + 1"),
+			"last_reply_at": reply.CreatedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update thread: %w", err)
+	}
+	return nil
+}
+
+// removeThreadReply uncounts a deleted reply, moving the last reply time back
+// to the latest reply left
+func removeThreadReply(tx *gorm.DB, reply *models.ChatMessage) error {
+	var lastReplyAt *time.Time
+	var latest models.ChatMessage
+	err := tx.Select("created_at").
+		Where("reply_to_id = ? AND is_deleted = ?", *reply.ReplyToID, false).
+		Order("created_at DESC").
+		First(&latest).Error
+	switch {
+	case err == nil:
+		lastReplyAt = &latest.CreatedAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return fmt.Errorf("failed to update thread: %w", err)
+	}
+
+	if err := tx.Model(&models.ChatMessage{}).
+		Where("id = ?", *reply.ReplyToID).
+		UpdateColumns(map[string]interface{}{
+			"reply_count":   gorm.Expr("CASE WHEN reply_count > 0 THEN reply_count - 1 ELSE 0 END"),
+			"last_reply_at": lastReplyAt,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update thread: %w", err)
+	}
+	return nil
+}
+
+// broadcastThreadUpdate tells the meeting that a thread's reply count
+// changed, so clients can update badges without fetching the thread
+func (s *ChatService) broadcastThreadUpdate(reply *models.ChatMessage, deleted bool) {
+	if s.webSocketService == nil || reply.ReplyToID == nil {
+		return
+	}
+
+	var root models.ChatMessage
+	if err := s.db.Select("id, meeting_id, reply_count, last_reply_at").
+		Where("id = ?", *reply.ReplyToID).
+		First(&root).Error; err != nil {
+		return
+	}
+
+	s.webSocketService.SendMessageToMeeting(root.MeetingID.String(), models.SignalingMessage{
+		Type: models.SignalingTypeChatThreadUpdated,
+		Data: models.ChatThreadUpdatedPayload{
+			ThreadID:          root.ID,
+			ReplyCount:        root.ReplyCount,
+			LastReplyAt:       root.LastReplyAt,
+			ReplyID:           reply.ID,
+			ReplyUserID:       reply.UserID,
+			ReplyPublicUserID: reply.PublicUserID,
+			Deleted:           deleted,
+		},
+	})
+}
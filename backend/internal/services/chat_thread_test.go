@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestChatService_Threads(t *testing.T) {
+	db := setupTestChatDB(t)
+	service := NewChatService(db, nil, nil, NewRoleService(db, nil))
+	meeting, hostID := createTestMeeting(t, db)
+	guest := addTestParticipant(t, db, meeting.ID, "guest", models.RoleAttendee)
+
+	send := func(userID uuid.UUID, content string, replyTo *uuid.UUID) *models.ChatMessage {
+		message, err := service.SendMessage(&userID, nil, &models.CreateChatMessageRequest{
+			MeetingID: meeting.ID, MessageType: models.MessageTypeText, Content: content, ReplyToID: replyTo,
+		})
+		require.NoError(t, err)
+		return message
+	}
+
+	root := send(hostID, "agenda?", nil)
+	first := send(*guest.UserID, "item 1", &root.ID)
+	// Replying to a reply joins the same thread
+	second := send(hostID, "item 2", &first.ID)
+	assert.Equal(t, root.ID, *second.ReplyToID)
+	third := send(*guest.UserID, "item 3", &root.ID)
+	send(hostID, "next topic", nil)
+
+	// Replies stay out of the main timeline; the root carries their count
+	timeline, _, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agenda?", "next topic"}, messageContents(timeline))
+	assert.Equal(t, 3, timeline[0].ReplyCount)
+	require.NotNil(t, timeline[0].LastReplyAt)
+	assert.True(t, timeline[0].LastReplyAt.Equal(third.CreatedAt))
+
+	around, _, err := service.GetMessages(meeting.ID, models.ChatHistoryQuery{Around: &second.ID, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agenda?"}, messageContents(around))
+
+	// The thread pages on its own, and opens from any of its replies
+	host := models.MeetingActor{UserID: &hostID}
+	thread, err := service.GetThread(meeting.ID, root.ID, host, models.ChatHistoryQuery{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, root.ID, thread.Root.ID)
+	assert.Equal(t, []string{"item 2", "item 3"}, messageContents(thread.Replies))
+	assert.True(t, thread.Pagination.HasOlder)
+	cursor, err := models.DecodeChatCursor(thread.Pagination.OlderCursor)
+	require.NoError(t, err)
+	thread, err = service.GetThread(meeting.ID, first.ID, host, models.ChatHistoryQuery{Cursor: cursor, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, root.ID, thread.Root.ID)
+	assert.Equal(t, []string{"item 1"}, messageContents(thread.Replies))
+	assert.False(t, thread.Pagination.HasOlder)
+
+	// Unread replies are counted per thread, apart from the timeline
+	assert.Equal(t, 2, thread.UnreadCount)
+	require.NoError(t, service.MarkMessageRead(&hostID, nil, first.ID))
+	counts, err := service.GetThreadUnreadCounts(&hostID, nil, meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]int{root.ID: 1}, counts)
+	unread, err := service.GetUnreadCount(guest.UserID, nil, meeting.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, unread)
+
+	// Deleting the latest reply moves the thread back to the one before
+	deleted := true
+	_, err = service.UpdateMessage(*guest.UserID, third.ID, &models.UpdateChatMessageRequest{IsDeleted: &deleted})
+	require.NoError(t, err)
+	var updated models.ChatMessage
+	require.NoError(t, db.First(&updated, "id = ?", root.ID).Error)
+	assert.Equal(t, 2, updated.ReplyCount)
+	require.NotNil(t, updated.LastReplyAt)
+	assert.True(t, updated.LastReplyAt.Equal(second.CreatedAt))
+	assert.Equal(t, root.UpdatedAt.Unix(), updated.UpdatedAt.Unix())
+
+	_, err = service.GetThread(meeting.ID, uuid.New(), host, models.ChatHistoryQuery{Limit: 2})
+	assert.EqualError(t, err, "message not found")
+
+	// Threads are for the meeting's members only
+	outsider := createNamedTestUser(t, db, "outsider")
+	_, err = service.GetThread(meeting.ID, root.ID, models.MeetingActor{UserID: &outsider.ID}, models.ChatHistoryQuery{Limit: 2})
+	assert.EqualError(t, err, "not a participant")
+}
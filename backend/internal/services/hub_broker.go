@@ -0,0 +1,316 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+const (
+	hubMeetingChannel    = "ws_hub:meetings"
+	hubNodeTTL           = 30 * time.Second
+	hubHeartbeatInterval = 10 * time.Second
+)
+
+// removePresenceScript deletes a presence entry only when it is still owned by
+// the calling node, so a client that reconnected elsewhere is left untouched
+var removePresenceScript = redis.NewScript(`
+if redis.call("GET", KEYS[2]) == ARGV[2] then
+	redis.call("DEL", KEYS[2])
+end
+local raw = redis.call("HGET", KEYS[1], ARGV[1])
+if raw and cjson.decode(raw)["nodeId"] == ARGV[2] then
+	redis.call("HDEL", KEYS[1], ARGV[1])
+end
+return 1
+`)
+
+// RedisHubBroker relays WebSocket hub traffic between backend nodes through
+// Redis pub/sub and keeps cluster-wide presence in Redis hashes. Presence
+// keys expire unless the heartbeat of a node with clients in them refreshes
+// them, so a node that crashes leaves no ghosts behind for long.
+type RedisHubBroker struct {
+	redis  *redis.Client
+	nodeID string
+	logger *logrus.Logger
+	pubsub *redis.PubSub
+	stop   chan struct{}
+
+	mu    sync.Mutex
+	local map[string]map[string]bool // meetingID -> clientIDs registered by this node
+}
+
+func NewRedisHubBroker(redisClient *redis.Client, nodeID string) *RedisHubBroker {
+	logger := logrus.New()
+	logger.SetLevel(logrus.InfoLevel)
+
+	return &RedisHubBroker{
+		redis:  redisClient,
+		nodeID: nodeID,
+		logger: logger,
+		stop:   make(chan struct{}),
+		local:  make(map[string]map[string]bool),
+	}
+}
+
+// getPresenceKey returns the Redis hash holding a meeting's presence entries
+func (b *RedisHubBroker) getPresenceKey(meetingID string) string {
+	return fmt.Sprintf("ws_hub:presence:%s", meetingID)
+}
+
+// getClientKey returns the Redis key mapping a client to its node
+func (b *RedisHubBroker) getClientKey(clientID string) string {
+	return fmt.Sprintf("ws_hub:client:%s", clientID)
+}
+
+// getNodeKey returns the Redis key used as a node liveness heartbeat
+func (b *RedisHubBroker) getNodeKey(nodeID string) string {
+	return fmt.Sprintf("ws_hub:node:%s", nodeID)
+}
+
+// getNodeChannel returns the pub/sub channel for messages directed to a node
+func (b *RedisHubBroker) getNodeChannel(nodeID string) string {
+	return fmt.Sprintf("ws_hub:node:%s:inbox", nodeID)
+}
+
+// NodeID returns the identifier of the local node
+func (b *RedisHubBroker) NodeID() string {
+	return b.nodeID
+}
+
+// PublishMeeting fans an envelope out to every node
+func (b *RedisHubBroker) PublishMeeting(envelope models.HubEnvelope) error {
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hub envelope: %w", err)
+	}
+
+	if err := b.redis.Publish(context.Background(), hubMeetingChannel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish hub envelope: %w", err)
+	}
+	return nil
+}
+
+// PublishClient delivers an envelope to the node hosting clientID
+func (b *RedisHubBroker) PublishClient(clientID string, envelope models.HubEnvelope) error {
+	ctx := context.Background()
+
+	nodeID, err := b.redis.Get(ctx, b.getClientKey(clientID)).Result()
+	if err == redis.Nil {
+		return models.ErrHubClientNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve client node: %w", err)
+	}
+
+	envelope.TargetClientID = clientID
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hub envelope: %w", err)
+	}
+
+	receivers, err := b.redis.Publish(ctx, b.getNodeChannel(nodeID), payload).Result()
+	if err != nil {
+		return fmt.Errorf("failed to publish hub envelope: %w", err)
+	}
+	if receivers == 0 {
+		// The owning node is gone; drop the stale mapping
+		b.redis.Del(ctx, b.getClientKey(clientID))
+		return models.ErrHubClientNotFound
+	}
+	return nil
+}
+
+// Subscribe starts delivering envelopes published by other nodes and keeps
+// the node heartbeat alive
+func (b *RedisHubBroker) Subscribe(deliver func(models.HubEnvelope)) error {
+	ctx := context.Background()
+
+	if err := b.heartbeat(ctx); err != nil {
+		return err
+	}
+
+	b.pubsub = b.redis.Subscribe(ctx, hubMeetingChannel, b.getNodeChannel(b.nodeID))
+	if _, err := b.pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("failed to subscribe to hub channels: %w", err)
+	}
+
+	go func() {
+		for msg := range b.pubsub.Channel() {
+			var envelope models.HubEnvelope
+			if err := json.Unmarshal([]byte(msg.Payload), &envelope); err != nil {
+				b.logger.WithError(err).Error("Invalid hub envelope")
+				continue
+			}
+			if envelope.NodeID == b.nodeID {
+				continue
+			}
+			deliver(envelope)
+		}
+	}()
+
+	go b.startHeartbeat()
+
+	b.logger.WithField("node_id", b.nodeID).Info("Joined WebSocket hub cluster")
+	return nil
+}
+
+// startHeartbeat periodically refreshes the node liveness key
+func (b *RedisHubBroker) startHeartbeat() {
+	ticker := time.NewTicker(hubHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := b.heartbeat(context.Background()); err != nil {
+				b.logger.WithError(err).Error("Failed to refresh hub node heartbeat")
+			}
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+// heartbeat marks the node as alive for hubNodeTTL, along with the presence
+// keys of its clients
+func (b *RedisHubBroker) heartbeat(ctx context.Context) error {
+	pipe := b.redis.Pipeline()
+	pipe.Set(ctx, b.getNodeKey(b.nodeID), time.Now().Unix(), hubNodeTTL)
+	b.mu.Lock()
+	for meetingID, clients := range b.local {
+		pipe.Expire(ctx, b.getPresenceKey(meetingID), hubNodeTTL)
+		for clientID := range clients {
+			pipe.Expire(ctx, b.getClientKey(clientID), hubNodeTTL)
+		}
+	}
+	b.mu.Unlock()
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to refresh node heartbeat: %w", err)
+	}
+	return nil
+}
+
+// AddPresence registers a client connected to the local node
+func (b *RedisHubBroker) AddPresence(presence models.HubPresence) error {
+	ctx := context.Background()
+	presence.NodeID = b.nodeID
+
+	payload, err := json.Marshal(presence)
+	if err != nil {
+		return fmt.Errorf("failed to marshal presence: %w", err)
+	}
+
+	pipe := b.redis.TxPipeline()
+	pipe.HSet(ctx, b.getPresenceKey(presence.MeetingID), presence.ClientID, payload)
+	pipe.Expire(ctx, b.getPresenceKey(presence.MeetingID), hubNodeTTL)
+	pipe.Set(ctx, b.getClientKey(presence.ClientID), b.nodeID, hubNodeTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store presence: %w", err)
+	}
+
+	b.mu.Lock()
+	if b.local[presence.MeetingID] == nil {
+		b.local[presence.MeetingID] = make(map[string]bool)
+	}
+	b.local[presence.MeetingID][presence.ClientID] = true
+	b.mu.Unlock()
+	return nil
+}
+
+// RemovePresence removes a client registered by the local node
+func (b *RedisHubBroker) RemovePresence(meetingID, clientID string) error {
+	ctx := context.Background()
+	keys := []string{b.getPresenceKey(meetingID), b.getClientKey(clientID)}
+
+	b.mu.Lock()
+	delete(b.local[meetingID], clientID)
+	if len(b.local[meetingID]) == 0 {
+		delete(b.local, meetingID)
+	}
+	b.mu.Unlock()
+
+	if err := removePresenceScript.Run(ctx, b.redis, keys, clientID, b.nodeID).Err(); err != nil {
+		return fmt.Errorf("failed to remove presence: %w", err)
+	}
+	return nil
+}
+
+// MeetingPresence returns every client in a meeting across the cluster.
+// Entries left behind by nodes whose heartbeat expired are pruned.
+func (b *RedisHubBroker) MeetingPresence(meetingID string) ([]models.HubPresence, error) {
+	ctx := context.Background()
+	key := b.getPresenceKey(meetingID)
+
+	entries, err := b.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load presence: %w", err)
+	}
+
+	alive := make(map[string]bool)
+	var presences []models.HubPresence
+	for clientID, raw := range entries {
+		var presence models.HubPresence
+		if err := json.Unmarshal([]byte(raw), &presence); err != nil {
+			b.logger.WithError(err).WithField("client_id", clientID).Warn("Dropping invalid presence entry")
+			b.redis.HDel(ctx, key, clientID)
+			continue
+		}
+
+		nodeAlive, checked := alive[presence.NodeID]
+		if !checked {
+			exists, err := b.redis.Exists(ctx, b.getNodeKey(presence.NodeID)).Result()
+			if err != nil {
+				return nil, fmt.Errorf("failed to check node liveness: %w", err)
+			}
+			nodeAlive = exists > 0
+			alive[presence.NodeID] = nodeAlive
+		}
+
+		if !nodeAlive {
+			b.redis.HDel(ctx, key, clientID)
+			continue
+		}
+		presences = append(presences, presence)
+	}
+
+	sort.Slice(presences, func(i, j int) bool {
+		return presences[i].JoinedAt.Before(presences[j].JoinedAt)
+	})
+	return presences, nil
+}
+
+// Close stops the subscription and heartbeat and drops the node liveness key
+// and the presence of the node's clients
+func (b *RedisHubBroker) Close() error {
+	close(b.stop)
+
+	var errs []error
+	if b.pubsub != nil {
+		errs = append(errs, b.pubsub.Close())
+	}
+
+	b.mu.Lock()
+	local := b.local
+	b.local = make(map[string]map[string]bool)
+	b.mu.Unlock()
+	ctx := context.Background()
+	for meetingID, clients := range local {
+		for clientID := range clients {
+			keys := []string{b.getPresenceKey(meetingID), b.getClientKey(clientID)}
+			errs = append(errs, removePresenceScript.Run(ctx, b.redis, keys, clientID, b.nodeID).Err())
+		}
+	}
+
+	errs = append(errs, b.redis.Del(ctx, b.getNodeKey(b.nodeID)).Err())
+	return errors.Join(errs...)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// appendEventScript numbers an event and adds it to the meeting's stream in
+// one step, so the stream stays in sequence order across nodes. A new
+// sequence starts from the current time in microseconds.
+var appendEventScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	local now = redis.call("TIME")
+	redis.call("SET", KEYS[1], now[1] * 1000000 + now[2])
+end
+local seq = redis.call("INCR", KEYS[1])
+redis.call("XADD", KEYS[2], "MAXLEN", "~", ARGV[2], "*", "seq", seq, "envelope", ARGV[1])
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+redis.call("PEXPIRE", KEYS[2], ARGV[3])
+return seq
+`)
+
+// RedisHubEventLog keeps the meetings' events in Redis streams, shared by
+// every node of the cluster. Each stream holds about the latest size events
+// and expires once its meeting has been quiet for the retention.
+type RedisHubEventLog struct {
+	redis     *redis.Client
+	size      int
+	retention time.Duration
+}
+
+func NewRedisHubEventLog(redisClient *redis.Client, size int, retention time.Duration) *RedisHubEventLog {
+	return &RedisHubEventLog{
+		redis:     redisClient,
+		size:      size,
+		retention: retention,
+	}
+}
+
+// getSeqKey returns the Redis key holding a meeting's latest sequence number
+func (l *RedisHubEventLog) getSeqKey(meetingID string) string {
+	return fmt.Sprintf("ws_hub:seq:%s", meetingID)
+}
+
+// getStreamKey returns the Redis stream holding a meeting's events
+func (l *RedisHubEventLog) getStreamKey(meetingID string) string {
+	return fmt.Sprintf("ws_hub:events:%s", meetingID)
+}
+
+// Append numbers an event with its meeting's next sequence number and
+// stores it
+func (l *RedisHubEventLog) Append(envelope models.HubEnvelope) (uint64, error) {
+	meetingID := envelope.Message.MeetingID
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal hub envelope: %w", err)
+	}
+
+	keys := []string{l.getSeqKey(meetingID), l.getStreamKey(meetingID)}
+	seq, err := appendEventScript.Run(context.Background(), l.redis, keys, payload, l.size, l.retention.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to append event: %w", err)
+	}
+	return uint64(seq), nil
+}
+
+// Since returns a meeting's events numbered after seq, oldest first
+func (l *RedisHubEventLog) Since(meetingID string, seq uint64) ([]models.HubEnvelope, bool, error) {
+	last, err := l.LastSeq(meetingID)
+	if err != nil {
+		return nil, false, err
+	}
+	if last == 0 || seq > last {
+		return nil, false, nil
+	}
+	if seq == last {
+		return []models.HubEnvelope{}, true, nil
+	}
+
+	entries, err := l.redis.XRange(context.Background(), l.getStreamKey(meetingID), "-", "+").Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load events: %w", err)
+	}
+
+	var events []models.HubEnvelope
+	complete := false
+	for _, entry := range entries {
+		raw, _ := entry.Values["seq"].(string)
+		entrySeq, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			continue
+		}
+		if entrySeq <= seq {
+			complete = true
+			continue
+		}
+		// The stream may have been trimmed past seq
+		if entrySeq == seq+1 {
+			complete = true
+		}
+		payload, _ := entry.Values["envelope"].(string)
+		var envelope models.HubEnvelope
+		if err := json.Unmarshal([]byte(payload), &envelope); err != nil {
+			return nil, false, fmt.Errorf("failed to parse event: %w", err)
+		}
+		envelope.Message.Seq = entrySeq
+		events = append(events, envelope)
+	}
+	if !complete {
+		return nil, false, nil
+	}
+	return events, true, nil
+}
+
+// LastSeq returns the number of a meeting's latest event
+func (l *RedisHubEventLog) LastSeq(meetingID string) (uint64, error) {
+	seq, err := l.redis.Get(context.Background(), l.getSeqKey(meetingID)).Uint64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load sequence number: %w", err)
+	}
+	return seq, nil
+}
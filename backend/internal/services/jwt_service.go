@@ -12,19 +12,43 @@ import (
 )
 
 type JWTService struct {
-	config config.JWTConfig
+	config   config.JWTConfig
+	sessions SessionChecker
 }
 
+// SessionChecker reports whether the login session an access token was
+// issued for is still active
+type SessionChecker interface {
+	IsSessionActive(userID, sessionID uuid.UUID) (bool, error)
+}
+
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+	TokenTypeInvite  = "invite"
+)
+
 type Claims struct {
-	UserID   uuid.UUID `json:"userId"`
-	Email    string    `json:"email"`
-	Username string    `json:"username"`
+	UserID    uuid.UUID `json:"userId"`
+	Email     string    `json:"email"`
+	Username  string    `json:"username"`
+	TokenType string    `json:"typ,omitempty"`
+	SessionID uuid.UUID `json:"sid"` // Refresh token family the token was issued for
+	jwt.RegisteredClaims
+}
+
+// InviteClaims are carried by meeting invite links. The token ID is the
+// MeetingInvite record, which holds the use count and revocation.
+type InviteClaims struct {
+	MeetingID uuid.UUID `json:"mid"`
+	TokenType string    `json:"typ"`
 	jwt.RegisteredClaims
 }
 
 type TokenPair struct {
-	AccessToken  string `json:"accessToken"`
-	RefreshToken string `json:"refreshToken"`
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
+	RefreshExpiresAt time.Time `json:"-"`
 }
 
 func NewJWTService(cfg config.JWTConfig) *JWTService {
@@ -33,30 +57,49 @@ func NewJWTService(cfg config.JWTConfig) *JWTService {
 	}
 }
 
-func (s *JWTService) GenerateTokenPair(user *models.User) (*TokenPair, error) {
+// SetSessionChecker makes access tokens of revoked or logged out sessions
+// invalid before they expire. Without it access tokens are only checked for
+// signature and expiry.
+func (s *JWTService) SetSessionChecker(sessions SessionChecker) {
+	s.sessions = sessions
+}
+
+// GenerateTokenPair issues an access token and a refresh token for the given
+// session. tokenID becomes the refresh token's jti and must match the
+// persisted refresh token record.
+func (s *JWTService) GenerateTokenPair(user *models.User, sessionID, tokenID uuid.UUID) (*TokenPair, error) {
+	return s.ReissueTokenPair(user, sessionID, tokenID, time.Now().Add(s.config.RefreshTokenExpiry))
+}
+
+// ReissueTokenPair issues a new access token and signs the refresh token of
+// an existing record again, keeping its expiry
+func (s *JWTService) ReissueTokenPair(user *models.User, sessionID, tokenID uuid.UUID, refreshExpiresAt time.Time) (*TokenPair, error) {
 	// Generate access token
-	accessToken, err := s.generateAccessToken(user)
+	accessToken, err := s.generateAccessToken(user, sessionID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Generate refresh token
-	refreshToken, err := s.generateRefreshToken(user)
+	refreshToken, err := s.generateRefreshToken(user, sessionID, tokenID, refreshExpiresAt)
 	if err != nil {
 		return nil, err
 	}
 
 	return &TokenPair{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
 	}, nil
 }
 
-func (s *JWTService) generateAccessToken(user *models.User) (string, error) {
+func (s *JWTService) generateAccessToken(user *models.User, sessionID uuid.UUID) (string, error) {
 	claims := &Claims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		Username: user.Username,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Username:  user.Username,
+		TokenType: TokenTypeAccess,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.AccessTokenExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -70,13 +113,16 @@ func (s *JWTService) generateAccessToken(user *models.User) (string, error) {
 	return token.SignedString([]byte(s.config.Secret))
 }
 
-func (s *JWTService) generateRefreshToken(user *models.User) (string, error) {
+func (s *JWTService) generateRefreshToken(user *models.User, sessionID, tokenID uuid.UUID, expiresAt time.Time) (string, error) {
 	claims := &Claims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		Username: user.Username,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Username:  user.Username,
+		TokenType: TokenTypeRefresh,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.config.RefreshTokenExpiry)),
+			ID:        tokenID.String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "gomeet-backend",
@@ -89,11 +135,70 @@ func (s *JWTService) generateRefreshToken(user *models.User) (string, error) {
 }
 
 func (s *JWTService) ValidateAccessToken(tokenString string) (*Claims, error) {
-	return s.validateToken(tokenString)
+	claims, err := s.validateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	// Refresh and invite tokens must not be usable as bearer tokens
+	if claims.TokenType == TokenTypeRefresh || claims.TokenType == TokenTypeInvite {
+		return nil, errors.New("invalid token type")
+	}
+	if s.sessions != nil {
+		active, err := s.sessions.IsSessionActive(claims.UserID, claims.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			return nil, errors.New("session revoked")
+		}
+	}
+	return claims, nil
 }
 
 func (s *JWTService) ValidateRefreshToken(tokenString string) (*Claims, error) {
-	return s.validateToken(tokenString)
+	claims, err := s.validateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeRefresh || claims.ID == "" {
+		return nil, errors.New("invalid token type")
+	}
+	return claims, nil
+}
+
+// GenerateInviteToken signs an invite link token for the invite record
+func (s *JWTService) GenerateInviteToken(invite *models.MeetingInvite) (string, error) {
+	claims := &InviteClaims{
+		MeetingID: invite.MeetingID,
+		TokenType: TokenTypeInvite,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        invite.ID.String(),
+			ExpiresAt: jwt.NewNumericDate(invite.ExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "gomeet-backend",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.config.Secret))
+}
+
+func (s *JWTService) ValidateInviteToken(tokenString string) (*InviteClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &InviteClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(s.config.Secret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*InviteClaims)
+	if !ok || !token.Valid || claims.TokenType != TokenTypeInvite || claims.ID == "" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
 }
 
 func (s *JWTService) validateToken(tokenString string) (*Claims, error) {
@@ -1,3 +1,9 @@
+//go:build livekit
+
+// These tests need the LiveKit service, which is commented out in
+// livekit_service.go, so they only build with -tags livekit until it
+// is restored.
+
 package services
 
 import (
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// LobbyService holds newcomers in a meeting's lobby until a host admits or
+// denies them. Knocks and decisions travel over the WebSocket hub.
+type LobbyService struct {
+	db          *gorm.DB
+	wsService   *WebSocketService
+	roleService *RoleService
+}
+
+func NewLobbyService(db *gorm.DB, wsService *WebSocketService, roleService *RoleService) *LobbyService {
+	return &LobbyService{
+		db:          db,
+		wsService:   wsService,
+		roleService: roleService,
+	}
+}
+
+// RequiresLobby reports whether the actor has to wait for admission. The host
+// and participants admitted before never wait.
+func (s *LobbyService) RequiresLobby(meeting *models.Meeting, actor models.MeetingActor) (bool, error) {
+	switch meeting.LobbyMode {
+	case models.LobbyModeGuests:
+		if actor.UserID != nil {
+			return false, nil
+		}
+	case models.LobbyModeEveryone:
+	default:
+		return false, nil
+	}
+
+	if actor.UserID != nil && *actor.UserID == meeting.HostID {
+		return false, nil
+	}
+
+	query := participantQuery(s.db, meeting.ID, actor)
+	if query == nil {
+		return true, nil
+	}
+
+	var count int64
+	if err := query.Where("lobby_status = ?", models.LobbyStatusAdmitted).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check participant: %w", err)
+	}
+	return count == 0, nil
+}
+
+// Hold puts the actor in the lobby and lets the hosts know. Knocking again
+// while already waiting does not notify the hosts twice, and a denied actor
+// stays denied until a host admits them.
+func (s *LobbyService) Hold(meeting *models.Meeting, actor models.MeetingActor) (*models.Participant, error) {
+	query := participantQuery(s.db, meeting.ID, actor)
+	if query == nil {
+		return nil, errors.New("identity required")
+	}
+
+	var participant models.Participant
+	err := query.Order("joined_at DESC").First(&participant).Error
+	switch {
+	case err == nil:
+		switch participant.LobbyStatus {
+		case models.LobbyStatusPending:
+			return &participant, nil
+		case models.LobbyStatusDenied:
+			return nil, errors.New("denied from lobby")
+		}
+		if err := s.db.Model(&participant).Updates(map[string]interface{}{
+			"lobby_status": models.LobbyStatusPending,
+			"is_active":    false,
+		}).Error; err != nil {
+			return nil, fmt.Errorf("failed to update participant: %w", err)
+		}
+		participant.LobbyStatus = models.LobbyStatusPending
+		participant.IsActive = false
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		name, avatarURL, err := s.actorProfile(actor)
+		if err != nil {
+			return nil, err
+		}
+		participant = models.Participant{
+			MeetingID:    meeting.ID,
+			UserID:       actor.UserID,
+			PublicUserID: actor.PublicUserID,
+			Name:         name,
+			AvatarURL:    avatarURL,
+			LobbyStatus:  models.LobbyStatusPending,
+		}
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&participant).Error; err != nil {
+				return err
+			}
+			// is_active defaults to true on insert
+			return tx.Model(&participant).Update("is_active", false).Error
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to join lobby: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("failed to fetch participant: %w", err)
+	}
+
+	s.broadcast(models.SignalingTypeLobbyRequest, &participant)
+	return &participant, nil
+}
+
+// GetPendingParticipants returns the participants waiting in the lobby
+func (s *LobbyService) GetPendingParticipants(meetingID uuid.UUID, actorID uuid.UUID) ([]models.Participant, error) {
+	if err := s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &actorID}, models.PermissionModerate); err != nil {
+		return nil, err
+	}
+
+	var participants []models.Participant
+	if err := s.db.Where("meeting_id = ? AND lobby_status = ?", meetingID, models.LobbyStatusPending).
+		Order("joined_at ASC").
+		Find(&participants).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch lobby: %w", err)
+	}
+	return participants, nil
+}
+
+// Admit lets the selected (or all) waiting participants into the meeting.
+// Selecting a denied participant lifts the denial.
+func (s *LobbyService) Admit(meetingID uuid.UUID, actorID uuid.UUID, req *models.LobbyDecisionRequest) ([]models.Participant, error) {
+	return s.decide(meetingID, actorID, req, models.LobbyStatusAdmitted)
+}
+
+// Deny turns away the selected (or all) waiting participants
+func (s *LobbyService) Deny(meetingID uuid.UUID, actorID uuid.UUID, req *models.LobbyDecisionRequest) ([]models.Participant, error) {
+	return s.decide(meetingID, actorID, req, models.LobbyStatusDenied)
+}
+
+func (s *LobbyService) decide(meetingID uuid.UUID, actorID uuid.UUID, req *models.LobbyDecisionRequest, status models.LobbyStatus) ([]models.Participant, error) {
+	if !req.All && len(req.ParticipantIDs) == 0 {
+		return nil, errors.New("no participants selected")
+	}
+
+	if err := s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &actorID}, models.PermissionModerate); err != nil {
+		return nil, err
+	}
+
+	// Only a host picking them out can let denied participants in
+	from := []models.LobbyStatus{models.LobbyStatusPending}
+	if status == models.LobbyStatusAdmitted && !req.All {
+		from = append(from, models.LobbyStatusDenied)
+	}
+
+	query := s.db.Where("meeting_id = ? AND lobby_status IN ?", meetingID, from)
+	if !req.All {
+		query = query.Where("id IN ?", req.ParticipantIDs)
+	}
+
+	var participants []models.Participant
+	if err := query.Find(&participants).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch lobby: %w", err)
+	}
+	if len(participants) == 0 {
+		return participants, nil
+	}
+
+	// Each update is conditional on the undecided status so two hosts
+	// deciding at once do not both notify the participant
+	decided := make(map[uuid.UUID]bool, len(participants))
+	for _, participant := range participants {
+		result := s.db.Model(&models.Participant{}).
+			Where("id = ? AND lobby_status = ?", participant.ID, participant.LobbyStatus).
+			Updates(map[string]interface{}{
+				"lobby_status": status,
+				"is_active":    status == models.LobbyStatusAdmitted,
+			})
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to update lobby: %w", result.Error)
+		}
+		decided[participant.ID] = result.RowsAffected > 0
+	}
+
+	messageType := models.SignalingTypeLobbyDenied
+	if status == models.LobbyStatusAdmitted {
+		messageType = models.SignalingTypeLobbyAdmitted
+	}
+
+	result := make([]models.Participant, 0, len(participants))
+	for _, participant := range participants {
+		if !decided[participant.ID] {
+			continue
+		}
+		participant.LobbyStatus = status
+		participant.IsActive = status == models.LobbyStatusAdmitted
+		s.broadcast(messageType, &participant)
+		result = append(result, participant)
+	}
+	return result, nil
+}
+
+// actorProfile returns the display name and avatar of a user or public user
+func (s *LobbyService) actorProfile(actor models.MeetingActor) (string, string, error) {
+	if actor.UserID != nil {
+		var user models.User
+		if err := s.db.Where("id = ?", *actor.UserID).First(&user).Error; err != nil {
+			return "", "", fmt.Errorf("failed to fetch user: %w", err)
+		}
+		return user.Username, user.AvatarURL, nil
+	}
+
+	var publicUser models.PublicUser
+	if err := s.db.Where("id = ?", *actor.PublicUserID).First(&publicUser).Error; err != nil {
+		return "", "", fmt.Errorf("failed to fetch public user: %w", err)
+	}
+	return publicUser.Name, "", nil
+}
+
+// broadcast sends a lobby message to the meeting. Clients filter it on
+// delivery: moderators see all lobby traffic, waiting clients only the
+// decision about themselves.
+func (s *LobbyService) broadcast(messageType models.SignalingMessageType, participant *models.Participant) {
+	if s.wsService == nil {
+		return
+	}
+
+	s.wsService.SendMessageToMeeting(participant.MeetingID.String(), models.SignalingMessage{
+		Type: messageType,
+		Data: models.LobbyPayload{
+			ParticipantID:   participant.ID,
+			UserID:          participant.UserID,
+			PublicUserID:    participant.PublicUserID,
+			Name:            participant.Name,
+			IsAuthenticated: participant.UserID != nil,
+			Status:          participant.LobbyStatus,
+		},
+	})
+}
+
+// participantQuery scopes a participants query to the actor's records in a
+// meeting. It returns nil for actors without an identity.
+func participantQuery(db *gorm.DB, meetingID uuid.UUID, actor models.MeetingActor) *gorm.DB {
+	query := db.Model(&models.Participant{}).Where("meeting_id = ?", meetingID)
+	switch {
+	case actor.UserID != nil:
+		return query.Where("user_id = ?", *actor.UserID)
+	case actor.PublicUserID != nil:
+		return query.Where("public_user_id = ?", *actor.PublicUserID)
+	}
+	return nil
+}
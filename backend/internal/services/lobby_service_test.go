@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func newTestLobbyServices(db *gorm.DB) (*LobbyService, *MeetingService, *PublicUserService) {
+	roleService := NewRoleService(db, nil)
+	lobbyService := NewLobbyService(db, nil, roleService)
+	meetingService := NewMeetingService(db, nil, roleService)
+	meetingService.SetLobbyService(lobbyService)
+	publicUserService := NewPublicUserService(db)
+	publicUserService.SetLobbyService(lobbyService)
+	return lobbyService, meetingService, publicUserService
+}
+
+func setLobbyMode(t *testing.T, db *gorm.DB, meeting *models.Meeting, mode models.LobbyMode) {
+	require.NoError(t, db.Model(meeting).Update("lobby_mode", mode).Error)
+}
+
+func TestLobbyService_RequiresLobby(t *testing.T) {
+	db := setupTestModerationDB(t)
+	lobbyService, _, _ := newTestLobbyServices(db)
+	meeting, hostID := createTestMeeting(t, db)
+	member := addTestParticipant(t, db, meeting.ID, "member", models.RoleAttendee)
+	guest := &models.PublicUser{Name: "Guest", SessionID: "guest-session"}
+	require.NoError(t, db.Create(guest).Error)
+	stranger := &models.User{Username: "stranger", Email: "stranger@example.com", PasswordHash: "x"}
+	require.NoError(t, db.Create(stranger).Error)
+
+	cases := []struct {
+		mode  models.LobbyMode
+		actor models.MeetingActor
+		held  bool
+	}{
+		{models.LobbyModeOff, models.MeetingActor{PublicUserID: &guest.ID}, false},
+		{models.LobbyModeGuests, models.MeetingActor{PublicUserID: &guest.ID}, true},
+		{models.LobbyModeGuests, models.MeetingActor{UserID: &stranger.ID}, false},
+		{models.LobbyModeEveryone, models.MeetingActor{UserID: &stranger.ID}, true},
+		{models.LobbyModeEveryone, models.MeetingActor{UserID: member.UserID}, false},
+		{models.LobbyModeEveryone, models.MeetingActor{UserID: &hostID}, false},
+	}
+	for _, tc := range cases {
+		meeting.LobbyMode = tc.mode
+		held, err := lobbyService.RequiresLobby(meeting, tc.actor)
+		require.NoError(t, err)
+		assert.Equal(t, tc.held, held, "mode %s", tc.mode)
+	}
+}
+
+func TestLobbyService_AdmitAndDeny(t *testing.T) {
+	db := setupTestModerationDB(t)
+	lobbyService, meetingService, publicUserService := newTestLobbyServices(db)
+	meeting, hostID := createTestMeeting(t, db)
+	setLobbyMode(t, db, meeting, models.LobbyModeEveryone)
+
+	stranger := &models.User{Username: "stranger", Email: "stranger@example.com", PasswordHash: "x"}
+	require.NoError(t, db.Create(stranger).Error)
+	waiting, err := meetingService.JoinMeeting(stranger.ID, meeting.ID, models.MeetingCredentials{})
+	require.NoError(t, err)
+	assert.Equal(t, models.LobbyStatusPending, waiting.LobbyStatus)
+	assert.False(t, waiting.IsActive)
+
+	guest, err := publicUserService.CreatePublicUser(&models.CreatePublicUserRequest{Name: "Guest", SessionID: "guest-session"})
+	require.NoError(t, err)
+	guestParticipant, err := publicUserService.JoinMeetingAsPublicUser(guest.SessionID, meeting.ID, models.MeetingCredentials{})
+	require.NoError(t, err)
+	assert.Equal(t, models.LobbyStatusPending, guestParticipant.LobbyStatus)
+
+	// Waiting participants hold no role yet
+	_, err = NewRoleService(db, nil).GetRole(meeting.ID, models.MeetingActor{UserID: &stranger.ID})
+	assert.EqualError(t, err, "not a participant")
+
+	pending, err := lobbyService.GetPendingParticipants(meeting.ID, hostID)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2)
+
+	_, err = lobbyService.Admit(meeting.ID, hostID, &models.LobbyDecisionRequest{})
+	assert.EqualError(t, err, "no participants selected")
+	_, err = lobbyService.Admit(meeting.ID, stranger.ID, &models.LobbyDecisionRequest{All: true})
+	assert.EqualError(t, err, "not a participant")
+
+	admitted, err := lobbyService.Admit(meeting.ID, hostID, &models.LobbyDecisionRequest{ParticipantIDs: []uuid.UUID{waiting.ID}})
+	require.NoError(t, err)
+	require.Len(t, admitted, 1)
+	assert.Equal(t, models.LobbyStatusAdmitted, admitted[0].LobbyStatus)
+
+	// Admitting twice is a no-op
+	admitted, err = lobbyService.Admit(meeting.ID, hostID, &models.LobbyDecisionRequest{ParticipantIDs: []uuid.UUID{waiting.ID}})
+	require.NoError(t, err)
+	assert.Empty(t, admitted)
+
+	denied, err := lobbyService.Deny(meeting.ID, hostID, &models.LobbyDecisionRequest{All: true})
+	require.NoError(t, err)
+	require.Len(t, denied, 1)
+	assert.Equal(t, guestParticipant.ID, denied[0].ID)
+
+	// Admitted participants come straight back in
+	rejoined, err := meetingService.JoinMeeting(stranger.ID, meeting.ID, models.MeetingCredentials{})
+	require.NoError(t, err)
+	assert.Equal(t, models.LobbyStatusAdmitted, rejoined.LobbyStatus)
+
+	// Denied guests cannot knock again, and admitting everyone waiting
+	// leaves them out
+	_, err = publicUserService.JoinMeetingAsPublicUser(guest.SessionID, meeting.ID, models.MeetingCredentials{})
+	assert.EqualError(t, err, "denied from lobby")
+	admitted, err = lobbyService.Admit(meeting.ID, hostID, &models.LobbyDecisionRequest{All: true})
+	require.NoError(t, err)
+	assert.Empty(t, admitted)
+
+	// Until a host admits them by name
+	admitted, err = lobbyService.Admit(meeting.ID, hostID, &models.LobbyDecisionRequest{ParticipantIDs: []uuid.UUID{guestParticipant.ID}})
+	require.NoError(t, err)
+	require.Len(t, admitted, 1)
+	rejoined, err = publicUserService.JoinMeetingAsPublicUser(guest.SessionID, meeting.ID, models.MeetingCredentials{})
+	require.NoError(t, err)
+	assert.Equal(t, models.LobbyStatusAdmitted, rejoined.LobbyStatus)
+}
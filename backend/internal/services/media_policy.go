@@ -0,0 +1,210 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// mediaPolicyCacheTTL is how long a meeting's policy is reused for the
+// signaling of its peers. Updates apply at once on the node that made them,
+// and within the TTL on the others.
+const mediaPolicyCacheTTL = 30 * time.Second
+
+var codecNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+type cachedMediaPolicy struct {
+	policy   *models.MediaPolicy
+	loadedAt time.Time
+}
+
+// MediaPolicyService keeps the meetings' WebRTC media policies and enforces
+// them on the offers, answers and ICE candidates relayed between peers
+type MediaPolicyService struct {
+	db          *gorm.DB
+	roleService *RoleService
+	defaults    models.MediaPolicy
+	maxSDPSize  int
+
+	mu    sync.Mutex
+	cache map[uuid.UUID]cachedMediaPolicy
+}
+
+func NewMediaPolicyService(db *gorm.DB, roleService *RoleService, cfg config.WebRTCConfig) (*MediaPolicyService, error) {
+	defaults := models.MediaPolicy{
+		MaxBitrate:  cfg.MaxBitrate,
+		Simulcast:   cfg.Simulcast,
+		PrivacyMode: cfg.PrivacyMode,
+		IsDefault:   true,
+	}
+	defaults.SetCodecs(cfg.AllowedCodecs)
+	for _, codec := range defaults.Codecs() {
+		if !codecNamePattern.MatchString(codec) {
+			return nil, fmt.Errorf("invalid codec name %q", codec)
+		}
+	}
+
+	return &MediaPolicyService{
+		db:          db,
+		roleService: roleService,
+		defaults:    defaults,
+		maxSDPSize:  cfg.MaxSDPSize,
+		cache:       make(map[uuid.UUID]cachedMediaPolicy),
+	}, nil
+}
+
+// GetMediaPolicy returns the policy a meeting's peers follow, its own or the
+// server default
+func (s *MediaPolicyService) GetMediaPolicy(meetingID uuid.UUID, actor models.MeetingActor) (*models.MediaPolicy, error) {
+	if _, err := s.roleService.GetRole(meetingID, actor); err != nil {
+		return nil, err
+	}
+	return s.loadPolicy(meetingID)
+}
+
+// UpdateMediaPolicy changes a meeting's policy. Only hosts and co-hosts may
+// change it; it applies to offers and answers sent from then on.
+func (s *MediaPolicyService) UpdateMediaPolicy(meetingID uuid.UUID, actor models.MeetingActor, req *models.UpdateMediaPolicyRequest) (*models.MediaPolicy, error) {
+	if err := s.roleService.Authorize(meetingID, actor, models.PermissionManageMeeting); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.loadPolicy(meetingID)
+	if err != nil {
+		return nil, err
+	}
+	if req.AllowedCodecs != nil {
+		policy.SetCodecs(*req.AllowedCodecs)
+		for _, codec := range policy.Codecs() {
+			if !codecNamePattern.MatchString(codec) {
+				return nil, errors.New("invalid codec")
+			}
+		}
+	}
+	if req.MaxBitrate != nil {
+		policy.MaxBitrate = *req.MaxBitrate
+	}
+	if req.Simulcast != nil {
+		policy.Simulcast = *req.Simulcast
+	}
+	if req.PrivacyMode != nil {
+		policy.PrivacyMode = *req.PrivacyMode
+	}
+
+	policy.MeetingID = meetingID
+	policy.IsDefault = false
+	if err := s.db.Save(policy).Error; err != nil {
+		return nil, fmt.Errorf("failed to save media policy: %w", err)
+	}
+
+	s.mu.Lock()
+	s.cache[meetingID] = cachedMediaPolicy{policy: policy, loadedAt: time.Now()}
+	s.mu.Unlock()
+	return policy, nil
+}
+
+// loadPolicy reads a meeting's policy, falling back to the server default
+func (s *MediaPolicyService) loadPolicy(meetingID uuid.UUID) (*models.MediaPolicy, error) {
+	var policy models.MediaPolicy
+	err := s.db.Where("meeting_id = ?", meetingID).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		policy = s.defaults
+		policy.MeetingID = meetingID
+		return &policy, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch media policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// cachedPolicy returns a meeting's policy for the signaling of its peers
+func (s *MediaPolicyService) cachedPolicy(meetingID uuid.UUID) (*models.MediaPolicy, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[meetingID]
+	s.mu.Unlock()
+	if ok && time.Since(cached.loadedAt) < mediaPolicyCacheTTL {
+		return cached.policy, nil
+	}
+
+	policy, err := s.loadPolicy(meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, entry := range s.cache {
+		if now.Sub(entry.loadedAt) >= mediaPolicyCacheTTL {
+			delete(s.cache, id)
+		}
+	}
+	s.cache[meetingID] = cachedMediaPolicy{policy: policy, loadedAt: now}
+	return policy, nil
+}
+
+// ApplyToSignal validates an offer, answer or ICE candidate and rewrites it
+// to follow its meeting's policy. For offers and answers it returns the
+// codec each media kind leads with; it reports false for candidates that
+// must not be relayed at all.
+func (s *MediaPolicyService) ApplyToSignal(message *models.SignalingMessage) (map[string]string, bool, error) {
+	meetingID, err := uuid.Parse(message.MeetingID)
+	if err != nil {
+		return nil, false, errors.New("meeting not found")
+	}
+	policy, err := s.cachedPolicy(meetingID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: unreadable payload", ErrInvalidSDP)
+	}
+
+	switch message.Type {
+	case models.SignalingTypeOffer, models.SignalingTypeAnswer:
+		var payload models.OfferAnswerPayload
+		if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+			return nil, false, fmt.Errorf("%w: unreadable payload", ErrInvalidSDP)
+		}
+		description, err := ParseSDP(payload.SDP, s.maxSDPSize)
+		if err != nil {
+			return nil, false, err
+		}
+		codecs, err := description.ApplyMediaPolicy(policy)
+		if err != nil {
+			return nil, false, err
+		}
+		payload.SDP = description.String()
+		message.Data = payload
+		return codecs, true, nil
+
+	case models.SignalingTypeIceCandidate:
+		var payload models.ICECandidatePayload
+		if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+			return nil, false, fmt.Errorf("%w: unreadable payload", ErrInvalidICECandidate)
+		}
+		message.Data = payload
+		// An empty candidate ends the gathering
+		if payload.Candidate == "" {
+			return nil, true, nil
+		}
+		candidateType, err := ICECandidateType(payload.Candidate)
+		if err != nil {
+			return nil, false, err
+		}
+		return nil, !(policy.PrivacyMode && candidateType == "host"), nil
+	}
+	return nil, true, nil
+}
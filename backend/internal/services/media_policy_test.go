@@ -0,0 +1,154 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestMediaPolicyService_Policies(t *testing.T) {
+	db := setupTestDB(t, &models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{}, &models.MediaPolicy{})
+	meeting, hostID := createTestMeeting(t, db)
+	attendee := addTestParticipant(t, db, meeting.ID, "attendee", models.RoleAttendee)
+
+	_, err := NewMediaPolicyService(db, NewRoleService(db, nil), config.WebRTCConfig{AllowedCodecs: []string{"VP8!"}})
+	assert.Error(t, err)
+
+	service, err := NewMediaPolicyService(db, NewRoleService(db, nil), config.WebRTCConfig{
+		AllowedCodecs: []string{"opus", "vp8"},
+		Simulcast:     true,
+	})
+	require.NoError(t, err)
+	host := models.MeetingActor{UserID: &hostID}
+
+	// Meetings start on the server default
+	policy, err := service.GetMediaPolicy(meeting.ID, models.MeetingActor{UserID: attendee.UserID})
+	require.NoError(t, err)
+	assert.True(t, policy.IsDefault)
+	assert.Equal(t, []string{"opus", "vp8"}, policy.Codecs())
+	assert.True(t, policy.Simulcast)
+
+	// Only hosts and co-hosts change it
+	bitrate := 1500
+	_, err = service.UpdateMediaPolicy(meeting.ID, models.MeetingActor{UserID: attendee.UserID}, &models.UpdateMediaPolicyRequest{MaxBitrate: &bitrate})
+	assert.EqualError(t, err, "permission denied")
+
+	_, err = service.UpdateMediaPolicy(meeting.ID, host, &models.UpdateMediaPolicyRequest{AllowedCodecs: &[]string{"h.264/avc"}})
+	assert.EqualError(t, err, "invalid codec")
+
+	privacy := true
+	_, err = service.UpdateMediaPolicy(meeting.ID, host, &models.UpdateMediaPolicyRequest{
+		AllowedCodecs: &[]string{" VP8 "},
+		MaxBitrate:    &bitrate,
+		PrivacyMode:   &privacy,
+	})
+	require.NoError(t, err)
+
+	// Fields left out keep the default; the meeting now has its own policy
+	policy, err = service.GetMediaPolicy(meeting.ID, host)
+	require.NoError(t, err)
+	assert.False(t, policy.IsDefault)
+	assert.Equal(t, []string{"vp8"}, policy.Codecs())
+	assert.Equal(t, 1500, policy.MaxBitrate)
+	assert.True(t, policy.Simulcast)
+	assert.True(t, policy.PrivacyMode)
+
+	outsider := &models.User{Username: "outsider", Email: "outsider@example.com", PasswordHash: "x"}
+	require.NoError(t, db.Create(outsider).Error)
+	_, err = service.GetMediaPolicy(meeting.ID, models.MeetingActor{UserID: &outsider.ID})
+	assert.EqualError(t, err, "not a participant")
+}
+
+func TestMediaPolicyService_ApplyToSignal(t *testing.T) {
+	db := setupTestDB(t, &models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{}, &models.MediaPolicy{})
+	meeting, hostID := createTestMeeting(t, db)
+
+	service, err := NewMediaPolicyService(db, NewRoleService(db, nil), config.WebRTCConfig{Simulcast: true, MaxSDPSize: 64 << 10})
+	require.NoError(t, err)
+	privacy := true
+	_, err = service.UpdateMediaPolicy(meeting.ID, models.MeetingActor{UserID: &hostID}, &models.UpdateMediaPolicyRequest{PrivacyMode: &privacy})
+	require.NoError(t, err)
+
+	signal := func(messageType models.SignalingMessageType, data interface{}) (*models.SignalingMessage, map[string]string, bool, error) {
+		message := &models.SignalingMessage{Type: messageType, MeetingID: meeting.ID.String(), From: "a", To: "b", Data: data}
+		codecs, relay, err := service.ApplyToSignal(message)
+		return message, codecs, relay, err
+	}
+
+	// Offers and answers are rewritten in place
+	message, codecs, relay, err := signal(models.SignalingTypeAnswer, map[string]interface{}{"sdp": testOffer, "type": "answer"})
+	require.NoError(t, err)
+	assert.True(t, relay)
+	assert.Equal(t, map[string]string{"audio": "opus", "video": "vp8"}, codecs)
+	payload, ok := message.Data.(models.OfferAnswerPayload)
+	require.True(t, ok)
+	assert.NotContains(t, payload.SDP, "typ host")
+
+	_, _, _, err = signal(models.SignalingTypeOffer, map[string]interface{}{"sdp": "v=0"})
+	assert.ErrorIs(t, err, ErrInvalidSDP)
+
+	// Host candidates are dropped, others relayed
+	_, _, relay, err = signal(models.SignalingTypeIceCandidate, map[string]interface{}{"candidate": "candidate:1 1 udp 2122260223 192.168.1.20 54400 typ host"})
+	require.NoError(t, err)
+	assert.False(t, relay)
+	_, _, relay, err = signal(models.SignalingTypeIceCandidate, map[string]interface{}{"candidate": "candidate:2 1 udp 1686052607 203.0.113.7 54400 typ srflx raddr 0.0.0.0 rport 0"})
+	require.NoError(t, err)
+	assert.True(t, relay)
+	_, _, relay, err = signal(models.SignalingTypeIceCandidate, map[string]interface{}{"candidate": ""})
+	require.NoError(t, err)
+	assert.True(t, relay)
+	_, _, _, err = signal(models.SignalingTypeIceCandidate, map[string]interface{}{"candidate": "garbage"})
+	assert.ErrorIs(t, err, ErrInvalidICECandidate)
+}
+
+func TestWebSocketService_SignalingMediaPolicy(t *testing.T) {
+	db := setupTestDB(t, &models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{}, &models.MediaPolicy{})
+	meeting, _ := createTestMeeting(t, db)
+	meetingID := meeting.ID.String()
+
+	mediaPolicy, err := NewMediaPolicyService(db, NewRoleService(db, nil), config.WebRTCConfig{
+		AllowedCodecs: []string{"opus", "h264"},
+		MaxSDPSize:    64 << 10,
+	})
+	require.NoError(t, err)
+	wsService := NewWebSocketService(db, nil, nil)
+	wsService.SetMediaPolicyService(mediaPolicy)
+	wsService.StartHub()
+	service := NewWebRTCService(db, wsService)
+	defer service.Stop()
+
+	connect := func(clientID string) *models.WebSocketClient {
+		client := &models.WebSocketClient{
+			ID:        clientID,
+			MeetingID: meetingID,
+			Name:      clientID,
+			Send:      make(chan models.SignalingMessage, 16),
+			Hub:       wsService.hub,
+		}
+		wsService.hub.Register <- client
+		return client
+	}
+	connect("alice")
+	bob := connect("bob")
+	require.Eventually(t, func() bool { return len(service.GetMeetingPeers(meetingID)) == 2 }, time.Second, 10*time.Millisecond)
+
+	// Bob gets the answer as the policy rewrote it
+	require.NoError(t, service.SendAnswer(meetingID, "alice", "bob", models.OfferAnswerPayload{SDP: testOffer}))
+	answer := waitForMessage(t, bob, models.SignalingTypeAnswer)
+	payload := answer.Data.(models.OfferAnswerPayload)
+	assert.NotContains(t, payload.SDP, "VP8")
+	assert.NotContains(t, payload.SDP, "a=simulcast")
+
+	// Both peers now report the negotiated codecs
+	negotiated := map[string]string{"audio": "opus", "video": "h264"}
+	stats := service.GetRoomStats(meetingID)
+	assert.Equal(t, map[string]map[string]string{"alice": negotiated, "bob": negotiated}, stats["codecs"])
+
+	err = service.SendOffer(meetingID, "alice", "bob", models.OfferAnswerPayload{SDP: "v=0"})
+	assert.ErrorIs(t, err, ErrInvalidSDP)
+}
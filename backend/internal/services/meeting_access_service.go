@@ -0,0 +1,359 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+const (
+	defaultInviteExpiry = 7 * 24 * time.Hour
+	maxInviteExpiry     = 90 * 24 * time.Hour
+
+	// pruneThreshold is the number of tracked clients above which expired
+	// passcode attempts are swept
+	pruneThreshold = 1024
+)
+
+// MeetingAccessService guards passcode-protected meetings and issues and
+// redeems signed invite links
+type MeetingAccessService struct {
+	db          *gorm.DB
+	jwtService  *JWTService
+	roleService *RoleService
+	throttle    *passcodeThrottle
+}
+
+// NewMeetingAccessService creates the service. A client that enters a wrong
+// passcode maxAttempts times is locked out of that meeting for lockout.
+func NewMeetingAccessService(db *gorm.DB, jwtService *JWTService, roleService *RoleService, maxAttempts int, lockout time.Duration) *MeetingAccessService {
+	return &MeetingAccessService{
+		db:          db,
+		jwtService:  jwtService,
+		roleService: roleService,
+		throttle:    newPasscodeThrottle(maxAttempts, lockout),
+	}
+}
+
+// CheckAccess verifies that the actor may enter the meeting. The host and
+// anyone already on the participant list pass; newcomers need a valid invite
+// or, when the meeting has one, the passcode. The redeemed invite, if any,
+// is returned so the caller can apply its role.
+func (s *MeetingAccessService) CheckAccess(meeting *models.Meeting, actor models.MeetingActor, creds models.MeetingCredentials) (*models.MeetingInvite, error) {
+	return s.checkAccess(meeting, actor, creds, nil)
+}
+
+// CheckAccessAndJoin is CheckAccess for connections that do not go through
+// a join endpoint. A newcomer let in by an invite is added to the
+// participant list with the invite's role in the same transaction as the
+// redemption, so reconnecting enters as a participant and does not use the
+// invite up again. Anonymous connections cannot be remembered and redeem the
+// invite every time.
+func (s *MeetingAccessService) CheckAccessAndJoin(meeting *models.Meeting, actor models.MeetingActor, name string, creds models.MeetingCredentials) (*models.MeetingInvite, error) {
+	return s.checkAccess(meeting, actor, creds, func(tx *gorm.DB, invite *models.MeetingInvite) error {
+		if actor.UserID == nil && actor.PublicUserID == nil {
+			return nil
+		}
+		participant := &models.Participant{
+			MeetingID:    meeting.ID,
+			UserID:       actor.UserID,
+			PublicUserID: actor.PublicUserID,
+			Name:         name,
+			Role:         models.RoleAttendee,
+			IsActive:     true,
+		}
+		if invite.Role != "" {
+			participant.Role = invite.Role
+		}
+		if err := tx.Create(participant).Error; err != nil {
+			return fmt.Errorf("failed to join meeting: %w", err)
+		}
+		return nil
+	})
+}
+
+// checkAccess implements CheckAccess; admit, when set, runs in the
+// transaction that redeems an invite
+func (s *MeetingAccessService) checkAccess(meeting *models.Meeting, actor models.MeetingActor, creds models.MeetingCredentials, admit func(tx *gorm.DB, invite *models.MeetingInvite) error) (*models.MeetingInvite, error) {
+	if actor.UserID != nil && *actor.UserID == meeting.HostID {
+		return nil, nil
+	}
+	if creds.InviteToken == "" && !meeting.HasPasscode() {
+		return nil, nil
+	}
+
+	if query := participantQuery(s.db, meeting.ID, actor); query != nil {
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to check participant: %w", err)
+		}
+		if count > 0 {
+			return nil, nil
+		}
+	}
+
+	if creds.InviteToken != "" {
+		var invite *models.MeetingInvite
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if invite, err = s.redeemInvite(tx, meeting, creds.InviteToken); err != nil {
+				return err
+			}
+			if admit != nil {
+				return admit(tx, invite)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return invite, nil
+	}
+	return nil, s.verifyPasscode(meeting, actor, creds)
+}
+
+// verifyPasscode compares the passcode against the meeting's hash. Failures
+// are counted per meeting and client so the passcode cannot be brute-forced.
+func (s *MeetingAccessService) verifyPasscode(meeting *models.Meeting, actor models.MeetingActor, creds models.MeetingCredentials) error {
+	if creds.Passcode == "" {
+		return errors.New("passcode required")
+	}
+
+	key := throttleKey(meeting.ID, actor, creds.ClientIP)
+	if !s.throttle.allow(key) {
+		return errors.New("too many passcode attempts")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(meeting.PasscodeHash), []byte(creds.Passcode)); err != nil {
+		s.throttle.fail(key)
+		return errors.New("invalid passcode")
+	}
+
+	s.throttle.reset(key)
+	return nil
+}
+
+// redeemInvite validates an invite token for the meeting and counts the use.
+// The use count is bumped with a conditional update so concurrent joins
+// cannot exceed MaxUses.
+func (s *MeetingAccessService) redeemInvite(tx *gorm.DB, meeting *models.Meeting, token string) (*models.MeetingInvite, error) {
+	claims, err := s.jwtService.ValidateInviteToken(token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errors.New("invite expired")
+		}
+		return nil, errors.New("invalid invite")
+	}
+	inviteID, err := uuid.Parse(claims.ID)
+	if err != nil || claims.MeetingID != meeting.ID {
+		return nil, errors.New("invalid invite")
+	}
+
+	var invite models.MeetingInvite
+	if err := tx.Where("id = ? AND meeting_id = ?", inviteID, meeting.ID).First(&invite).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid invite")
+		}
+		return nil, fmt.Errorf("failed to fetch invite: %w", err)
+	}
+	if invite.RevokedAt != nil {
+		return nil, errors.New("invite revoked")
+	}
+	if time.Now().After(invite.ExpiresAt) {
+		return nil, errors.New("invite expired")
+	}
+
+	result := tx.Model(&models.MeetingInvite{}).
+		Where("id = ? AND revoked_at IS NULL AND (max_uses = 0 OR uses < max_uses)", invite.ID).
+		Update("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to redeem invite: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("invite exhausted")
+	}
+
+	invite.Uses++
+	return &invite, nil
+}
+
+// CreateInvite issues a signed invite link for the meeting. Only roles below
+// the creator's own may be pre-assigned.
+func (s *MeetingAccessService) CreateInvite(meetingID uuid.UUID, actorID uuid.UUID, req *models.CreateMeetingInviteRequest) (*models.MeetingInvite, string, error) {
+	actor := models.MeetingActor{UserID: &actorID}
+	if err := s.roleService.Authorize(meetingID, actor, models.PermissionManageMeeting); err != nil {
+		return nil, "", err
+	}
+
+	if req.Role != "" && req.Role != models.RoleAttendee {
+		actorRole, err := s.roleService.GetRole(meetingID, actor)
+		if err != nil {
+			return nil, "", err
+		}
+		if !models.CanAssignRole(actorRole, models.RoleViewer, req.Role) {
+			return nil, "", errors.New("role not assignable")
+		}
+	}
+
+	expiresAt := time.Now().Add(defaultInviteExpiry)
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+	if !expiresAt.After(time.Now()) || expiresAt.After(time.Now().Add(maxInviteExpiry)) {
+		return nil, "", errors.New("invalid invite expiry")
+	}
+
+	invite := &models.MeetingInvite{
+		MeetingID: meetingID,
+		CreatedBy: actorID,
+		Role:      req.Role,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.db.Create(invite).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to create invite: %w", err)
+	}
+
+	token, err := s.jwtService.GenerateInviteToken(invite)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to sign invite: %w", err)
+	}
+	return invite, token, nil
+}
+
+// GetInvites lists the meeting's invites, newest first
+func (s *MeetingAccessService) GetInvites(meetingID uuid.UUID, actorID uuid.UUID) ([]models.MeetingInvite, error) {
+	if err := s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &actorID}, models.PermissionManageMeeting); err != nil {
+		return nil, err
+	}
+
+	var invites []models.MeetingInvite
+	if err := s.db.Where("meeting_id = ?", meetingID).Order("created_at DESC").Find(&invites).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch invites: %w", err)
+	}
+	return invites, nil
+}
+
+// RevokeInvite stops an invite link from being redeemed
+func (s *MeetingAccessService) RevokeInvite(meetingID uuid.UUID, inviteID uuid.UUID, actorID uuid.UUID) (*models.MeetingInvite, error) {
+	if err := s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &actorID}, models.PermissionManageMeeting); err != nil {
+		return nil, err
+	}
+
+	var invite models.MeetingInvite
+	if err := s.db.Where("id = ? AND meeting_id = ?", inviteID, meetingID).First(&invite).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invite not found")
+		}
+		return nil, fmt.Errorf("failed to fetch invite: %w", err)
+	}
+	if invite.RevokedAt != nil {
+		return &invite, nil
+	}
+
+	now := time.Now()
+	if err := s.db.Model(&invite).Update("revoked_at", now).Error; err != nil {
+		return nil, fmt.Errorf("failed to revoke invite: %w", err)
+	}
+	invite.RevokedAt = &now
+	return &invite, nil
+}
+
+// hashPasscode hashes a meeting passcode the same way account passwords are
+func hashPasscode(passcode string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(passcode), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash passcode: %w", err)
+	}
+	return string(hash), nil
+}
+
+// throttleKey identifies who is guessing: the client address when known,
+// otherwise the actor
+func throttleKey(meetingID uuid.UUID, actor models.MeetingActor, clientIP string) string {
+	switch {
+	case clientIP != "":
+		return meetingID.String() + "|ip:" + clientIP
+	case actor.UserID != nil:
+		return meetingID.String() + "|user:" + actor.UserID.String()
+	case actor.PublicUserID != nil:
+		return meetingID.String() + "|public:" + actor.PublicUserID.String()
+	}
+	return meetingID.String() + "|anonymous"
+}
+
+// passcodeThrottle counts failed passcode attempts per key within a window
+type passcodeThrottle struct {
+	mu          sync.Mutex
+	maxAttempts int
+	window      time.Duration
+	attempts    map[string]*passcodeAttempts
+}
+
+type passcodeAttempts struct {
+	failures int
+	since    time.Time
+}
+
+func newPasscodeThrottle(maxAttempts int, window time.Duration) *passcodeThrottle {
+	return &passcodeThrottle{
+		maxAttempts: maxAttempts,
+		window:      window,
+		attempts:    make(map[string]*passcodeAttempts),
+	}
+}
+
+func (t *passcodeThrottle) allow(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.attempts[key]
+	if !ok {
+		return true
+	}
+	if time.Since(entry.since) > t.window {
+		delete(t.attempts, key)
+		return true
+	}
+	return entry.failures < t.maxAttempts
+}
+
+func (t *passcodeThrottle) fail(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := t.attempts[key]
+	if !ok || now.Sub(entry.since) > t.window {
+		if len(t.attempts) >= pruneThreshold {
+			t.prune(now)
+		}
+		t.attempts[key] = &passcodeAttempts{failures: 1, since: now}
+		return
+	}
+	entry.failures++
+}
+
+func (t *passcodeThrottle) reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.attempts, key)
+}
+
+// prune drops expired entries so abandoned keys do not accumulate. Callers
+// hold t.mu.
+func (t *passcodeThrottle) prune(now time.Time) {
+	for key, entry := range t.attempts {
+		if now.Sub(entry.since) > t.window {
+			delete(t.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func newTestAccessServices(t *testing.T, maxAttempts int) (*gorm.DB, *MeetingAccessService, *MeetingService, *PublicUserService) {
+	db := setupTestDB(t, &models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{},
+		&models.MeetingBan{}, &models.MeetingInvite{})
+	jwtService := NewJWTService(config.JWTConfig{Secret: "test-secret", AccessTokenExpiry: time.Minute})
+	roleService := NewRoleService(db, nil)
+	accessService := NewMeetingAccessService(db, jwtService, roleService, maxAttempts, time.Minute)
+	lobbyService := NewLobbyService(db, nil, roleService)
+
+	meetingService := NewMeetingService(db, nil, roleService)
+	meetingService.SetAccessService(accessService)
+	meetingService.SetLobbyService(lobbyService)
+	publicUserService := NewPublicUserService(db)
+	publicUserService.SetAccessService(accessService)
+	publicUserService.SetLobbyService(lobbyService)
+	return db, accessService, meetingService, publicUserService
+}
+
+func createNamedTestUser(t *testing.T, db *gorm.DB, username string) *models.User {
+	user := &models.User{Username: username, Email: username + "@example.com", PasswordHash: "x"}
+	require.NoError(t, db.Create(user).Error)
+	return user
+}
+
+func TestMeetingAccessService_Passcode(t *testing.T) {
+	db, _, meetingService, _ := newTestAccessServices(t, 3)
+	host := createNamedTestUser(t, db, "host")
+	meeting, err := meetingService.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:      "Board",
+		StartTime: time.Now().Add(time.Hour),
+		Passcode:  "s3cret",
+	})
+	require.NoError(t, err)
+	assert.True(t, meeting.HasPasscode())
+	assert.NotEqual(t, "s3cret", meeting.PasscodeHash)
+	assert.Empty(t, meeting.ToPublicResponse().Host.ID)
+
+	newcomer := createNamedTestUser(t, db, "newcomer")
+	_, err = meetingService.JoinMeeting(newcomer.ID, meeting.ID, models.MeetingCredentials{})
+	assert.EqualError(t, err, "passcode required")
+	_, err = meetingService.JoinMeeting(newcomer.ID, meeting.ID, models.MeetingCredentials{Passcode: "guess"})
+	assert.EqualError(t, err, "invalid passcode")
+	_, err = meetingService.JoinMeeting(newcomer.ID, meeting.ID, models.MeetingCredentials{Passcode: "s3cret"})
+	require.NoError(t, err)
+
+	// Participants and the host are not asked again
+	_, err = meetingService.JoinMeeting(newcomer.ID, meeting.ID, models.MeetingCredentials{})
+	assert.NoError(t, err)
+	_, err = meetingService.JoinMeeting(host.ID, meeting.ID, models.MeetingCredentials{})
+	assert.NoError(t, err)
+
+	// Clearing the passcode opens the meeting
+	cleared := ""
+	meeting, err = meetingService.UpdateMeeting(meeting.ID, host.ID, &models.UpdateMeetingRequest{Passcode: &cleared})
+	require.NoError(t, err)
+	assert.False(t, meeting.HasPasscode())
+	_, err = meetingService.JoinMeeting(createNamedTestUser(t, db, "late").ID, meeting.ID, models.MeetingCredentials{})
+	assert.NoError(t, err)
+}
+
+func TestMeetingAccessService_PasscodeThrottle(t *testing.T) {
+	db, _, meetingService, _ := newTestAccessServices(t, 3)
+	host := createNamedTestUser(t, db, "host")
+	meeting, err := meetingService.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:      "Board",
+		StartTime: time.Now().Add(time.Hour),
+		Passcode:  "s3cret",
+	})
+	require.NoError(t, err)
+	newcomer := createNamedTestUser(t, db, "newcomer")
+
+	for i := 0; i < 3; i++ {
+		_, err = meetingService.JoinMeeting(newcomer.ID, meeting.ID, models.MeetingCredentials{Passcode: "guess", ClientIP: "10.0.0.1"})
+		assert.EqualError(t, err, "invalid passcode")
+	}
+
+	// Locked out even with the right passcode, but only from that address
+	_, err = meetingService.JoinMeeting(newcomer.ID, meeting.ID, models.MeetingCredentials{Passcode: "s3cret", ClientIP: "10.0.0.1"})
+	assert.EqualError(t, err, "too many passcode attempts")
+	_, err = meetingService.JoinMeeting(newcomer.ID, meeting.ID, models.MeetingCredentials{Passcode: "s3cret", ClientIP: "10.0.0.2"})
+	assert.NoError(t, err)
+}
+
+func TestMeetingAccessService_Invites(t *testing.T) {
+	db, accessService, meetingService, publicUserService := newTestAccessServices(t, 3)
+	host := createNamedTestUser(t, db, "host")
+	meeting, err := meetingService.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:      "Board",
+		StartTime: time.Now().Add(time.Hour),
+		Passcode:  "s3cret",
+		LobbyMode: models.LobbyModeEveryone,
+	})
+	require.NoError(t, err)
+
+	invite, token, err := accessService.CreateInvite(meeting.ID, host.ID, &models.CreateMeetingInviteRequest{
+		MaxUses: 1,
+		Role:    models.RolePresenter,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+
+	// Invited guests skip the passcode and the lobby and get the invite's role
+	guest, err := publicUserService.CreatePublicUser(&models.CreatePublicUserRequest{Name: "Guest", SessionID: "guest-session"})
+	require.NoError(t, err)
+	participant, err := publicUserService.JoinMeetingAsPublicUser(guest.SessionID, meeting.ID, models.MeetingCredentials{InviteToken: token})
+	require.NoError(t, err)
+	assert.Equal(t, models.LobbyStatusAdmitted, participant.LobbyStatus)
+	assert.Equal(t, models.RolePresenter, participant.Role)
+
+	_, err = meetingService.JoinMeeting(createNamedTestUser(t, db, "second").ID, meeting.ID, models.MeetingCredentials{InviteToken: token})
+	assert.EqualError(t, err, "invite exhausted")
+
+	invites, err := accessService.GetInvites(meeting.ID, host.ID)
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	assert.Equal(t, 1, invites[0].Uses)
+
+	// Revoked invites and invites for other meetings are refused
+	_, token, err = accessService.CreateInvite(meeting.ID, host.ID, &models.CreateMeetingInviteRequest{})
+	require.NoError(t, err)
+	other, err := meetingService.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "Other", StartTime: time.Now().Add(3 * time.Hour)})
+	require.NoError(t, err)
+	_, err = meetingService.JoinMeeting(createNamedTestUser(t, db, "third").ID, other.ID, models.MeetingCredentials{InviteToken: token})
+	assert.EqualError(t, err, "invalid invite")
+
+	_, err = accessService.RevokeInvite(meeting.ID, invite.ID, host.ID)
+	require.NoError(t, err)
+	_, err = meetingService.JoinMeeting(createNamedTestUser(t, db, "fourth").ID, meeting.ID, models.MeetingCredentials{InviteToken: mustInviteToken(t, accessService, invite)})
+	assert.EqualError(t, err, "invite revoked")
+
+	// Invite tokens are not bearer tokens
+	_, err = accessService.jwtService.ValidateAccessToken(token)
+	assert.Error(t, err)
+}
+
+func TestMeetingAccessService_InviteOnConnect(t *testing.T) {
+	db, accessService, meetingService, publicUserService := newTestAccessServices(t, 3)
+	host := createNamedTestUser(t, db, "host")
+	meeting, err := meetingService.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:      "Board",
+		StartTime: time.Now().Add(time.Hour),
+		Passcode:  "s3cret",
+	})
+	require.NoError(t, err)
+	_, token, err := accessService.CreateInvite(meeting.ID, host.ID, &models.CreateMeetingInviteRequest{
+		MaxUses: 2,
+		Role:    models.RolePresenter,
+	})
+	require.NoError(t, err)
+	creds := models.MeetingCredentials{InviteToken: token}
+
+	// Connecting with an invite redeems it once and joins with its role;
+	// reconnecting enters as a participant
+	guest := createNamedTestUser(t, db, "guest")
+	actor := models.MeetingActor{UserID: &guest.ID}
+	invite, err := accessService.CheckAccessAndJoin(meeting, actor, guest.Username, creds)
+	require.NoError(t, err)
+	require.NotNil(t, invite)
+	invite, err = accessService.CheckAccessAndJoin(meeting, actor, guest.Username, creds)
+	require.NoError(t, err)
+	assert.Nil(t, invite)
+
+	var participant models.Participant
+	require.NoError(t, db.Where("meeting_id = ? AND user_id = ?", meeting.ID, guest.ID).First(&participant).Error)
+	assert.Equal(t, models.RolePresenter, participant.Role)
+	assert.Equal(t, "guest", participant.Name)
+
+	// Public users are remembered the same way, and the invite's second use
+	// goes to them
+	visitor, err := publicUserService.CreatePublicUser(&models.CreatePublicUserRequest{Name: "Visitor", SessionID: "visitor-session"})
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = accessService.CheckAccessAndJoin(meeting, models.MeetingActor{PublicUserID: &visitor.ID}, visitor.Name, creds)
+		require.NoError(t, err)
+	}
+	_, err = accessService.CheckAccessAndJoin(meeting, models.MeetingActor{UserID: &createNamedTestUser(t, db, "late").ID}, "late", creds)
+	assert.EqualError(t, err, "invite exhausted")
+
+	var stored models.MeetingInvite
+	require.NoError(t, db.First(&stored, "meeting_id = ?", meeting.ID).Error)
+	assert.Equal(t, 2, stored.Uses)
+}
+
+func TestMeetingAccessService_CreateInviteRoles(t *testing.T) {
+	db, accessService, _, _ := newTestAccessServices(t, 3)
+	meeting, hostID := createTestMeeting(t, db)
+	coHost := addTestParticipant(t, db, meeting.ID, "cohost", models.RoleCoHost)
+	attendee := addTestParticipant(t, db, meeting.ID, "attendee", models.RoleAttendee)
+
+	_, _, err := accessService.CreateInvite(meeting.ID, *coHost.UserID, &models.CreateMeetingInviteRequest{Role: models.RoleCoHost})
+	assert.EqualError(t, err, "role not assignable")
+	_, _, err = accessService.CreateInvite(meeting.ID, *coHost.UserID, &models.CreateMeetingInviteRequest{Role: models.RoleViewer})
+	assert.NoError(t, err)
+	_, _, err = accessService.CreateInvite(meeting.ID, hostID, &models.CreateMeetingInviteRequest{Role: models.RoleCoHost})
+	assert.NoError(t, err)
+	_, _, err = accessService.CreateInvite(meeting.ID, *attendee.UserID, &models.CreateMeetingInviteRequest{})
+	assert.EqualError(t, err, "permission denied")
+
+	past := time.Now().Add(-time.Minute)
+	_, _, err = accessService.CreateInvite(meeting.ID, hostID, &models.CreateMeetingInviteRequest{ExpiresAt: &past})
+	assert.EqualError(t, err, "invalid invite expiry")
+}
+
+func mustInviteToken(t *testing.T, accessService *MeetingAccessService, invite *models.MeetingInvite) string {
+	token, err := accessService.jwtService.GenerateInviteToken(invite)
+	require.NoError(t, err)
+	return token
+}
@@ -0,0 +1,632 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// recurrenceHorizon is how far ahead occurrences of a series are
+// materialized as meetings
+const recurrenceHorizon = 90 * 24 * time.Hour
+
+// parseRecurrenceRule wraps rule errors so controllers can tell them apart
+func parseRecurrenceRule(value string) (*models.RecurrenceRule, error) {
+	rule, err := models.ParseRecurrenceRule(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+	return rule, nil
+}
+
+// seriesHorizon returns the time up to which a series should be materialized
+func seriesHorizon(series *models.MeetingSeries) time.Time {
+	from := time.Now()
+	if series.StartTime.After(from) {
+		from = series.StartTime
+	}
+	return from.Add(recurrenceHorizon)
+}
+
+// createMeetingSeries creates a recurring meeting and returns its first
+// occurrence
+func (s *MeetingService) createMeetingSeries(hostID uuid.UUID, req *models.CreateMeetingRequest, passcodeHash string, schedule *meetingSchedule) (*models.Meeting, error) {
+	rule, err := parseRecurrenceRule(req.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &models.MeetingSeries{
+		HostID:         hostID,
+		Name:           req.Name,
+		StartTime:      req.StartTime,
+		Duration:       schedule.duration,
+		TimeZone:       schedule.timeZone,
+		RecurrenceRule: rule.String(),
+		LobbyMode:      req.LobbyMode,
+		PrivateChat:    req.PrivateChat,
+		PasscodeHash:   passcodeHash,
+	}
+	series.SetInvitees(req.Participants)
+	for _, exception := range req.ExceptionDates {
+		series.AddException(exception)
+	}
+
+	var first models.Meeting
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if !req.AllowOverlap {
+			if err := checkOverlap(tx, hostID, seriesSlots(series, rule, seriesHorizon(series)), nil); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(series).Error; err != nil {
+			return fmt.Errorf("failed to create meeting series: %w", err)
+		}
+		if err := s.materializeSeries(tx, series, seriesHorizon(series)); err != nil {
+			return err
+		}
+		if err := tx.Where("series_id = ?", series.ID).Order("start_time ASC").First(&first).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("recurrence rule has no occurrences")
+			}
+			return fmt.Errorf("failed to fetch first occurrence: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Participants").Preload("Host").Preload("Series").First(&first, first.ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch created meeting: %w", err)
+	}
+	return &first, nil
+}
+
+// materializeSeries creates the meetings for the series' occurrences that
+// start before until and do not exist yet. Cancelled occurrences are
+// skipped; occurrences edited on their own keep their original
+// occurrence time and are not recreated. Callers extending an existing
+// series must hold its row lock (see lockSeries).
+func (s *MeetingService) materializeSeries(tx *gorm.DB, series *models.MeetingSeries, until time.Time) error {
+	if !until.After(series.MaterializedUntil) {
+		return nil
+	}
+
+	rule, err := series.Rule()
+	if err != nil {
+		return fmt.Errorf("invalid recurrence rule on series %s: %w", series.ID, err)
+	}
+
+	var existing []time.Time
+	if err := tx.Model(&models.Meeting{}).
+		Where("series_id = ? AND occurrence_time >= ?", series.ID, series.MaterializedUntil).
+		Pluck("occurrence_time", &existing).Error; err != nil {
+		return fmt.Errorf("failed to fetch occurrences: %w", err)
+	}
+	materialized := make(map[int64]bool, len(existing))
+	for _, t := range existing {
+		materialized[t.Unix()] = true
+	}
+
+	invitees := series.InviteeList()
+	for _, occurrence := range rule.Occurrences(series.DTStart(), series.MaterializedUntil, until, 0) {
+		if materialized[occurrence.Unix()] || series.IsException(occurrence) {
+			continue
+		}
+
+		occurrenceTime := occurrence
+		meeting := &models.Meeting{
+			Name:           series.Name,
+			StartTime:      occurrence,
+			Duration:       series.Duration,
+			TimeZone:       series.TimeZone,
+			HostID:         series.HostID,
+			LobbyMode:      series.LobbyMode,
+			PrivateChat:    series.PrivateChat,
+			PasscodeHash:   series.PasscodeHash,
+			SeriesID:       &series.ID,
+			OccurrenceTime: &occurrenceTime,
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(meeting)
+		if result.Error != nil {
+			return fmt.Errorf("failed to create occurrence: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			// Already materialized
+			continue
+		}
+		if err := createInvitees(tx, meeting.ID, invitees); err != nil {
+			return err
+		}
+	}
+
+	series.MaterializedUntil = until
+	if err := tx.Model(series).Update("materialized_until", until).Error; err != nil {
+		return fmt.Errorf("failed to update meeting series: %w", err)
+	}
+	return nil
+}
+
+// extendHostSeries materializes the upcoming occurrences of the host's
+// series so listings include them
+func (s *MeetingService) extendHostSeries(hostID uuid.UUID) error {
+	var seriesList []models.MeetingSeries
+	if err := s.db.Where("host_id = ? AND materialized_until < ?", hostID, time.Now().Add(recurrenceHorizon)).
+		Find(&seriesList).Error; err != nil {
+		return fmt.Errorf("failed to fetch meeting series: %w", err)
+	}
+
+	for i := range seriesList {
+		seriesID := seriesList[i].ID
+		if err := s.db.Transaction(func(tx *gorm.DB) error {
+			// Another listing may have extended the series since it was read
+			series, err := lockSeries(tx, seriesID)
+			if err != nil {
+				return err
+			}
+			return s.materializeSeries(tx, series, seriesHorizon(series))
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockSeries loads a series and locks its row until the transaction ends,
+// so concurrent listings and edits materialize its occurrences one at a
+// time
+func lockSeries(tx *gorm.DB, seriesID uuid.UUID) (*models.MeetingSeries, error) {
+	var series models.MeetingSeries
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&series, "id = ?", seriesID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch meeting series: %w", err)
+	}
+	return &series, nil
+}
+
+// updateSeries applies an edit to the occurrences of a series selected by
+// scope. A "following" edit splits the series at the occurrence so earlier
+// occurrences keep the old settings. It returns the edited occurrence, or
+// the next one when the new rule no longer produces it.
+func (s *MeetingService) updateSeries(meeting *models.Meeting, req *models.UpdateMeetingRequest, scope models.RecurrenceScope) (*models.Meeting, error) {
+	var rule *models.RecurrenceRule
+	if req.RecurrenceRule != nil {
+		var err error
+		if rule, err = parseRecurrenceRule(*req.RecurrenceRule); err != nil {
+			return nil, err
+		}
+	}
+
+	var seriesID uuid.UUID
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var series models.MeetingSeries
+		if meeting.SeriesID == nil {
+			// A one-off meeting that gets a rule becomes the first
+			// occurrence of a new series
+			converted, err := s.convertToSeries(tx, meeting)
+			if err != nil {
+				return err
+			}
+			series = *converted
+		} else {
+			locked, err := lockSeries(tx, *meeting.SeriesID)
+			if err != nil {
+				return err
+			}
+			series = *locked
+		}
+
+		occurrence := *meeting.OccurrenceTime
+		if scope == models.RecurrenceScopeFollowing && occurrence.After(series.StartTime) {
+			split, err := s.splitSeries(tx, &series, occurrence)
+			if err != nil {
+				return err
+			}
+			series = *split
+		}
+		seriesID = series.ID
+
+		return s.applySeriesUpdate(tx, &series, meeting, req, rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var updated models.Meeting
+	err = s.db.Preload("Participants").Preload("Host").Preload("Series").First(&updated, "id = ?", meeting.ID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = s.db.Preload("Participants").Preload("Host").Preload("Series").
+			Where("series_id = ? AND start_time >= ?", seriesID, time.Now()).
+			Order("start_time ASC").First(&updated).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("recurrence rule has no occurrences")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch updated meeting: %w", err)
+	}
+	return &updated, nil
+}
+
+// convertToSeries makes a one-off meeting the first occurrence of a new
+// series; the caller then sets the rule
+func (s *MeetingService) convertToSeries(tx *gorm.DB, meeting *models.Meeting) (*models.MeetingSeries, error) {
+	var participants []models.Participant
+	if err := tx.Where("meeting_id = ? AND user_id IS NULL AND public_user_id IS NULL", meeting.ID).Find(&participants).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch participants: %w", err)
+	}
+	invitees := make([]models.CreateParticipantRequest, len(participants))
+	for i, participant := range participants {
+		invitees[i] = models.CreateParticipantRequest{Name: participant.Name, AvatarURL: participant.AvatarURL}
+	}
+
+	series := &models.MeetingSeries{
+		HostID:            meeting.HostID,
+		Name:              meeting.Name,
+		StartTime:         meeting.StartTime,
+		Duration:          meeting.Duration,
+		TimeZone:          meeting.TimeZone,
+		RecurrenceRule:    "FREQ=DAILY;COUNT=1",
+		LobbyMode:         meeting.LobbyMode,
+		PrivateChat:       meeting.PrivateChat,
+		PasscodeHash:      meeting.PasscodeHash,
+		MaterializedUntil: meeting.StartTime.Add(time.Second),
+	}
+	series.SetInvitees(invitees)
+	if err := tx.Create(series).Error; err != nil {
+		return nil, fmt.Errorf("failed to create meeting series: %w", err)
+	}
+
+	occurrence := meeting.StartTime
+	if err := tx.Model(meeting).Updates(map[string]interface{}{
+		"series_id":       series.ID,
+		"occurrence_time": occurrence,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to attach meeting to series: %w", err)
+	}
+	meeting.SeriesID = &series.ID
+	meeting.OccurrenceTime = &occurrence
+	return series, nil
+}
+
+// splitSeries ends the series before the occurrence and moves the
+// occurrence and everything after it to a new series with the same rule
+func (s *MeetingService) splitSeries(tx *gorm.DB, series *models.MeetingSeries, occurrence time.Time) (*models.MeetingSeries, error) {
+	oldRule, err := series.Rule()
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule on series %s: %w", series.ID, err)
+	}
+	newRule := *oldRule
+	truncateRule(oldRule, &newRule, series.DTStart(), occurrence)
+
+	next := &models.MeetingSeries{
+		HostID:            series.HostID,
+		Name:              series.Name,
+		StartTime:         occurrence,
+		Duration:          series.Duration,
+		TimeZone:          series.TimeZone,
+		RecurrenceRule:    newRule.String(),
+		LobbyMode:         series.LobbyMode,
+		PrivateChat:       series.PrivateChat,
+		PasscodeHash:      series.PasscodeHash,
+		Invitees:          series.Invitees,
+		MaterializedUntil: series.MaterializedUntil,
+	}
+	for _, exception := range series.Exceptions() {
+		if !exception.Before(occurrence) {
+			next.AddException(exception)
+		}
+	}
+	if err := tx.Create(next).Error; err != nil {
+		return nil, fmt.Errorf("failed to create meeting series: %w", err)
+	}
+
+	if err := tx.Model(series).Update("recurrence_rule", oldRule.String()).Error; err != nil {
+		return nil, fmt.Errorf("failed to update meeting series: %w", err)
+	}
+	if err := tx.Model(&models.Meeting{}).
+		Where("series_id = ? AND occurrence_time >= ?", series.ID, occurrence).
+		Update("series_id", next.ID).Error; err != nil {
+		return nil, fmt.Errorf("failed to move occurrences: %w", err)
+	}
+	return next, nil
+}
+
+// truncateRule makes before stop ahead of the occurrence at cut. When after
+// is given it continues from cut with whatever COUNT is left.
+func truncateRule(before *models.RecurrenceRule, after *models.RecurrenceRule, dtstart, cut time.Time) {
+	if before.Count > 0 {
+		earlier := len(before.Occurrences(dtstart, dtstart, cut, 0))
+		if after != nil {
+			after.Count = before.Count - earlier
+		}
+		before.Count = earlier
+		return
+	}
+	until := cut.Add(-time.Second)
+	before.Until = &until
+}
+
+// applySeriesUpdate updates the series template and the occurrences that
+// follow it: scheduled occurrences that were not edited on their own, plus
+// the occurrence the edit was made on
+func (s *MeetingService) applySeriesUpdate(tx *gorm.DB, series *models.MeetingSeries, meeting *models.Meeting, req *models.UpdateMeetingRequest, rule *models.RecurrenceRule) error {
+	seriesUpdates := make(map[string]interface{})
+	meetingUpdates := make(map[string]interface{})
+	if req.Name != nil {
+		seriesUpdates["name"] = *req.Name
+		meetingUpdates["name"] = *req.Name
+	}
+	if req.LobbyMode != nil {
+		seriesUpdates["lobby_mode"] = *req.LobbyMode
+		meetingUpdates["lobby_mode"] = *req.LobbyMode
+	}
+	if req.PrivateChat != nil {
+		seriesUpdates["private_chat"] = *req.PrivateChat
+		meetingUpdates["private_chat"] = *req.PrivateChat
+	}
+	if req.Passcode != nil {
+		passcodeHash := ""
+		if *req.Passcode != "" {
+			hash, err := hashPasscode(*req.Passcode)
+			if err != nil {
+				return err
+			}
+			passcodeHash = hash
+		}
+		seriesUpdates["passcode_hash"] = passcodeHash
+		meetingUpdates["passcode_hash"] = passcodeHash
+	}
+	if req.Participants != nil {
+		series.SetInvitees(*req.Participants)
+		seriesUpdates["invitees"] = series.Invitees
+	}
+
+	if req.TimeZone != nil {
+		if _, err := models.LoadTimeZone(*req.TimeZone); err != nil {
+			return err
+		}
+		series.TimeZone = *req.TimeZone
+		seriesUpdates["time_zone"] = series.TimeZone
+		meetingUpdates["time_zone"] = series.TimeZone
+	}
+	loc := series.Location()
+
+	// Moving the edited occurrence moves the whole series by the same
+	// amount of wall clock time in the series' time zone
+	var shift time.Duration
+	if req.StartTime != nil {
+		shift = wallClockDelta(*meeting.OccurrenceTime, *req.StartTime, loc)
+		series.StartTime = shiftWallClock(series.StartTime, shift, loc)
+		seriesUpdates["start_time"] = series.StartTime
+	}
+	_, duration, _, err := plannedUpdate(meeting, req)
+	if err != nil {
+		return err
+	}
+	durationChanged := duration != series.Duration
+	if durationChanged {
+		series.Duration = duration
+		seriesUpdates["duration"] = duration
+		meetingUpdates["duration"] = duration
+	}
+	length := time.Duration(series.Duration) * time.Minute
+
+	if rule != nil {
+		series.RecurrenceRule = rule.String()
+		seriesUpdates["recurrence_rule"] = series.RecurrenceRule
+	}
+	if rule == nil {
+		var err error
+		if rule, err = series.Rule(); err != nil {
+			return fmt.Errorf("invalid recurrence rule on series %s: %w", series.ID, err)
+		}
+	}
+	timingChanged := shift != 0 || req.RecurrenceRule != nil || req.TimeZone != nil
+
+	var occurrences []models.Meeting
+	if err := tx.Where("series_id = ? AND status = ? AND (is_detached = ? OR id = ?)",
+		series.ID, models.MeetingStatusScheduled, false, meeting.ID).
+		Find(&occurrences).Error; err != nil {
+		return fmt.Errorf("failed to fetch occurrences: %w", err)
+	}
+
+	// Occurrences the new timing still produces keep their meeting (and
+	// with it their participants and chat); the others are removed
+	keep := make(map[int64]bool)
+	if timingChanged {
+		for _, t := range rule.Occurrences(series.DTStart(), series.StartTime, series.MaterializedUntil.Add(shift), 0) {
+			keep[t.Unix()] = true
+		}
+	}
+
+	// Occurrences edited on their own keep their settings but follow the
+	// shift, so the slot they replace is not materialized again
+	if shift != 0 {
+		var detached []models.Meeting
+		if err := tx.Where("series_id = ? AND is_detached = ? AND id <> ?", series.ID, true, meeting.ID).
+			Find(&detached).Error; err != nil {
+			return fmt.Errorf("failed to fetch occurrences: %w", err)
+		}
+		for _, occurrence := range detached {
+			if err := tx.Model(&models.Meeting{}).Where("id = ?", occurrence.ID).
+				Update("occurrence_time", shiftWallClock(*occurrence.OccurrenceTime, shift, loc)).Error; err != nil {
+				return fmt.Errorf("failed to update occurrence: %w", err)
+			}
+		}
+	}
+
+	for _, occurrence := range occurrences {
+		updates := make(map[string]interface{}, len(meetingUpdates)+3)
+		for key, value := range meetingUpdates {
+			updates[key] = value
+		}
+		updates["is_detached"] = false
+
+		if durationChanged {
+			updates["end_time"] = occurrence.StartTime.Add(length)
+		}
+		if timingChanged {
+			shifted := shiftWallClock(*occurrence.OccurrenceTime, shift, loc)
+			if !keep[shifted.Unix()] || series.IsException(shifted) {
+				if err := recordCancellations(tx, []models.Meeting{occurrence}); err != nil {
+					return err
+				}
+				if err := tx.Delete(&models.Meeting{}, "id = ?", occurrence.ID).Error; err != nil {
+					return fmt.Errorf("failed to delete occurrence: %w", err)
+				}
+				continue
+			}
+			updates["start_time"] = shifted
+			updates["end_time"] = shifted.Add(length)
+			updates["occurrence_time"] = shifted
+		}
+
+		if err := tx.Model(&models.Meeting{}).Where("id = ?", occurrence.ID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update occurrence: %w", err)
+		}
+		if req.Participants != nil {
+			if err := replaceInvitees(tx, occurrence.ID, *req.Participants); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(seriesUpdates) > 0 {
+		if err := tx.Model(series).Updates(seriesUpdates).Error; err != nil {
+			return fmt.Errorf("failed to update meeting series: %w", err)
+		}
+	}
+
+	// Fill in occurrences the new timing adds
+	if timingChanged {
+		horizon := seriesHorizon(series)
+		series.MaterializedUntil = time.Now()
+		if err := s.materializeSeries(tx, series, horizon); err != nil {
+			return err
+		}
+	}
+
+	if (timingChanged || durationChanged) && !req.AllowOverlap {
+		var scheduled []models.Meeting
+		if err := tx.Where("series_id = ? AND status = ? AND end_time > ?", series.ID, models.MeetingStatusScheduled, time.Now()).
+			Find(&scheduled).Error; err != nil {
+			return fmt.Errorf("failed to fetch occurrences: %w", err)
+		}
+		slots := make([]timeSlot, len(scheduled))
+		for i, occurrence := range scheduled {
+			slots[i] = timeSlot{occurrence.StartTime, occurrence.EndTime}
+		}
+		skip := func(other *models.Meeting) bool { return other.SeriesID != nil && *other.SeriesID == series.ID }
+		return checkOverlap(tx, series.HostID, slots, skip)
+	}
+	return nil
+}
+
+// deleteSeriesOccurrences deletes the occurrences of a series selected by
+// scope. Deleting a single occurrence records it as an exception so it is
+// not materialized again.
+func (s *MeetingService) deleteSeriesOccurrences(meeting *models.Meeting, scope models.RecurrenceScope) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		series, err := lockSeries(tx, *meeting.SeriesID)
+		if err != nil {
+			return err
+		}
+
+		occurrence := *meeting.OccurrenceTime
+		if scope == models.RecurrenceScopeFollowing && !occurrence.After(series.StartTime) {
+			scope = models.RecurrenceScopeAll
+		}
+
+		// Calendar feeds publish the deleted occurrences as cancelled
+		cancelled := tx.Where("series_id = ?", series.ID)
+		switch scope {
+		case models.RecurrenceScopeAll:
+		case models.RecurrenceScopeFollowing:
+			cancelled = cancelled.Where("occurrence_time >= ?", occurrence)
+		default:
+			cancelled = cancelled.Where("id = ?", meeting.ID)
+		}
+		var occurrences []models.Meeting
+		if err := cancelled.Find(&occurrences).Error; err != nil {
+			return fmt.Errorf("failed to fetch occurrences: %w", err)
+		}
+		if err := recordCancellations(tx, occurrences); err != nil {
+			return err
+		}
+
+		switch scope {
+		case models.RecurrenceScopeAll:
+			if err := tx.Where("series_id = ?", series.ID).Delete(&models.Meeting{}).Error; err != nil {
+				return fmt.Errorf("failed to delete occurrences: %w", err)
+			}
+			if err := tx.Delete(series).Error; err != nil {
+				return fmt.Errorf("failed to delete meeting series: %w", err)
+			}
+
+		case models.RecurrenceScopeFollowing:
+			rule, err := series.Rule()
+			if err != nil {
+				return fmt.Errorf("invalid recurrence rule on series %s: %w", series.ID, err)
+			}
+			truncateRule(rule, nil, series.DTStart(), occurrence)
+			if err := tx.Model(series).Update("recurrence_rule", rule.String()).Error; err != nil {
+				return fmt.Errorf("failed to update meeting series: %w", err)
+			}
+			if err := tx.Where("series_id = ? AND occurrence_time >= ?", series.ID, occurrence).Delete(&models.Meeting{}).Error; err != nil {
+				return fmt.Errorf("failed to delete occurrences: %w", err)
+			}
+
+		default:
+			series.AddException(occurrence)
+			if err := tx.Model(series).Update("exception_dates", series.ExceptionDates).Error; err != nil {
+				return fmt.Errorf("failed to update meeting series: %w", err)
+			}
+			if err := tx.Delete(meeting).Error; err != nil {
+				return fmt.Errorf("failed to delete meeting: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// seriesSlots returns the planned times of the series' occurrences up to until
+func seriesSlots(series *models.MeetingSeries, rule *models.RecurrenceRule, until time.Time) []timeSlot {
+	length := time.Duration(series.Duration) * time.Minute
+	var slots []timeSlot
+	for _, occurrence := range rule.Occurrences(series.DTStart(), series.StartTime, until, 0) {
+		if !series.IsException(occurrence) {
+			slots = append(slots, timeSlot{occurrence, occurrence.Add(length)})
+		}
+	}
+	return slots
+}
+
+// createInvitees adds the named participants to a meeting
+func createInvitees(tx *gorm.DB, meetingID uuid.UUID, invitees []models.CreateParticipantRequest) error {
+	for _, invitee := range invitees {
+		participant := &models.Participant{
+			MeetingID: meetingID,
+			Name:      invitee.Name,
+			AvatarURL: invitee.AvatarURL,
+		}
+		if err := tx.Create(participant).Error; err != nil {
+			return fmt.Errorf("failed to create participant: %w", err)
+		}
+	}
+	return nil
+}
+
+// replaceInvitees replaces a meeting's participant list
+func replaceInvitees(tx *gorm.DB, meetingID uuid.UUID, invitees []models.CreateParticipantRequest) error {
+	if err := tx.Where("meeting_id = ?", meetingID).Delete(&models.Participant{}).Error; err != nil {
+		return fmt.Errorf("failed to delete existing participants: %w", err)
+	}
+	return createInvitees(tx, meetingID, invitees)
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func setupTestRecurrence(t *testing.T) (*gorm.DB, *MeetingService, *models.User) {
+	db := setupTestDB(t, &models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{}, &models.MeetingSeries{},
+		&models.MeetingCancellation{})
+	service := NewMeetingService(db, nil, NewRoleService(db, nil))
+	return db, service, createNamedTestUser(t, db, "host")
+}
+
+// seriesStarts returns the start times of a series' occurrences in order
+func seriesStarts(t *testing.T, db *gorm.DB, meeting *models.Meeting) []time.Time {
+	var starts []time.Time
+	require.NoError(t, db.Model(&models.Meeting{}).Where("series_id = ?", *meeting.SeriesID).
+		Order("start_time ASC").Pluck("start_time", &starts).Error)
+	return starts
+}
+
+func TestMeetingService_CreateRecurringMeeting(t *testing.T) {
+	db, service, host := setupTestRecurrence(t)
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	first, err := service.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:           "Standup",
+		StartTime:      start,
+		RecurrenceRule: "FREQ=DAILY;COUNT=5",
+		ExceptionDates: []time.Time{start.AddDate(0, 0, 2)},
+		Participants:   []models.CreateParticipantRequest{{Name: "Ana"}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, first.SeriesID)
+	assert.True(t, first.StartTime.Equal(start))
+	assert.Equal(t, "FREQ=DAILY;COUNT=5", first.ToResponse().RecurrenceRule)
+
+	// Each occurrence is its own meeting with its own participants
+	starts := seriesStarts(t, db, first)
+	assert.Len(t, starts, 4)
+	var participants int64
+	require.NoError(t, db.Model(&models.Participant{}).Where("name = ?", "Ana").Count(&participants).Error)
+	assert.Equal(t, int64(4), participants)
+
+	upcoming, err := service.GetUpcomingMeetings(host.ID, 10, models.MeetingListFilter{})
+	require.NoError(t, err)
+	assert.Len(t, upcoming, 4)
+
+	_, err = service.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "Bad", StartTime: start, RecurrenceRule: "FREQ=YEARLY"})
+	assert.ErrorContains(t, err, "invalid recurrence rule")
+}
+
+func TestMeetingService_ExtendsUnboundedSeries(t *testing.T) {
+	db, service, host := setupTestRecurrence(t)
+	first, err := service.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:           "Weekly",
+		StartTime:      time.Now().Add(time.Hour),
+		RecurrenceRule: "FREQ=WEEKLY",
+	})
+	require.NoError(t, err)
+	count := len(seriesStarts(t, db, first))
+	assert.InDelta(t, 13, count, 1)
+
+	// Listing picks up occurrences once the horizon moves
+	require.NoError(t, db.Model(&models.MeetingSeries{}).Where("id = ?", *first.SeriesID).
+		Update("materialized_until", time.Now().Add(30*24*time.Hour)).Error)
+	require.NoError(t, db.Where("series_id = ? AND start_time > ?", *first.SeriesID, time.Now().Add(30*24*time.Hour)).
+		Delete(&models.Meeting{}).Error)
+	_, err = service.GetMeetings(host.ID, 1, 100, "", models.MeetingListFilter{})
+	require.NoError(t, err)
+	assert.Len(t, seriesStarts(t, db, first), count)
+
+	// A listing that read the series before another one extended it does
+	// not create the occurrences again
+	var stale models.MeetingSeries
+	require.NoError(t, db.First(&stale, "id = ?", *first.SeriesID).Error)
+	stale.MaterializedUntil = first.StartTime
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return service.materializeSeries(tx, &stale, seriesHorizon(&stale))
+	}))
+	assert.Len(t, seriesStarts(t, db, first), count)
+
+	duplicate := &models.Meeting{Name: "Weekly", StartTime: *first.OccurrenceTime, HostID: host.ID,
+		SeriesID: first.SeriesID, OccurrenceTime: first.OccurrenceTime}
+	assert.Error(t, db.Create(duplicate).Error)
+}
+
+func TestMeetingService_EditRecurringMeeting(t *testing.T) {
+	db, service, host := setupTestRecurrence(t)
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	first, err := service.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:           "Standup",
+		StartTime:      start,
+		RecurrenceRule: "FREQ=DAILY;COUNT=6",
+	})
+	require.NoError(t, err)
+
+	var occurrences []models.Meeting
+	require.NoError(t, db.Where("series_id = ?", *first.SeriesID).Order("start_time ASC").Find(&occurrences).Error)
+	require.Len(t, occurrences, 6)
+
+	// This occurrence only; it is detached from later series-wide edits
+	renamed := "Planning"
+	edited, err := service.UpdateMeeting(occurrences[1].ID, host.ID, &models.UpdateMeetingRequest{Name: &renamed})
+	require.NoError(t, err)
+	assert.True(t, edited.IsDetached)
+
+	// The whole series, moved by 30 minutes
+	allName := "Daily sync"
+	later := occurrences[0].StartTime.Add(30 * time.Minute)
+	_, err = service.UpdateMeeting(occurrences[0].ID, host.ID, &models.UpdateMeetingRequest{
+		Name:      &allName,
+		StartTime: &later,
+		Scope:     models.RecurrenceScopeAll,
+	})
+	require.NoError(t, err)
+
+	var reloaded []models.Meeting
+	require.NoError(t, db.Where("series_id = ?", *first.SeriesID).Order("occurrence_time ASC").Find(&reloaded).Error)
+	require.Len(t, reloaded, 6)
+	assert.Equal(t, occurrences[0].ID, reloaded[0].ID)
+	assert.Equal(t, "Daily sync", reloaded[0].Name)
+	assert.True(t, reloaded[0].StartTime.Equal(later))
+	assert.Equal(t, "Planning", reloaded[1].Name)
+	assert.Equal(t, "Daily sync", reloaded[5].Name)
+	assert.True(t, reloaded[5].StartTime.Equal(later.AddDate(0, 0, 5)))
+
+	// This and following: the series splits at the fourth occurrence
+	followingName := "Retro"
+	_, err = service.UpdateMeeting(reloaded[3].ID, host.ID, &models.UpdateMeetingRequest{
+		Name:  &followingName,
+		Scope: models.RecurrenceScopeFollowing,
+	})
+	require.NoError(t, err)
+
+	var original, split models.MeetingSeries
+	require.NoError(t, db.First(&original, "id = ?", *first.SeriesID).Error)
+	assert.Equal(t, "FREQ=DAILY;COUNT=3", original.RecurrenceRule)
+	var moved models.Meeting
+	require.NoError(t, db.First(&moved, "id = ?", reloaded[3].ID).Error)
+	require.NoError(t, db.First(&split, "id = ?", *moved.SeriesID).Error)
+	assert.Equal(t, "FREQ=DAILY;COUNT=3", split.RecurrenceRule)
+	assert.Equal(t, "Retro", moved.Name)
+	assert.Len(t, seriesStarts(t, db, first), 3)
+	assert.Len(t, seriesStarts(t, db, &moved), 3)
+
+	// The rule cannot change for a single occurrence
+	rule := "FREQ=WEEKLY"
+	_, err = service.UpdateMeeting(reloaded[0].ID, host.ID, &models.UpdateMeetingRequest{RecurrenceRule: &rule})
+	assert.EqualError(t, err, "recurrence rule can only be changed for the series")
+}
+
+func TestMeetingService_DeleteRecurringMeeting(t *testing.T) {
+	db, service, host := setupTestRecurrence(t)
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	first, err := service.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:           "Standup",
+		StartTime:      start,
+		RecurrenceRule: "FREQ=DAILY;COUNT=6",
+	})
+	require.NoError(t, err)
+
+	var occurrences []models.Meeting
+	require.NoError(t, db.Where("series_id = ?", *first.SeriesID).Order("start_time ASC").Find(&occurrences).Error)
+
+	// A deleted occurrence is not materialized again
+	require.NoError(t, service.DeleteMeeting(occurrences[1].ID, host.ID, models.RecurrenceScopeThis))
+	require.NoError(t, db.Model(&models.MeetingSeries{}).Where("id = ?", *first.SeriesID).
+		Update("materialized_until", time.Time{}).Error)
+	_, err = service.GetUpcomingMeetings(host.ID, 10, models.MeetingListFilter{})
+	require.NoError(t, err)
+	assert.Len(t, seriesStarts(t, db, first), 5)
+
+	require.NoError(t, service.DeleteMeeting(occurrences[4].ID, host.ID, models.RecurrenceScopeFollowing))
+	assert.Len(t, seriesStarts(t, db, first), 3)
+
+	require.NoError(t, service.DeleteMeeting(occurrences[0].ID, host.ID, models.RecurrenceScopeAll))
+	assert.Empty(t, seriesStarts(t, db, first))
+	var series int64
+	require.NoError(t, db.Model(&models.MeetingSeries{}).Count(&series).Error)
+	assert.Zero(t, series)
+}
+
+func TestMeetingService_ConvertToRecurringMeeting(t *testing.T) {
+	db, service, host := setupTestRecurrence(t)
+	meeting, err := service.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "One-off", StartTime: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	rule := "FREQ=WEEKLY;COUNT=3"
+	updated, err := service.UpdateMeeting(meeting.ID, host.ID, &models.UpdateMeetingRequest{RecurrenceRule: &rule})
+	require.NoError(t, err)
+	assert.Equal(t, meeting.ID, updated.ID)
+	require.NotNil(t, updated.SeriesID)
+	assert.Len(t, seriesStarts(t, db, updated), 3)
+}
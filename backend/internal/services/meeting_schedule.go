@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// timeSlot is the planned time of a meeting, [start, end)
+type timeSlot struct {
+	start time.Time
+	end   time.Time
+}
+
+// meetingSchedule is the resolved duration and time zone of a new meeting
+type meetingSchedule struct {
+	duration int // Minutes
+	timeZone string
+}
+
+// resolveSchedule works out the planned duration from either a duration or
+// an end time, and the time zone from the request or the host's preference
+func (s *MeetingService) resolveSchedule(hostID uuid.UUID, start time.Time, duration int, end *time.Time, timeZone string) (*meetingSchedule, error) {
+	if end != nil {
+		if !end.After(start) {
+			return nil, errors.New("end time must be after start time")
+		}
+		duration = int((end.Sub(start) + time.Minute - 1) / time.Minute)
+	}
+	if duration == 0 {
+		duration = models.DefaultMeetingDuration
+	}
+	if duration < 1 || duration > models.MaxMeetingDuration {
+		return nil, errors.New("invalid meeting duration")
+	}
+
+	if timeZone == "" {
+		var host models.User
+		if err := s.db.Select("id", "time_zone").First(&host, "id = ?", hostID).Error; err == nil {
+			timeZone = host.TimeZone
+		}
+	}
+	if timeZone == "" {
+		timeZone = models.DefaultTimeZone
+	}
+	if _, err := models.LoadTimeZone(timeZone); err != nil {
+		return nil, err
+	}
+
+	return &meetingSchedule{duration: duration, timeZone: timeZone}, nil
+}
+
+// checkOverlap fails when one of the slots overlaps another scheduled or
+// live meeting of the host. Meetings matched by skip (the ones being edited)
+// are ignored.
+func checkOverlap(tx *gorm.DB, hostID uuid.UUID, slots []timeSlot, skip func(*models.Meeting) bool) error {
+	if len(slots) == 0 {
+		return nil
+	}
+	from, to := slots[0].start, slots[0].end
+	for _, slot := range slots[1:] {
+		if slot.start.Before(from) {
+			from = slot.start
+		}
+		if slot.end.After(to) {
+			to = slot.end
+		}
+	}
+
+	var meetings []models.Meeting
+	if err := tx.Where("host_id = ? AND status IN ? AND start_time < ? AND end_time > ?",
+		hostID, []models.MeetingStatus{models.MeetingStatusScheduled, models.MeetingStatusLive}, to, from).
+		Find(&meetings).Error; err != nil {
+		return fmt.Errorf("failed to check overlapping meetings: %w", err)
+	}
+
+	for i := range meetings {
+		if skip != nil && skip(&meetings[i]) {
+			continue
+		}
+		for _, slot := range slots {
+			if meetings[i].Overlaps(slot.start, slot.end) {
+				return errors.New("meeting overlaps another meeting")
+			}
+		}
+	}
+	return nil
+}
+
+// GetInProgressMeetings returns the host's meetings that are live or within
+// their planned time, earliest first
+func (s *MeetingService) GetInProgressMeetings(hostID uuid.UUID, filter models.MeetingListFilter) ([]models.Meeting, error) {
+	filter.Category = models.MeetingCategoryInProgress
+
+	var meetings []models.Meeting
+	if err := s.db.Preload("Participants").Preload("Host").Preload("Series").
+		Where("host_id = ?", hostID).
+		Scopes(listFilterScope(filter, time.Now())).
+		Order("start_time ASC").
+		Find(&meetings).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch in-progress meetings: %w", err)
+	}
+	return meetings, nil
+}
+
+// listFilterScope applies a meeting list filter. The categories match
+// Meeting.CategoryAt.
+func listFilterScope(filter models.MeetingListFilter, now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.From != nil {
+			db = db.Where("meetings.end_time > ?", *filter.From)
+		}
+		if filter.To != nil {
+			db = db.Where("meetings.start_time < ?", *filter.To)
+		}
+
+		switch filter.Category {
+		case models.MeetingCategoryUpcoming:
+			db = db.Where("meetings.status = ? AND meetings.start_time > ?", models.MeetingStatusScheduled, now)
+		case models.MeetingCategoryInProgress:
+			db = db.Where("(meetings.status = ? OR (meetings.status = ? AND meetings.start_time <= ? AND meetings.end_time > ?))",
+				models.MeetingStatusLive, models.MeetingStatusScheduled, now, now)
+		case models.MeetingCategoryPast:
+			db = db.Where("(meetings.status IN ? OR (meetings.status = ? AND meetings.end_time <= ?))",
+				[]models.MeetingStatus{models.MeetingStatusEnded, models.MeetingStatusArchived}, models.MeetingStatusScheduled, now)
+		}
+		return db
+	}
+}
+
+// shiftWallClock moves t by shift on the wall clock of loc, so a meeting
+// moved by an hour stays at the same local time on both sides of a
+// daylight saving change
+func shiftWallClock(t time.Time, shift time.Duration, loc *time.Location) time.Time {
+	local := t.In(loc)
+	wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC).Add(shift)
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc)
+}
+
+// wallClockDelta returns how far the wall clock of loc moves from a to b
+func wallClockDelta(a, b time.Time, loc *time.Location) time.Duration {
+	wall := func(t time.Time) time.Time {
+		local := t.In(loc)
+		return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC)
+	}
+	return wall(b).Sub(wall(a))
+}
+
+// plannedUpdate returns the start, duration and time zone a meeting has
+// after the update. An end time sets the duration.
+func plannedUpdate(meeting *models.Meeting, req *models.UpdateMeetingRequest) (time.Time, int, string, error) {
+	start := meeting.StartTime
+	if req.StartTime != nil {
+		start = *req.StartTime
+	}
+	duration := meeting.Duration
+	if req.Duration != nil {
+		duration = *req.Duration
+	}
+	if req.EndTime != nil {
+		if !req.EndTime.After(start) {
+			return time.Time{}, 0, "", errors.New("end time must be after start time")
+		}
+		duration = int((req.EndTime.Sub(start) + time.Minute - 1) / time.Minute)
+	}
+	if duration < 1 || duration > models.MaxMeetingDuration {
+		return time.Time{}, 0, "", errors.New("invalid meeting duration")
+	}
+
+	timeZone := meeting.TimeZone
+	if req.TimeZone != nil {
+		if _, err := models.LoadTimeZone(*req.TimeZone); err != nil {
+			return time.Time{}, 0, "", err
+		}
+		timeZone = *req.TimeZone
+	}
+	return start, duration, timeZone, nil
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestMeetingService_ScheduleDefaults(t *testing.T) {
+	db, service, host := setupTestRecurrence(t)
+	require.NoError(t, db.Model(host).Update("time_zone", "Europe/Berlin").Error)
+	start := time.Now().Add(time.Hour).Truncate(time.Minute)
+
+	meeting, err := service.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "Defaults", StartTime: start})
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultMeetingDuration, meeting.Duration)
+	assert.True(t, meeting.EndTime.Equal(start.Add(time.Hour)))
+	assert.Equal(t, "Europe/Berlin", meeting.TimeZone)
+	assert.Equal(t, models.MeetingCategoryUpcoming, meeting.ToResponse().Category)
+
+	end := start.Add(26*time.Hour + 90*time.Minute)
+	meeting, err = service.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "End", StartTime: start.Add(25 * time.Hour), EndTime: &end, TimeZone: "Asia/Tokyo"})
+	require.NoError(t, err)
+	assert.Equal(t, 150, meeting.Duration)
+	assert.Equal(t, "Asia/Tokyo", meeting.TimeZone)
+
+	_, err = service.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "Bad", StartTime: start, TimeZone: "Mars/Olympus"})
+	assert.EqualError(t, err, "invalid time zone")
+	_, err = service.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "Bad", StartTime: start, EndTime: &start})
+	assert.EqualError(t, err, "end time must be after start time")
+}
+
+func TestMeetingService_Overlap(t *testing.T) {
+	_, service, host := setupTestRecurrence(t)
+	start := time.Now().Add(time.Hour).Truncate(time.Minute)
+
+	first, err := service.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "First", StartTime: start, Duration: 30})
+	require.NoError(t, err)
+
+	// Back-to-back meetings do not overlap
+	second, err := service.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "Second", StartTime: start.Add(30 * time.Minute), Duration: 30})
+	require.NoError(t, err)
+
+	// Lengthening a meeting into the next one is caught; the meeting does
+	// not clash with itself
+	longer := 45
+	_, err = service.UpdateMeeting(second.ID, host.ID, &models.UpdateMeetingRequest{Duration: &longer})
+	assert.NoError(t, err)
+	_, err = service.UpdateMeeting(first.ID, host.ID, &models.UpdateMeetingRequest{Duration: &longer})
+	assert.EqualError(t, err, "meeting overlaps another meeting")
+
+	_, err = service.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "Clash", StartTime: start.Add(15 * time.Minute)})
+	assert.EqualError(t, err, "meeting overlaps another meeting")
+	_, err = service.CreateMeeting(host.ID, &models.CreateMeetingRequest{Name: "Clash", StartTime: start.Add(15 * time.Minute), AllowOverlap: true})
+	assert.NoError(t, err)
+
+	// Recurring meetings are checked occurrence by occurrence
+	_, err = service.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:           "Daily",
+		StartTime:      start.Add(-24*time.Hour + 20*time.Minute),
+		RecurrenceRule: "FREQ=DAILY;COUNT=3",
+	})
+	assert.EqualError(t, err, "meeting overlaps another meeting")
+}
+
+func TestMeetingService_Categories(t *testing.T) {
+	db, service, host := setupTestRecurrence(t)
+	now := time.Now()
+	create := func(name string, start time.Time, status models.MeetingStatus) *models.Meeting {
+		meeting := &models.Meeting{Name: name, StartTime: start, Duration: 60, HostID: host.ID, Status: status}
+		require.NoError(t, db.Create(meeting).Error)
+		return meeting
+	}
+	upcoming := create("Upcoming", now.Add(2*time.Hour), models.MeetingStatusScheduled)
+	running := create("Running", now.Add(-30*time.Minute), models.MeetingStatusScheduled)
+	earlyStart := create("Early start", now.Add(10*time.Minute), models.MeetingStatusLive)
+	overdue := create("Overdue", now.Add(-3*time.Hour), models.MeetingStatusScheduled)
+	endedEarly := create("Ended early", now.Add(-20*time.Minute), models.MeetingStatusEnded)
+	nextWeek := create("Next week", now.Add(7*24*time.Hour), models.MeetingStatusScheduled)
+
+	names := func(meetings []models.Meeting) []string {
+		var result []string
+		for _, meeting := range meetings {
+			result = append(result, meeting.Name)
+		}
+		return result
+	}
+
+	meetings, err := service.GetUpcomingMeetings(host.ID, 10, models.MeetingListFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{upcoming.Name, nextWeek.Name}, names(meetings))
+
+	meetings, err = service.GetInProgressMeetings(host.ID, models.MeetingListFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{running.Name, earlyStart.Name}, names(meetings))
+	assert.Equal(t, models.MeetingCategoryInProgress, meetings[1].ToResponse().Category)
+
+	past, err := service.GetPastMeetings(host.ID, 1, 10, models.MeetingListFilter{})
+	require.NoError(t, err)
+	require.Len(t, past.Meetings, 2)
+	assert.Equal(t, endedEarly.ID, past.Meetings[0].ID)
+	assert.Equal(t, overdue.ID, past.Meetings[1].ID)
+
+	// Date ranges select meetings that overlap them
+	from, to := now.Add(24*time.Hour), now.Add(8*24*time.Hour)
+	meetings, err = service.GetUpcomingMeetings(host.ID, 10, models.MeetingListFilter{From: &from, To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, []string{nextWeek.Name}, names(meetings))
+
+	to = now
+	list, err := service.GetMeetings(host.ID, 1, 10, "", models.MeetingListFilter{To: &to, Category: models.MeetingCategoryInProgress})
+	require.NoError(t, err)
+	require.Len(t, list.Meetings, 1)
+	assert.Equal(t, running.ID, list.Meetings[0].ID)
+}
+
+func TestMeetingService_RecurrenceKeepsLocalTime(t *testing.T) {
+	db, service, host := setupTestRecurrence(t)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Daylight saving time starts in New York on 10 March 2030
+	start := time.Date(2030, 3, 4, 9, 0, 0, 0, newYork)
+	first, err := service.CreateMeeting(host.ID, &models.CreateMeetingRequest{
+		Name:           "Weekly",
+		StartTime:      start,
+		TimeZone:       "America/New_York",
+		RecurrenceRule: "FREQ=WEEKLY;COUNT=3",
+	})
+	require.NoError(t, err)
+
+	starts := seriesStarts(t, db, first)
+	require.Len(t, starts, 3)
+	for _, s := range starts {
+		assert.Equal(t, 9, s.In(newYork).Hour())
+	}
+	assert.Equal(t, 7*24*time.Hour-time.Hour, starts[1].Sub(starts[0]))
+
+	// Moving the series by an hour keeps every occurrence at the new local time
+	later := starts[0].Add(time.Hour)
+	_, err = service.UpdateMeeting(first.ID, host.ID, &models.UpdateMeetingRequest{StartTime: &later, Scope: models.RecurrenceScopeAll})
+	require.NoError(t, err)
+	starts = seriesStarts(t, db, first)
+	require.Len(t, starts, 3)
+	for _, s := range starts {
+		assert.Equal(t, 10, s.In(newYork).Hour())
+	}
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,7 +13,11 @@ import (
 )
 
 type MeetingService struct {
-	db *gorm.DB
+	db           *gorm.DB
+	wsService    *WebSocketService
+	roleService  *RoleService
+	lobbyService *LobbyService
+	accessService *MeetingAccessService
 }
 
 type MeetingListResponse struct {
@@ -20,18 +25,61 @@ type MeetingListResponse struct {
 	Pagination models.PaginationInfo    `json:"pagination"`
 }
 
-func NewMeetingService(db *gorm.DB) *MeetingService {
+func NewMeetingService(db *gorm.DB, wsService *WebSocketService, roleService *RoleService) *MeetingService {
 	return &MeetingService{
-		db: db,
+		db:          db,
+		wsService:   wsService,
+		roleService: roleService,
 	}
 }
 
+// SetLobbyService sets the lobby service used to hold newcomers for admission
+func (s *MeetingService) SetLobbyService(lobbyService *LobbyService) {
+	s.lobbyService = lobbyService
+}
+
+// SetAccessService sets the service that checks passcodes and invites on join
+func (s *MeetingService) SetAccessService(accessService *MeetingAccessService) {
+	s.accessService = accessService
+}
+
 func (s *MeetingService) CreateMeeting(hostID uuid.UUID, req *models.CreateMeetingRequest) (*models.Meeting, error) {
+	passcodeHash := ""
+	if req.Passcode != "" {
+		hash, err := hashPasscode(req.Passcode)
+		if err != nil {
+			return nil, err
+		}
+		passcodeHash = hash
+	}
+
+	schedule, err := s.resolveSchedule(hostID, req.StartTime, req.Duration, req.EndTime, req.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recurring meetings are created as a series of occurrences
+	if req.RecurrenceRule != "" {
+		return s.createMeetingSeries(hostID, req, passcodeHash, schedule)
+	}
+
 	// Create meeting
 	meeting := &models.Meeting{
-		Name:      req.Name,
-		StartTime: req.StartTime,
-		HostID:    hostID,
+		Name:         req.Name,
+		StartTime:    req.StartTime,
+		Duration:     schedule.duration,
+		EndTime:      req.StartTime.Add(time.Duration(schedule.duration) * time.Minute),
+		TimeZone:     schedule.timeZone,
+		HostID:       hostID,
+		LobbyMode:    req.LobbyMode,
+		PrivateChat:  req.PrivateChat,
+		PasscodeHash: passcodeHash,
+	}
+
+	if !req.AllowOverlap {
+		if err := checkOverlap(s.db, hostID, []timeSlot{{meeting.StartTime, meeting.EndTime}}, nil); err != nil {
+			return nil, err
+		}
 	}
 
 	// Start transaction
@@ -76,10 +124,17 @@ func (s *MeetingService) CreateMeeting(hostID uuid.UUID, req *models.CreateMeeti
 	return meeting, nil
 }
 
-func (s *MeetingService) GetMeetings(hostID uuid.UUID, page, limit int, search string) (*MeetingListResponse, error) {
+// GetMeetings lists the host's meetings, optionally narrowed to a date
+// range and a category
+func (s *MeetingService) GetMeetings(hostID uuid.UUID, page, limit int, search string, filter models.MeetingListFilter) (*MeetingListResponse, error) {
 	var meetings []models.Meeting
 	var total int64
 
+	// Make sure upcoming occurrences of recurring meetings are listed
+	if err := s.extendHostSeries(hostID); err != nil {
+		return nil, err
+	}
+
 	// Build query
 	query := s.db.Model(&models.Meeting{}).Where("host_id = ?", hostID)
 
@@ -87,6 +142,7 @@ func (s *MeetingService) GetMeetings(hostID uuid.UUID, page, limit int, search s
 	if search != "" {
 		query = query.Where("name ILIKE ?", "%"+search+"%")
 	}
+	query = query.Scopes(listFilterScope(filter, time.Now()))
 
 	// Count total records
 	if err := query.Count(&total).Error; err != nil {
@@ -98,7 +154,7 @@ func (s *MeetingService) GetMeetings(hostID uuid.UUID, page, limit int, search s
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
 
 	// Fetch meetings with relationships
-	if err := query.Preload("Participants").Preload("Host").
+	if err := query.Preload("Participants").Preload("Host").Preload("Series").
 		Offset(offset).Limit(limit).
 		Order("start_time ASC").
 		Find(&meetings).Error; err != nil {
@@ -126,7 +182,7 @@ func (s *MeetingService) GetMeetingByID(meetingID uuid.UUID, userID uuid.UUID) (
 	var meeting models.Meeting
 
 	// Fetch meeting with relationships
-	if err := s.db.Preload("Participants").Preload("Host").
+	if err := s.db.Preload("Participants").Preload("Host").Preload("Series").
 		Where("id = ?", meetingID).
 		First(&meeting).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -169,6 +225,35 @@ func (s *MeetingService) UpdateMeeting(meetingID uuid.UUID, userID uuid.UUID, re
 		return nil, fmt.Errorf("failed to fetch meeting: %w", err)
 	}
 
+	// Edits of a whole series (or of this and the following occurrences)
+	// and recurrence rule changes go through the series
+	scope := req.Scope
+	if scope == "" {
+		scope = models.RecurrenceScopeThis
+	}
+	if req.RecurrenceRule != nil {
+		if meeting.SeriesID != nil && scope == models.RecurrenceScopeThis {
+			return nil, errors.New("recurrence rule can only be changed for the series")
+		}
+		return s.updateSeries(&meeting, req, scope)
+	}
+	if meeting.SeriesID != nil && scope != models.RecurrenceScopeThis {
+		return s.updateSeries(&meeting, req, scope)
+	}
+
+	start, duration, timeZone, err := plannedUpdate(&meeting, req)
+	if err != nil {
+		return nil, err
+	}
+	timingChanged := req.StartTime != nil || req.Duration != nil || req.EndTime != nil
+	if timingChanged && !req.AllowOverlap {
+		end := start.Add(time.Duration(duration) * time.Minute)
+		skip := func(other *models.Meeting) bool { return other.ID == meeting.ID }
+		if err := checkOverlap(s.db, meeting.HostID, []timeSlot{{start, end}}, skip); err != nil {
+			return nil, err
+		}
+	}
+
 	// Start transaction
 	tx := s.db.Begin()
 	defer func() {
@@ -182,8 +267,36 @@ func (s *MeetingService) UpdateMeeting(meetingID uuid.UUID, userID uuid.UUID, re
 	if req.Name != nil {
 		updates["name"] = *req.Name
 	}
-	if req.StartTime != nil {
-		updates["start_time"] = *req.StartTime
+	if timingChanged {
+		updates["start_time"] = start
+		updates["duration"] = duration
+		updates["end_time"] = start.Add(time.Duration(duration) * time.Minute)
+	}
+	if req.TimeZone != nil {
+		updates["time_zone"] = timeZone
+	}
+	if req.LobbyMode != nil {
+		updates["lobby_mode"] = *req.LobbyMode
+	}
+	if req.PrivateChat != nil {
+		updates["private_chat"] = *req.PrivateChat
+	}
+	if req.Passcode != nil {
+		passcodeHash := ""
+		if *req.Passcode != "" {
+			hash, err := hashPasscode(*req.Passcode)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			passcodeHash = hash
+		}
+		updates["passcode_hash"] = passcodeHash
+	}
+
+	// An occurrence edited on its own no longer follows series-wide edits
+	if meeting.SeriesID != nil && (len(updates) > 0 || req.Participants != nil) {
+		updates["is_detached"] = true
 	}
 
 	if len(updates) > 0 {
@@ -221,14 +334,17 @@ func (s *MeetingService) UpdateMeeting(meetingID uuid.UUID, userID uuid.UUID, re
 	}
 
 	// Fetch updated meeting with relationships
-	if err := s.db.Preload("Participants").Preload("Host").First(&meeting, meetingID).Error; err != nil {
+	if err := s.db.Preload("Participants").Preload("Host").Preload("Series").First(&meeting, meetingID).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch updated meeting: %w", err)
 	}
 
 	return &meeting, nil
 }
 
-func (s *MeetingService) DeleteMeeting(meetingID uuid.UUID, userID uuid.UUID) error {
+// DeleteMeeting deletes a meeting. For an occurrence of a recurring meeting
+// the scope selects whether only it, it and the following occurrences, or
+// the whole series is deleted.
+func (s *MeetingService) DeleteMeeting(meetingID uuid.UUID, userID uuid.UUID, scope models.RecurrenceScope) error {
 	// Check if meeting exists and user is the host
 	var meeting models.Meeting
 	if err := s.db.Where("id = ? AND host_id = ?", meetingID, userID).First(&meeting).Error; err != nil {
@@ -238,20 +354,38 @@ func (s *MeetingService) DeleteMeeting(meetingID uuid.UUID, userID uuid.UUID) er
 		return fmt.Errorf("failed to fetch meeting: %w", err)
 	}
 
-	// Delete meeting (participants will be deleted due to cascade)
-	if err := s.db.Delete(&meeting).Error; err != nil {
-		return fmt.Errorf("failed to delete meeting: %w", err)
+	if meeting.SeriesID != nil {
+		return s.deleteSeriesOccurrences(&meeting, scope)
 	}
 
-	return nil
+	// Delete meeting (participants will be deleted due to cascade);
+	// calendar feeds publish it as cancelled
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := recordCancellations(tx, []models.Meeting{meeting}); err != nil {
+			return err
+		}
+		if err := tx.Delete(&meeting).Error; err != nil {
+			return fmt.Errorf("failed to delete meeting: %w", err)
+		}
+		return nil
+	})
 }
 
-func (s *MeetingService) GetUpcomingMeetings(hostID uuid.UUID, limit int) ([]models.Meeting, error) {
+// GetUpcomingMeetings returns the host's meetings that have not started
+// yet; meetings in progress are listed by GetInProgressMeetings
+func (s *MeetingService) GetUpcomingMeetings(hostID uuid.UUID, limit int, filter models.MeetingListFilter) ([]models.Meeting, error) {
 	var meetings []models.Meeting
 
+	// Make sure upcoming occurrences of recurring meetings are listed
+	if err := s.extendHostSeries(hostID); err != nil {
+		return nil, err
+	}
+
 	// Fetch upcoming meetings
-	if err := s.db.Preload("Participants").Preload("Host").
-		Where("host_id = ? AND start_time > ?", hostID, time.Now()).
+	filter.Category = models.MeetingCategoryUpcoming
+	if err := s.db.Preload("Participants").Preload("Host").Preload("Series").
+		Where("host_id = ?", hostID).
+		Scopes(listFilterScope(filter, time.Now())).
 		Order("start_time ASC").
 		Limit(limit).
 		Find(&meetings).Error; err != nil {
@@ -261,13 +395,17 @@ func (s *MeetingService) GetUpcomingMeetings(hostID uuid.UUID, limit int) ([]mod
 	return meetings, nil
 }
 
-func (s *MeetingService) GetPastMeetings(hostID uuid.UUID, page, limit int) (*MeetingListResponse, error) {
+// GetPastMeetings lists the host's meetings that have ended or whose
+// planned end has passed, most recent first
+func (s *MeetingService) GetPastMeetings(hostID uuid.UUID, page, limit int, filter models.MeetingListFilter) (*MeetingListResponse, error) {
 	var meetings []models.Meeting
 	var total int64
 
 	// Build query
+	filter.Category = models.MeetingCategoryPast
 	query := s.db.Model(&models.Meeting{}).
-		Where("host_id = ? AND start_time < ?", hostID, time.Now())
+		Where("host_id = ?", hostID).
+		Scopes(listFilterScope(filter, time.Now()))
 
 	// Count total records
 	if err := query.Count(&total).Error; err != nil {
@@ -303,7 +441,7 @@ func (s *MeetingService) GetPastMeetings(hostID uuid.UUID, page, limit int) (*Me
 	}, nil
 }
 
-func (s *MeetingService) JoinMeeting(userID uuid.UUID, meetingID uuid.UUID) (*models.Participant, error) {
+func (s *MeetingService) JoinMeeting(userID uuid.UUID, meetingID uuid.UUID, creds models.MeetingCredentials) (*models.Participant, error) {
 	// Check if meeting exists
 	var meeting models.Meeting
 	if err := s.db.Where("id = ?", meetingID).First(&meeting).Error; err != nil {
@@ -313,6 +451,37 @@ func (s *MeetingService) JoinMeeting(userID uuid.UUID, meetingID uuid.UUID) (*mo
 		return nil, fmt.Errorf("failed to fetch meeting: %w", err)
 	}
 
+	if meeting.Status == models.MeetingStatusArchived {
+		return nil, errors.New("meeting is archived")
+	}
+
+	// Refuse banned users and newcomers to locked meetings
+	actor := models.MeetingActor{UserID: &userID}
+	if err := checkMeetingAdmission(s.db, &meeting, actor); err != nil {
+		return nil, err
+	}
+
+	// Protected meetings need the passcode or an invite
+	var invite *models.MeetingInvite
+	if s.accessService != nil {
+		var err error
+		if invite, err = s.accessService.CheckAccess(&meeting, actor, creds); err != nil {
+			return nil, err
+		}
+	}
+
+	// Newcomers may have to wait in the lobby until a host admits them.
+	// Invited participants were let in by the host already.
+	if s.lobbyService != nil && invite == nil {
+		held, err := s.lobbyService.RequiresLobby(&meeting, actor)
+		if err != nil {
+			return nil, err
+		}
+		if held {
+			return s.lobbyService.Hold(&meeting, actor)
+		}
+	}
+
 	// Check if user is already a participant
 	var existingParticipant models.Participant
 	if err := s.db.Where("meeting_id = ? AND user_id = ?", meetingID, userID).First(&existingParticipant).Error; err == nil {
@@ -331,8 +500,14 @@ func (s *MeetingService) JoinMeeting(userID uuid.UUID, meetingID uuid.UUID) (*mo
 		UserID:    &userID,
 		Name:      user.Username,
 		AvatarURL: user.AvatarURL,
+		Role:      models.RoleAttendee,
 		IsActive:  true,
 	}
+	if meeting.HostID == userID {
+		participant.Role = models.RoleHost
+	} else if invite != nil && invite.Role != "" {
+		participant.Role = invite.Role
+	}
 
 	if err := s.db.Create(participant).Error; err != nil {
 		return nil, fmt.Errorf("failed to join meeting: %w", err)
@@ -373,10 +548,7 @@ func (s *MeetingService) GetMeetingParticipants(meetingID uuid.UUID, userID uuid
 	}
 
 	// Check if user is host or participant
-	var participantCount int64
-	s.db.Model(&models.Participant{}).Where("meeting_id = ? AND user_id = ?", meetingID, userID).Count(&participantCount)
-	
-	if meeting.HostID != userID && participantCount == 0 {
+	if _, err := s.roleService.GetRole(meetingID, models.MeetingActor{UserID: &userID}); err != nil {
 		return nil, errors.New("unauthorized access to meeting")
 	}
 
@@ -401,6 +573,10 @@ func (s *MeetingService) GetMeetingParticipantsPublic(meetingID uuid.UUID) ([]mo
 		return nil, fmt.Errorf("failed to fetch meeting: %w", err)
 	}
 
+	if meeting.HasPasscode() {
+		return nil, errors.New("meeting is protected")
+	}
+
 	// Get all active participants (both authenticated and public users)
 	var participants []models.Participant
 	if err := s.db.Where("meeting_id = ? AND is_active = ?", meetingID, true).
@@ -414,14 +590,15 @@ func (s *MeetingService) GetMeetingParticipantsPublic(meetingID uuid.UUID) ([]mo
 	return participants, nil
 }
 
-func (s *MeetingService) GetJoinedMeetings(userID uuid.UUID, page, limit int) (*MeetingListResponse, error) {
+func (s *MeetingService) GetJoinedMeetings(userID uuid.UUID, page, limit int, filter models.MeetingListFilter) (*MeetingListResponse, error) {
 	var meetings []models.Meeting
 	var total int64
 
 	// Build query - get meetings where user is a participant
 	query := s.db.Model(&models.Meeting{}).
 		Joins("INNER JOIN participants ON meetings.id = participants.meeting_id").
-		Where("participants.user_id = ? AND participants.is_active = ?", userID, true)
+		Where("participants.user_id = ? AND participants.is_active = ?", userID, true).
+		Scopes(listFilterScope(filter, time.Now()))
 
 	// Count total records
 	if err := query.Count(&total).Error; err != nil {
@@ -458,59 +635,199 @@ func (s *MeetingService) GetJoinedMeetings(userID uuid.UUID, page, limit int) (*
 }
 
 func (s *MeetingService) StartMeeting(meetingID uuid.UUID, userID uuid.UUID) (*models.Meeting, error) {
-	// Check if meeting exists and user is the host
-	var meeting models.Meeting
-	if err := s.db.Where("id = ? AND host_id = ?", meetingID, userID).First(&meeting).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("meeting not found or unauthorized")
-		}
-		return nil, fmt.Errorf("failed to fetch meeting: %w", err)
+	// Check if meeting exists and user may manage it
+	meeting, err := s.getManagedMeeting(meetingID, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update meeting to active
-	if err := s.db.Model(&meeting).Updates(map[string]interface{}{
-		"is_active": true,
-	}).Error; err != nil {
-		return nil, fmt.Errorf("failed to start meeting: %w", err)
+	if meeting.Status != models.MeetingStatusLive {
+		if !meeting.Status.CanTransitionTo(models.MeetingStatusLive) {
+			return nil, errors.New("meeting is archived")
+		}
+		if _, err := s.transitionMeeting(meetingID, models.MeetingStatusLive, "host"); err != nil {
+			return nil, fmt.Errorf("failed to start meeting: %w", err)
+		}
 	}
 
 	// Fetch updated meeting with relationships
-	if err := s.db.Preload("Participants").Preload("Host").First(&meeting, meetingID).Error; err != nil {
+	if err := s.db.Preload("Participants").Preload("Host").First(meeting, meetingID).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch updated meeting: %w", err)
 	}
 
-	return &meeting, nil
+	return meeting, nil
 }
 
 func (s *MeetingService) EndMeeting(meetingID uuid.UUID, userID uuid.UUID) (*models.Meeting, error) {
-	// Check if meeting exists and user is the host
-	var meeting models.Meeting
-	if err := s.db.Where("id = ? AND host_id = ?", meetingID, userID).First(&meeting).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("meeting not found or unauthorized")
+	// Check if meeting exists and user may manage it
+	meeting, err := s.getManagedMeeting(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if meeting.Status != models.MeetingStatusEnded {
+		if !meeting.Status.CanTransitionTo(models.MeetingStatusEnded) {
+			return nil, errors.New("meeting is not live")
+		}
+		if _, err := s.transitionMeeting(meetingID, models.MeetingStatusEnded, "host"); err != nil {
+			return nil, fmt.Errorf("failed to end meeting: %w", err)
 		}
-		return nil, fmt.Errorf("failed to fetch meeting: %w", err)
 	}
 
-	// Update meeting to inactive
-	if err := s.db.Model(&meeting).Updates(map[string]interface{}{
-		"is_active": false,
-	}).Error; err != nil {
-		return nil, fmt.Errorf("failed to end meeting: %w", err)
+	// Fetch updated meeting with relationships
+	if err := s.db.Preload("Participants").Preload("Host").First(meeting, meetingID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch updated meeting: %w", err)
 	}
 
-	// Deactivate all participants
-	if err := s.db.Model(&models.Participant{}).Where("meeting_id = ?", meetingID).Updates(map[string]interface{}{
-		"is_active": false,
-		"left_at":   time.Now(),
-	}).Error; err != nil {
-		return nil, fmt.Errorf("failed to deactivate participants: %w", err)
+	return meeting, nil
+}
+
+func (s *MeetingService) ArchiveMeeting(meetingID uuid.UUID, userID uuid.UUID) (*models.Meeting, error) {
+	// Check if meeting exists and user may manage it
+	meeting, err := s.getManagedMeeting(meetingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if meeting.Status != models.MeetingStatusArchived {
+		if !meeting.Status.CanTransitionTo(models.MeetingStatusArchived) {
+			return nil, errors.New("meeting is live")
+		}
+		if _, err := s.transitionMeeting(meetingID, models.MeetingStatusArchived, "host"); err != nil {
+			return nil, fmt.Errorf("failed to archive meeting: %w", err)
+		}
 	}
 
 	// Fetch updated meeting with relationships
-	if err := s.db.Preload("Participants").Preload("Host").First(&meeting, meetingID).Error; err != nil {
+	if err := s.db.Preload("Participants").Preload("Host").First(meeting, meetingID).Error; err != nil {
 		return nil, fmt.Errorf("failed to fetch updated meeting: %w", err)
 	}
 
+	return meeting, nil
+}
+
+// getManagedMeeting loads a meeting after checking that the user's meeting
+// role allows managing it (host or co-host)
+func (s *MeetingService) getManagedMeeting(meetingID uuid.UUID, userID uuid.UUID) (*models.Meeting, error) {
+	if err := s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &userID}, models.PermissionManageMeeting); err != nil {
+		switch err.Error() {
+		case "meeting not found", "not a participant", "permission denied":
+			return nil, errors.New("meeting not found or unauthorized")
+		}
+		return nil, err
+	}
+
+	var meeting models.Meeting
+	if err := s.db.Where("id = ?", meetingID).First(&meeting).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch meeting: %w", err)
+	}
 	return &meeting, nil
-}
\ No newline at end of file
+}
+
+// AutoStartMeeting puts a meeting live when its first participant connects.
+// It is a no-op for meetings that are already live or archived.
+func (s *MeetingService) AutoStartMeeting(meetingID uuid.UUID) error {
+	_, err := s.transitionMeeting(meetingID, models.MeetingStatusLive, "participant-joined")
+	return err
+}
+
+// EndIdleMeeting ends a live meeting whose room stayed empty for the grace period
+func (s *MeetingService) EndIdleMeeting(meetingID uuid.UUID) error {
+	_, err := s.transitionMeeting(meetingID, models.MeetingStatusEnded, "empty")
+	return err
+}
+
+// GetLiveMeetingIDs returns the IDs of all live meetings
+func (s *MeetingService) GetLiveMeetingIDs() ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := s.db.Model(&models.Meeting{}).
+		Where("status = ?", models.MeetingStatusLive).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch live meetings: %w", err)
+	}
+	return ids, nil
+}
+
+// transitionMeeting moves a meeting to the given status when its current
+// status allows it and broadcasts the change. The update is conditional on
+// the current status so concurrent callers (or nodes) transition only once;
+// it reports whether this call performed the transition.
+func (s *MeetingService) transitionMeeting(meetingID uuid.UUID, to models.MeetingStatus, reason string) (bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":    to,
+		"is_active": to == models.MeetingStatusLive,
+	}
+	switch to {
+	case models.MeetingStatusLive:
+		updates["started_at"] = gorm.Expr("COALESCE(started_at, ?)", now)
+		updates["ended_at"] = nil
+	case models.MeetingStatusEnded:
+		updates["ended_at"] = now
+	case models.MeetingStatusArchived:
+		updates["archived_at"] = now
+	}
+
+	var meeting models.Meeting
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Meeting{}).
+			Where("id = ? AND status IN ?", meetingID, to.TransitionSources()).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if to == models.MeetingStatusEnded {
+			// Deactivate all participants
+			if err := tx.Model(&models.Participant{}).Where("meeting_id = ? AND is_active = ?", meetingID, true).Updates(map[string]interface{}{
+				"is_active": false,
+				"left_at":   now,
+			}).Error; err != nil {
+				return fmt.Errorf("failed to deactivate participants: %w", err)
+			}
+		}
+
+		return tx.First(&meeting, meetingID).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	if meeting.ID == uuid.Nil {
+		return false, nil
+	}
+
+	log.Printf("Meeting %s is now %s (reason: %s)", meetingID, to, reason)
+	s.notifyStatusChange(&meeting, reason)
+	return true, nil
+}
+
+// notifyStatusChange broadcasts meeting-started / meeting-ended to the room
+func (s *MeetingService) notifyStatusChange(meeting *models.Meeting, reason string) {
+	if s.wsService == nil {
+		return
+	}
+
+	var messageType models.SignalingMessageType
+	switch meeting.Status {
+	case models.MeetingStatusLive:
+		messageType = models.SignalingTypeMeetingStarted
+	case models.MeetingStatusEnded:
+		messageType = models.SignalingTypeMeetingEnded
+	default:
+		return
+	}
+
+	s.wsService.SendMessageToMeeting(meeting.ID.String(), models.SignalingMessage{
+		Type: messageType,
+		Data: models.MeetingStatusPayload{
+			MeetingID: meeting.ID.String(),
+			Status:    meeting.Status,
+			StartedAt: meeting.StartedAt,
+			EndedAt:   meeting.EndedAt,
+			Reason:    reason,
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func setupTestMeetingDB(t *testing.T) *gorm.DB {
+	return setupTestDB(t, &models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{})
+}
+
+func createTestMeeting(t *testing.T, db *gorm.DB) (*models.Meeting, uuid.UUID) {
+	host := &models.User{Username: "host", Email: "host@example.com", PasswordHash: "x"}
+	require.NoError(t, db.Create(host).Error)
+
+	meeting := &models.Meeting{Name: "Standup", StartTime: time.Now().Add(time.Hour), HostID: host.ID}
+	require.NoError(t, db.Create(meeting).Error)
+	return meeting, host.ID
+}
+
+func TestMeetingStatus_CanTransitionTo(t *testing.T) {
+	assert.True(t, models.MeetingStatusScheduled.CanTransitionTo(models.MeetingStatusLive))
+	assert.True(t, models.MeetingStatusLive.CanTransitionTo(models.MeetingStatusEnded))
+	assert.True(t, models.MeetingStatusEnded.CanTransitionTo(models.MeetingStatusLive))
+	assert.True(t, models.MeetingStatusEnded.CanTransitionTo(models.MeetingStatusArchived))
+	assert.False(t, models.MeetingStatusLive.CanTransitionTo(models.MeetingStatusArchived))
+	assert.False(t, models.MeetingStatusArchived.CanTransitionTo(models.MeetingStatusLive))
+}
+
+func TestMeetingService_Lifecycle(t *testing.T) {
+	db := setupTestMeetingDB(t)
+	service := NewMeetingService(db, nil, NewRoleService(db, nil))
+	meeting, hostID := createTestMeeting(t, db)
+	assert.Equal(t, models.MeetingStatusScheduled, meeting.Status)
+
+	_, err := service.EndMeeting(meeting.ID, hostID)
+	assert.EqualError(t, err, "meeting is not live")
+
+	started, err := service.StartMeeting(meeting.ID, hostID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MeetingStatusLive, started.Status)
+	assert.True(t, started.IsActive)
+	require.NotNil(t, started.StartedAt)
+
+	_, err = service.ArchiveMeeting(meeting.ID, hostID)
+	assert.EqualError(t, err, "meeting is live")
+
+	ended, err := service.EndMeeting(meeting.ID, hostID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MeetingStatusEnded, ended.Status)
+	assert.False(t, ended.IsActive)
+	assert.NotNil(t, ended.EndedAt)
+
+	archived, err := service.ArchiveMeeting(meeting.ID, hostID)
+	require.NoError(t, err)
+	assert.Equal(t, models.MeetingStatusArchived, archived.Status)
+
+	_, err = service.StartMeeting(meeting.ID, hostID)
+	assert.EqualError(t, err, "meeting is archived")
+
+	_, err = service.StartMeeting(meeting.ID, uuid.New())
+	assert.EqualError(t, err, "meeting not found or unauthorized")
+}
+
+func TestMeetingService_AutoStartIsIdempotent(t *testing.T) {
+	db := setupTestMeetingDB(t)
+	service := NewMeetingService(db, nil, NewRoleService(db, nil))
+	meeting, _ := createTestMeeting(t, db)
+
+	changed, err := service.transitionMeeting(meeting.ID, models.MeetingStatusLive, "participant-joined")
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = service.transitionMeeting(meeting.ID, models.MeetingStatusLive, "participant-joined")
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	ids, err := service.GetLiveMeetingIDs()
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{meeting.ID}, ids)
+}
+
+func TestWebRTCService_EndsEmptyMeetingAfterGracePeriod(t *testing.T) {
+	db := setupTestMeetingDB(t)
+	meetingService := NewMeetingService(db, nil, NewRoleService(db, nil))
+	meeting, _ := createTestMeeting(t, db)
+	require.NoError(t, meetingService.AutoStartMeeting(meeting.ID))
+
+	wsService := NewWebSocketService(db, nil, nil)
+	webrtcService := NewWebRTCService(db, wsService)
+	defer webrtcService.Stop()
+	webrtcService.SetMeetingService(meetingService, 0)
+
+	// The first pass only notices that the room is empty
+	webrtcService.cleanupInactiveRooms()
+	require.NoError(t, db.First(meeting, meeting.ID).Error)
+	assert.Equal(t, models.MeetingStatusLive, meeting.Status)
+
+	webrtcService.cleanupInactiveRooms()
+	require.NoError(t, db.First(meeting, meeting.ID).Error)
+	assert.Equal(t, models.MeetingStatusEnded, meeting.Status)
+	assert.NotNil(t, meeting.EndedAt)
+}
@@ -0,0 +1,498 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// ModerationService implements host moderation: muting, removing and banning
+// participants and locking meetings. Every action is written to the
+// moderation log and broadcast to the room.
+type ModerationService struct {
+	db          *gorm.DB
+	wsService   *WebSocketService
+	roleService *RoleService
+}
+
+func NewModerationService(db *gorm.DB, wsService *WebSocketService, roleService *RoleService) *ModerationService {
+	return &ModerationService{
+		db:          db,
+		wsService:   wsService,
+		roleService: roleService,
+	}
+}
+
+// MuteParticipant asks a participant's clients to stop publishing audio
+// and/or video
+func (s *ModerationService) MuteParticipant(meetingID uuid.UUID, actorID uuid.UUID, participantID uuid.UUID, req *models.MuteParticipantRequest) (*models.ModerationLog, error) {
+	if !req.Audio && !req.Video {
+		return nil, errors.New("nothing to mute")
+	}
+
+	participant, err := s.getModeratedParticipant(meetingID, actorID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []string
+	if req.Audio {
+		tracks = append(tracks, "audio")
+	}
+	if req.Video {
+		tracks = append(tracks, "video")
+	}
+
+	entry := newParticipantLogEntry(participant, actorID, models.ModerationActionMute, "")
+	entry.Details = strings.Join(tracks, ",")
+	if err := s.db.Create(entry).Error; err != nil {
+		return nil, fmt.Errorf("failed to record moderation action: %w", err)
+	}
+
+	payload := participantPayload(entry)
+	payload.Audio = req.Audio
+	payload.Video = req.Video
+	s.broadcast(meetingID, models.SignalingTypeForceMute, payload)
+
+	return entry, nil
+}
+
+// MuteParticipantChat keeps a participant from writing in the meeting's
+// chat, for req.Duration seconds or until UnmuteParticipantChat
+func (s *ModerationService) MuteParticipantChat(meetingID uuid.UUID, actorID uuid.UUID, participantID uuid.UUID, req *models.ChatMuteRequest) (*models.ModerationLog, error) {
+	participant, err := s.getModeratedParticipant(meetingID, actorID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var until *time.Time
+	entry := newParticipantLogEntry(participant, actorID, models.ModerationActionChatMute, req.Reason)
+	if req.Duration > 0 {
+		end := time.Now().Add(time.Duration(req.Duration) * time.Second)
+		until = &end
+		entry.Details = "until " + end.UTC().Format(time.RFC3339)
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(participant).Updates(map[string]interface{}{
+			"is_chat_muted":    true,
+			"chat_muted_until": until,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to mute participant in chat: %w", err)
+	}
+
+	payload := participantPayload(entry)
+	payload.Until = until
+	s.broadcast(meetingID, models.SignalingTypeChatMuted, payload)
+	return entry, nil
+}
+
+// UnmuteParticipantChat lifts a participant's chat mute
+func (s *ModerationService) UnmuteParticipantChat(meetingID uuid.UUID, actorID uuid.UUID, participantID uuid.UUID) (*models.ModerationLog, error) {
+	participant, err := s.getModeratedParticipant(meetingID, actorID, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if !participant.ChatMuted(time.Now()) {
+		return nil, errors.New("participant not muted in chat")
+	}
+
+	entry := newParticipantLogEntry(participant, actorID, models.ModerationActionChatUnmute, "")
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(participant).Updates(map[string]interface{}{
+			"is_chat_muted":    false,
+			"chat_muted_until": nil,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmute participant in chat: %w", err)
+	}
+
+	s.broadcast(meetingID, models.SignalingTypeChatUnmuted, participantPayload(entry))
+	return entry, nil
+}
+
+// SetChatSlowMode makes participants wait seconds between two chat
+// messages; 0 turns slow mode off. Hosts and co-hosts are exempt.
+func (s *ModerationService) SetChatSlowMode(meetingID uuid.UUID, actorID uuid.UUID, seconds int) (*models.Meeting, error) {
+	if err := s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &actorID}, models.PermissionModerate); err != nil {
+		return nil, err
+	}
+	if seconds < 0 || seconds > models.MaxChatSlowMode {
+		return nil, errors.New("invalid slow mode")
+	}
+
+	entry := &models.ModerationLog{
+		MeetingID: meetingID,
+		ActorID:   actorID,
+		Action:    models.ModerationActionSlowMode,
+		Details:   fmt.Sprintf("%ds", seconds),
+	}
+	changed := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Conditional so that repeated calls neither log nor broadcast twice
+		result := tx.Model(&models.Meeting{}).
+			Where("id = ? AND chat_slow_mode <> ?", meetingID, seconds).
+			Update("chat_slow_mode", seconds)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		changed = true
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update slow mode: %w", err)
+	}
+
+	if changed {
+		s.broadcast(meetingID, models.SignalingTypeChatSlowMode, models.ModerationPayload{
+			Action:    models.ModerationActionSlowMode,
+			MeetingID: meetingID.String(),
+			ActorID:   actorID,
+			SlowMode:  &seconds,
+		})
+	}
+
+	var meeting models.Meeting
+	if err := s.db.Preload("Host").First(&meeting, "id = ?", meetingID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch updated meeting: %w", err)
+	}
+	return &meeting, nil
+}
+
+// RemoveParticipant disconnects a participant from the meeting. They may
+// rejoin unless the meeting is locked.
+func (s *ModerationService) RemoveParticipant(meetingID uuid.UUID, actorID uuid.UUID, participantID uuid.UUID, reason string) (*models.ModerationLog, error) {
+	participant, err := s.getModeratedParticipant(meetingID, actorID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := newParticipantLogEntry(participant, actorID, models.ModerationActionRemove, reason)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := deactivateParticipant(tx, participant); err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove participant: %w", err)
+	}
+
+	s.disconnectParticipant(participant, entry)
+	return entry, nil
+}
+
+// BanParticipant removes a participant and keeps their account or public
+// session from rejoining the meeting
+func (s *ModerationService) BanParticipant(meetingID uuid.UUID, actorID uuid.UUID, participantID uuid.UUID, reason string) (*models.ModerationLog, error) {
+	participant, err := s.getModeratedParticipant(meetingID, actorID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := newParticipantLogEntry(participant, actorID, models.ModerationActionBan, reason)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		banned, err := isBanned(tx, meetingID, models.MeetingActor{UserID: participant.UserID, PublicUserID: participant.PublicUserID})
+		if err != nil {
+			return err
+		}
+		if !banned {
+			ban := &models.MeetingBan{
+				MeetingID:    meetingID,
+				UserID:       participant.UserID,
+				PublicUserID: participant.PublicUserID,
+				Name:         participant.Name,
+				BannedByID:   actorID,
+				Reason:       reason,
+			}
+			if err := tx.Create(ban).Error; err != nil {
+				return err
+			}
+		}
+		if err := deactivateParticipant(tx, participant); err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to ban participant: %w", err)
+	}
+
+	s.disconnectParticipant(participant, entry)
+	return entry, nil
+}
+
+// UnbanParticipant lifts a ban
+func (s *ModerationService) UnbanParticipant(meetingID uuid.UUID, actorID uuid.UUID, banID uuid.UUID) error {
+	if err := s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &actorID}, models.PermissionModerate); err != nil {
+		return err
+	}
+
+	var ban models.MeetingBan
+	if err := s.db.Where("id = ? AND meeting_id = ?", banID, meetingID).First(&ban).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("ban not found")
+		}
+		return fmt.Errorf("failed to fetch ban: %w", err)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&ban).Error; err != nil {
+			return fmt.Errorf("failed to lift ban: %w", err)
+		}
+		entry := &models.ModerationLog{
+			MeetingID:          meetingID,
+			ActorID:            actorID,
+			Action:             models.ModerationActionUnban,
+			TargetUserID:       ban.UserID,
+			TargetPublicUserID: ban.PublicUserID,
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to record moderation action: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetBans returns the bans of a meeting
+func (s *ModerationService) GetBans(meetingID uuid.UUID, actorID uuid.UUID) ([]models.MeetingBan, error) {
+	if err := s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &actorID}, models.PermissionModerate); err != nil {
+		return nil, err
+	}
+
+	var bans []models.MeetingBan
+	if err := s.db.Where("meeting_id = ?", meetingID).Order("created_at DESC").Find(&bans).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch bans: %w", err)
+	}
+	return bans, nil
+}
+
+// GetModerationLog returns the most recent moderation actions of a meeting
+func (s *ModerationService) GetModerationLog(meetingID uuid.UUID, actorID uuid.UUID, limit int) ([]models.ModerationLog, error) {
+	if err := s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &actorID}, models.PermissionModerate); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	var entries []models.ModerationLog
+	if err := s.db.Where("meeting_id = ?", meetingID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch moderation log: %w", err)
+	}
+	return entries, nil
+}
+
+// LockMeeting refuses new participants until the meeting is unlocked
+func (s *ModerationService) LockMeeting(meetingID uuid.UUID, actorID uuid.UUID) (*models.Meeting, error) {
+	return s.setLocked(meetingID, actorID, true)
+}
+
+// UnlockMeeting lets new participants join again
+func (s *ModerationService) UnlockMeeting(meetingID uuid.UUID, actorID uuid.UUID) (*models.Meeting, error) {
+	return s.setLocked(meetingID, actorID, false)
+}
+
+func (s *ModerationService) setLocked(meetingID uuid.UUID, actorID uuid.UUID, locked bool) (*models.Meeting, error) {
+	if err := s.roleService.Authorize(meetingID, models.MeetingActor{UserID: &actorID}, models.PermissionModerate); err != nil {
+		return nil, err
+	}
+
+	action := models.ModerationActionUnlock
+	messageType := models.SignalingTypeMeetingUnlocked
+	updates := map[string]interface{}{"is_locked": false, "locked_at": nil}
+	if locked {
+		action = models.ModerationActionLock
+		messageType = models.SignalingTypeMeetingLocked
+		updates = map[string]interface{}{"is_locked": true, "locked_at": time.Now()}
+	}
+
+	entry := &models.ModerationLog{MeetingID: meetingID, ActorID: actorID, Action: action}
+	changed := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Conditional so that repeated calls neither log nor broadcast twice
+		result := tx.Model(&models.Meeting{}).
+			Where("id = ? AND is_locked = ?", meetingID, !locked).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		changed = true
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update meeting lock: %w", err)
+	}
+
+	if changed {
+		s.broadcast(meetingID, messageType, models.ModerationPayload{
+			Action:    action,
+			MeetingID: meetingID.String(),
+			ActorID:   actorID,
+		})
+	}
+
+	var meeting models.Meeting
+	if err := s.db.Preload("Host").First(&meeting, "id = ?", meetingID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch updated meeting: %w", err)
+	}
+	return &meeting, nil
+}
+
+// getModeratedParticipant loads the target of a moderation action after
+// checking that the actor may moderate it. Participants can only be
+// moderated by someone holding a higher role.
+func (s *ModerationService) getModeratedParticipant(meetingID uuid.UUID, actorID uuid.UUID, participantID uuid.UUID) (*models.Participant, error) {
+	actorRole, err := s.roleService.GetRole(meetingID, models.MeetingActor{UserID: &actorID})
+	if err != nil {
+		return nil, err
+	}
+	if !actorRole.Can(models.PermissionModerate) {
+		return nil, errors.New("permission denied")
+	}
+
+	var participant models.Participant
+	if err := s.db.Where("id = ? AND meeting_id = ?", participantID, meetingID).First(&participant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("participant not found")
+		}
+		return nil, fmt.Errorf("failed to fetch participant: %w", err)
+	}
+
+	if participant.UserID != nil && *participant.UserID == actorID {
+		return nil, errors.New("cannot moderate yourself")
+	}
+	if !actorRole.Outranks(participant.Role) {
+		return nil, errors.New("permission denied")
+	}
+
+	return &participant, nil
+}
+
+// disconnectParticipant tells the room; the node holding the participant's
+// WebSocket closes it when the message is delivered, which drops its WebRTC
+// peer as well
+func (s *ModerationService) disconnectParticipant(participant *models.Participant, entry *models.ModerationLog) {
+	s.broadcast(participant.MeetingID, models.SignalingTypeParticipantRemoved, participantPayload(entry))
+}
+
+func (s *ModerationService) broadcast(meetingID uuid.UUID, messageType models.SignalingMessageType, payload models.ModerationPayload) {
+	if s.wsService == nil {
+		return
+	}
+
+	s.wsService.SendMessageToMeeting(meetingID.String(), models.SignalingMessage{
+		Type: messageType,
+		Data: payload,
+	})
+}
+
+func newParticipantLogEntry(participant *models.Participant, actorID uuid.UUID, action models.ModerationAction, reason string) *models.ModerationLog {
+	return &models.ModerationLog{
+		MeetingID:           participant.MeetingID,
+		ActorID:             actorID,
+		Action:              action,
+		TargetParticipantID: &participant.ID,
+		TargetUserID:        participant.UserID,
+		TargetPublicUserID:  participant.PublicUserID,
+		Reason:              reason,
+	}
+}
+
+func participantPayload(entry *models.ModerationLog) models.ModerationPayload {
+	return models.ModerationPayload{
+		Action:        entry.Action,
+		MeetingID:     entry.MeetingID.String(),
+		ParticipantID: entry.TargetParticipantID,
+		UserID:        entry.TargetUserID,
+		PublicUserID:  entry.TargetPublicUserID,
+		Reason:        entry.Reason,
+		ActorID:       entry.ActorID,
+	}
+}
+
+func deactivateParticipant(tx *gorm.DB, participant *models.Participant) error {
+	return tx.Model(participant).Updates(map[string]interface{}{
+		"is_active": false,
+		"left_at":   time.Now(),
+	}).Error
+}
+
+// isBanned reports whether the user or public user is banned from a meeting
+func isBanned(db *gorm.DB, meetingID uuid.UUID, actor models.MeetingActor) (bool, error) {
+	query := db.Model(&models.MeetingBan{}).Where("meeting_id = ?", meetingID)
+	switch {
+	case actor.UserID != nil:
+		query = query.Where("user_id = ?", *actor.UserID)
+	case actor.PublicUserID != nil:
+		query = query.Where("public_user_id = ?", *actor.PublicUserID)
+	default:
+		return false, nil
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check meeting bans: %w", err)
+	}
+	return count > 0, nil
+}
+
+// checkMeetingAdmission decides whether someone may enter a meeting. The host
+// is always admitted; banned users never are, and a locked meeting only
+// readmits participants that are still active in it.
+func checkMeetingAdmission(db *gorm.DB, meeting *models.Meeting, actor models.MeetingActor) error {
+	if actor.UserID != nil && *actor.UserID == meeting.HostID {
+		return nil
+	}
+
+	banned, err := isBanned(db, meeting.ID, actor)
+	if err != nil {
+		return err
+	}
+	if banned {
+		return errors.New("banned from meeting")
+	}
+
+	if !meeting.IsLocked {
+		return nil
+	}
+
+	query := participantQuery(db, meeting.ID, actor)
+	if query == nil {
+		return errors.New("meeting is locked")
+	}
+
+	var count int64
+	if err := query.Where("is_active = ?", true).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check participant: %w", err)
+	}
+	if count == 0 {
+		return errors.New("meeting is locked")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func setupTestModerationDB(t *testing.T) *gorm.DB {
+	return setupTestDB(t, &models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{},
+		&models.MeetingBan{}, &models.ModerationLog{})
+}
+
+func newTestModerationService(db *gorm.DB) (*ModerationService, *MeetingService) {
+	roleService := NewRoleService(db, nil)
+	return NewModerationService(db, nil, roleService), NewMeetingService(db, nil, roleService)
+}
+
+func TestModerationService_RemoveAndBan(t *testing.T) {
+	db := setupTestModerationDB(t)
+	moderationService, meetingService := newTestModerationService(db)
+	meeting, hostID := createTestMeeting(t, db)
+	attendee := addTestParticipant(t, db, meeting.ID, "attendee", models.RoleAttendee)
+
+	entry, err := moderationService.RemoveParticipant(meeting.ID, hostID, attendee.ID, "spam")
+	require.NoError(t, err)
+	assert.Equal(t, models.ModerationActionRemove, entry.Action)
+	assert.Equal(t, attendee.UserID, entry.TargetUserID)
+
+	var stored models.Participant
+	require.NoError(t, db.First(&stored, attendee.ID).Error)
+	assert.False(t, stored.IsActive)
+	assert.NotNil(t, stored.LeftAt)
+
+	// A removed participant may come back
+	_, err = meetingService.JoinMeeting(*attendee.UserID, meeting.ID, models.MeetingCredentials{})
+	require.NoError(t, err)
+
+	_, err = moderationService.BanParticipant(meeting.ID, hostID, attendee.ID, "spam again")
+	require.NoError(t, err)
+	_, err = meetingService.JoinMeeting(*attendee.UserID, meeting.ID, models.MeetingCredentials{})
+	assert.EqualError(t, err, "banned from meeting")
+
+	bans, err := moderationService.GetBans(meeting.ID, hostID)
+	require.NoError(t, err)
+	require.Len(t, bans, 1)
+
+	require.NoError(t, moderationService.UnbanParticipant(meeting.ID, hostID, bans[0].ID))
+	_, err = meetingService.JoinMeeting(*attendee.UserID, meeting.ID, models.MeetingCredentials{})
+	assert.NoError(t, err)
+
+	entries, err := moderationService.GetModerationLog(meeting.ID, hostID, 0)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func TestModerationService_BanPublicUser(t *testing.T) {
+	db := setupTestModerationDB(t)
+	moderationService, _ := newTestModerationService(db)
+	publicUserService := NewPublicUserService(db)
+	meeting, hostID := createTestMeeting(t, db)
+
+	guest, err := publicUserService.CreatePublicUser(&models.CreatePublicUserRequest{Name: "Guest", SessionID: "guest-session"})
+	require.NoError(t, err)
+	participant, err := publicUserService.JoinMeetingAsPublicUser(guest.SessionID, meeting.ID, models.MeetingCredentials{})
+	require.NoError(t, err)
+
+	_, err = moderationService.BanParticipant(meeting.ID, hostID, participant.ID, "")
+	require.NoError(t, err)
+
+	_, err = publicUserService.JoinMeetingAsPublicUser(guest.SessionID, meeting.ID, models.MeetingCredentials{})
+	assert.EqualError(t, err, "banned from meeting")
+}
+
+func TestModerationService_LockMeeting(t *testing.T) {
+	db := setupTestModerationDB(t)
+	moderationService, meetingService := newTestModerationService(db)
+	meeting, hostID := createTestMeeting(t, db)
+	attendee := addTestParticipant(t, db, meeting.ID, "attendee", models.RoleAttendee)
+	newcomer := &models.User{Username: "newcomer", Email: "newcomer@example.com", PasswordHash: "x"}
+	require.NoError(t, db.Create(newcomer).Error)
+
+	locked, err := moderationService.LockMeeting(meeting.ID, hostID)
+	require.NoError(t, err)
+	assert.True(t, locked.IsLocked)
+	require.NotNil(t, locked.LockedAt)
+
+	// Locking twice is a no-op
+	_, err = moderationService.LockMeeting(meeting.ID, hostID)
+	require.NoError(t, err)
+
+	_, err = meetingService.JoinMeeting(newcomer.ID, meeting.ID, models.MeetingCredentials{})
+	assert.EqualError(t, err, "meeting is locked")
+	_, err = meetingService.JoinMeeting(*attendee.UserID, meeting.ID, models.MeetingCredentials{})
+	assert.NoError(t, err, "active participants may reconnect")
+	_, err = meetingService.JoinMeeting(hostID, meeting.ID, models.MeetingCredentials{})
+	assert.NoError(t, err, "the host is always admitted")
+
+	unlocked, err := moderationService.UnlockMeeting(meeting.ID, hostID)
+	require.NoError(t, err)
+	assert.False(t, unlocked.IsLocked)
+	_, err = meetingService.JoinMeeting(newcomer.ID, meeting.ID, models.MeetingCredentials{})
+	assert.NoError(t, err)
+
+	entries, err := moderationService.GetModerationLog(meeting.ID, hostID, 0)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestModerationService_Permissions(t *testing.T) {
+	db := setupTestModerationDB(t)
+	moderationService, _ := newTestModerationService(db)
+	meeting, hostID := createTestMeeting(t, db)
+	host := &models.Participant{MeetingID: meeting.ID, UserID: &hostID, Name: "host", Role: models.RoleHost, IsActive: true}
+	require.NoError(t, db.Create(host).Error)
+	coHost := addTestParticipant(t, db, meeting.ID, "cohost", models.RoleCoHost)
+	otherCoHost := addTestParticipant(t, db, meeting.ID, "cohost2", models.RoleCoHost)
+	attendee := addTestParticipant(t, db, meeting.ID, "attendee", models.RoleAttendee)
+
+	_, err := moderationService.MuteParticipant(meeting.ID, *coHost.UserID, attendee.ID, &models.MuteParticipantRequest{Audio: true})
+	require.NoError(t, err)
+
+	_, err = moderationService.MuteParticipant(meeting.ID, *coHost.UserID, attendee.ID, &models.MuteParticipantRequest{})
+	assert.EqualError(t, err, "nothing to mute")
+
+	_, err = moderationService.RemoveParticipant(meeting.ID, *coHost.UserID, host.ID, "")
+	assert.EqualError(t, err, "permission denied")
+	_, err = moderationService.RemoveParticipant(meeting.ID, *coHost.UserID, otherCoHost.ID, "")
+	assert.EqualError(t, err, "permission denied")
+	_, err = moderationService.RemoveParticipant(meeting.ID, *attendee.UserID, coHost.ID, "")
+	assert.EqualError(t, err, "permission denied")
+	_, err = moderationService.RemoveParticipant(meeting.ID, *coHost.UserID, coHost.ID, "")
+	assert.EqualError(t, err, "cannot moderate yourself")
+
+	_, err = moderationService.LockMeeting(meeting.ID, *attendee.UserID)
+	assert.EqualError(t, err, "permission denied")
+
+	// The host can act on co-hosts
+	_, err = moderationService.RemoveParticipant(meeting.ID, hostID, coHost.ID, "")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// peerKey identifies a peer of a meeting
+type peerKey struct {
+	meetingID string
+	peerID    string
+}
+
+// pairKey identifies a pair of peers of a meeting, in either order
+type pairKey struct {
+	meetingID string
+	first     string
+	second    string
+}
+
+func newPairKey(meetingID, a, b string) pairKey {
+	if a > b {
+		a, b = b, a
+	}
+	return pairKey{meetingID: meetingID, first: a, second: b}
+}
+
+// NegotiationCoordinator recovers peer connections that break. Once a peer
+// has been disconnected or failed for the restart timeout, it asks the
+// impolite side of each of the peer's pairs to restart ICE, again after
+// every timeout the peer stays down, and gives up on a pair after
+// maxAttempts restarts, telling both of its peers.
+type NegotiationCoordinator struct {
+	wsService   *WebSocketService
+	timeout     time.Duration
+	maxAttempts int
+
+	mu       sync.Mutex
+	timers   map[peerKey]*time.Timer // Peers waiting out the restart timeout
+	attempts map[pairKey]int         // ICE restarts requested per pair since it last connected; above maxAttempts once given up
+	stopped  bool
+}
+
+func NewNegotiationCoordinator(wsService *WebSocketService, timeout time.Duration, maxAttempts int) *NegotiationCoordinator {
+	return &NegotiationCoordinator{
+		wsService:   wsService,
+		timeout:     timeout,
+		maxAttempts: maxAttempts,
+		timers:      make(map[peerKey]*time.Timer),
+		attempts:    make(map[pairKey]int),
+	}
+}
+
+// PeerStateChanged starts the restart timeout of a peer that went
+// disconnected or failed, and forgets the attempts of one that connected or
+// closed its peer connection
+func (c *NegotiationCoordinator) PeerStateChanged(meetingID, peerID string, state models.PeerConnectionState) {
+	key := peerKey{meetingID: meetingID, peerID: peerID}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch state {
+	case models.PeerStateDisconnected, models.PeerStateFailed:
+		if _, waiting := c.timers[key]; !waiting && !c.stopped {
+			c.timers[key] = time.AfterFunc(c.timeout, func() { c.recoverPeer(key) })
+		}
+	case models.PeerStateConnected, models.PeerStateClosed:
+		c.forgetPeerLocked(key)
+	}
+}
+
+// Stop cancels the pending restart timeouts
+func (c *NegotiationCoordinator) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stopped = true
+	for key, timer := range c.timers {
+		timer.Stop()
+		delete(c.timers, key)
+	}
+}
+
+// forgetPeerLocked cancels a peer's restart timeout and resets the attempts
+// of its pairs. The caller must hold c.mu.
+func (c *NegotiationCoordinator) forgetPeerLocked(key peerKey) {
+	if timer, ok := c.timers[key]; ok {
+		timer.Stop()
+		delete(c.timers, key)
+	}
+	for pair := range c.attempts {
+		if pair.meetingID == key.meetingID && (pair.first == key.peerID || pair.second == key.peerID) {
+			delete(c.attempts, pair)
+		}
+	}
+}
+
+type iceRestart struct {
+	target  string
+	peerID  string
+	attempt int
+}
+
+// recoverPeer runs when a peer's restart timeout expires. While the peer is
+// still down, it requests an ICE restart for each of its pairs with a peer
+// connection, or gives up on those out of attempts.
+func (c *NegotiationCoordinator) recoverPeer(key peerKey) {
+	presences := c.wsService.GetMeetingParticipants(key.meetingID)
+	var self *models.HubPresence
+	for i := range presences {
+		if presences[i].ClientID == key.peerID {
+			self = &presences[i]
+			break
+		}
+	}
+
+	c.mu.Lock()
+	delete(c.timers, key)
+	if c.stopped {
+		c.mu.Unlock()
+		return
+	}
+	// The peer left, or recovered without saying so
+	if self == nil {
+		c.forgetPeerLocked(key)
+		c.mu.Unlock()
+		return
+	}
+	if state := self.ToPeer().State; state != models.PeerStateDisconnected && state != models.PeerStateFailed {
+		c.mu.Unlock()
+		return
+	}
+
+	var restarts []iceRestart
+	var failed []string
+	deferred := false
+	for _, other := range presences {
+		if other.ClientID == self.ClientID {
+			continue
+		}
+		// Peers without a peer connection have nothing to recover
+		state := other.ToPeer().State
+		if state == models.PeerStateNew || state == models.PeerStateClosed {
+			continue
+		}
+		// When both peers are down, the restart timeout of the one that
+		// restarts counts the attempt, so each timeout counts once per pair
+		if (state == models.PeerStateDisconnected || state == models.PeerStateFailed) && self.IsPoliteTo(other) {
+			deferred = true
+			continue
+		}
+
+		pair := newPairKey(key.meetingID, self.ClientID, other.ClientID)
+		if c.attempts[pair] > c.maxAttempts {
+			continue
+		}
+		c.attempts[pair]++
+		if c.attempts[pair] > c.maxAttempts {
+			failed = append(failed, other.ClientID)
+			continue
+		}
+
+		// The impolite peer restarts, so the restart offer never collides
+		// with one from the other side
+		restart := iceRestart{target: self.ClientID, peerID: other.ClientID, attempt: c.attempts[pair]}
+		if self.IsPoliteTo(other) {
+			restart.target, restart.peerID = other.ClientID, self.ClientID
+		}
+		restarts = append(restarts, restart)
+	}
+	if len(restarts) > 0 || deferred {
+		c.timers[key] = time.AfterFunc(c.timeout, func() { c.recoverPeer(key) })
+	}
+	c.mu.Unlock()
+
+	for _, restart := range restarts {
+		c.send(key.meetingID, restart.target, models.SignalingTypeICERestartRequested, models.ICERestartRequestPayload{
+			PeerID:      restart.peerID,
+			Attempt:     restart.attempt,
+			MaxAttempts: c.maxAttempts,
+		})
+	}
+	for _, otherID := range failed {
+		log.Printf("Giving up on the connection between peers %s and %s in meeting %s", self.ClientID, otherID, key.meetingID)
+		for _, side := range [][2]string{{self.ClientID, otherID}, {otherID, self.ClientID}} {
+			c.send(key.meetingID, side[0], models.SignalingTypeNegotiationFailed, models.NegotiationFailedPayload{
+				PeerID:   side[1],
+				Attempts: c.maxAttempts,
+				Reason:   "ICE restart limit reached",
+			})
+		}
+	}
+}
+
+func (c *NegotiationCoordinator) send(meetingID, clientID string, messageType models.SignalingMessageType, payload interface{}) {
+	message := models.SignalingMessage{
+		Type:      messageType,
+		MeetingID: meetingID,
+		To:        clientID,
+		Data:      payload,
+	}
+	if err := c.wsService.SendMessageToClient(clientID, message); err != nil {
+		log.Printf("[DEBUG] Failed to send %s to peer %s: %v", messageType, clientID, err)
+	}
+}
+
+// markPolite tells the receiver of an offer or answer whether it is the
+// polite peer of the pair, so that it knows which offer gives way when both
+// peers make one at once. Roles are left out when either peer is gone; the
+// signal then fails to route anyway.
+func (s *WebSocketService) markPolite(message *models.SignalingMessage) error {
+	var sender, receiver *models.HubPresence
+	presences := s.hub.GetMeetingParticipants(message.MeetingID)
+	for i := range presences {
+		switch presences[i].ClientID {
+		case message.From:
+			sender = &presences[i]
+		case message.To:
+			receiver = &presences[i]
+		}
+	}
+	if sender == nil || receiver == nil {
+		return nil
+	}
+
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		return fmt.Errorf("%w: unreadable payload", ErrInvalidSDP)
+	}
+	var payload models.OfferAnswerPayload
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return fmt.Errorf("%w: unreadable payload", ErrInvalidSDP)
+	}
+	polite := receiver.IsPoliteTo(*sender)
+	payload.Polite = &polite
+	message.Data = payload
+	return nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestWebRTCService_ICERestarts(t *testing.T) {
+	db := setupTestMeetingDB(t)
+	meeting, _ := createTestMeeting(t, db)
+	meetingID := meeting.ID.String()
+
+	wsService := NewWebSocketService(db, nil, nil)
+	wsService.StartHub()
+	service := NewWebRTCService(db, wsService)
+	service.SetICERestartPolicy(20*time.Millisecond, 2)
+	defer service.Stop()
+
+	joinedAt := time.Now()
+	connect := func(clientID string, offset time.Duration) *models.WebSocketClient {
+		client := &models.WebSocketClient{
+			ID:        clientID,
+			MeetingID: meetingID,
+			Name:      clientID,
+			JoinedAt:  joinedAt.Add(offset),
+			Send:      make(chan models.SignalingMessage, 64),
+			Hub:       wsService.hub,
+		}
+		wsService.hub.Register <- client
+		return client
+	}
+	alice := connect("alice", 0)
+	bob := connect("bob", time.Second)
+	require.Eventually(t, func() bool { return len(service.GetMeetingPeers(meetingID)) == 2 }, time.Second, 10*time.Millisecond)
+
+	// Bob joined later, so he is the polite peer and Alice is told she is not
+	require.NoError(t, service.SendOffer(meetingID, "alice", "bob", models.OfferAnswerPayload{SDP: "v=0"}))
+	offer := waitForMessage(t, bob, models.SignalingTypeOffer).Data.(models.OfferAnswerPayload)
+	require.NotNil(t, offer.Polite)
+	assert.True(t, *offer.Polite)
+	require.NoError(t, service.SendAnswer(meetingID, "bob", "alice", models.OfferAnswerPayload{SDP: "v=0"}))
+	answer := waitForMessage(t, alice, models.SignalingTypeAnswer).Data.(models.OfferAnswerPayload)
+	require.NotNil(t, answer.Polite)
+	assert.False(t, *answer.Polite)
+
+	require.NoError(t, service.UpdatePeerState(meetingID, "alice", models.PeerStateConnected))
+	require.NoError(t, service.UpdatePeerState(meetingID, "bob", models.PeerStateConnected))
+
+	// Bob dropping makes Alice, the impolite side, restart ICE on every
+	// timeout until the pair runs out of attempts
+	require.NoError(t, service.UpdatePeerState(meetingID, "bob", models.PeerStateDisconnected))
+	for attempt := 1; attempt <= 2; attempt++ {
+		restart := waitForMessage(t, alice, models.SignalingTypeICERestartRequested).Data.(models.ICERestartRequestPayload)
+		assert.Equal(t, models.ICERestartRequestPayload{PeerID: "bob", Attempt: attempt, MaxAttempts: 2}, restart)
+	}
+	failure := waitForMessage(t, alice, models.SignalingTypeNegotiationFailed).Data.(models.NegotiationFailedPayload)
+	assert.Equal(t, "bob", failure.PeerID)
+	failure = waitForMessage(t, bob, models.SignalingTypeNegotiationFailed).Data.(models.NegotiationFailedPayload)
+	assert.Equal(t, "alice", failure.PeerID)
+	assert.Equal(t, 2, failure.Attempts)
+
+	// Reconnecting starts the count over
+	require.NoError(t, service.UpdatePeerState(meetingID, "bob", models.PeerStateConnected))
+	require.NoError(t, service.UpdatePeerState(meetingID, "bob", models.PeerStateFailed))
+	restart := waitForMessage(t, alice, models.SignalingTypeICERestartRequested).Data.(models.ICERestartRequestPayload)
+	assert.Equal(t, 1, restart.Attempt)
+
+	// A peer that recovers before the timeout needs no restart
+	require.NoError(t, service.UpdatePeerState(meetingID, "bob", models.PeerStateConnected))
+	require.NoError(t, service.UpdatePeerState(meetingID, "alice", models.PeerStateDisconnected))
+	require.NoError(t, service.UpdatePeerState(meetingID, "alice", models.PeerStateConnected))
+	time.Sleep(50 * time.Millisecond)
+	for len(alice.Send) > 0 {
+		assert.NotEqual(t, models.SignalingTypeICERestartRequested, (<-alice.Send).Type)
+	}
+
+	// With both peers down, each timeout still counts one attempt for the pair
+	require.NoError(t, service.UpdatePeerState(meetingID, "alice", models.PeerStateDisconnected))
+	require.NoError(t, service.UpdatePeerState(meetingID, "bob", models.PeerStateDisconnected))
+	restart = waitForMessage(t, alice, models.SignalingTypeICERestartRequested).Data.(models.ICERestartRequestPayload)
+	assert.Equal(t, 1, restart.Attempt)
+	time.Sleep(5 * time.Millisecond)
+	for len(alice.Send) > 0 {
+		assert.NotEqual(t, models.SignalingTypeICERestartRequested, (<-alice.Send).Type)
+	}
+	restart = waitForMessage(t, alice, models.SignalingTypeICERestartRequested).Data.(models.ICERestartRequestPayload)
+	assert.Equal(t, 2, restart.Attempt)
+}
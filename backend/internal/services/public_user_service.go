@@ -13,7 +13,9 @@ import (
 )
 
 type PublicUserService struct {
-	db *gorm.DB
+	db           *gorm.DB
+	lobbyService *LobbyService
+	accessService *MeetingAccessService
 }
 
 func NewPublicUserService(db *gorm.DB) *PublicUserService {
@@ -22,6 +24,16 @@ func NewPublicUserService(db *gorm.DB) *PublicUserService {
 	}
 }
 
+// SetLobbyService sets the lobby service used to hold guests for admission
+func (s *PublicUserService) SetLobbyService(lobbyService *LobbyService) {
+	s.lobbyService = lobbyService
+}
+
+// SetAccessService sets the service that checks passcodes and invites on join
+func (s *PublicUserService) SetAccessService(accessService *MeetingAccessService) {
+	s.accessService = accessService
+}
+
 func (s *PublicUserService) CreatePublicUser(req *models.CreatePublicUserRequest) (*models.PublicUser, error) {
 	// Check if public user with this session ID already exists
 	var existingUser models.PublicUser
@@ -56,7 +68,7 @@ func (s *PublicUserService) GetPublicUserBySessionID(sessionID string) (*models.
 	return &publicUser, nil
 }
 
-func (s *PublicUserService) JoinMeetingAsPublicUser(sessionID string, meetingID uuid.UUID) (*models.Participant, error) {
+func (s *PublicUserService) JoinMeetingAsPublicUser(sessionID string, meetingID uuid.UUID, creds models.MeetingCredentials) (*models.Participant, error) {
 	// Get public user
 	publicUser, err := s.GetPublicUserBySessionID(sessionID)
 	if err != nil {
@@ -72,6 +84,33 @@ func (s *PublicUserService) JoinMeetingAsPublicUser(sessionID string, meetingID
 		return nil, fmt.Errorf("failed to fetch meeting: %w", err)
 	}
 
+	// Refuse banned sessions and newcomers to locked meetings
+	actor := models.MeetingActor{PublicUserID: &publicUser.ID}
+	if err := checkMeetingAdmission(s.db, &meeting, actor); err != nil {
+		return nil, err
+	}
+
+	// Protected meetings need the passcode or an invite
+	var invite *models.MeetingInvite
+	if s.accessService != nil {
+		var err error
+		if invite, err = s.accessService.CheckAccess(&meeting, actor, creds); err != nil {
+			return nil, err
+		}
+	}
+
+	// Newcomers may have to wait in the lobby until a host admits them.
+	// Invited guests were let in by the host already.
+	if s.lobbyService != nil && invite == nil {
+		held, err := s.lobbyService.RequiresLobby(&meeting, actor)
+		if err != nil {
+			return nil, err
+		}
+		if held {
+			return s.lobbyService.Hold(&meeting, actor)
+		}
+	}
+
 	// Check if public user is already a participant
 	var existingParticipant models.Participant
 	if err := s.db.Where("meeting_id = ? AND public_user_id = ?", meetingID, publicUser.ID).First(&existingParticipant).Error; err == nil {
@@ -95,6 +134,9 @@ func (s *PublicUserService) JoinMeetingAsPublicUser(sessionID string, meetingID
 		Name:         publicUser.Name,
 		IsActive:     true,
 	}
+	if invite != nil {
+		participant.Role = invite.Role
+	}
 
 	if err := s.db.Create(participant).Error; err != nil {
 		return nil, fmt.Errorf("failed to join meeting: %w", err)
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+const (
+	// defaultQualityWindow is how far back a meeting's quality summary looks
+	defaultQualityWindow = 5 * time.Minute
+	// defaultPoorConnectionWindow is how far back the poor connection query
+	// looks, short enough to only report peers struggling now
+	defaultPoorConnectionWindow = time.Minute
+	// maxQualitySamples bounds the samples returned for a peer at once
+	maxQualitySamples = 1000
+)
+
+// QualityService stores the connection quality stats clients report for
+// their WebRTC peers, as a time series per peer, and scores meetings from
+// them. Samples are kept for the retention period; a peer may submit one
+// every minInterval at most.
+type QualityService struct {
+	db          *gorm.DB
+	roleService *RoleService
+	retention   time.Duration
+	minInterval time.Duration
+
+	mu         sync.Mutex
+	lastSample map[peerKey]time.Time
+}
+
+func NewQualityService(db *gorm.DB, roleService *RoleService, cfg config.WebRTCConfig) *QualityService {
+	return &QualityService{
+		db:          db,
+		roleService: roleService,
+		retention:   cfg.StatsRetention,
+		minInterval: cfg.StatsMinInterval,
+		lastSample:  make(map[peerKey]time.Time),
+	}
+}
+
+// RecordSample stores the stats a peer submitted, with their score
+func (s *QualityService) RecordSample(peer *models.WebRTCPeer, stats *models.QualityStatsPayload) (*models.QualitySample, error) {
+	if err := stats.Validate(); err != nil {
+		return nil, err
+	}
+	meetingID, err := uuid.Parse(peer.MeetingID)
+	if err != nil {
+		return nil, errors.New("meeting not found")
+	}
+
+	now := time.Now()
+	key := peerKey{meetingID: peer.MeetingID, peerID: peer.ID}
+	s.mu.Lock()
+	if last, ok := s.lastSample[key]; ok && now.Sub(last) < s.minInterval {
+		s.mu.Unlock()
+		return nil, errors.New("stats submitted too often")
+	}
+	s.lastSample[key] = now
+	s.mu.Unlock()
+
+	sample := &models.QualitySample{
+		MeetingID:     meetingID,
+		PeerID:        peer.ID,
+		UserID:        peer.UserID,
+		PublicUserID:  peer.PublicUserID,
+		Name:          peer.Name,
+		RTT:           stats.RTT,
+		Jitter:        stats.Jitter,
+		PacketLoss:    stats.PacketLoss,
+		Bitrate:       stats.Bitrate,
+		FrameRate:     stats.FrameRate,
+		CandidateType: stats.CandidateType,
+		Score:         stats.Score(),
+		CreatedAt:     now,
+	}
+	if err := s.db.Create(sample).Error; err != nil {
+		return nil, fmt.Errorf("failed to save quality sample: %w", err)
+	}
+	return sample, nil
+}
+
+// GetMeetingQuality summarizes the samples of a meeting's peers over the
+// last window, worst peer first. Only hosts and co-hosts may see it.
+func (s *QualityService) GetMeetingQuality(meetingID uuid.UUID, actor models.MeetingActor, window time.Duration) (*models.MeetingQualityResponse, error) {
+	if err := s.roleService.Authorize(meetingID, actor, models.PermissionManageMeeting); err != nil {
+		return nil, err
+	}
+
+	window = s.clampWindow(window, defaultQualityWindow)
+	peers, err := s.summarize(meetingID, window)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.MeetingQualityResponse{
+		MeetingID:     meetingID,
+		Score:         meetingScore(peers),
+		WindowSeconds: int(window / time.Second),
+		Peers:         peers,
+	}
+	for _, peer := range peers {
+		if peer.Score < models.PoorQualityScore {
+			response.PoorCount++
+		}
+	}
+	return response, nil
+}
+
+// GetPoorConnections returns the peers of a meeting whose average score
+// over the last window is below threshold, worst first. Only hosts and
+// co-hosts may see them.
+func (s *QualityService) GetPoorConnections(meetingID uuid.UUID, actor models.MeetingActor, window time.Duration, threshold int) ([]models.PeerQualitySummary, error) {
+	if err := s.roleService.Authorize(meetingID, actor, models.PermissionManageMeeting); err != nil {
+		return nil, err
+	}
+
+	peers, err := s.summarize(meetingID, s.clampWindow(window, defaultPoorConnectionWindow))
+	if err != nil {
+		return nil, err
+	}
+	poor := make([]models.PeerQualitySummary, 0)
+	for _, peer := range peers {
+		if peer.Score < threshold {
+			poor = append(poor, peer)
+		}
+	}
+	return poor, nil
+}
+
+// GetPeerSamples returns a peer's samples taken after since, oldest first.
+// Only hosts and co-hosts may see them.
+func (s *QualityService) GetPeerSamples(meetingID uuid.UUID, actor models.MeetingActor, peerID string, since time.Time, limit int) ([]models.QualitySample, error) {
+	if err := s.roleService.Authorize(meetingID, actor, models.PermissionManageMeeting); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxQualitySamples {
+		limit = maxQualitySamples
+	}
+
+	var samples []models.QualitySample
+	if err := s.db.Where("meeting_id = ? AND peer_id = ? AND created_at > ?", meetingID, peerID, since).
+		Order("created_at ASC").Limit(limit).Find(&samples).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch quality samples: %w", err)
+	}
+	return samples, nil
+}
+
+// MeetingScore returns a meeting's score over the last few minutes, or nil
+// when no peer submitted stats in that time
+func (s *QualityService) MeetingScore(meetingID string) *int {
+	id, err := uuid.Parse(meetingID)
+	if err != nil {
+		return nil
+	}
+	peers, err := s.summarize(id, s.clampWindow(0, defaultQualityWindow))
+	if err != nil {
+		return nil
+	}
+	return meetingScore(peers)
+}
+
+// PruneSamples deletes the samples older than the retention period
+func (s *QualityService) PruneSamples() (int64, error) {
+	now := time.Now()
+	result := s.db.Where("created_at < ?", now.Add(-s.retention)).Delete(&models.QualitySample{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to prune quality samples: %w", result.Error)
+	}
+
+	s.mu.Lock()
+	for key, last := range s.lastSample {
+		if now.Sub(last) >= s.minInterval {
+			delete(s.lastSample, key)
+		}
+	}
+	s.mu.Unlock()
+	return result.RowsAffected, nil
+}
+
+// clampWindow defaults a missing window and keeps it within retention
+func (s *QualityService) clampWindow(window, fallback time.Duration) time.Duration {
+	if window <= 0 {
+		window = fallback
+	}
+	if s.retention > 0 && window > s.retention {
+		window = s.retention
+	}
+	return window
+}
+
+// summarize averages each peer's samples over the last window, worst peer
+// first
+func (s *QualityService) summarize(meetingID uuid.UUID, window time.Duration) ([]models.PeerQualitySummary, error) {
+	var samples []models.QualitySample
+	if err := s.db.Where("meeting_id = ? AND created_at >= ?", meetingID, time.Now().Add(-window)).
+		Order("created_at ASC").Find(&samples).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch quality samples: %w", err)
+	}
+
+	type totals struct {
+		summary                                            models.PeerQualitySummary
+		score, rtt, jitter, packetLoss, bitrate, frameRate float64
+	}
+	byPeer := make(map[string]*totals)
+	var order []string
+	for _, sample := range samples {
+		peer, ok := byPeer[sample.PeerID]
+		if !ok {
+			peer = &totals{}
+			byPeer[sample.PeerID] = peer
+			order = append(order, sample.PeerID)
+		}
+		// Samples come oldest first, so the latest one names the peer
+		peer.summary.PeerID = sample.PeerID
+		peer.summary.Name = sample.Name
+		peer.summary.UserID = sample.UserID
+		peer.summary.PublicUserID = sample.PublicUserID
+		peer.summary.CandidateType = sample.CandidateType
+		peer.summary.LastSampleAt = sample.CreatedAt
+		peer.summary.Samples++
+		peer.score += float64(sample.Score)
+		peer.rtt += sample.RTT
+		peer.jitter += sample.Jitter
+		peer.packetLoss += sample.PacketLoss
+		peer.bitrate += sample.Bitrate
+		peer.frameRate += sample.FrameRate
+	}
+
+	summaries := make([]models.PeerQualitySummary, 0, len(order))
+	for _, peerID := range order {
+		peer := byPeer[peerID]
+		count := float64(peer.summary.Samples)
+		summary := peer.summary
+		summary.Score = int(math.Round(peer.score / count))
+		summary.RTT = roundStat(peer.rtt / count)
+		summary.Jitter = roundStat(peer.jitter / count)
+		summary.PacketLoss = roundStat(peer.packetLoss / count)
+		summary.Bitrate = roundStat(peer.bitrate / count)
+		summary.FrameRate = roundStat(peer.frameRate / count)
+		summaries = append(summaries, summary)
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].Score < summaries[j].Score
+	})
+	return summaries, nil
+}
+
+// meetingScore averages the peers' scores, each peer counting once however
+// many samples it sent
+func meetingScore(peers []models.PeerQualitySummary) *int {
+	if len(peers) == 0 {
+		return nil
+	}
+	total := 0
+	for _, peer := range peers {
+		total += peer.Score
+	}
+	score := int(math.Round(float64(total) / float64(len(peers))))
+	return &score
+}
+
+// roundStat rounds an averaged stat to two decimals
+func roundStat(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestQualityStatsPayload_Score(t *testing.T) {
+	perfect := models.QualityStatsPayload{}
+	assert.Equal(t, 100, perfect.Score())
+
+	good := models.QualityStatsPayload{RTT: 60, Jitter: 5, PacketLoss: 0.5}
+	assert.Greater(t, good.Score(), 90)
+
+	poor := models.QualityStatsPayload{RTT: 600, Jitter: 40, PacketLoss: 8}
+	assert.Less(t, poor.Score(), models.PoorQualityScore)
+
+	unusable := models.QualityStatsPayload{RTT: 2000, PacketLoss: 50}
+	assert.Equal(t, 0, unusable.Score())
+
+	assert.NoError(t, good.Validate())
+	for _, invalid := range []models.QualityStatsPayload{
+		{RTT: -1},
+		{PacketLoss: 101},
+		{FrameRate: 500},
+		{CandidateType: "tunnel"},
+	} {
+		assert.Error(t, invalid.Validate())
+	}
+}
+
+func TestQualityService_Samples(t *testing.T) {
+	db := setupTestDB(t, &models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{}, &models.QualitySample{})
+	meeting, hostID := createTestMeeting(t, db)
+	attendee := addTestParticipant(t, db, meeting.ID, "attendee", models.RoleAttendee)
+	host := models.MeetingActor{UserID: &hostID}
+
+	service := NewQualityService(db, NewRoleService(db, nil), config.WebRTCConfig{
+		StatsRetention:   time.Hour,
+		StatsMinInterval: time.Minute,
+	})
+	peer := func(id string) *models.WebRTCPeer {
+		return &models.WebRTCPeer{ID: id, MeetingID: meeting.ID.String(), Name: id}
+	}
+	record := func(peerID string, stats models.QualityStatsPayload, age time.Duration) {
+		sample, err := service.RecordSample(peer(peerID), &stats)
+		require.NoError(t, err)
+		require.NoError(t, db.Model(sample).Update("created_at", sample.CreatedAt.Add(-age)).Error)
+		// Let the peer submit again right away
+		service.lastSample = make(map[peerKey]time.Time)
+	}
+
+	// Peers may not submit faster than the minimum interval
+	_, err := service.RecordSample(peer("alice"), &models.QualityStatsPayload{RTT: 40})
+	require.NoError(t, err)
+	_, err = service.RecordSample(peer("alice"), &models.QualityStatsPayload{RTT: 40})
+	assert.EqualError(t, err, "stats submitted too often")
+	_, err = service.RecordSample(peer("bob"), &models.QualityStatsPayload{PacketLoss: -1})
+	assert.Error(t, err)
+	service.lastSample = make(map[peerKey]time.Time)
+
+	record("alice", models.QualityStatsPayload{RTT: 60, Jitter: 4, CandidateType: "srflx"}, 10*time.Second)
+	record("bob", models.QualityStatsPayload{RTT: 900, Jitter: 60, PacketLoss: 12, CandidateType: "relay"}, 20*time.Second)
+	record("bob", models.QualityStatsPayload{RTT: 700, Jitter: 50, PacketLoss: 10, CandidateType: "relay"}, 3*time.Minute)
+	record("carol", models.QualityStatsPayload{RTT: 800, PacketLoss: 20}, 2*time.Hour)
+
+	// Only hosts and co-hosts see the dashboard
+	_, err = service.GetMeetingQuality(meeting.ID, models.MeetingActor{UserID: attendee.UserID}, 0)
+	assert.EqualError(t, err, "permission denied")
+	_, err = service.GetMeetingQuality(uuid.New(), host, 0)
+	assert.EqualError(t, err, "meeting not found")
+
+	// The summary covers the window, worst peer first
+	quality, err := service.GetMeetingQuality(meeting.ID, host, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 300, quality.WindowSeconds)
+	require.Len(t, quality.Peers, 2)
+	assert.Equal(t, "bob", quality.Peers[0].PeerID)
+	assert.Equal(t, 2, quality.Peers[0].Samples)
+	assert.Equal(t, "relay", quality.Peers[0].CandidateType)
+	assert.Equal(t, float64(11), quality.Peers[0].PacketLoss)
+	assert.Equal(t, "alice", quality.Peers[1].PeerID)
+	assert.Equal(t, 2, quality.Peers[1].Samples)
+	assert.Equal(t, 1, quality.PoorCount)
+	require.NotNil(t, quality.Score)
+	assert.Equal(t, (quality.Peers[0].Score+quality.Peers[1].Score+1)/2, *quality.Score)
+
+	// The poor connection query looks at the last minute by default
+	poor, err := service.GetPoorConnections(meeting.ID, host, 0, models.PoorQualityScore)
+	require.NoError(t, err)
+	require.Len(t, poor, 1)
+	assert.Equal(t, "bob", poor[0].PeerID)
+	assert.Equal(t, 1, poor[0].Samples)
+	poor, err = service.GetPoorConnections(meeting.ID, host, 0, 100)
+	require.NoError(t, err)
+	assert.Len(t, poor, 2)
+
+	// A peer's history comes oldest first
+	samples, err := service.GetPeerSamples(meeting.ID, host, "bob", time.Time{}, 0)
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, float64(700), samples[0].RTT)
+	samples, err = service.GetPeerSamples(meeting.ID, host, "bob", time.Now().Add(-time.Minute), 0)
+	require.NoError(t, err)
+	assert.Len(t, samples, 1)
+
+	// Samples past retention are pruned
+	pruned, err := service.PruneSamples()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+	samples, err = service.GetPeerSamples(meeting.ID, host, "carol", time.Time{}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+}
+
+func TestWebSocketService_QualityStats(t *testing.T) {
+	db := setupTestDB(t, &models.User{}, &models.PublicUser{}, &models.Meeting{}, &models.Participant{}, &models.QualitySample{})
+	meeting, _ := createTestMeeting(t, db)
+
+	quality := NewQualityService(db, NewRoleService(db, nil), config.WebRTCConfig{
+		StatsRetention:   time.Hour,
+		StatsMinInterval: time.Minute,
+	})
+	wsService := NewWebSocketService(db, nil, nil)
+	wsService.SetQualityService(quality)
+	wsService.StartHub()
+	service := NewWebRTCService(db, wsService)
+	service.SetQualityService(quality)
+	defer service.Stop()
+
+	client := &models.WebSocketClient{
+		ID:        "alice",
+		MeetingID: meeting.ID.String(),
+		Name:      "Alice",
+		Send:      make(chan models.SignalingMessage, 16),
+		Hub:       wsService.hub,
+	}
+	wsService.hub.Register <- client
+	require.Eventually(t, func() bool { return wsService.GetParticipantCount(meeting.ID.String()) == 1 }, time.Second, 10*time.Millisecond)
+
+	submit := func(data interface{}) {
+		wsService.handleQualityStats(client, &models.SignalingMessage{Type: models.SignalingTypeQualityStats, Data: data})
+	}
+
+	// Stats are recorded for the client's peer and show up in room stats
+	submit(map[string]interface{}{"rttMs": 80, "jitterMs": 6, "packetLoss": 1, "candidateType": "host"})
+	var samples []models.QualitySample
+	require.NoError(t, db.Find(&samples).Error)
+	require.Len(t, samples, 1)
+	assert.Equal(t, "alice", samples[0].PeerID)
+	assert.Equal(t, "Alice", samples[0].Name)
+	score := samples[0].Score
+	assert.Equal(t, &score, service.GetRoomStats(meeting.ID.String())["qualityScore"])
+
+	// Invalid and too frequent stats are refused
+	submit(map[string]interface{}{"packetLoss": 150})
+	rejected := waitForMessage(t, client, models.SignalingTypeError).Data.(models.ErrorPayload)
+	assert.Equal(t, "INVALID_STATS", rejected.Code)
+	submit(map[string]interface{}{"rttMs": 80})
+	rejected = waitForMessage(t, client, models.SignalingTypeError).Data.(models.ErrorPayload)
+	assert.Equal(t, "TOO_FREQUENT", rejected.Code)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// RoleService resolves participants' meeting roles and is the single place
+// where in-meeting permissions are checked
+type RoleService struct {
+	db        *gorm.DB
+	wsService *WebSocketService
+}
+
+func NewRoleService(db *gorm.DB, wsService *WebSocketService) *RoleService {
+	return &RoleService{
+		db:        db,
+		wsService: wsService,
+	}
+}
+
+// GetRole returns the actor's role in a meeting. The meeting owner is always
+// the host; everyone else needs a participant record.
+func (s *RoleService) GetRole(meetingID uuid.UUID, actor models.MeetingActor) (models.ParticipantRole, error) {
+	var meeting models.Meeting
+	if err := s.db.Select("id", "host_id").Where("id = ?", meetingID).First(&meeting).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.New("meeting not found")
+		}
+		return "", fmt.Errorf("failed to fetch meeting: %w", err)
+	}
+
+	if actor.UserID != nil && *actor.UserID == meeting.HostID {
+		return models.RoleHost, nil
+	}
+
+	// Participants still waiting in the lobby (or denied) hold no role
+	query := participantQuery(s.db, meetingID, actor)
+	if query == nil {
+		return "", errors.New("not a participant")
+	}
+
+	var participant models.Participant
+	if err := query.Where("lobby_status = ?", models.LobbyStatusAdmitted).Order("joined_at DESC").First(&participant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.New("not a participant")
+		}
+		return "", fmt.Errorf("failed to fetch participant: %w", err)
+	}
+
+	if participant.Role == "" {
+		return models.RoleAttendee, nil
+	}
+	return participant.Role, nil
+}
+
+// Authorize checks that the actor's role in the meeting grants the permission
+func (s *RoleService) Authorize(meetingID uuid.UUID, actor models.MeetingActor, permission models.Permission) error {
+	role, err := s.GetRole(meetingID, actor)
+	if err != nil {
+		return err
+	}
+	if !role.Can(permission) {
+		return errors.New("permission denied")
+	}
+	return nil
+}
+
+// ChangeRole promotes or demotes a participant and broadcasts the change
+func (s *RoleService) ChangeRole(meetingID uuid.UUID, actor models.MeetingActor, participantID uuid.UUID, role models.ParticipantRole) (*models.Participant, error) {
+	actorRole, err := s.GetRole(meetingID, actor)
+	if err != nil {
+		return nil, err
+	}
+	if !actorRole.Can(models.PermissionManageRoles) {
+		return nil, errors.New("permission denied")
+	}
+
+	var participant models.Participant
+	if err := s.db.Where("id = ? AND meeting_id = ?", participantID, meetingID).First(&participant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("participant not found")
+		}
+		return nil, fmt.Errorf("failed to fetch participant: %w", err)
+	}
+
+	previousRole := participant.Role
+	if !models.CanAssignRole(actorRole, previousRole, role) {
+		return nil, errors.New("permission denied")
+	}
+	if previousRole == role {
+		return &participant, nil
+	}
+
+	if err := s.db.Model(&participant).Update("role", role).Error; err != nil {
+		return nil, fmt.Errorf("failed to update participant role: %w", err)
+	}
+
+	s.broadcastRoleChange(&participant, previousRole)
+	return &participant, nil
+}
+
+// broadcastRoleChange notifies the room; each node applies the new role to
+// the matching connections as the message is delivered
+func (s *RoleService) broadcastRoleChange(participant *models.Participant, previousRole models.ParticipantRole) {
+	if s.wsService == nil {
+		return
+	}
+
+	s.wsService.SendMessageToMeeting(participant.MeetingID.String(), models.SignalingMessage{
+		Type: models.SignalingTypeParticipantRoleChanged,
+		Data: models.RoleChangedPayload{
+			ParticipantID: participant.ID,
+			UserID:        participant.UserID,
+			PublicUserID:  participant.PublicUserID,
+			Role:          participant.Role,
+			PreviousRole:  previousRole,
+		},
+	})
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func addTestParticipant(t *testing.T, db *gorm.DB, meetingID uuid.UUID, username string, role models.ParticipantRole) *models.Participant {
+	user := &models.User{Username: username, Email: username + "@example.com", PasswordHash: "x"}
+	require.NoError(t, db.Create(user).Error)
+
+	participant := &models.Participant{MeetingID: meetingID, UserID: &user.ID, Role: role, IsActive: true}
+	require.NoError(t, db.Create(participant).Error)
+	return participant
+}
+
+func TestCanAssignRole(t *testing.T) {
+	assert.True(t, models.CanAssignRole(models.RoleHost, models.RoleAttendee, models.RoleCoHost))
+	assert.True(t, models.CanAssignRole(models.RoleHost, models.RoleCoHost, models.RoleViewer))
+	assert.True(t, models.CanAssignRole(models.RoleCoHost, models.RoleAttendee, models.RolePresenter))
+	assert.False(t, models.CanAssignRole(models.RoleCoHost, models.RoleAttendee, models.RoleCoHost))
+	assert.False(t, models.CanAssignRole(models.RoleCoHost, models.RoleCoHost, models.RoleViewer))
+	assert.False(t, models.CanAssignRole(models.RoleHost, models.RoleAttendee, models.RoleHost))
+	assert.False(t, models.CanAssignRole(models.RolePresenter, models.RoleViewer, models.RoleAttendee))
+	assert.False(t, models.CanAssignRole(models.RoleHost, models.RoleAttendee, models.ParticipantRole("owner")))
+}
+
+func TestRoleService_GetRoleAndAuthorize(t *testing.T) {
+	db := setupTestMeetingDB(t)
+	service := NewRoleService(db, nil)
+	meeting, hostID := createTestMeeting(t, db)
+	viewer := addTestParticipant(t, db, meeting.ID, "viewer", models.RoleViewer)
+
+	role, err := service.GetRole(meeting.ID, models.MeetingActor{UserID: &hostID})
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleHost, role)
+
+	role, err = service.GetRole(meeting.ID, models.MeetingActor{UserID: viewer.UserID})
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleViewer, role)
+
+	stranger := uuid.New()
+	_, err = service.GetRole(meeting.ID, models.MeetingActor{UserID: &stranger})
+	assert.EqualError(t, err, "not a participant")
+
+	_, err = service.GetRole(uuid.New(), models.MeetingActor{UserID: &hostID})
+	assert.EqualError(t, err, "meeting not found")
+
+	assert.NoError(t, service.Authorize(meeting.ID, models.MeetingActor{UserID: &hostID}, models.PermissionManageMeeting))
+	assert.EqualError(t, service.Authorize(meeting.ID, models.MeetingActor{UserID: viewer.UserID}, models.PermissionChat), "permission denied")
+}
+
+func TestRoleService_ChangeRole(t *testing.T) {
+	db := setupTestMeetingDB(t)
+	service := NewRoleService(db, nil)
+	meeting, hostID := createTestMeeting(t, db)
+	coHost := addTestParticipant(t, db, meeting.ID, "cohost", models.RoleAttendee)
+	attendee := addTestParticipant(t, db, meeting.ID, "attendee", models.RoleAttendee)
+
+	updated, err := service.ChangeRole(meeting.ID, models.MeetingActor{UserID: &hostID}, coHost.ID, models.RoleCoHost)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleCoHost, updated.Role)
+
+	// Co-hosts can manage attendees but not promote them to their own level
+	coHostActor := models.MeetingActor{UserID: coHost.UserID}
+	updated, err = service.ChangeRole(meeting.ID, coHostActor, attendee.ID, models.RolePresenter)
+	require.NoError(t, err)
+	assert.Equal(t, models.RolePresenter, updated.Role)
+
+	_, err = service.ChangeRole(meeting.ID, coHostActor, attendee.ID, models.RoleCoHost)
+	assert.EqualError(t, err, "permission denied")
+
+	// Attendees cannot change roles at all
+	_, err = service.ChangeRole(meeting.ID, models.MeetingActor{UserID: attendee.UserID}, coHost.ID, models.RoleViewer)
+	assert.EqualError(t, err, "permission denied")
+
+	_, err = service.ChangeRole(meeting.ID, models.MeetingActor{UserID: &hostID}, uuid.New(), models.RoleViewer)
+	assert.EqualError(t, err, "participant not found")
+
+	var stored models.Participant
+	require.NoError(t, db.First(&stored, attendee.ID).Error)
+	assert.Equal(t, models.RolePresenter, stored.Role)
+}
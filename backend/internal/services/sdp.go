@@ -0,0 +1,396 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// Errors for offers, answers and ICE candidates that are malformed or break
+// the meeting's media policy; the details are wrapped around them
+var (
+	ErrInvalidSDP           = errors.New("invalid sdp")
+	ErrInvalidICECandidate  = errors.New("invalid ice candidate")
+	ErrMediaPolicyViolation = errors.New("media policy violation")
+)
+
+// staticPayloadCodecs names the RTP payload types assigned by RFC 3551,
+// which need no rtpmap line
+var staticPayloadCodecs = map[string]string{
+	"0":  "pcmu",
+	"3":  "gsm",
+	"4":  "g723",
+	"8":  "pcma",
+	"9":  "g722",
+	"18": "g729",
+	"34": "h263",
+}
+
+// auxiliaryCodecs support the media codecs rather than carry media of their
+// own. The codec policy leaves them alone; rtx goes with the codec it
+// retransmits.
+var auxiliaryCodecs = map[string]bool{
+	"rtx":             true,
+	"red":             true,
+	"ulpfec":          true,
+	"flexfec-03":      true,
+	"telephone-event": true,
+	"cn":              true,
+}
+
+// SessionDescription is a parsed SDP offer or answer (RFC 8866)
+type SessionDescription struct {
+	Session []string            // Session-level lines, "v=0" first
+	Media   []*MediaDescription // Media sections, in order
+}
+
+// MediaDescription is one media section of a session description
+type MediaDescription struct {
+	Kind    string   // audio, video, application...
+	Port    string   // Port, with the "/<count>" suffix if any
+	Proto   string   // Transport, e.g. UDP/TLS/RTP/SAVPF
+	Formats []string // RTP payload types, or other formats for non-RTP media
+	Lines   []string // Lines after the m= line
+}
+
+// ParseSDP parses and validates an SDP body no longer than maxSize bytes.
+// Lines may end in CRLF or LF.
+func ParseSDP(raw string, maxSize int) (*SessionDescription, error) {
+	if raw == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidSDP)
+	}
+	if maxSize > 0 && len(raw) > maxSize {
+		return nil, fmt.Errorf("%w: larger than %d bytes", ErrInvalidSDP, maxSize)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	description := &SessionDescription{}
+	seen := make(map[byte]bool)
+	var media *MediaDescription
+	for i, line := range lines {
+		if len(line) < 2 || line[1] != '=' || line[0] < 'a' || line[0] > 'z' || strings.ContainsAny(line, "\r\x00") {
+			return nil, fmt.Errorf("%w: malformed line %d", ErrInvalidSDP, i+1)
+		}
+		if i == 0 && line != "v=0" {
+			return nil, fmt.Errorf("%w: must start with v=0", ErrInvalidSDP)
+		}
+
+		switch {
+		case line[0] == 'm':
+			parsed, err := parseMediaLine(line)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidSDP, i+1, err)
+			}
+			media = parsed
+			description.Media = append(description.Media, media)
+		case media != nil:
+			media.Lines = append(media.Lines, line)
+		default:
+			seen[line[0]] = true
+			description.Session = append(description.Session, line)
+		}
+	}
+
+	for _, required := range []byte("ost") {
+		if !seen[required] {
+			return nil, fmt.Errorf("%w: missing %c= line", ErrInvalidSDP, required)
+		}
+	}
+	if len(description.Media) == 0 {
+		return nil, fmt.Errorf("%w: no media section", ErrInvalidSDP)
+	}
+	return description, nil
+}
+
+// parseMediaLine parses "m=<media> <port> <proto> <fmt> ..."
+func parseMediaLine(line string) (*MediaDescription, error) {
+	fields := strings.Fields(line[2:])
+	if len(fields) < 4 {
+		return nil, errors.New("media line needs a port, a transport and formats")
+	}
+
+	port, _, _ := strings.Cut(fields[1], "/")
+	if number, err := strconv.Atoi(port); err != nil || number < 0 || number > 65535 {
+		return nil, fmt.Errorf("bad port %q", fields[1])
+	}
+	if strings.Contains(fields[2], "RTP") {
+		for _, format := range fields[3:] {
+			if payloadType, err := strconv.Atoi(format); err != nil || payloadType < 0 || payloadType > 127 {
+				return nil, fmt.Errorf("bad payload type %q", format)
+			}
+		}
+	}
+
+	return &MediaDescription{
+		Kind:    fields[0],
+		Port:    fields[1],
+		Proto:   fields[2],
+		Formats: fields[3:],
+	}, nil
+}
+
+// String serializes the session description with CRLF line endings
+func (d *SessionDescription) String() string {
+	var b strings.Builder
+	for _, line := range d.Session {
+		b.WriteString(line)
+		b.WriteString("\r\n")
+	}
+	for _, media := range d.Media {
+		fmt.Fprintf(&b, "m=%s %s %s %s\r\n", media.Kind, media.Port, media.Proto, strings.Join(media.Formats, " "))
+		for _, line := range media.Lines {
+			b.WriteString(line)
+			b.WriteString("\r\n")
+		}
+	}
+	return b.String()
+}
+
+// ApplyMediaPolicy rewrites the session description to follow policy and
+// returns the codec each audio and video section now leads with, by media
+// kind. It fails when a section is left without an allowed codec.
+func (d *SessionDescription) ApplyMediaPolicy(policy *models.MediaPolicy) (map[string]string, error) {
+	allowed := make(map[string]bool)
+	for _, codec := range policy.Codecs() {
+		allowed[codec] = true
+	}
+
+	codecs := make(map[string]string)
+	for _, media := range d.Media {
+		if policy.PrivacyMode {
+			media.removeHostCandidates()
+		}
+		if !media.isRTP() || (media.Kind != "audio" && media.Kind != "video") {
+			continue
+		}
+		// A port of zero rejects the section
+		if port, _, _ := strings.Cut(media.Port, "/"); port == "0" {
+			continue
+		}
+
+		if len(allowed) > 0 {
+			if err := media.filterCodecs(allowed); err != nil {
+				return nil, err
+			}
+		}
+		if media.Kind == "video" && policy.MaxBitrate > 0 {
+			media.capBitrate(policy.MaxBitrate)
+		}
+		if !policy.Simulcast {
+			media.removeSimulcast()
+		}
+		if codec := media.leadingCodec(); codec != "" {
+			codecs[media.Kind] = codec
+		}
+	}
+	return codecs, nil
+}
+
+func (m *MediaDescription) isRTP() bool {
+	return strings.Contains(m.Proto, "RTP")
+}
+
+// codecs returns the lower-case codec name of each payload type
+func (m *MediaDescription) codecs() map[string]string {
+	codecs := make(map[string]string)
+	for _, format := range m.Formats {
+		if name, ok := staticPayloadCodecs[format]; ok {
+			codecs[format] = name
+		}
+	}
+	for _, line := range m.Lines {
+		if rest, ok := strings.CutPrefix(line, "a=rtpmap:"); ok {
+			payloadType, encoding, _ := strings.Cut(rest, " ")
+			name, _, _ := strings.Cut(encoding, "/")
+			codecs[payloadType] = strings.ToLower(strings.TrimSpace(name))
+		}
+	}
+	return codecs
+}
+
+// formatParameter returns a parameter of a payload type's a=fmtp line
+func (m *MediaDescription) formatParameter(payloadType, name string) string {
+	for _, line := range m.Lines {
+		rest, ok := strings.CutPrefix(line, "a=fmtp:"+payloadType+" ")
+		if !ok {
+			continue
+		}
+		for _, parameter := range strings.Split(rest, ";") {
+			key, value, _ := strings.Cut(strings.TrimSpace(parameter), "=")
+			if key == name {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// filterCodecs drops the payload types of codecs that are not allowed,
+// along with their attributes
+func (m *MediaDescription) filterCodecs(allowed map[string]bool) error {
+	codecs := m.codecs()
+	keep := make(map[string]bool)
+	for _, format := range m.Formats {
+		if allowed[codecs[format]] {
+			keep[format] = true
+		}
+	}
+	if len(keep) == 0 {
+		return fmt.Errorf("%w: no allowed %s codec", ErrMediaPolicyViolation, m.Kind)
+	}
+	for _, format := range m.Formats {
+		name := codecs[format]
+		if keep[format] || !auxiliaryCodecs[name] {
+			continue
+		}
+		if name != "rtx" || keep[m.formatParameter(format, "apt")] {
+			keep[format] = true
+		}
+	}
+
+	formats := m.Formats[:0]
+	for _, format := range m.Formats {
+		if keep[format] {
+			formats = append(formats, format)
+		}
+	}
+	m.Formats = formats
+
+	lines := m.Lines[:0]
+	for _, line := range m.Lines {
+		if payloadType, ok := attributePayloadType(line); ok && payloadType != "*" && !keep[payloadType] {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	m.Lines = lines
+	return nil
+}
+
+// attributePayloadType returns the payload type an rtpmap, fmtp or rtcp-fb
+// attribute is about
+func attributePayloadType(line string) (string, bool) {
+	for _, prefix := range []string{"a=rtpmap:", "a=fmtp:", "a=rtcp-fb:"} {
+		if rest, ok := strings.CutPrefix(line, prefix); ok {
+			payloadType, _, _ := strings.Cut(rest, " ")
+			return payloadType, true
+		}
+	}
+	return "", false
+}
+
+// capBitrate limits the section to maxKbps, keeping lower limits the peer
+// set itself
+func (m *MediaDescription) capBitrate(maxKbps int) {
+	capped := false
+	lines := make([]string, 0, len(m.Lines)+1)
+	for _, line := range m.Lines {
+		if value, ok := strings.CutPrefix(line, "b=AS:"); ok {
+			if kbps, err := strconv.Atoi(value); err == nil && kbps > 0 && kbps <= maxKbps {
+				lines = append(lines, line)
+				capped = true
+			}
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "b=TIAS:"); ok {
+			if bps, err := strconv.Atoi(value); err == nil && bps > 0 && bps <= maxKbps*1000 {
+				lines = append(lines, line)
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	if !capped {
+		// Bandwidth lines follow the title and connection lines
+		at := 0
+		for at < len(lines) && (strings.HasPrefix(lines[at], "i=") || strings.HasPrefix(lines[at], "c=")) {
+			at++
+		}
+		lines = append(lines[:at], append([]string{"b=AS:" + strconv.Itoa(maxKbps)}, lines[at:]...)...)
+	}
+	m.Lines = lines
+}
+
+// removeSimulcast drops the simulcast and RID attributes, so the peer sends
+// a single layer
+func (m *MediaDescription) removeSimulcast() {
+	lines := m.Lines[:0]
+	for _, line := range m.Lines {
+		if strings.HasPrefix(line, "a=simulcast:") || strings.HasPrefix(line, "a=rid:") || strings.HasPrefix(line, "a=ssrc-group:SIM ") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	m.Lines = lines
+}
+
+// removeHostCandidates drops the candidates carrying the peer's local
+// addresses
+func (m *MediaDescription) removeHostCandidates() {
+	lines := m.Lines[:0]
+	for _, line := range m.Lines {
+		if strings.HasPrefix(line, "a=candidate:") {
+			if candidateType, err := ICECandidateType(line); err != nil || candidateType == "host" {
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	m.Lines = lines
+}
+
+// leadingCodec returns the first media codec of the section, the one the
+// peers use when it is an answer
+func (m *MediaDescription) leadingCodec() string {
+	codecs := m.codecs()
+	for _, format := range m.Formats {
+		if name := codecs[format]; name != "" && !auxiliaryCodecs[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// ICECandidateType validates an ICE candidate attribute (RFC 8839), with or
+// without its "a=" prefix, and returns its type: host, srflx, prflx or relay
+func ICECandidateType(candidate string) (string, error) {
+	if len(candidate) > models.MaxICECandidateLength {
+		return "", fmt.Errorf("%w: longer than %d bytes", ErrInvalidICECandidate, models.MaxICECandidateLength)
+	}
+	value, ok := strings.CutPrefix(strings.TrimPrefix(candidate, "a="), "candidate:")
+	if !ok {
+		return "", fmt.Errorf("%w: missing candidate: prefix", ErrInvalidICECandidate)
+	}
+
+	// foundation component transport priority address port "typ" type ...
+	fields := strings.Fields(value)
+	if len(fields) < 8 || fields[6] != "typ" {
+		return "", fmt.Errorf("%w: malformed", ErrInvalidICECandidate)
+	}
+	if component, err := strconv.Atoi(fields[1]); err != nil || component < 1 || component > 256 {
+		return "", fmt.Errorf("%w: bad component %q", ErrInvalidICECandidate, fields[1])
+	}
+	if transport := strings.ToLower(fields[2]); transport != "udp" && transport != "tcp" {
+		return "", fmt.Errorf("%w: bad transport %q", ErrInvalidICECandidate, fields[2])
+	}
+	if _, err := strconv.ParseUint(fields[3], 10, 32); err != nil {
+		return "", fmt.Errorf("%w: bad priority %q", ErrInvalidICECandidate, fields[3])
+	}
+	if port, err := strconv.Atoi(fields[5]); err != nil || port < 0 || port > 65535 {
+		return "", fmt.Errorf("%w: bad port %q", ErrInvalidICECandidate, fields[5])
+	}
+
+	switch fields[7] {
+	case "host", "srflx", "prflx", "relay":
+		return fields[7], nil
+	}
+	return "", fmt.Errorf("%w: bad type %q", ErrInvalidICECandidate, fields[7])
+}
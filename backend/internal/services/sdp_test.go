@@ -0,0 +1,146 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+const testOffer = "v=0\r\n" +
+	"o=- 4611731400430051336 2 IN IP4 127.0.0.1\r\n" +
+	"s=-\r\n" +
+	"t=0 0\r\n" +
+	"a=group:BUNDLE 0 1\r\n" +
+	"m=audio 9 UDP/TLS/RTP/SAVPF 111 0\r\n" +
+	"c=IN IP4 0.0.0.0\r\n" +
+	"a=mid:0\r\n" +
+	"a=rtpmap:111 opus/48000/2\r\n" +
+	"a=fmtp:111 minptime=10;useinbandfec=1\r\n" +
+	"a=candidate:1 1 udp 2122260223 192.168.1.20 54400 typ host generation 0\r\n" +
+	"a=candidate:2 1 udp 1686052607 203.0.113.7 54400 typ srflx raddr 192.168.1.20 rport 54400\r\n" +
+	"m=video 9 UDP/TLS/RTP/SAVPF 96 97 98 99\r\n" +
+	"c=IN IP4 0.0.0.0\r\n" +
+	"b=AS:4000\r\n" +
+	"a=mid:1\r\n" +
+	"a=rtpmap:96 VP8/90000\r\n" +
+	"a=rtcp-fb:96 nack\r\n" +
+	"a=rtpmap:97 rtx/90000\r\n" +
+	"a=fmtp:97 apt=96\r\n" +
+	"a=rtpmap:98 H264/90000\r\n" +
+	"a=fmtp:98 profile-level-id=42e01f\r\n" +
+	"a=rtpmap:99 rtx/90000\r\n" +
+	"a=fmtp:99 apt=98\r\n" +
+	"a=rid:h send\r\n" +
+	"a=rid:l send\r\n" +
+	"a=simulcast:send h;l\r\n"
+
+func TestParseSDP(t *testing.T) {
+	description, err := ParseSDP(testOffer, 0)
+	require.NoError(t, err)
+	require.Len(t, description.Media, 2)
+	assert.Equal(t, "audio", description.Media[0].Kind)
+	assert.Equal(t, []string{"96", "97", "98", "99"}, description.Media[1].Formats)
+	assert.Equal(t, testOffer, description.String())
+
+	// LF line endings are accepted and written back as CRLF
+	description, err = ParseSDP(strings.ReplaceAll(testOffer, "\r\n", "\n"), 0)
+	require.NoError(t, err)
+	assert.Equal(t, testOffer, description.String())
+
+	invalid := map[string]string{
+		"empty":          "",
+		"too large":      testOffer + strings.Repeat("a=x\r\n", 100),
+		"no version":     strings.TrimPrefix(testOffer, "v=0\r\n"),
+		"malformed line": strings.Replace(testOffer, "s=-", "s-", 1),
+		"missing origin": strings.Replace(testOffer, "o=- 4611731400430051336 2 IN IP4 127.0.0.1\r\n", "", 1),
+		"no media":       "v=0\r\no=- 1 2 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n",
+		"bad port":       strings.Replace(testOffer, "m=audio 9 ", "m=audio 70000 ", 1),
+		"bad payload":    strings.Replace(testOffer, "SAVPF 111 0", "SAVPF 111 200", 1),
+		"short m= line":  strings.Replace(testOffer, "m=audio 9 UDP/TLS/RTP/SAVPF 111 0", "m=audio 9", 1),
+	}
+	for name, raw := range invalid {
+		_, err := ParseSDP(raw, len(testOffer))
+		assert.ErrorIs(t, err, ErrInvalidSDP, name)
+	}
+}
+
+func TestSessionDescription_ApplyMediaPolicy(t *testing.T) {
+	apply := func(policy *models.MediaPolicy) (string, map[string]string) {
+		description, err := ParseSDP(testOffer, 0)
+		require.NoError(t, err)
+		codecs, err := description.ApplyMediaPolicy(policy)
+		require.NoError(t, err)
+		return description.String(), codecs
+	}
+
+	// The default policy leaves the offer alone
+	sdp, codecs := apply(&models.MediaPolicy{Simulcast: true})
+	assert.Equal(t, testOffer, sdp)
+	assert.Equal(t, map[string]string{"audio": "opus", "video": "vp8"}, codecs)
+
+	// Disallowed codecs go with their attributes and retransmission payloads
+	policy := &models.MediaPolicy{Simulcast: true}
+	policy.SetCodecs([]string{"Opus", "H264"})
+	sdp, codecs = apply(policy)
+	assert.Contains(t, sdp, "m=audio 9 UDP/TLS/RTP/SAVPF 111\r\n")
+	assert.Contains(t, sdp, "m=video 9 UDP/TLS/RTP/SAVPF 98 99\r\n")
+	assert.NotContains(t, sdp, "VP8")
+	assert.NotContains(t, sdp, "a=rtcp-fb:96")
+	assert.NotContains(t, sdp, "apt=96")
+	assert.Contains(t, sdp, "a=fmtp:99 apt=98\r\n")
+	assert.Equal(t, map[string]string{"audio": "opus", "video": "h264"}, codecs)
+
+	// A section left without an allowed codec breaks the policy
+	policy.SetCodecs([]string{"opus", "av1"})
+	description, err := ParseSDP(testOffer, 0)
+	require.NoError(t, err)
+	_, err = description.ApplyMediaPolicy(policy)
+	assert.ErrorIs(t, err, ErrMediaPolicyViolation)
+
+	// A higher bitrate is capped; a lower one is kept
+	sdp, _ = apply(&models.MediaPolicy{MaxBitrate: 1500, Simulcast: true})
+	assert.Contains(t, sdp, "c=IN IP4 0.0.0.0\r\nb=AS:1500\r\na=mid:1\r\n")
+	assert.NotContains(t, sdp, "b=AS:4000")
+	sdp, _ = apply(&models.MediaPolicy{MaxBitrate: 8000, Simulcast: true})
+	assert.Contains(t, sdp, "b=AS:4000\r\n")
+	assert.NotContains(t, sdp, "b=AS:8000")
+
+	// Simulcast off leaves a single layer
+	sdp, _ = apply(&models.MediaPolicy{})
+	assert.NotContains(t, sdp, "a=simulcast")
+	assert.NotContains(t, sdp, "a=rid")
+
+	// Privacy mode strips host candidates only
+	sdp, _ = apply(&models.MediaPolicy{Simulcast: true, PrivacyMode: true})
+	assert.NotContains(t, sdp, "typ host")
+	assert.Contains(t, sdp, "typ srflx")
+}
+
+func TestICECandidateType(t *testing.T) {
+	candidateType, err := ICECandidateType("candidate:1 1 udp 2122260223 192.168.1.20 54400 typ host generation 0")
+	require.NoError(t, err)
+	assert.Equal(t, "host", candidateType)
+
+	candidateType, err = ICECandidateType("a=candidate:3 1 UDP 41885439 198.51.100.4 3478 typ relay raddr 203.0.113.7 rport 54400")
+	require.NoError(t, err)
+	assert.Equal(t, "relay", candidateType)
+
+	invalid := []string{
+		"1 1 udp 2122260223 192.168.1.20 54400 typ host",
+		"candidate:1 1 udp 2122260223 192.168.1.20 54400 host",
+		"candidate:1 0 udp 2122260223 192.168.1.20 54400 typ host",
+		"candidate:1 1 sctp 2122260223 192.168.1.20 54400 typ host",
+		"candidate:1 1 udp -5 192.168.1.20 54400 typ host",
+		"candidate:1 1 udp 2122260223 192.168.1.20 99999 typ host",
+		"candidate:1 1 udp 2122260223 192.168.1.20 54400 typ local",
+		"candidate:1 1 udp 2122260223 192.168.1.20 54400 typ host " + strings.Repeat("x", models.MaxICECandidateLength),
+	}
+	for _, candidate := range invalid {
+		_, err := ICECandidateType(candidate)
+		assert.ErrorIs(t, err, ErrInvalidICECandidate, candidate)
+	}
+}
@@ -1,33 +1,41 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"github.com/your-org/gomeet-backend/internal/models"
 )
 
+// WebRTCService handles WebRTC signaling between the peers of a meeting.
+// Every client admitted to a meeting over WebSocket is one of its peers; the
+// WebSocket hub's presence is the only peer registry, so peers come and go
+// with their connections.
 type WebRTCService struct {
 	db              *gorm.DB
 	wsService       *WebSocketService
-	rooms           map[string]*models.WebRTCRoom
-	roomsMutex      sync.RWMutex
 	cleanupTicker   *time.Ticker
 	cleanupStopChan chan bool
+
+	meetingService   *MeetingService
+	emptyGracePeriod time.Duration
+	emptySince       map[string]time.Time // meetingID -> when the live meeting became empty; cleanup goroutine only
+
+	negotiation *NegotiationCoordinator
+	quality     *QualityService
 }
 
 func NewWebRTCService(db *gorm.DB, wsService *WebSocketService) *WebRTCService {
 	service := &WebRTCService{
 		db:              db,
 		wsService:       wsService,
-		rooms:           make(map[string]*models.WebRTCRoom),
-		cleanupTicker:   time.NewTicker(5 * time.Minute), // Cleanup every 5 minutes
+		cleanupTicker:   time.NewTicker(time.Minute), // Cleanup every minute
 		cleanupStopChan: make(chan bool),
+		emptySince:      make(map[string]time.Time),
 	}
 
 	// Start cleanup routine
@@ -36,7 +44,7 @@ func NewWebRTCService(db *gorm.DB, wsService *WebSocketService) *WebRTCService {
 	return service
 }
 
-// startCleanupRoutine periodically cleans up inactive rooms and peers
+// startCleanupRoutine periodically ends meetings left empty
 func (s *WebRTCService) startCleanupRoutine() {
 	for {
 		select {
@@ -49,30 +57,86 @@ func (s *WebRTCService) startCleanupRoutine() {
 	}
 }
 
-// cleanupInactiveRooms removes inactive rooms and peers
+// SetMeetingService enables ending live meetings that stay empty for longer
+// than emptyGracePeriod
+func (s *WebRTCService) SetMeetingService(meetingService *MeetingService, emptyGracePeriod time.Duration) {
+	s.meetingService = meetingService
+	s.emptyGracePeriod = emptyGracePeriod
+}
+
+// SetICERestartPolicy enables recovering peer connections that stay
+// disconnected or failed for longer than timeout, giving up on a pair of
+// peers after maxAttempts ICE restarts
+func (s *WebRTCService) SetICERestartPolicy(timeout time.Duration, maxAttempts int) {
+	s.negotiation = NewNegotiationCoordinator(s.wsService, timeout, maxAttempts)
+}
+
+// SetQualityService adds the peers' connection quality to room statistics
+// and prunes quality samples past their retention
+func (s *WebRTCService) SetQualityService(quality *QualityService) {
+	s.quality = quality
+}
+
+// cleanupInactiveRooms ends live meetings that have been empty for the
+// grace period, and drops expired quality samples. Peers need no cleanup:
+// they leave with their connections.
 func (s *WebRTCService) cleanupInactiveRooms() {
-	s.roomsMutex.Lock()
-	defer s.roomsMutex.Unlock()
+	s.endIdleMeetings()
+
+	if s.quality != nil {
+		if pruned, err := s.quality.PruneSamples(); err != nil {
+			log.Printf("Failed to prune quality samples: %v", err)
+		} else if pruned > 0 {
+			log.Printf("Pruned %d expired quality samples", pruned)
+		}
+	}
+}
+
+// endIdleMeetings ends live meetings with no connected participants once
+// they have been empty for longer than the grace period
+func (s *WebRTCService) endIdleMeetings() {
+	if s.meetingService == nil {
+		return
+	}
+
+	meetingIDs, err := s.meetingService.GetLiveMeetingIDs()
+	if err != nil {
+		log.Printf("Failed to list live meetings: %v", err)
+		return
+	}
 
 	now := time.Now()
-	inactiveThreshold := 10 * time.Minute // Remove rooms inactive for 10 minutes
-
-	for meetingID, room := range s.rooms {
-		// Remove inactive peers
-		for peerID, peer := range room.Peers {
-			if now.Sub(peer.LastSeen) > inactiveThreshold {
-				log.Printf("Removing inactive peer %s from room %s", peerID, meetingID)
-				delete(room.Peers, peerID)
-				
-				// Notify other peers about the disconnection
-				s.notifyPeerLeft(meetingID, peerID)
-			}
+	live := make(map[string]bool, len(meetingIDs))
+	for _, meetingID := range meetingIDs {
+		key := meetingID.String()
+		live[key] = true
+
+		if s.wsService.GetParticipantCount(key) > 0 {
+			delete(s.emptySince, key)
+			continue
 		}
 
-		// Remove empty rooms
-		if room.IsEmpty() || now.Sub(room.LastActivity) > inactiveThreshold {
-			log.Printf("Removing inactive room %s", meetingID)
-			delete(s.rooms, meetingID)
+		since, tracked := s.emptySince[key]
+		if !tracked {
+			s.emptySince[key] = now
+			continue
+		}
+		if now.Sub(since) < s.emptyGracePeriod {
+			continue
+		}
+
+		log.Printf("Ending meeting %s after being empty since %s", key, since.Format(time.RFC3339))
+		if err := s.meetingService.EndIdleMeeting(meetingID); err != nil {
+			log.Printf("Failed to end idle meeting %s: %v", key, err)
+			continue
+		}
+		delete(s.emptySince, key)
+	}
+
+	// Forget meetings that are no longer live
+	for key := range s.emptySince {
+		if !live[key] {
+			delete(s.emptySince, key)
 		}
 	}
 }
@@ -80,130 +144,106 @@ func (s *WebRTCService) cleanupInactiveRooms() {
 // Stop stops the WebRTC service and cleanup routines
 func (s *WebRTCService) Stop() {
 	s.cleanupStopChan <- true
+	if s.negotiation != nil {
+		s.negotiation.Stop()
+	}
 }
 
-// JoinMeeting adds a peer to a WebRTC room
-func (s *WebRTCService) JoinMeeting(meetingID string, peerID string, userID *uuid.UUID, publicUserID *uuid.UUID, name string, isAuth bool) (*models.WebRTCPeer, error) {
+// JoinMeeting marks the WebSocket connection peerID of a meeting as setting
+// up its WebRTC peer connection. An empty peerID picks the actor's first
+// connection to the meeting.
+func (s *WebRTCService) JoinMeeting(meetingID string, peerID string, actor models.MeetingActor) (*models.WebRTCPeer, error) {
 	// Validate meeting exists
 	var meeting models.Meeting
 	if err := s.db.Where("id = ?", meetingID).First(&meeting).Error; err != nil {
-		return nil, fmt.Errorf("meeting not found: %w", err)
-	}
-
-	s.roomsMutex.Lock()
-	defer s.roomsMutex.Unlock()
-
-	// Get or create room
-	room, exists := s.rooms[meetingID]
-	if !exists {
-		room = models.NewWebRTCRoom(meetingID)
-		s.rooms[meetingID] = room
+		return nil, errors.New("meeting not found")
 	}
 
-	// Check if peer already exists
-	if peer, exists := room.GetPeer(peerID); exists {
-		return peer, nil
+	peer, err := s.FindActorPeer(meetingID, peerID, actor)
+	if err != nil {
+		return nil, err
 	}
 
-	// Create new peer
-	peer := &models.WebRTCPeer{
-		ID:           peerID,
-		MeetingID:    meetingID,
-		UserID:       userID,
-		PublicUserID: publicUserID,
-		Name:         name,
-		IsAuth:       isAuth,
-		State:        models.PeerStateNew,
-		JoinedAt:     time.Now(),
-		LastSeen:     time.Now(),
+	if err := s.UpdatePeerState(meetingID, peer.ID, models.PeerStateConnecting); err != nil {
+		return nil, err
 	}
+	peer.State = models.PeerStateConnecting
 
-	// Add peer to room
-	room.AddPeer(peer)
-
-	// Notify other peers about the new participant
-	s.notifyPeerJoined(meetingID, peer)
-
-	log.Printf("Peer %s (%s) joined meeting %s", peerID, name, meetingID)
+	log.Printf("Peer %s (%s) joined meeting %s", peer.ID, peer.Name, meetingID)
 
 	return peer, nil
 }
 
-// LeaveMeeting removes a peer from a WebRTC room
+// LeaveMeeting marks a peer's WebRTC connection as closed. The peer stays in
+// the meeting until its WebSocket connection closes.
 func (s *WebRTCService) LeaveMeeting(meetingID string, peerID string) error {
-	s.roomsMutex.Lock()
-	defer s.roomsMutex.Unlock()
-
-	room, exists := s.rooms[meetingID]
-	if !exists {
-		return fmt.Errorf("room not found for meeting %s", meetingID)
+	if err := s.UpdatePeerState(meetingID, peerID, models.PeerStateClosed); err != nil {
+		return err
 	}
 
-	_, exists = room.GetPeer(peerID)
-	if !exists {
-		return fmt.Errorf("peer %s not found in room %s", peerID, meetingID)
-	}
-
-	// Remove peer from room
-	room.RemovePeer(peerID)
-
-	// Remove room if empty
-	if room.IsEmpty() {
-		delete(s.rooms, meetingID)
-	}
-
-	// Notify other peers about the disconnection
-	s.notifyPeerLeft(meetingID, peerID)
-
 	log.Printf("Peer %s left meeting %s", peerID, meetingID)
 
 	return nil
 }
 
-// GetMeetingPeers returns all peers in a meeting
-func (s *WebRTCService) GetMeetingPeers(meetingID string) []*models.WebRTCPeer {
-	s.roomsMutex.RLock()
-	defer s.roomsMutex.RUnlock()
-
-	room, exists := s.rooms[meetingID]
-	if !exists {
-		return []*models.WebRTCPeer{}
+// FindActorPeer returns the actor's WebSocket connection peerID to a
+// meeting, or its first one when peerID is empty. It fails with "peer not
+// connected" when there is no such connection and "peer not owned" when it
+// is someone else's.
+func (s *WebRTCService) FindActorPeer(meetingID string, peerID string, actor models.MeetingActor) (*models.WebRTCPeer, error) {
+	for _, presence := range s.wsService.GetMeetingParticipants(meetingID) {
+		if peerID == "" {
+			if presence.Matches(actor) {
+				return presence.ToPeer(), nil
+			}
+			continue
+		}
+		if presence.ClientID == peerID {
+			if !presence.Matches(actor) {
+				return nil, errors.New("peer not owned")
+			}
+			return presence.ToPeer(), nil
+		}
 	}
+	return nil, errors.New("peer not connected")
+}
 
-	return room.GetAllPeers()
+// GetMeetingPeers returns all peers in a meeting, on every node of the
+// cluster, in the order they joined
+func (s *WebRTCService) GetMeetingPeers(meetingID string) []*models.WebRTCPeer {
+	presences := s.wsService.GetMeetingParticipants(meetingID)
+	peers := make([]*models.WebRTCPeer, 0, len(presences))
+	for _, presence := range presences {
+		peers = append(peers, presence.ToPeer())
+	}
+	return peers
 }
 
 // GetPeer returns a specific peer in a meeting
 func (s *WebRTCService) GetPeer(meetingID string, peerID string) (*models.WebRTCPeer, error) {
-	s.roomsMutex.RLock()
-	defer s.roomsMutex.RUnlock()
-
-	room, exists := s.rooms[meetingID]
-	if !exists {
-		return nil, fmt.Errorf("room not found for meeting %s", meetingID)
-	}
-
-	peer, exists := room.GetPeer(peerID)
-	if !exists {
-		return nil, fmt.Errorf("peer %s not found in room %s", peerID, meetingID)
+	for _, presence := range s.wsService.GetMeetingParticipants(meetingID) {
+		if presence.ClientID == peerID {
+			return presence.ToPeer(), nil
+		}
 	}
-
-	return peer, nil
+	return nil, fmt.Errorf("peer %s not found in meeting %s", peerID, meetingID)
 }
 
-// UpdatePeerState updates a peer's connection state
+// UpdatePeerState updates a peer's connection state and tells the meeting.
+// Peers that stay disconnected or failed are asked to restart ICE.
 func (s *WebRTCService) UpdatePeerState(meetingID string, peerID string, state models.PeerConnectionState) error {
-	s.roomsMutex.Lock()
-	defer s.roomsMutex.Unlock()
-
-	room, exists := s.rooms[meetingID]
-	if !exists {
-		return fmt.Errorf("room not found for meeting %s", meetingID)
+	if _, err := s.wsService.UpdatePeerState(meetingID, peerID, state); err != nil {
+		if errors.Is(err, models.ErrHubClientNotFound) {
+			return fmt.Errorf("peer %s not found in meeting %s", peerID, meetingID)
+		}
+		return err
 	}
-
-	room.UpdatePeerState(peerID, state)
 	log.Printf("Peer %s state updated to %s in meeting %s", peerID, state, meetingID)
 
+	if s.negotiation != nil {
+		s.negotiation.PeerStateChanged(meetingID, peerID, state)
+	}
+
 	return nil
 }
 
@@ -231,7 +271,7 @@ func (s *WebRTCService) SendOffer(meetingID string, fromPeerID string, toPeerID
 	}
 
 	// Send via WebSocket
-	if err := s.wsService.SendMessageToClient(toPeerID, message); err != nil {
+	if err := s.wsService.SendSignal(message); err != nil {
 		return fmt.Errorf("failed to send offer: %w", err)
 	}
 
@@ -263,7 +303,7 @@ func (s *WebRTCService) SendAnswer(meetingID string, fromPeerID string, toPeerID
 	}
 
 	// Send via WebSocket
-	if err := s.wsService.SendMessageToClient(toPeerID, message); err != nil {
+	if err := s.wsService.SendSignal(message); err != nil {
 		return fmt.Errorf("failed to send answer: %w", err)
 	}
 
@@ -295,7 +335,7 @@ func (s *WebRTCService) SendIceCandidate(meetingID string, fromPeerID string, to
 	}
 
 	// Send via WebSocket
-	if err := s.wsService.SendMessageToClient(toPeerID, message); err != nil {
+	if err := s.wsService.SendSignal(message); err != nil {
 		return fmt.Errorf("failed to send ICE candidate: %w", err)
 	}
 
@@ -303,93 +343,42 @@ func (s *WebRTCService) SendIceCandidate(meetingID string, fromPeerID string, to
 	return nil
 }
 
-// notifyPeerJoined notifies other peers about a new participant
-func (s *WebRTCService) notifyPeerJoined(meetingID string, newPeer *models.WebRTCPeer) {
-	peers := s.GetMeetingPeers(meetingID)
-	
-	for _, peer := range peers {
-		if peer.ID != newPeer.ID {
-			message := models.SignalingMessage{
-				Type:      models.SignalingTypeParticipantJoined,
-				MeetingID: meetingID,
-				From:      newPeer.ID,
-				Data: models.JoinPayload{
-					ParticipantID:   newPeer.ID,
-					Name:            newPeer.Name,
-					AvatarURL:       "", // Will be populated from user data if needed
-					IsAuthenticated: newPeer.IsAuth,
-				},
-				Timestamp: time.Now(),
-			}
-
-			if err := s.wsService.SendMessageToClient(peer.ID, message); err != nil {
-				log.Printf("Failed to notify peer %s about new participant: %v", peer.ID, err)
-			}
-		}
-	}
-}
-
-// notifyPeerLeft notifies other peers about a participant leaving
-func (s *WebRTCService) notifyPeerLeft(meetingID string, leftPeerID string) {
-	peers := s.GetMeetingPeers(meetingID)
-	
-	for _, peer := range peers {
-		if peer.ID != leftPeerID {
-			message := models.SignalingMessage{
-				Type:      models.SignalingTypeParticipantLeft,
-				MeetingID: meetingID,
-				From:      leftPeerID,
-				Data: models.LeavePayload{
-					ParticipantID: leftPeerID,
-				},
-				Timestamp: time.Now(),
-			}
-
-			if err := s.wsService.SendMessageToClient(peer.ID, message); err != nil {
-				log.Printf("Failed to notify peer %s about participant leaving: %v", peer.ID, err)
-			}
-		}
-	}
-}
-
-// GetRoomStats returns statistics about a WebRTC room
+// GetRoomStats returns statistics about a meeting's WebRTC peers
 func (s *WebRTCService) GetRoomStats(meetingID string) map[string]interface{} {
-	s.roomsMutex.RLock()
-	defer s.roomsMutex.RUnlock()
-
-	room, exists := s.rooms[meetingID]
-	if !exists {
+	peers := s.GetMeetingPeers(meetingID)
+	if len(peers) == 0 {
 		return map[string]interface{}{
 			"exists": false,
 		}
 	}
 
-	peers := room.GetAllPeers()
 	stateCount := make(map[models.PeerConnectionState]int)
-	
+	codecs := make(map[string]map[string]string)
+	createdAt := peers[0].JoinedAt
+	lastActivity := peers[0].LastSeen
 	for _, peer := range peers {
 		stateCount[peer.State]++
+		if len(peer.Codecs) > 0 {
+			codecs[peer.ID] = peer.Codecs
+		}
+		if peer.JoinedAt.Before(createdAt) {
+			createdAt = peer.JoinedAt
+		}
+		if peer.LastSeen.After(lastActivity) {
+			lastActivity = peer.LastSeen
+		}
 	}
 
-	return map[string]interface{}{
+	stats := map[string]interface{}{
 		"exists":         true,
 		"peerCount":      len(peers),
-		"createdAt":      room.CreatedAt,
-		"lastActivity":   room.LastActivity,
+		"createdAt":      createdAt,
+		"lastActivity":   lastActivity,
 		"stateCount":     stateCount,
+		"codecs":         codecs,
 	}
-}
-
-// GetAllRooms returns all active WebRTC rooms (for admin/monitoring)
-func (s *WebRTCService) GetAllRooms() map[string]*models.WebRTCRoom {
-	s.roomsMutex.RLock()
-	defer s.roomsMutex.RUnlock()
-
-	// Return a copy to avoid concurrent modification
-	roomsCopy := make(map[string]*models.WebRTCRoom)
-	for k, v := range s.rooms {
-		roomsCopy[k] = v
+	if s.quality != nil {
+		stats["qualityScore"] = s.quality.MeetingScore(meetingID)
 	}
-
-	return roomsCopy
-}
\ No newline at end of file
+	return stats
+}
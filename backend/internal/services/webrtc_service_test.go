@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestWebRTCService_PeerRegistry(t *testing.T) {
+	db := setupTestMeetingDB(t)
+	meeting, hostID := createTestMeeting(t, db)
+	meetingID := meeting.ID.String()
+
+	wsService := NewWebSocketService(db, nil, nil)
+	wsService.StartHub()
+	service := NewWebRTCService(db, wsService)
+	defer service.Stop()
+
+	connect := func(clientID string, userID uuid.UUID) *models.WebSocketClient {
+		client := &models.WebSocketClient{
+			ID:        clientID,
+			MeetingID: meetingID,
+			UserID:    &userID,
+			Name:      clientID,
+			IsAuth:    true,
+			Send:      make(chan models.SignalingMessage, 16),
+			Hub:       wsService.hub,
+		}
+		wsService.hub.Register <- client
+		return client
+	}
+	peerIDs := func() []string {
+		var ids []string
+		for _, peer := range service.GetMeetingPeers(meetingID) {
+			ids = append(ids, peer.ID)
+		}
+		return ids
+	}
+
+	host := connect("host", hostID)
+	guest := connect("guest", uuid.New())
+	require.Eventually(t, func() bool { return len(peerIDs()) == 2 }, time.Second, 10*time.Millisecond)
+
+	// WebRTC peers are the WebSocket participants
+	var participantIDs []string
+	for _, participant := range wsService.GetMeetingParticipants(meetingID) {
+		participantIDs = append(participantIDs, participant.ClientID)
+	}
+	assert.ElementsMatch(t, participantIDs, peerIDs())
+
+	// Joining picks the user's connection and tells the room
+	peer, err := service.JoinMeeting(meetingID, "", models.MeetingActor{UserID: &hostID})
+	require.NoError(t, err)
+	assert.Equal(t, "host", peer.ID)
+	assert.Equal(t, models.PeerStateConnecting, peer.State)
+	updated := waitForMessage(t, guest, models.SignalingTypeParticipantUpdated)
+	assert.Equal(t, "host", updated.From)
+
+	// Other people's connections are refused, unlike missing ones
+	_, err = service.JoinMeeting(meetingID, "guest", models.MeetingActor{UserID: &hostID})
+	assert.EqualError(t, err, "peer not owned")
+	_, err = service.JoinMeeting(meetingID, "nobody", models.MeetingActor{UserID: &hostID})
+	assert.EqualError(t, err, "peer not connected")
+
+	require.NoError(t, service.UpdatePeerState(meetingID, "host", models.PeerStateConnected))
+	peer, err = service.GetPeer(meetingID, "host")
+	require.NoError(t, err)
+	assert.Equal(t, models.PeerStateConnected, peer.State)
+	stats := service.GetRoomStats(meetingID)
+	assert.Equal(t, 2, stats["peerCount"])
+	assert.Equal(t, map[models.PeerConnectionState]int{models.PeerStateConnected: 1, models.PeerStateNew: 1}, stats["stateCount"])
+
+	// Disconnecting drops the peer
+	wsService.hub.Unregister <- host
+	require.Eventually(t, func() bool { return len(peerIDs()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Error(t, service.UpdatePeerState(meetingID, "host", models.PeerStateClosed))
+	_, err = service.GetPeer(meetingID, "host")
+	assert.Error(t, err)
+}
+
+// waitForMessage reads a client's queued messages until one of the given
+// type arrives
+func waitForMessage(t *testing.T, client *models.WebSocketClient, messageType models.SignalingMessageType) models.SignalingMessage {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case message := <-client.Send:
+			if message.Type == messageType {
+				return message
+			}
+		case <-timeout:
+			t.Fatalf("no %s message arrived", messageType)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/config"
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestWebSocketService_ChatMessages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestChatDB(t, &models.MeetingBan{})
+	meeting, hostID := createTestMeeting(t, db)
+	guest := addTestParticipant(t, db, meeting.ID, "guest", models.RoleAttendee)
+
+	jwtService := NewJWTService(config.JWTConfig{Secret: "test-secret", AccessTokenExpiry: time.Minute})
+	roleService := NewRoleService(db, nil)
+	service := NewWebSocketService(db, jwtService, nil)
+	chatService := NewChatService(db, service, nil, roleService)
+	filter, err := NewRuleChatFilter([]models.ChatFilterRule{
+		{Name: "threats", Words: []string{"hack you"}, Action: models.ChatFilterBlock},
+	})
+	require.NoError(t, err)
+	chatService.SetContentFilter(filter)
+	service.SetChatService(chatService)
+	moderation := NewModerationService(db, nil, roleService)
+	service.StartHub()
+	router := gin.New()
+	router.GET("/meetings/:id/ws", service.HandleWebSocket)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	dial := func(userID uuid.UUID) *websocket.Conn {
+		var user models.User
+		require.NoError(t, db.First(&user, "id = ?", userID).Error)
+		tokens, err := jwtService.GenerateTokenPair(&user, uuid.New(), uuid.New())
+		require.NoError(t, err)
+
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/meetings/" + meeting.ID.String() + "/ws"
+		conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Authorization": {"Bearer " + tokens.AccessToken}})
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	send := func(conn *websocket.Conn, messageType models.SignalingMessageType, data map[string]interface{}) {
+		require.NoError(t, conn.WriteJSON(models.SignalingMessage{Type: messageType, Data: data}))
+	}
+	refused := func(conn *websocket.Conn) string {
+		rejected, _ := readUntil(t, conn, models.SignalingTypeError)
+		return rejected.Data.(map[string]interface{})["code"].(string)
+	}
+
+	host := dial(hostID)
+	attendee := dial(*guest.UserID)
+	require.Eventually(t, func() bool {
+		return service.GetParticipantCount(meeting.ID.String()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Messages sent over the socket are saved and broadcast by the chat
+	// service, not relayed as sent
+	send(attendee, models.SignalingTypeChatMessage, map[string]interface{}{"content": "hello", "userId": hostID})
+	received, _ := readUntil(t, host, models.SignalingTypeChatMessage)
+	sent := received.Data.(map[string]interface{})["message"].(map[string]interface{})
+	assert.Equal(t, "hello", sent["content"])
+	assert.Equal(t, guest.UserID.String(), sent["userId"])
+	messageID, err := uuid.Parse(sent["id"].(string))
+	require.NoError(t, err)
+
+	// The content filter applies to messages and edits alike
+	send(attendee, models.SignalingTypeChatMessage, map[string]interface{}{"content": "I will hack you"})
+	assert.Equal(t, "CONTENT_BLOCKED", refused(attendee))
+	send(attendee, models.SignalingTypeChatMessageEdit, map[string]interface{}{"messageId": messageID, "content": "I will hack you"})
+	assert.Equal(t, "CONTENT_BLOCKED", refused(attendee))
+
+	// Only the author edits a message
+	send(host, models.SignalingTypeChatMessageEdit, map[string]interface{}{"messageId": messageID, "content": "bye"})
+	assert.Equal(t, "PERMISSION_DENIED", refused(host))
+	send(attendee, models.SignalingTypeChatMessageEdit, map[string]interface{}{"messageId": messageID, "content": "hello all"})
+	edited, _ := readUntil(t, host, models.SignalingTypeChatMessageEdit)
+	assert.Equal(t, "hello all", edited.Data.(map[string]interface{})["message"].(map[string]interface{})["content"])
+
+	// Slow mode holds the attendee back
+	_, err = moderation.SetChatSlowMode(meeting.ID, hostID, 30)
+	require.NoError(t, err)
+	send(attendee, models.SignalingTypeChatMessage, map[string]interface{}{"content": "again"})
+	assert.Equal(t, "SLOW_MODE", refused(attendee))
+
+	var saved int64
+	require.NoError(t, db.Model(&models.ChatMessage{}).Where("meeting_id = ?", meeting.ID).Count(&saved).Error)
+	assert.Equal(t, int64(1), saved)
+
+	// Reactions are validated and saved, and carry the sender's identity
+	send(attendee, models.SignalingTypeChatReaction, map[string]interface{}{"messageId": messageID, "reaction": "not an emoji"})
+	assert.Equal(t, "INVALID_REACTION", refused(attendee))
+	send(attendee, models.SignalingTypeChatReaction, map[string]interface{}{"messageId": messageID, "reaction": "👍", "userId": hostID})
+	reacted, _ := readUntil(t, host, models.SignalingTypeChatReaction)
+	assert.Equal(t, guest.UserID.String(), reacted.Data.(map[string]interface{})["userId"])
+	var reactions int64
+	require.NoError(t, db.Model(&models.ChatMessageReaction{}).Where("message_id = ? AND user_id = ?", messageID, *guest.UserID).Count(&reactions).Error)
+	assert.Equal(t, int64(1), reactions)
+
+	// A moderator's delete leaves a tombstone
+	send(host, models.SignalingTypeChatMessageDelete, map[string]interface{}{"messageId": messageID, "reason": "off topic"})
+	deleted, _ := readUntil(t, attendee, models.SignalingTypeChatMessageDelete)
+	assert.Equal(t, messageID.String(), deleted.Data.(map[string]interface{})["message"].(map[string]interface{})["id"])
+	var tombstone models.ChatMessage
+	require.NoError(t, db.First(&tombstone, "id = ?", messageID).Error)
+	assert.True(t, tombstone.IsDeleted)
+	require.NotNil(t, tombstone.DeletedByID)
+	assert.Equal(t, hostID, *tombstone.DeletedByID)
+}
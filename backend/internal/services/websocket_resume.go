@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// snapshotMessageLimit is how many of the latest chat messages a snapshot
+// carries
+const snapshotMessageLimit = 50
+
+// resumeSession catches a reconnecting client up from the last event it
+// saw: the missed events are replayed, followed by a resumed message, or a
+// snapshot of the meeting is sent when they are no longer all kept. It
+// returns the sequence number up to which the client is now current, so
+// that events queued meanwhile are not delivered twice. Only writePump may
+// call it, as it writes to the connection.
+func (s *WebSocketService) resumeSession(client *models.WebSocketClient, lastSeq uint64) (uint64, error) {
+	events, toSeq, complete := s.hub.EventsSince(client.MeetingID, client.ID, lastSeq)
+	if !complete {
+		return s.sendSnapshot(client)
+	}
+
+	for _, event := range events {
+		s.applyClientState(client, event)
+		if err := s.writeMessage(client, event); err != nil {
+			return 0, err
+		}
+	}
+
+	err := s.writeMessage(client, models.SignalingMessage{
+		Type:      models.SignalingTypeResumed,
+		MeetingID: client.MeetingID,
+		Data: models.ResumedPayload{
+			FromSeq:  lastSeq,
+			ToSeq:    toSeq,
+			Replayed: len(events),
+		},
+		Timestamp: time.Now(),
+	})
+	return toSeq, err
+}
+
+// sendSnapshot writes the meeting's current state to the client: its
+// status, roster, latest chat messages and pins
+func (s *WebSocketService) sendSnapshot(client *models.WebSocketClient) (uint64, error) {
+	// The sequence number is taken first; later events may show in the
+	// snapshot as well as arrive on their own, which clients take in stride
+	seq := s.hub.LastSeq(client.MeetingID)
+
+	var meeting models.Meeting
+	if err := s.db.Where("id = ?", client.MeetingID).First(&meeting).Error; err != nil {
+		return 0, fmt.Errorf("failed to load meeting: %w", err)
+	}
+	snapshot := models.SnapshotPayload{
+		Seq:          seq,
+		Status:       meeting.Status,
+		IsLocked:     meeting.IsLocked,
+		ChatSlowMode: meeting.ChatSlowMode,
+		Participants: []models.JoinPayload{},
+		Messages:     []models.ChatMessageResponse{},
+		Pinned:       []models.ChatMessageResponse{},
+	}
+
+	for _, participant := range s.hub.GetMeetingParticipants(client.MeetingID) {
+		if participant.ClientID == client.ID {
+			continue
+		}
+		snapshot.Participants = append(snapshot.Participants, models.JoinPayload{
+			ParticipantID:   participant.ClientID,
+			Name:            participant.Name,
+			IsAuthenticated: participant.IsAuth,
+			PeerState:       participant.PeerState,
+		})
+	}
+
+	if s.chatService != nil {
+		viewer := models.MeetingActor{UserID: client.UserID, PublicUserID: client.PublicUserID}
+		messages, pagination, err := s.chatService.GetMessages(meeting.ID, models.ChatHistoryQuery{Limit: snapshotMessageLimit, Viewer: viewer})
+		if err != nil {
+			return 0, err
+		}
+		for _, message := range messages {
+			snapshot.Messages = append(snapshot.Messages, message.ToResponse())
+		}
+		snapshot.Pagination = pagination
+
+		// The client was admitted when it connected
+		pinned, err := s.chatService.pinnedMessages(meeting.ID, viewer)
+		if err != nil {
+			return 0, err
+		}
+		for _, message := range pinned {
+			snapshot.Pinned = append(snapshot.Pinned, message.ToResponse())
+		}
+	}
+
+	err := s.writeMessage(client, models.SignalingMessage{
+		Type:      models.SignalingTypeSnapshot,
+		MeetingID: client.MeetingID,
+		Data:      snapshot,
+		Timestamp: time.Now(),
+	})
+	return seq, err
+}
+
+// writeMessage writes a message straight to the client's connection
+func (s *WebSocketService) writeMessage(client *models.WebSocketClient, message models.SignalingMessage) error {
+	client.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if err := client.Conn.WriteJSON(message); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
+
+// SetEventLog keeps the meetings' chat and roster events in events, so
+// reconnecting clients can resume. It must be called before StartHub.
+func (s *WebSocketService) SetEventLog(events models.HubEventLog) {
+	s.hub.SetEventLog(events)
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+// createTestGuest creates a public user to connect as, returning its session
+// ID and the client ID its connections get
+func createTestGuest(t *testing.T, db *gorm.DB, name string) (string, string) {
+	guest := &models.PublicUser{Name: name, SessionID: name + "-session"}
+	require.NoError(t, db.Create(guest).Error)
+	return guest.SessionID, "public_" + guest.ID.String()
+}
+
+// readUntil reads from a connection until a message of the given type
+// arrives, returning it and the messages before it
+func readUntil(t *testing.T, conn *websocket.Conn, messageType models.SignalingMessageType) (models.SignalingMessage, []models.SignalingMessage) {
+	var before []models.SignalingMessage
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var message models.SignalingMessage
+		require.NoError(t, conn.ReadJSON(&message))
+		if message.Type == messageType {
+			return message, before
+		}
+		before = append(before, message)
+	}
+}
+
+func TestWebSocketService_Resume(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestChatDB(t, &models.MeetingBan{})
+	meeting, hostID := createTestMeeting(t, db)
+	createTestChatMessages(t, db, meeting.ID, hostID, 3)
+
+	service := NewWebSocketService(db, nil, nil)
+	service.SetEventLog(models.NewMemoryHubEventLog(4, time.Minute))
+	service.SetChatService(NewChatService(db, service, nil, NewRoleService(db, nil)))
+	service.StartHub()
+	router := gin.New()
+	router.GET("/meetings/:id/ws", service.HandleWebSocket)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	dial := func(sessionID, query string) *websocket.Conn {
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/meetings/" + meeting.ID.String() + "/ws?sessionId=" + sessionID + query
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	disconnect := func(conn *websocket.Conn, remaining int) {
+		conn.Close()
+		require.Eventually(t, func() bool {
+			return service.GetParticipantCount(meeting.ID.String()) == remaining
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+	chat := func(content string) {
+		service.SendMessageToMeeting(meeting.ID.String(), models.SignalingMessage{
+			Type: models.SignalingTypeChatMessage,
+			Data: map[string]interface{}{"content": content},
+		})
+	}
+
+	aliceSession, _ := createTestGuest(t, db, "alice")
+	bobSession, bobID := createTestGuest(t, db, "bob")
+	alice := dial(aliceSession, "")
+	bob := dial(bobSession, "")
+	joined, _ := readUntil(t, alice, models.SignalingTypeParticipantJoined)
+	require.NotZero(t, joined.Seq)
+
+	// Alice misses a message while away and gets it on reconnecting, but not
+	// her own leaving
+	disconnect(alice, 1)
+	chat("while you were away")
+	live, _ := readUntil(t, bob, models.SignalingTypeChatMessage)
+	assert.NotZero(t, live.Seq)
+
+	alice = dial(aliceSession, "&lastSeq="+strconv.FormatUint(joined.Seq, 10))
+	resumed, replayed := readUntil(t, alice, models.SignalingTypeResumed)
+	require.Len(t, replayed, 1)
+	assert.Equal(t, models.SignalingTypeChatMessage, replayed[0].Type)
+	assert.Equal(t, live.Seq, replayed[0].Seq)
+	payload := resumed.Data.(map[string]interface{})
+	assert.Equal(t, float64(1), payload["replayed"])
+
+	// Once more is missed than the log keeps, a snapshot replaces it
+	disconnect(alice, 1)
+	for _, content := range []string{"one", "two", "three", "four", "five"} {
+		chat(content)
+	}
+	alice = dial(aliceSession, "&lastSeq="+strconv.FormatUint(live.Seq, 10))
+	snapshot, replayed := readUntil(t, alice, models.SignalingTypeSnapshot)
+	assert.Empty(t, replayed)
+	state := snapshot.Data.(map[string]interface{})
+	participants := state["participants"].([]interface{})
+	require.Len(t, participants, 1)
+	assert.Equal(t, bobID, participants[0].(map[string]interface{})["participantId"])
+	assert.Len(t, state["messages"].([]interface{}), 3)
+
+	// Events up to the snapshot are not delivered again; later ones are
+	chat("after")
+	next, before := readUntil(t, alice, models.SignalingTypeChatMessage)
+	assert.Empty(t, before)
+	assert.Greater(t, next.Seq, uint64(state["seq"].(float64)))
+	assert.Equal(t, "after", next.Data.(map[string]interface{})["content"])
+}
+
+func TestWebSocketService_ResumeAppliesClientState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestChatDB(t, &models.MeetingBan{})
+	meeting, _ := createTestMeeting(t, db)
+
+	service := NewWebSocketService(db, nil, nil)
+	service.SetEventLog(models.NewMemoryHubEventLog(16, time.Minute))
+	service.StartHub()
+	router := gin.New()
+	router.GET("/meetings/:id/ws", service.HandleWebSocket)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	dial := func(sessionID, query string) *websocket.Conn {
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/meetings/" + meeting.ID.String() + "/ws?sessionId=" + sessionID + query
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	peerState := func(clientID string) models.PeerConnectionState {
+		for _, participant := range service.GetMeetingParticipants(meeting.ID.String()) {
+			if participant.ClientID == clientID {
+				return participant.PeerState
+			}
+		}
+		return ""
+	}
+
+	aliceSession, aliceID := createTestGuest(t, db, "alice")
+	bobSession, _ := createTestGuest(t, db, "bob")
+	alice := dial(aliceSession, "")
+	dial(bobSession, "")
+	joined, _ := readUntil(t, alice, models.SignalingTypeParticipantJoined)
+
+	// An update about Alice's peer, sent while she was away, still applies
+	// to her connection once replayed
+	alice.Close()
+	require.Eventually(t, func() bool {
+		return service.GetParticipantCount(meeting.ID.String()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	service.SendMessageToMeeting(meeting.ID.String(), models.SignalingMessage{
+		Type: models.SignalingTypeParticipantUpdated,
+		From: aliceID,
+		Data: models.PeerStatePayload{ParticipantID: aliceID, State: models.PeerStateConnected, LastSeen: time.Now()},
+	})
+
+	alice = dial(aliceSession, "&lastSeq="+strconv.FormatUint(joined.Seq, 10))
+	_, replayed := readUntil(t, alice, models.SignalingTypeResumed)
+	require.Len(t, replayed, 1)
+	assert.Equal(t, models.SignalingTypeParticipantUpdated, replayed[0].Type)
+	require.Eventually(t, func() bool {
+		return peerState(aliceID) == models.PeerStateConnected
+	}, 5*time.Second, 10*time.Millisecond)
+}
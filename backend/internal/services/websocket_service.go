@@ -2,10 +2,13 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,6 +24,13 @@ type WebSocketService struct {
 	upgrader     websocket.Upgrader
 	jwtService   *JWTService
 	webrtcService *WebRTCService
+	meetingService *MeetingService
+	roleService   *RoleService
+	lobbyService  *LobbyService
+	accessService *MeetingAccessService
+	chatService   *ChatService
+	mediaPolicy   *MediaPolicyService
+	quality       *QualityService
 }
 
 func NewWebSocketService(db *gorm.DB, jwtService *JWTService, webrtcService *WebRTCService) *WebSocketService {
@@ -67,6 +77,11 @@ func (s *WebSocketService) HandleWebSocket(ctx *gin.Context) {
 
 	log.Printf("Meeting found: %s", meeting.Name)
 
+	if meeting.Status == models.MeetingStatusArchived {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Meeting is archived"})
+		return
+	}
+
 	// Upgrade HTTP connection to WebSocket
 	conn, err := s.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
 	if err != nil {
@@ -75,9 +90,17 @@ func (s *WebSocketService) HandleWebSocket(ctx *gin.Context) {
 	}
 
 	// Get client information from query parameters or JWT token
-	clientID := ctx.Query("clientId")
 	sessionID := ctx.Query("sessionId")
 
+	// Reconnecting clients pass the sequence number of the last event they
+	// saw, to be caught up on what they missed
+	var resumeFrom *uint64
+	if raw := ctx.Query("lastSeq"); raw != "" {
+		if seq, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			resumeFrom = &seq
+		}
+	}
+
 	// Determine if user is authenticated or public user
 	var userID *uuid.UUID
 	var publicUserID *uuid.UUID
@@ -110,27 +133,74 @@ func (s *WebSocketService) HandleWebSocket(ctx *gin.Context) {
 		}
 	}
 
+	// CRITICAL FIX: Use deterministic client ID instead of random UUID
+	// This prevents WebSocket client explosion by ensuring same user gets same ID.
+	// The ID is the client's peer identity, so it is always derived from who
+	// connected: a new connection with the same ID replaces the old one.
+	var clientID string
+	if userID != nil {
+		clientID = fmt.Sprintf("user_%s", userID.String())
+	} else if publicUserID != nil {
+		clientID = fmt.Sprintf("public_%s", publicUserID.String())
+	} else if sessionID != "" {
+		clientID = fmt.Sprintf("session_%s_%s", meetingID, sessionID)
+	} else {
+		// Last resort: generate but log for debugging
+		clientID = uuid.New().String()
+		log.Printf("[WARNING] Generated random client ID %s - no user identity found", clientID)
+	}
+	if requested := ctx.Query("clientId"); requested != "" && requested != clientID {
+		log.Printf("WebSocket connection refused for meeting %s: client ID %s does not match %s", meetingID, requested, clientID)
+		s.rejectConnection(conn, meetingID, errors.New("client ID mismatch"))
+		return
+	}
+
+	// Refuse banned users and newcomers to locked meetings
+	actor := models.MeetingActor{UserID: userID, PublicUserID: publicUserID}
+	if err := checkMeetingAdmission(s.db, &meeting, actor); err != nil {
+		log.Printf("WebSocket connection refused for meeting %s: %v", meetingID, err)
+		s.rejectConnection(conn, meetingID, err)
+		return
+	}
+
+	// Protected meetings need the passcode or an invite; browsers cannot set
+	// headers on the upgrade request, so both come as query parameters. An
+	// invite makes the newcomer a participant, who later reconnects as such.
+	var invite *models.MeetingInvite
+	if s.accessService != nil {
+		creds := models.MeetingCredentials{
+			Passcode:    ctx.Query("passcode"),
+			InviteToken: ctx.Query("inviteToken"),
+			ClientIP:    ctx.ClientIP(),
+		}
+		invite, err = s.accessService.CheckAccessAndJoin(&meeting, actor, userName, creds)
+		if err != nil {
+			log.Printf("WebSocket connection refused for meeting %s: %v", meetingID, err)
+			s.rejectConnection(conn, meetingID, err)
+			return
+		}
+	}
+
+	// Newcomers may have to wait in the lobby until a host admits them
+	pending := false
+	if s.lobbyService != nil && invite == nil {
+		held, err := s.lobbyService.RequiresLobby(&meeting, actor)
+		if err == nil && held {
+			_, err = s.lobbyService.Hold(&meeting, actor)
+		}
+		if err != nil {
+			log.Printf("WebSocket connection refused for meeting %s: %v", meetingID, err)
+			s.rejectConnection(conn, meetingID, err)
+			return
+		}
+		pending = held
+	}
+
 	// If still no user info, use anonymous
 	if userName == "" {
 		userName = "Anonymous User"
 	}
 	
-	// CRITICAL FIX: Use deterministic client ID instead of random UUID
-	// This prevents WebSocket client explosion by ensuring same user gets same ID
-	if clientID == "" {
-		if userID != nil {
-			clientID = fmt.Sprintf("user_%s", userID.String())
-		} else if publicUserID != nil {
-			clientID = fmt.Sprintf("public_%s", publicUserID.String())
-		} else if sessionID != "" {
-			clientID = fmt.Sprintf("session_%s_%s", meetingID, sessionID)
-		} else {
-			// Last resort: generate but log for debugging
-			clientID = uuid.New().String()
-			log.Printf("[WARNING] Generated random client ID %s - no user identity found", clientID)
-		}
-	}
-	
 	log.Printf("[DEBUG] Generated deterministic client ID: %s for user: %s (auth: %t)", clientID, userName, isAuth)
 
 	// Create WebSocket client
@@ -144,13 +214,24 @@ func (s *WebSocketService) HandleWebSocket(ctx *gin.Context) {
 		Conn:         conn,
 		Send:         make(chan models.SignalingMessage, 256),
 		Hub:          s.hub,
+		JoinedAt:     time.Now(),
 	}
+	client.SetRole(s.resolveClientRole(meeting.ID, userID, publicUserID))
+	client.SetChatMute(s.resolveClientChatMute(meeting.ID, userID, publicUserID))
+	client.SetPending(pending)
 
 	// Register client with hub
 	s.hub.Register <- client
 
+	// The first participant to connect puts the meeting live
+	if !pending && meeting.Status != models.MeetingStatusLive && s.meetingService != nil {
+		if err := s.meetingService.AutoStartMeeting(meeting.ID); err != nil {
+			log.Printf("[ERROR] Failed to start meeting %s: %v", meetingID, err)
+		}
+	}
+
 	// Start goroutines for reading and writing
-	go s.writePump(client)
+	go s.writePump(client, resumeFrom)
 	go s.readPump(client)
 }
 
@@ -186,18 +267,42 @@ func (s *WebSocketService) readPump(client *models.WebSocketClient) {
 			continue
 		}
 
-		// Set message metadata
+		// Set message metadata. Only peer signaling names a recipient; the
+		// hub checks it is in the client's meeting.
 		message.MeetingID = client.MeetingID
 		message.From = client.ID
 		message.Timestamp = time.Now()
+		message.Seq = 0
+		if !models.IsDirectedSignalingType(message.Type) {
+			message.To = ""
+		}
+
+		// Clients waiting in the lobby may not talk to the meeting
+		if client.IsPending() {
+			s.sendError(client, message.Type, "AWAITING_ADMISSION", "Wait for a host to admit you")
+			continue
+		}
+
+		// Enforce the client's meeting role before dispatching
+		if permission, required := models.PermissionForSignalingType(message.Type); required && !client.Role().Can(permission) {
+			log.Printf("[DEBUG] Rejected message type: %s from client: %s (role: %s)", message.Type, client.ID, client.Role())
+			s.sendError(client, message.Type, "PERMISSION_DENIED", "Your role does not allow this action")
+			continue
+		}
+
+		// Participants muted in chat may still read it, but not write to it
+		if permission, _ := models.PermissionForSignalingType(message.Type); permission == models.PermissionChat && client.IsChatMuted() {
+			s.sendError(client, message.Type, "CHAT_MUTED", "A moderator muted you in chat")
+			continue
+		}
 
 		// Handle different message types
 		log.Printf("[DEBUG] Processing message type: %s from client: %s", message.Type, client.ID)
 		switch message.Type {
 		case models.SignalingTypeOffer, models.SignalingTypeAnswer, models.SignalingTypeIceCandidate:
-			// Forward WebRTC signaling messages
-			log.Printf("[DEBUG] Forwarding WebRTC signaling message: %s", message.Type)
-			s.hub.Broadcast <- message
+			// Forward WebRTC signaling messages to their target only
+			log.Printf("[DEBUG] Forwarding WebRTC signaling message: %s to client: %s", message.Type, message.To)
+			s.handleSignal(client, &message)
 			
 		case models.SignalingTypeJoin:
 			// Handle join meeting (already handled in registration)
@@ -224,30 +329,51 @@ func (s *WebSocketService) readPump(client *models.WebSocketClient) {
 			log.Printf("[DEBUG] Handling chat reaction from client: %s", client.ID)
 			s.handleChatReaction(client, &message)
 			
-		case models.SignalingTypeChatReadStatus:
-			// Handle chat read status
-			log.Printf("[DEBUG] Handling chat read status from client: %s", client.ID)
-			s.handleChatReadStatus(client, &message)
+		case models.SignalingTypeChatMarkRead:
+			// Move the client's read cursor
+			log.Printf("[DEBUG] Handling chat mark read from client: %s", client.ID)
+			s.handleChatMarkRead(client, &message)
 			
 		case models.SignalingTypeChatTyping, models.SignalingTypeChatTypingStop:
 			// Handle chat typing indicators
 			log.Printf("[DEBUG] Handling chat typing: %s from client: %s", message.Type, client.ID)
 			s.handleChatTyping(client, &message)
 			
+		case models.SignalingTypeScreenShareStart, models.SignalingTypeScreenShareStop:
+			// Relay screen share state to the rest of the room
+			log.Printf("[DEBUG] Handling screen share: %s from client: %s", message.Type, client.ID)
+			s.broadcastToMeeting(client.MeetingID, message, client.ID)
+			
+		case models.SignalingTypeQualityStats:
+			// Record the client's connection quality
+			s.handleQualityStats(client, &message)
+			
 		default:
 			log.Printf("[DEBUG] Unknown message type: %s from client: %s", message.Type, client.ID)
 		}
 	}
 }
 
-// writePump handles writing messages to the WebSocket connection
-func (s *WebSocketService) writePump(client *models.WebSocketClient) {
+// writePump handles writing messages to the WebSocket connection. A
+// reconnecting client is first caught up from resumeFrom.
+func (s *WebSocketService) writePump(client *models.WebSocketClient, resumeFrom *uint64) {
 	ticker := time.NewTicker(54 * time.Second)
 	defer func() {
 		ticker.Stop()
 		client.Conn.Close()
 	}()
 
+	// Events queued while catching up that the client got then are skipped
+	var current uint64
+	if resumeFrom != nil && !client.IsPending() {
+		var err error
+		current, err = s.resumeSession(client, *resumeFrom)
+		if err != nil {
+			log.Printf("Failed to resume session of client %s: %v", client.ID, err)
+			return
+		}
+	}
+
 	for {
 		select {
 		case message, ok := <-client.Send:
@@ -258,12 +384,46 @@ func (s *WebSocketService) writePump(client *models.WebSocketClient) {
 				return
 			}
 
+			s.applyClientState(client, message)
+			if message.Seq != 0 && message.Seq <= current {
+				continue
+			}
+
+			// Lobby traffic is filtered per client; waiting clients get
+			// nothing else
+			denied := false
+			if models.IsLobbySignalingType(message.Type) {
+				var deliver bool
+				deliver, denied = s.applyLobbyMessage(client, message)
+				if !deliver {
+					continue
+				}
+			} else if client.IsPending() && message.Type != models.SignalingTypeError {
+				continue
+			}
+
 			// Write message
 			if err := client.Conn.WriteJSON(message); err != nil {
 				log.Printf("WebSocket write error: %v", err)
 				return
 			}
 
+			if denied {
+				log.Printf("[DEBUG] Closing connection of client denied entry: %s", client.ID)
+				client.Conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "entry denied"))
+				return
+			}
+
+			// A host removed this participant; closing the connection makes
+			// readPump unregister the client
+			if message.Type == models.SignalingTypeParticipantRemoved && s.isRemovalTarget(client, message) {
+				log.Printf("[DEBUG] Closing connection of removed client: %s", client.ID)
+				client.Conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "removed from meeting"))
+				return
+			}
+
 		case <-ticker.C:
 			// Send ping
 			client.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
@@ -280,25 +440,22 @@ func (s *WebSocketService) handleJoinMessage(client *models.WebSocketClient, mes
 	participants := s.hub.GetMeetingParticipants(client.MeetingID)
 	
 	for _, participant := range participants {
-		if participant.ID != client.ID {
+		if participant.ClientID != client.ID {
 			joinMessage := models.SignalingMessage{
 				Type:      models.SignalingTypeParticipantJoined,
 				MeetingID: client.MeetingID,
-				From:      participant.ID,
+				From:      participant.ClientID,
 				Data: models.JoinPayload{
-					ParticipantID:   participant.ID,
+					ParticipantID:   participant.ClientID,
 					Name:            participant.Name,
 					AvatarURL:       "", // Will be populated from user data
 					IsAuthenticated: participant.IsAuth,
+					PeerState:       participant.PeerState,
 				},
 				Timestamp: time.Now(),
 			}
 			
-			select {
-			case client.Send <- joinMessage:
-			default:
-				close(client.Send)
-			}
+			s.sendToClient(client.ID, joinMessage)
 		}
 	}
 }
@@ -320,15 +477,10 @@ func (s *WebSocketService) handleLeaveMessage(client *models.WebSocketClient, me
 		log.Printf("[DEBUG] Successfully updated participant status in database for client: %s", client.ID)
 	}
 	
-	// 2. Unregister from hub (this will also notify other participants)
+	// 2. Unregister from hub (this will also notify other participants and
+	// drop the client's WebRTC peer)
 	s.hub.Unregister <- client
 	
-	// 3. Remove from WebRTC service
-	if s.webrtcService != nil {
-		s.webrtcService.LeaveMeeting(client.MeetingID, client.ID)
-		log.Printf("[DEBUG] Removed client from WebRTC service: %s", client.ID)
-	}
-	
 	log.Printf("[DEBUG] Completed atomic cleanup for client: %s", client.ID)
 }
 
@@ -368,7 +520,7 @@ func (s *WebSocketService) updateParticipantStatus(tx *gorm.DB, client *models.W
 }
 
 // GetMeetingParticipants returns active WebSocket participants for a meeting
-func (s *WebSocketService) GetMeetingParticipants(meetingID string) []*models.WebSocketClient {
+func (s *WebSocketService) GetMeetingParticipants(meetingID string) []models.HubPresence {
 	return s.hub.GetMeetingParticipants(meetingID)
 }
 
@@ -392,17 +544,80 @@ func (s *WebSocketService) SendMessageToMeeting(meetingID string, message models
 // SendMessageToClient sends a message to a specific client
 func (s *WebSocketService) SendMessageToClient(clientID string, message models.SignalingMessage) error {
 	message.Timestamp = time.Now()
-	
-	if client, ok := s.hub.GetClientByID(clientID); ok {
-		select {
-		case client.Send <- message:
+	return s.hub.SendToClient(clientID, message)
+}
+
+// UpdatePeerState records the WebRTC peer state of a client admitted to a
+// meeting and broadcasts it to the meeting
+func (s *WebSocketService) UpdatePeerState(meetingID, clientID string, state models.PeerConnectionState) (models.HubPresence, error) {
+	return s.hub.UpdatePeerState(meetingID, clientID, state)
+}
+
+// SendSignal routes an offer, answer or ICE candidate to its target, which
+// must be admitted to the message's meeting, once the meeting's media policy
+// is applied to it. Host candidates the policy strips are dropped silently;
+// offers and answers tell the target its role in the pair.
+func (s *WebSocketService) SendSignal(message models.SignalingMessage) error {
+	message.Timestamp = time.Now()
+
+	var codecs map[string]string
+	if s.mediaPolicy != nil {
+		var relay bool
+		var err error
+		codecs, relay, err = s.mediaPolicy.ApplyToSignal(&message)
+		if err != nil {
+			return err
+		}
+		if !relay {
 			return nil
-		default:
-			return fmt.Errorf("client send channel is blocked")
 		}
 	}
-	
-	return fmt.Errorf("client not found")
+	if message.Type == models.SignalingTypeOffer || message.Type == models.SignalingTypeAnswer {
+		if err := s.markPolite(&message); err != nil {
+			return err
+		}
+	}
+
+	if err := s.hub.RouteSignal(message); err != nil {
+		return err
+	}
+
+	// An answer settles the codecs of both of its peers
+	if message.Type == models.SignalingTypeAnswer && len(codecs) > 0 {
+		for _, peerID := range []string{message.From, message.To} {
+			if _, err := s.hub.UpdatePeerCodecs(message.MeetingID, peerID, codecs); err != nil {
+				log.Printf("[DEBUG] Failed to record codecs of peer %s: %v", peerID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SendMessageToActors sends a message only to the connections of the given
+// users in a meeting, on whichever node they are connected
+func (s *WebSocketService) SendMessageToActors(meetingID string, actors []models.MeetingActor, message models.SignalingMessage) {
+	message.MeetingID = meetingID
+	message.Timestamp = time.Now()
+	for _, presence := range s.hub.GetMeetingParticipants(meetingID) {
+		for _, actor := range actors {
+			if presence.Matches(actor) {
+				s.sendToClient(presence.ClientID, message)
+				break
+			}
+		}
+	}
+}
+
+// SendMessageToModerators sends a message only to the meeting's hosts and
+// co-hosts
+func (s *WebSocketService) SendMessageToModerators(meetingID string, message models.SignalingMessage) {
+	message.MeetingID = meetingID
+	message.Timestamp = time.Now()
+	for _, presence := range s.hub.GetMeetingParticipants(meetingID) {
+		if presence.Role.Can(models.PermissionModerate) {
+			s.sendToClient(presence.ClientID, message)
+		}
+	}
 }
 
 // SetWebRTCService sets the WebRTC service reference (used to break circular dependency)
@@ -411,41 +626,509 @@ func (s *WebSocketService) SetWebRTCService(webrtcService *WebRTCService) {
 	log.Printf("[DEBUG] WebRTC service reference set in WebSocket service")
 }
 
-// handleChatMessage handles incoming chat messages
+// SetMeetingService sets the meeting service reference (used to break circular dependency)
+func (s *WebSocketService) SetMeetingService(meetingService *MeetingService) {
+	s.meetingService = meetingService
+}
+
+// SetLobbyService sets the lobby service used to hold newcomers for admission
+func (s *WebSocketService) SetLobbyService(lobbyService *LobbyService) {
+	s.lobbyService = lobbyService
+}
+
+// SetAccessService sets the service that checks passcodes and invites on connect
+func (s *WebSocketService) SetAccessService(accessService *MeetingAccessService) {
+	s.accessService = accessService
+}
+
+// SetChatService sets the chat service that sends, edits and reads clients' chat
+// messages
+func (s *WebSocketService) SetChatService(chatService *ChatService) {
+	s.chatService = chatService
+}
+
+// SetMediaPolicyService enforces the meetings' media policies on the
+// signaling relayed between peers
+func (s *WebSocketService) SetMediaPolicyService(mediaPolicy *MediaPolicyService) {
+	s.mediaPolicy = mediaPolicy
+}
+
+// SetQualityService records the connection quality stats clients send
+func (s *WebSocketService) SetQualityService(quality *QualityService) {
+	s.quality = quality
+}
+
+// SetRoleService sets the role service used to resolve clients' meeting roles
+func (s *WebSocketService) SetRoleService(roleService *RoleService) {
+	s.roleService = roleService
+}
+
+// resolveClientRole returns the meeting role of a connecting client. Guests
+// without a participant record join as attendees.
+func (s *WebSocketService) resolveClientRole(meetingID uuid.UUID, userID *uuid.UUID, publicUserID *uuid.UUID) models.ParticipantRole {
+	if s.roleService == nil {
+		return models.RoleAttendee
+	}
+
+	role, err := s.roleService.GetRole(meetingID, models.MeetingActor{UserID: userID, PublicUserID: publicUserID})
+	if err != nil {
+		return models.RoleAttendee
+	}
+	return role
+}
+
+// applyClientState updates the client's own state from a message on its way
+// to it, live or replayed: its role, chat mute and WebRTC peer details
+func (s *WebSocketService) applyClientState(client *models.WebSocketClient, message models.SignalingMessage) {
+	switch message.Type {
+	case models.SignalingTypeParticipantRoleChanged:
+		s.applyRoleChange(client, message)
+	case models.SignalingTypeChatMuted, models.SignalingTypeChatUnmuted:
+		s.applyChatMute(client, message)
+	case models.SignalingTypeParticipantUpdated:
+		s.applyPeerState(client, message)
+	}
+}
+
+// applyRoleChange updates the client's role when a role change for it is
+// delivered, so every node keeps its own connections in sync
+func (s *WebSocketService) applyRoleChange(client *models.WebSocketClient, message models.SignalingMessage) {
+	var payload models.RoleChangedPayload
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		log.Printf("Invalid role change payload: %v", err)
+		return
+	}
+
+	if client.Matches(payload.UserID, payload.PublicUserID) {
+		log.Printf("[DEBUG] Client %s role changed: %s -> %s", client.ID, client.Role(), payload.Role)
+		client.SetRole(payload.Role)
+		s.hub.RefreshPresence(client)
+	}
+}
+
+// applyPeerState records the client's WebRTC peer details when an update for
+// it is delivered, so the node holding the connection keeps its presence in
+// sync wherever the update came from
+func (s *WebSocketService) applyPeerState(client *models.WebSocketClient, message models.SignalingMessage) {
+	var payload models.PeerStatePayload
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		log.Printf("Invalid peer state payload: %v", err)
+		return
+	}
+
+	if payload.ParticipantID != client.ID {
+		return
+	}
+	if state, lastSeen := client.PeerState(); state == payload.State && lastSeen.Equal(payload.LastSeen) {
+		return
+	}
+	client.SetPeerState(payload.State, payload.LastSeen)
+	client.SetPeerCodecs(payload.Codecs)
+	s.hub.RefreshPresence(client)
+}
+
+// resolveClientChatMute returns whether a connecting client's participant
+// is muted in chat, and until when
+func (s *WebSocketService) resolveClientChatMute(meetingID uuid.UUID, userID *uuid.UUID, publicUserID *uuid.UUID) (bool, *time.Time) {
+	query := participantQuery(s.db, meetingID, models.MeetingActor{UserID: userID, PublicUserID: publicUserID})
+	if query == nil {
+		return false, nil
+	}
+	var participant models.Participant
+	if err := query.Select("is_chat_muted, chat_muted_until").Order("joined_at DESC").First(&participant).Error; err != nil {
+		return false, nil
+	}
+	return participant.ChatMuted(time.Now()), participant.ChatMutedUntil
+}
+
+// applyChatMute updates the client's chat mute when a mute or unmute for it
+// is delivered, like applyRoleChange does for roles
+func (s *WebSocketService) applyChatMute(client *models.WebSocketClient, message models.SignalingMessage) {
+	var payload models.ModerationPayload
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		log.Printf("Invalid chat mute payload: %v", err)
+		return
+	}
+
+	if client.Matches(payload.UserID, payload.PublicUserID) {
+		client.SetChatMute(message.Type == models.SignalingTypeChatMuted, payload.Until)
+	}
+}
+
+// applyLobbyMessage decides whether a lobby message is written to the client
+// and applies admission decisions about it. Moderators see all lobby traffic;
+// a waiting client only sees the decision about itself and is moved into the
+// meeting when admitted. It also reports whether the client was denied.
+func (s *WebSocketService) applyLobbyMessage(client *models.WebSocketClient, message models.SignalingMessage) (bool, bool) {
+	if !client.IsPending() {
+		return client.Role().Can(models.PermissionModerate), false
+	}
+	if message.Type == models.SignalingTypeLobbyRequest {
+		return false, false
+	}
+
+	var payload models.LobbyPayload
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		return false, false
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		log.Printf("Invalid lobby payload: %v", err)
+		return false, false
+	}
+	if !client.Matches(payload.UserID, payload.PublicUserID) {
+		return false, false
+	}
+
+	if message.Type == models.SignalingTypeLobbyDenied {
+		return true, true
+	}
+
+	if meetingID, err := uuid.Parse(client.MeetingID); err == nil {
+		client.SetRole(s.resolveClientRole(meetingID, client.UserID, client.PublicUserID))
+	}
+	s.hub.AdmitClient(client)
+	return true, false
+}
+
+// isRemovalTarget reports whether a participant-removed message is about
+// the client itself
+func (s *WebSocketService) isRemovalTarget(client *models.WebSocketClient, message models.SignalingMessage) bool {
+	var payload models.ModerationPayload
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		log.Printf("Invalid moderation payload: %v", err)
+		return false
+	}
+	return client.Matches(payload.UserID, payload.PublicUserID)
+}
+
+// rejectConnection tells a client why it may not enter the meeting and
+// closes the freshly upgraded connection
+func (s *WebSocketService) rejectConnection(conn *websocket.Conn, meetingID string, reason error) {
+	code := "ADMISSION_FAILED"
+	switch reason.Error() {
+	case "banned from meeting":
+		code = "BANNED_FROM_MEETING"
+	case "denied from lobby":
+		code = "LOBBY_DENIED"
+	case "meeting is locked":
+		code = "MEETING_LOCKED"
+	case "identity required":
+		code = "IDENTITY_REQUIRED"
+	case "passcode required":
+		code = "PASSCODE_REQUIRED"
+	case "invalid passcode":
+		code = "INVALID_PASSCODE"
+	case "too many passcode attempts":
+		code = "TOO_MANY_ATTEMPTS"
+	case "invalid invite", "invite expired", "invite revoked", "invite exhausted":
+		code = "INVALID_INVITE"
+	case "client ID mismatch":
+		code = "CLIENT_ID_MISMATCH"
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	conn.WriteJSON(models.SignalingMessage{
+		Type:      models.SignalingTypeError,
+		MeetingID: meetingID,
+		Data: models.ErrorPayload{
+			Code:    code,
+			Message: reason.Error(),
+		},
+		Timestamp: time.Now(),
+	})
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason.Error()))
+	conn.Close()
+}
+
+// sendError tells a client that one of its messages was rejected
+func (s *WebSocketService) sendError(client *models.WebSocketClient, messageType models.SignalingMessageType, code, reason string) {
+	s.sendToClient(client.ID, models.SignalingMessage{
+		Type:      models.SignalingTypeError,
+		MeetingID: client.MeetingID,
+		Data: models.ErrorPayload{
+			Code:        code,
+			Message:     reason,
+			MessageType: messageType,
+		},
+		Timestamp: time.Now(),
+	})
+}
+
+// SetHubBroker attaches a cluster broker so the hub relays meeting fan-out,
+// directed signaling and presence across backend nodes. It must be called
+// before StartHub.
+func (s *WebSocketService) SetHubBroker(broker models.HubBroker) error {
+	if err := s.hub.SetBroker(broker); err != nil {
+		return fmt.Errorf("failed to attach hub broker: %w", err)
+	}
+	log.Printf("[DEBUG] Hub broker attached to WebSocket service (node: %s)", broker.NodeID())
+	return nil
+}
+
+// handleSignal routes a client's offer, answer or ICE candidate to its
+// target and tells the client when it was rejected or could not be
+// delivered
+func (s *WebSocketService) handleSignal(client *models.WebSocketClient, message *models.SignalingMessage) {
+	err := s.SendSignal(*message)
+	if err == nil {
+		return
+	}
+
+	var code, reason string
+	switch {
+	case errors.Is(err, ErrInvalidSDP):
+		code, reason = "INVALID_SDP", err.Error()
+	case errors.Is(err, ErrInvalidICECandidate):
+		code, reason = "INVALID_ICE_CANDIDATE", err.Error()
+	case errors.Is(err, ErrMediaPolicyViolation):
+		code, reason = "MEDIA_POLICY_VIOLATION", err.Error()
+	case errors.Is(err, models.ErrSignalingTargetRequired):
+		code, reason = "TARGET_REQUIRED", "Signaling messages must name a target peer"
+	case errors.Is(err, models.ErrSignalingTargetNotInMeeting):
+		code, reason = "TARGET_NOT_IN_MEETING", "The target peer is not in this meeting"
+	case errors.Is(err, models.ErrHubClientNotFound):
+		code, reason = "TARGET_GONE", "The target peer has left the meeting"
+	default:
+		log.Printf("[ERROR] Failed to route message type: %s from client %s to %s: %v", message.Type, client.ID, message.To, err)
+		code, reason = "SIGNALING_FAILED", "The message could not be delivered"
+	}
+
+	s.sendToClient(client.ID, models.SignalingMessage{
+		Type:      models.SignalingTypeSignalingError,
+		MeetingID: client.MeetingID,
+		To:        client.ID,
+		Data: models.SignalingErrorPayload{
+			Code:        code,
+			Message:     reason,
+			MessageType: message.Type,
+			To:          message.To,
+		},
+		Timestamp: time.Now(),
+	})
+}
+
+// handleChatMessage sends a chat message like the send message endpoint:
+// the chat service checks, filters and saves it, then broadcasts it. The
+// sender's own payload is never relayed.
 func (s *WebSocketService) handleChatMessage(client *models.WebSocketClient, message *models.SignalingMessage) {
-	// Parse chat message payload from frontend format
-	var payload map[string]interface{}
+	if s.chatService == nil {
+		s.sendError(client, message.Type, "CHAT_UNAVAILABLE", "Chat is not available")
+		return
+	}
+
+	var req models.CreateChatMessageRequest
 	payloadBytes, err := json.Marshal(message.Data)
 	if err != nil {
 		log.Printf("Failed to marshal chat message payload: %v", err)
 		return
 	}
-	
-	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
-		log.Printf("Invalid chat message payload: %v", err)
+	if err := json.Unmarshal(payloadBytes, &req); err != nil || utf8.RuneCountInString(req.Content) > 2000 {
+		s.sendError(client, message.Type, "INVALID_PAYLOAD", "Invalid chat message")
 		return
 	}
-	
-	// Broadcast chat message to all participants in the meeting
-	s.hub.Broadcast <- *message
+
+	meetingID, err := uuid.Parse(client.MeetingID)
+	if err != nil {
+		return
+	}
+	req.MeetingID = meetingID
+	if req.MessageType == "" {
+		req.MessageType = models.MessageTypeText
+	}
+
+	if _, err := s.chatService.SendMessage(client.UserID, client.PublicUserID, &req); err != nil {
+		s.sendError(client, message.Type, chatErrorCode(err, "SEND_MESSAGE_FAILED"), err.Error())
+	}
 }
 
-// handleChatMessageUpdate handles chat message edits and deletions
+// handleChatMessageUpdate edits or deletes a chat message like the update
+// message endpoint; the chat service broadcasts the change
 func (s *WebSocketService) handleChatMessageUpdate(client *models.WebSocketClient, message *models.SignalingMessage) {
-	// Broadcast message update to all participants in the meeting
-	s.hub.Broadcast <- *message
+	if s.chatService == nil {
+		s.sendError(client, message.Type, "CHAT_UNAVAILABLE", "Chat is not available")
+		return
+	}
+	// Only registered users own messages they can change
+	if client.UserID == nil {
+		s.sendError(client, message.Type, "PERMISSION_DENIED", "Sign in to edit or delete messages")
+		return
+	}
+
+	var payload struct {
+		MessageID uuid.UUID `json:"messageId"`
+		models.UpdateChatMessageRequest
+	}
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		log.Printf("Failed to marshal chat message update payload: %v", err)
+		return
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil || payload.MessageID == uuid.Nil {
+		s.sendError(client, message.Type, "INVALID_PAYLOAD", "A message ID is required")
+		return
+	}
+
+	req := payload.UpdateChatMessageRequest
+	if message.Type == models.SignalingTypeChatMessageDelete {
+		deleted := true
+		req.IsDeleted = &deleted
+		req.Content = nil
+	} else if req.Content == nil {
+		s.sendError(client, message.Type, "INVALID_PAYLOAD", "The new content is required")
+		return
+	}
+	if (req.Content != nil && utf8.RuneCountInString(*req.Content) > 2000) || utf8.RuneCountInString(req.Reason) > 500 {
+		s.sendError(client, message.Type, "INVALID_PAYLOAD", "Invalid chat message update")
+		return
+	}
+
+	// The message must belong to the client's meeting
+	var existing models.ChatMessage
+	if err := s.db.Select("meeting_id").Where("id = ?", payload.MessageID).First(&existing).Error; err != nil || existing.MeetingID.String() != client.MeetingID {
+		s.sendError(client, message.Type, "NOT_FOUND", "message not found")
+		return
+	}
+
+	if _, err := s.chatService.UpdateMessage(*client.UserID, payload.MessageID, &req); err != nil {
+		s.sendError(client, message.Type, chatErrorCode(err, "UPDATE_MESSAGE_FAILED"), err.Error())
+	}
+}
+
+// chatErrorCode maps a chat service error to the code sent to the client,
+// the same cases the chat controller turns into HTTP statuses
+func chatErrorCode(err error, fallback string) string {
+	switch err.Error() {
+	case "permission denied", "unauthorized", "announcements restricted to hosts",
+		"private chat disabled", "private chat restricted to hosts":
+		return "PERMISSION_DENIED"
+	case "muted in chat":
+		return "CHAT_MUTED"
+	case "slow mode":
+		return "SLOW_MODE"
+	case "message blocked by content filter":
+		return "CONTENT_BLOCKED"
+	case "message not found", "meeting not found":
+		return "NOT_FOUND"
+	case "attachment not found":
+		return "INVALID_ATTACHMENT"
+	case "announcements must be meeting-wide":
+		return "INVALID_ANNOUNCEMENT"
+	case "recipient not found", "too many recipients", "private messages cannot be threaded":
+		return "INVALID_RECIPIENTS"
+	case "invalid reaction":
+		return "INVALID_REACTION"
+	case "reaction limit reached":
+		return "REACTION_LIMIT_REACHED"
+	}
+	return fallback
 }
 
-// handleChatReaction handles chat reactions
+// handleChatReaction toggles the client's reaction to a message like the
+// reactions endpoint; the chat service broadcasts the new summary
 func (s *WebSocketService) handleChatReaction(client *models.WebSocketClient, message *models.SignalingMessage) {
-	// Broadcast reaction to all participants in the meeting
-	s.hub.Broadcast <- *message
+	if s.chatService == nil {
+		s.sendError(client, message.Type, "CHAT_UNAVAILABLE", "Chat is not available")
+		return
+	}
+
+	var req models.CreateChatMessageReactionRequest
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		log.Printf("Failed to marshal chat reaction payload: %v", err)
+		return
+	}
+	if err := json.Unmarshal(payloadBytes, &req); err != nil || req.MessageID == uuid.Nil || req.Reaction == "" {
+		s.sendError(client, message.Type, "INVALID_PAYLOAD", "A message ID and a reaction are required")
+		return
+	}
+
+	// The message must belong to the client's meeting
+	var existing models.ChatMessage
+	if err := s.db.Select("meeting_id").Where("id = ?", req.MessageID).First(&existing).Error; err != nil || existing.MeetingID.String() != client.MeetingID {
+		s.sendError(client, message.Type, "NOT_FOUND", "message not found")
+		return
+	}
+
+	if _, err := s.chatService.ToggleReaction(client.UserID, client.PublicUserID, &req); err != nil {
+		s.sendError(client, message.Type, chatErrorCode(err, "REACTION_FAILED"), err.Error())
+	}
+}
+
+// handleChatMarkRead moves the client's read cursor up to a message, like
+// the mark-read-up-to endpoint. The chat service broadcasts the move.
+func (s *WebSocketService) handleChatMarkRead(client *models.WebSocketClient, message *models.SignalingMessage) {
+	if s.chatService == nil {
+		return
+	}
+
+	var payload models.MarkReadUpToRequest
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		log.Printf("Failed to marshal mark read payload: %v", err)
+		return
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil || payload.MessageID == uuid.Nil {
+		s.sendError(client, message.Type, "INVALID_PAYLOAD", "A message ID is required")
+		return
+	}
+
+	meetingID, err := uuid.Parse(client.MeetingID)
+	if err != nil {
+		return
+	}
+	actor := models.MeetingActor{UserID: client.UserID, PublicUserID: client.PublicUserID}
+	if _, err := s.chatService.MarkReadUpTo(meetingID, actor, payload.MessageID); err != nil {
+		s.sendError(client, message.Type, "MARK_READ_FAILED", err.Error())
+	}
 }
 
-// handleChatReadStatus handles chat read status updates
-func (s *WebSocketService) handleChatReadStatus(client *models.WebSocketClient, message *models.SignalingMessage) {
-	// Broadcast read status to all participants in the meeting
-	s.hub.Broadcast <- *message
+// handleQualityStats records the getStats() summary a client sent for its
+// peer connection, like the quality stats endpoint
+func (s *WebSocketService) handleQualityStats(client *models.WebSocketClient, message *models.SignalingMessage) {
+	if s.quality == nil {
+		return
+	}
+
+	var payload models.QualityStatsPayload
+	payloadBytes, err := json.Marshal(message.Data)
+	if err != nil {
+		log.Printf("Failed to marshal quality stats payload: %v", err)
+		return
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		s.sendError(client, message.Type, "INVALID_STATS", "Unreadable stats")
+		return
+	}
+	if err := payload.Validate(); err != nil {
+		s.sendError(client, message.Type, "INVALID_STATS", err.Error())
+		return
+	}
+
+	peer := client.ToPresence("").ToPeer()
+	if _, err := s.quality.RecordSample(peer, &payload); err != nil {
+		if err.Error() == "stats submitted too often" {
+			s.sendError(client, message.Type, "TOO_FREQUENT", "Stats were submitted too often")
+			return
+		}
+		log.Printf("Failed to record quality stats of client %s: %v", client.ID, err)
+	}
 }
 
 // handleChatTyping handles typing indicators
@@ -477,32 +1160,12 @@ func (s *WebSocketService) handleChatTyping(client *models.WebSocketClient, mess
 
 // broadcastToMeeting sends message to all clients in a meeting except the sender
 func (s *WebSocketService) broadcastToMeeting(meetingID string, message models.SignalingMessage, excludeClientID string) {
-	if meetingClients, ok := s.hub.Meetings[meetingID]; ok {
-		for clientID := range meetingClients {
-			if clientID != excludeClientID {
-				s.sendToClient(clientID, message)
-			}
-		}
-	}
+	s.hub.BroadcastToMeeting(meetingID, message, excludeClientID)
 }
 
 // sendToClient sends message to a specific client
 func (s *WebSocketService) sendToClient(clientID string, message models.SignalingMessage) {
-	if client, ok := s.hub.GetClientByID(clientID); ok {
-		select {
-		case client.Send <- message:
-		default:
-			// Client send channel is blocked, close connection
-			close(client.Send)
-			delete(s.hub.Clients, clientID)
-			
-			// Remove from meeting
-			if meetingClients, ok := s.hub.Meetings[client.MeetingID]; ok {
-				delete(meetingClients, clientID)
-				if len(meetingClients) == 0 {
-					delete(s.hub.Meetings, client.MeetingID)
-				}
-			}
-		}
+	if err := s.hub.SendToClient(clientID, message); err != nil {
+		log.Printf("[DEBUG] Failed to send message type: %s to client %s: %v", message.Type, clientID, err)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/your-org/gomeet-backend/internal/models"
+)
+
+func TestWebSocketService_DirectedSignaling(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestChatDB(t, &models.MeetingBan{})
+	meeting, _ := createTestMeeting(t, db)
+
+	service := NewWebSocketService(db, nil, nil)
+	service.StartHub()
+	router := gin.New()
+	router.GET("/meetings/:id/ws", service.HandleWebSocket)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// Each connection is a guest; its client ID follows from its session
+	dial := func(name string) (*websocket.Conn, string) {
+		sessionID, clientID := createTestGuest(t, db, name)
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/meetings/" + meeting.ID.String() + "/ws?sessionId=" + sessionID
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn, clientID
+	}
+	send := func(conn *websocket.Conn, messageType models.SignalingMessageType, to string) {
+		require.NoError(t, conn.WriteJSON(models.SignalingMessage{
+			Type: messageType,
+			To:   to,
+			Data: models.OfferAnswerPayload{SDP: "v=0"},
+		}))
+	}
+
+	alice, aliceID := dial("alice")
+	bob, bobID := dial("bob")
+	carol, _ := dial("carol")
+	require.Eventually(t, func() bool {
+		return service.GetParticipantCount(meeting.ID.String()) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The offer reaches Bob alone, and untargeted signaling bounces back
+	send(alice, models.SignalingTypeOffer, bobID)
+	offer, _ := readUntil(t, bob, models.SignalingTypeOffer)
+	assert.Equal(t, aliceID, offer.From)
+	assert.Equal(t, bobID, offer.To)
+
+	send(carol, models.SignalingTypeAnswer, "")
+	rejected, before := readUntil(t, carol, models.SignalingTypeSignalingError)
+	for _, message := range before {
+		assert.NotEqual(t, models.SignalingTypeOffer, message.Type)
+	}
+	data := rejected.Data.(map[string]interface{})
+	assert.Equal(t, "TARGET_REQUIRED", data["code"])
+	assert.Equal(t, string(models.SignalingTypeAnswer), data["messageType"])
+
+	// Signaling a peer that left tells the sender so
+	bob.Close()
+	require.Eventually(t, func() bool {
+		return service.GetParticipantCount(meeting.ID.String()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	send(alice, models.SignalingTypeIceCandidate, bobID)
+	rejected, _ = readUntil(t, alice, models.SignalingTypeSignalingError)
+	data = rejected.Data.(map[string]interface{})
+	assert.Equal(t, "TARGET_GONE", data["code"])
+	assert.Equal(t, bobID, data["to"])
+
+	// Nobody can connect under another client's ID
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/meetings/" + meeting.ID.String() + "/ws?clientId=" + aliceID
+	impostor, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer impostor.Close()
+	refused, _ := readUntil(t, impostor, models.SignalingTypeError)
+	assert.Equal(t, "CLIENT_ID_MISMATCH", refused.Data.(map[string]interface{})["code"])
+	assert.Equal(t, 2, service.GetParticipantCount(meeting.ID.String()))
+}
@@ -157,4 +157,17 @@ func GetUserIDUUID(c *gin.Context) (uuid.UUID, bool) {
 	default:
 		return uuid.Nil, false
 	}
-}
\ No newline at end of file
+}
+
+// GetSessionID helper function to get the current session ID from context
+func GetSessionID(c *gin.Context) uuid.UUID {
+	sessionID, exists := c.Get("sessionID")
+	if !exists {
+		return uuid.Nil
+	}
+
+	if id, ok := sessionID.(uuid.UUID); ok {
+		return id
+	}
+	return uuid.Nil
+}
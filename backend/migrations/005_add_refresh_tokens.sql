@@ -0,0 +1,24 @@
+-- Migration: Add refresh token storage
+-- Description: Persist refresh tokens so they can be rotated and revoked
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    replaced_by_id UUID,
+    user_agent VARCHAR(500),
+    ip_address VARCHAR(45),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    session_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_active ON refresh_tokens(user_id, expires_at) WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE refresh_tokens IS 'Issued refresh tokens; each family is one login session';
+COMMENT ON COLUMN refresh_tokens.id IS 'JWT ID (jti) of the refresh token';
+COMMENT ON COLUMN refresh_tokens.family_id IS 'Rotation chain the token belongs to';
+COMMENT ON COLUMN refresh_tokens.replaced_by_id IS 'Token issued when this one was rotated; presenting a rotated token revokes the family';
@@ -0,0 +1,17 @@
+-- Migration: Add meeting lifecycle
+-- Description: Replace the bare is_active flag with a scheduled/live/ended/archived status
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'scheduled'
+    CHECK (status IN ('scheduled', 'live', 'ended', 'archived'));
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS started_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS ended_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+-- Meetings that were already running are live
+UPDATE meetings SET status = 'live', started_at = COALESCE(started_at, updated_at)
+WHERE is_active = TRUE AND status = 'scheduled';
+
+CREATE INDEX IF NOT EXISTS idx_meetings_status ON meetings(status);
+
+COMMENT ON COLUMN meetings.status IS 'Lifecycle state: scheduled, live, ended or archived';
+COMMENT ON COLUMN meetings.is_active IS 'Kept in sync with status = live for older clients';
@@ -0,0 +1,14 @@
+-- Migration: Add participant roles
+-- Description: Per-meeting roles used for in-meeting authorization
+
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'attendee'
+    CHECK (role IN ('host', 'co-host', 'presenter', 'attendee', 'viewer'));
+
+-- Hosts that joined their own meetings
+UPDATE participants p SET role = 'host'
+FROM meetings m
+WHERE p.meeting_id = m.id AND p.user_id = m.host_id;
+
+CREATE INDEX IF NOT EXISTS idx_participants_meeting_role ON participants(meeting_id, role);
+
+COMMENT ON COLUMN participants.role IS 'Meeting role: host, co-host, presenter, attendee or viewer';
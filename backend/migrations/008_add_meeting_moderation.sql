@@ -0,0 +1,40 @@
+-- Migration: Add meeting moderation
+-- Description: Meeting locks, bans and an audit log of moderation actions
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS is_locked BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS locked_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS meeting_bans (
+    id UUID PRIMARY KEY,
+    meeting_id UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    public_user_id UUID REFERENCES public_users(id) ON DELETE CASCADE,
+    name VARCHAR(255),
+    banned_by_id UUID NOT NULL REFERENCES users(id),
+    reason VARCHAR(500),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((user_id IS NOT NULL) <> (public_user_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_bans_meeting_id ON meeting_bans(meeting_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_meeting_bans_user ON meeting_bans(meeting_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_meeting_bans_public_user ON meeting_bans(meeting_id, public_user_id) WHERE public_user_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS moderation_logs (
+    id UUID PRIMARY KEY,
+    meeting_id UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL REFERENCES users(id),
+    action VARCHAR(20) NOT NULL CHECK (action IN ('mute', 'remove', 'ban', 'unban', 'lock', 'unlock')),
+    target_participant_id UUID,
+    target_user_id UUID,
+    target_public_user_id UUID,
+    reason VARCHAR(500),
+    details VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_logs_meeting_created ON moderation_logs(meeting_id, created_at DESC);
+
+COMMENT ON COLUMN meetings.is_locked IS 'Locked meetings refuse participants that are not already in the room';
+COMMENT ON TABLE meeting_bans IS 'Users and public sessions that may not rejoin a meeting';
+COMMENT ON TABLE moderation_logs IS 'Audit log of host moderation actions';
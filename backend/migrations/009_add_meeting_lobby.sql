@@ -0,0 +1,14 @@
+-- Migration: Add meeting lobby
+-- Description: Optional waiting room where hosts admit or deny newcomers
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS lobby_mode VARCHAR(20) NOT NULL DEFAULT 'off'
+    CHECK (lobby_mode IN ('off', 'guests', 'everyone'));
+
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS lobby_status VARCHAR(20) NOT NULL DEFAULT 'admitted'
+    CHECK (lobby_status IN ('pending', 'admitted', 'denied'));
+
+CREATE INDEX IF NOT EXISTS idx_participants_lobby_pending ON participants(meeting_id, joined_at)
+    WHERE lobby_status = 'pending';
+
+COMMENT ON COLUMN meetings.lobby_mode IS 'Who waits for admission: off, guests (public users) or everyone except previously admitted participants';
+COMMENT ON COLUMN participants.lobby_status IS 'Admission state: pending (in the lobby), admitted or denied';
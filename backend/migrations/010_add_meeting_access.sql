@@ -0,0 +1,22 @@
+-- Migration: Add meeting access control
+-- Description: Hashed meeting passcodes and signed, revocable invite links
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS passcode_hash VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS meeting_invites (
+    id UUID PRIMARY KEY,
+    meeting_id UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    role VARCHAR(20) CHECK (role IN ('co-host', 'presenter', 'attendee', 'viewer')),
+    max_uses INTEGER NOT NULL DEFAULT 0 CHECK (max_uses >= 0),
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_invites_meeting_id ON meeting_invites(meeting_id, created_at DESC);
+
+COMMENT ON COLUMN meetings.passcode_hash IS 'bcrypt hash of the meeting passcode; NULL when the meeting is not protected';
+COMMENT ON TABLE meeting_invites IS 'Invite links; the signed token references the row, which tracks uses and revocation';
+COMMENT ON COLUMN meeting_invites.max_uses IS '0 means unlimited';
@@ -0,0 +1,31 @@
+-- Migration: Add recurring meetings
+-- Description: Meeting series with an RRULE; each occurrence is its own meeting row
+
+CREATE TABLE IF NOT EXISTS meeting_series (
+    id UUID PRIMARY KEY,
+    host_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    recurrence_rule VARCHAR(500) NOT NULL,
+    exception_dates TEXT,
+    lobby_mode VARCHAR(20) NOT NULL DEFAULT 'off' CHECK (lobby_mode IN ('off', 'guests', 'everyone')),
+    passcode_hash VARCHAR(255),
+    invitees TEXT,
+    materialized_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_series_host_id ON meeting_series(host_id, materialized_until);
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES meeting_series(id) ON DELETE SET NULL;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS occurrence_time TIMESTAMP WITH TIME ZONE;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS is_detached BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_meetings_series_occurrence ON meetings(series_id, occurrence_time);
+
+COMMENT ON TABLE meeting_series IS 'Recurring meeting template; occurrences are materialized into meetings ahead of time';
+COMMENT ON COLUMN meeting_series.exception_dates IS 'Cancelled occurrences (EXDATE), comma-separated RFC 3339 timestamps';
+COMMENT ON COLUMN meeting_series.invitees IS 'JSON list of participants copied to each new occurrence';
+COMMENT ON COLUMN meetings.occurrence_time IS 'Start time the series rule gave this occurrence (RECURRENCE-ID)';
+COMMENT ON COLUMN meetings.is_detached IS 'Occurrence was edited on its own and is left alone by series-wide edits';
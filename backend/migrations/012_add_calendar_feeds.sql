@@ -0,0 +1,28 @@
+-- Migration: Add calendar feeds
+-- Description: Per-user iCalendar subscription feeds and cancelled meetings published through them
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    last_accessed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS meeting_cancellations (
+    id UUID PRIMARY KEY,
+    meeting_id UUID NOT NULL,
+    host_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    sequence INTEGER NOT NULL DEFAULT 0,
+    cancelled_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_cancellations_host_start ON meeting_cancellations(host_id, start_time);
+CREATE INDEX IF NOT EXISTS idx_meeting_cancellations_meeting_id ON meeting_cancellations(meeting_id);
+
+COMMENT ON TABLE calendar_feeds IS 'Calendar subscription feeds; the feed URL carries a secret token';
+COMMENT ON COLUMN calendar_feeds.token_hash IS 'SHA-256 hex digest of the feed token';
+COMMENT ON TABLE meeting_cancellations IS 'Upcoming meetings that were deleted, published as STATUS:CANCELLED in calendar feeds';
+COMMENT ON COLUMN meeting_cancellations.meeting_id IS 'ID of the deleted meeting; no foreign key as the meeting row is gone';
@@ -0,0 +1,26 @@
+-- Migration: Add meeting duration, end time and time zones
+-- Description: Planned duration and end of meetings, IANA time zones on meetings, series and user preferences
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS duration INTEGER NOT NULL DEFAULT 60 CHECK (duration BETWEEN 1 AND 1440);
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS end_time TIMESTAMP WITH TIME ZONE;
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+UPDATE meetings SET end_time = start_time + make_interval(mins => duration) WHERE end_time IS NULL;
+ALTER TABLE meetings ALTER COLUMN end_time SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_meetings_host_end ON meetings(host_id, end_time);
+
+ALTER TABLE meeting_series ADD COLUMN IF NOT EXISTS duration INTEGER NOT NULL DEFAULT 60 CHECK (duration BETWEEN 1 AND 1440);
+ALTER TABLE meeting_series ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS time_zone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE meeting_cancellations ADD COLUMN IF NOT EXISTS end_time TIMESTAMP WITH TIME ZONE;
+UPDATE meeting_cancellations SET end_time = start_time + INTERVAL '1 hour' WHERE end_time IS NULL;
+ALTER TABLE meeting_cancellations ALTER COLUMN end_time SET NOT NULL;
+
+COMMENT ON COLUMN meetings.duration IS 'Planned length in minutes';
+COMMENT ON COLUMN meetings.end_time IS 'Planned end, start_time plus duration; a live meeting may run past it';
+COMMENT ON COLUMN meetings.time_zone IS 'IANA time zone the meeting was scheduled in';
+COMMENT ON COLUMN meeting_series.time_zone IS 'IANA time zone the recurrence rule is expanded in, so occurrences keep their local time across DST changes';
+COMMENT ON COLUMN users.time_zone IS 'IANA time zone preference, the default for new meetings';
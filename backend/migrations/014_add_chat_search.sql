@@ -0,0 +1,18 @@
+-- Migration: Add chat history keyset index and full-text search
+-- Description: Index for cursor pagination on (created_at, id) and a tsvector over message content
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_meeting_created_id
+ON chat_messages(meeting_id, created_at DESC, id DESC);
+
+-- Superseded by the index above
+DROP INDEX IF EXISTS idx_chat_messages_meeting_created;
+
+-- 'simple' rather than a language configuration: meetings chat in any language,
+-- and ChatService.SearchMessages queries with the same configuration
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS search_vector tsvector
+GENERATED ALWAYS AS (to_tsvector('simple', content)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_search
+ON chat_messages USING GIN (search_vector);
+
+COMMENT ON COLUMN chat_messages.search_vector IS 'Full-text search vector of content, maintained by Postgres';
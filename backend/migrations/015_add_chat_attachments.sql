@@ -0,0 +1,25 @@
+-- Migration: Add chat attachments
+-- Description: Files uploaded to a meeting's chat, stored through the attachment storage driver and claimed by the message that sends them
+
+CREATE TABLE IF NOT EXISTS chat_attachments (
+    id UUID PRIMARY KEY,
+    meeting_id UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    message_id UUID UNIQUE REFERENCES chat_messages(id) ON DELETE SET NULL,
+    uploader_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    uploader_public_user_id UUID REFERENCES public_users(id) ON DELETE SET NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    storage_key VARCHAR(500) NOT NULL,
+    thumbnail_key VARCHAR(500),
+    thumbnail_type VARCHAR(100),
+    width INTEGER,
+    height INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_attachments_meeting ON chat_attachments(meeting_id);
+
+COMMENT ON TABLE chat_attachments IS 'Chat files; the objects themselves live in the attachment storage (local disk or S3) under storage_key';
+COMMENT ON COLUMN chat_attachments.message_id IS 'Message that sent the file; NULL while the upload is not sent yet';
+COMMENT ON COLUMN chat_attachments.content_type IS 'MIME type detected from the file content';
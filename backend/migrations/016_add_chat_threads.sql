@@ -0,0 +1,40 @@
+-- Migration: Add chat threads
+-- Description: Denormalized reply count and last reply time on the message that starts a thread, and indexes for paging the timeline and threads
+
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS reply_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS last_reply_at TIMESTAMP;
+
+-- Threads are one level deep: replies to replies join the thread of the
+-- message at the top of their chain
+WITH RECURSIVE ancestry(id, root_id, depth) AS (
+    SELECT id, reply_to_id, 1 FROM chat_messages WHERE reply_to_id IS NOT NULL
+    UNION ALL
+    SELECT a.id, p.reply_to_id, a.depth + 1
+    FROM ancestry a JOIN chat_messages p ON p.id = a.root_id
+    WHERE p.reply_to_id IS NOT NULL
+)
+UPDATE chat_messages m SET reply_to_id = r.root_id
+FROM (SELECT DISTINCT ON (id) id, root_id FROM ancestry ORDER BY id, depth DESC) r
+WHERE m.id = r.id AND m.reply_to_id <> r.root_id;
+
+UPDATE chat_messages m SET reply_count = t.reply_count, last_reply_at = t.last_reply_at
+FROM (
+    SELECT reply_to_id, COUNT(*) AS reply_count, MAX(created_at) AS last_reply_at
+    FROM chat_messages
+    WHERE reply_to_id IS NOT NULL AND is_deleted = FALSE
+    GROUP BY reply_to_id
+) t
+WHERE m.id = t.reply_to_id;
+
+-- The main timeline leaves replies out
+CREATE INDEX IF NOT EXISTS idx_chat_messages_timeline
+ON chat_messages(meeting_id, created_at DESC, id DESC) WHERE reply_to_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_thread
+ON chat_messages(reply_to_id, created_at DESC, id DESC) WHERE reply_to_id IS NOT NULL;
+
+-- Superseded by the thread index
+DROP INDEX IF EXISTS idx_chat_messages_reply_to;
+
+COMMENT ON COLUMN chat_messages.reply_count IS 'Replies in the thread this message starts, not counting deleted ones';
+COMMENT ON COLUMN chat_messages.last_reply_at IS 'Time of the latest reply in the thread this message starts';
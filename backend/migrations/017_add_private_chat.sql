@@ -0,0 +1,28 @@
+-- Migration: Add private chat
+-- Description: Private chat messages addressed to some participants, and the per-meeting policy on who may send them
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS private_chat VARCHAR(20) NOT NULL DEFAULT 'everyone'
+    CHECK (private_chat IN ('everyone', 'host-only', 'off'));
+ALTER TABLE meeting_series ADD COLUMN IF NOT EXISTS private_chat VARCHAR(20) NOT NULL DEFAULT 'everyone'
+    CHECK (private_chat IN ('everyone', 'host-only', 'off'));
+
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS is_private BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS chat_message_recipients (
+    id UUID PRIMARY KEY,
+    message_id UUID NOT NULL REFERENCES chat_messages(id) ON DELETE CASCADE,
+    participant_id UUID NOT NULL REFERENCES participants(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    public_user_id UUID REFERENCES public_users(id) ON DELETE CASCADE,
+    name VARCHAR(255),
+    UNIQUE (message_id, participant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_message_recipients_message ON chat_message_recipients(message_id);
+CREATE INDEX IF NOT EXISTS idx_chat_message_recipients_user ON chat_message_recipients(user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_chat_message_recipients_public_user ON chat_message_recipients(public_user_id) WHERE public_user_id IS NOT NULL;
+
+COMMENT ON COLUMN meetings.private_chat IS 'Who may send private chat messages: everyone, host-only (only to or from the host and co-hosts) or off';
+COMMENT ON COLUMN meeting_series.private_chat IS 'Private chat policy copied to the occurrences of the series';
+COMMENT ON COLUMN chat_messages.is_private IS 'Whether the message is only visible to its sender and the participants in chat_message_recipients';
+COMMENT ON TABLE chat_message_recipients IS 'Recipients of private chat messages; user IDs are copied from the participant so visibility checks need no join';
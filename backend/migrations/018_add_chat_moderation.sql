@@ -0,0 +1,40 @@
+-- Migration: Add chat moderation
+-- Description: Slow mode, per-participant chat mute, tombstones of messages removed by moderators, and the queue of messages flagged by the content filter
+
+ALTER TABLE meetings ADD COLUMN IF NOT EXISTS chat_slow_mode INTEGER NOT NULL DEFAULT 0
+    CHECK (chat_slow_mode BETWEEN 0 AND 3600);
+
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS is_chat_muted BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS chat_muted_until TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS deleted_by_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE moderation_logs ADD COLUMN IF NOT EXISTS target_message_id UUID;
+ALTER TABLE moderation_logs DROP CONSTRAINT IF EXISTS moderation_logs_action_check;
+ALTER TABLE moderation_logs ADD CONSTRAINT moderation_logs_action_check CHECK (action IN (
+    'mute', 'remove', 'ban', 'unban', 'lock', 'unlock',
+    'delete-message', 'chat-mute', 'chat-unmute', 'slow-mode', 'dismiss-flag'));
+
+CREATE TABLE IF NOT EXISTS chat_message_flags (
+    id UUID PRIMARY KEY,
+    meeting_id UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    message_id UUID NOT NULL REFERENCES chat_messages(id) ON DELETE CASCADE,
+    rule VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'dismissed', 'removed')),
+    reviewed_by_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_message_flags_queue ON chat_message_flags(meeting_id, status, created_at);
+CREATE INDEX IF NOT EXISTS idx_chat_message_flags_message ON chat_message_flags(message_id);
+
+-- Slow mode looks up the sender's latest message
+CREATE INDEX IF NOT EXISTS idx_chat_messages_sender ON chat_messages(meeting_id, user_id, created_at DESC);
+
+COMMENT ON COLUMN meetings.chat_slow_mode IS 'Seconds a participant waits between two chat messages; 0 is off. Hosts and co-hosts are exempt';
+COMMENT ON COLUMN participants.is_chat_muted IS 'Whether a moderator muted the participant in chat';
+COMMENT ON COLUMN participants.chat_muted_until IS 'End of a timed chat mute; NULL keeps the participant muted until a moderator lifts it';
+COMMENT ON COLUMN chat_messages.deleted_by_id IS 'Moderator who removed the message; such messages stay in the history as empty tombstones';
+COMMENT ON COLUMN moderation_logs.target_message_id IS 'Chat message a moderation action was about';
+COMMENT ON TABLE chat_message_flags IS 'Messages the chat content filter flagged, one row per matching rule, for hosts and co-hosts to review';
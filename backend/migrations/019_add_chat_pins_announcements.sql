@@ -0,0 +1,34 @@
+-- Migration: Add pinned messages and announcements
+-- Description: Hosts pin a bounded number of messages per meeting and send announcements that may ask participants for acknowledgement
+
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS is_pinned BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS pinned_by_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS requires_ack BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE chat_messages DROP CONSTRAINT IF EXISTS chat_messages_type_check;
+ALTER TABLE chat_messages ADD CONSTRAINT chat_messages_type_check CHECK (
+    message_type IN ('text', 'image', 'file', 'system', 'reaction', 'announcement'));
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_pinned ON chat_messages(meeting_id, pinned_at DESC) WHERE is_pinned = TRUE;
+
+CREATE TABLE IF NOT EXISTS chat_message_acknowledgements (
+    id UUID PRIMARY KEY,
+    message_id UUID NOT NULL REFERENCES chat_messages(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    public_user_id UUID REFERENCES public_users(id) ON DELETE CASCADE,
+    acknowledged_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chat_message_acknowledgements_user_check CHECK (
+        (user_id IS NOT NULL AND public_user_id IS NULL) OR
+        (user_id IS NULL AND public_user_id IS NOT NULL)
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_message_acknowledgements_user ON chat_message_acknowledgements(message_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_message_acknowledgements_public_user ON chat_message_acknowledgements(message_id, public_user_id) WHERE public_user_id IS NOT NULL;
+
+COMMENT ON COLUMN chat_messages.is_pinned IS 'Whether a host or co-host pinned the message; a meeting keeps at most 5 pinned';
+COMMENT ON COLUMN chat_messages.pinned_by_id IS 'Host or co-host who pinned the message';
+COMMENT ON COLUMN chat_messages.requires_ack IS 'Whether an announcement asks participants to acknowledge it';
+COMMENT ON TABLE chat_message_acknowledgements IS 'Participants who acknowledged an announcement asking for it';
@@ -0,0 +1,42 @@
+-- Migration: Add chat read cursors
+-- Description: Replace per-message read receipts with one read cursor per reader for a meeting's main timeline and for each thread they read
+
+CREATE TABLE IF NOT EXISTS chat_read_cursors (
+    id UUID PRIMARY KEY,
+    meeting_id UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    thread_id UUID REFERENCES chat_messages(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    public_user_id UUID REFERENCES public_users(id) ON DELETE CASCADE,
+    last_read_message_id UUID NOT NULL REFERENCES chat_messages(id) ON DELETE CASCADE,
+    last_read_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT chat_read_cursors_user_check CHECK (
+        (user_id IS NOT NULL AND public_user_id IS NULL) OR
+        (user_id IS NULL AND public_user_id IS NOT NULL)
+    )
+);
+
+-- One cursor per reader for the timeline (the nil thread) and for each thread
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_read_cursors_user
+ON chat_read_cursors(meeting_id, user_id, COALESCE(thread_id, '00000000-0000-0000-0000-000000000000'))
+WHERE user_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_read_cursors_public_user
+ON chat_read_cursors(meeting_id, public_user_id, COALESCE(thread_id, '00000000-0000-0000-0000-000000000000'))
+WHERE public_user_id IS NOT NULL;
+
+-- Each reader's cursor starts at the latest message they had marked read
+INSERT INTO chat_read_cursors (id, meeting_id, thread_id, user_id, public_user_id, last_read_message_id, last_read_at, updated_at)
+SELECT DISTINCT ON (m.meeting_id, m.reply_to_id, rs.user_id, rs.public_user_id)
+    gen_random_uuid(), m.meeting_id, m.reply_to_id, rs.user_id, rs.public_user_id, m.id, m.created_at, rs.read_at
+FROM chat_message_read_status rs
+JOIN chat_messages m ON m.id = rs.message_id
+ORDER BY m.meeting_id, m.reply_to_id, rs.user_id, rs.public_user_id, m.created_at DESC, m.id DESC
+ON CONFLICT DO NOTHING;
+
+DROP TABLE IF EXISTS chat_message_read_status;
+
+COMMENT ON TABLE chat_read_cursors IS 'How far each reader has read a meeting''s chat: messages up to last_read_message_id count as read';
+COMMENT ON COLUMN chat_read_cursors.thread_id IS 'Thread the cursor is for; NULL for the main timeline';
+COMMENT ON COLUMN chat_read_cursors.last_read_at IS 'Creation time of the last read message; with its ID, the cursor''s position in the history';
@@ -0,0 +1,9 @@
+-- Migration: Add chat reaction limits
+-- Description: Widen reactions to hold emoji ZWJ sequences and custom emoji names, and index reactions by emoji for per-message summaries
+
+ALTER TABLE chat_message_reactions ALTER COLUMN reaction TYPE VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_chat_reactions_message_reaction
+ON chat_message_reactions(message_id, reaction, created_at);
+
+COMMENT ON COLUMN chat_message_reactions.reaction IS 'A single Unicode emoji, possibly a ZWJ sequence or with a skin tone, or a custom emoji name like :partyparrot:';
@@ -0,0 +1,19 @@
+-- Migration: Add meeting media policies
+-- Description: Store per-meeting WebRTC media policies enforced on the offers, answers and ICE candidates relayed between peers; meetings without one follow the server default
+
+CREATE TABLE IF NOT EXISTS meeting_media_policies (
+    meeting_id UUID PRIMARY KEY REFERENCES meetings(id) ON DELETE CASCADE,
+    allowed_codecs VARCHAR(500) NOT NULL DEFAULT '',
+    max_bitrate INTEGER NOT NULL DEFAULT 0,
+    simulcast BOOLEAN NOT NULL DEFAULT TRUE,
+    privacy_mode BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT meeting_media_policies_max_bitrate_check CHECK (max_bitrate >= 0)
+);
+
+COMMENT ON TABLE meeting_media_policies IS 'Per-meeting WebRTC media policy; meetings without a row follow the server default from WEBRTC_* settings';
+COMMENT ON COLUMN meeting_media_policies.allowed_codecs IS 'Comma-separated lower case codec names peers may negotiate, like opus,vp8; empty allows any codec';
+COMMENT ON COLUMN meeting_media_policies.max_bitrate IS 'Cap on each video stream in Kbps, written into SDP as b=AS; 0 is unlimited';
+COMMENT ON COLUMN meeting_media_policies.simulcast IS 'Whether simulcast attributes are kept in offers and answers';
+COMMENT ON COLUMN meeting_media_policies.privacy_mode IS 'Whether host ICE candidates are stripped so peers never learn each other''s local addresses';
@@ -0,0 +1,43 @@
+-- Migration: Add WebRTC quality samples
+-- Description: Store the connection quality stats clients report for their peers as a time series per peer, kept for WEBRTC_STATS_RETENTION
+
+CREATE TABLE IF NOT EXISTS webrtc_quality_samples (
+    id UUID PRIMARY KEY,
+    meeting_id UUID NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    peer_id VARCHAR(100) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    public_user_id UUID REFERENCES public_users(id) ON DELETE SET NULL,
+    name VARCHAR(255),
+    rtt DOUBLE PRECISION NOT NULL DEFAULT 0,
+    jitter DOUBLE PRECISION NOT NULL DEFAULT 0,
+    packet_loss DOUBLE PRECISION NOT NULL DEFAULT 0,
+    bitrate DOUBLE PRECISION NOT NULL DEFAULT 0,
+    frame_rate DOUBLE PRECISION NOT NULL DEFAULT 0,
+    candidate_type VARCHAR(10),
+    score INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT webrtc_quality_samples_packet_loss_check CHECK (packet_loss >= 0 AND packet_loss <= 100),
+    CONSTRAINT webrtc_quality_samples_candidate_type_check CHECK (candidate_type IS NULL OR candidate_type IN ('', 'host', 'srflx', 'prflx', 'relay')),
+    CONSTRAINT webrtc_quality_samples_score_check CHECK (score >= 0 AND score <= 100)
+);
+
+-- Meeting summaries read a recent window; peer histories read one peer's samples in order
+CREATE INDEX IF NOT EXISTS idx_webrtc_quality_samples_meeting_created
+ON webrtc_quality_samples(meeting_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_webrtc_quality_samples_peer_created
+ON webrtc_quality_samples(meeting_id, peer_id, created_at);
+
+-- Pruning drops samples past retention
+CREATE INDEX IF NOT EXISTS idx_webrtc_quality_samples_created_at
+ON webrtc_quality_samples(created_at);
+
+COMMENT ON TABLE webrtc_quality_samples IS 'Client-reported getStats() summaries per WebRTC peer, pruned after WEBRTC_STATS_RETENTION';
+COMMENT ON COLUMN webrtc_quality_samples.peer_id IS 'Client ID of the WebSocket connection the peer belongs to';
+COMMENT ON COLUMN webrtc_quality_samples.rtt IS 'Round-trip time in milliseconds';
+COMMENT ON COLUMN webrtc_quality_samples.jitter IS 'Jitter in milliseconds';
+COMMENT ON COLUMN webrtc_quality_samples.packet_loss IS 'Percent of packets lost';
+COMMENT ON COLUMN webrtc_quality_samples.bitrate IS 'Bitrate in Kbps';
+COMMENT ON COLUMN webrtc_quality_samples.candidate_type IS 'Type of the local candidate of the selected candidate pair: host, srflx, prflx or relay';
+COMMENT ON COLUMN webrtc_quality_samples.score IS 'Quality score from 0 to 100, from a simplified E-model of the RTT, jitter and packet loss';
@@ -0,0 +1,17 @@
+-- Migration: Make meeting series occurrences unique
+-- Description: A series occurrence is materialized into exactly one meeting, even when two requests extend the series at once
+
+-- Occurrences duplicated before this migration become one-off meetings,
+-- keeping the oldest in the series; nothing is deleted
+UPDATE meetings AS m
+SET series_id = NULL
+FROM meetings AS d
+WHERE m.series_id = d.series_id
+  AND m.occurrence_time = d.occurrence_time
+  AND (m.created_at, m.id) > (d.created_at, d.id);
+
+DROP INDEX IF EXISTS idx_meetings_series_occurrence;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_meetings_series_occurrence ON meetings(series_id, occurrence_time);
+
+COMMENT ON INDEX idx_meetings_series_occurrence IS 'One meeting per series occurrence; materializing inserts with ON CONFLICT DO NOTHING';
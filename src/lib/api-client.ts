@@ -172,9 +172,18 @@ class APIClient {
   }
 
   async logout(): Promise<void> {
+    const refreshToken =
+      typeof window !== "undefined" &&
+      typeof localStorage !== "undefined" &&
+      typeof localStorage.getItem === "function"
+        ? localStorage.getItem("refreshToken")
+        : null;
+
     try {
       await this.request("/auth/logout", {
         method: "POST",
+        // Without a refresh token the server ends the bearer token's session
+        body: refreshToken ? JSON.stringify({ refreshToken }) : undefined,
       });
     } finally {
       // Hapus token regardless of API call success
@@ -230,14 +239,14 @@ class APIClient {
 
     const response = await this.request<{
       success: boolean;
-      data: { accessToken: string };
+      data: { accessToken: string; refreshToken: string };
     }>("/auth/refresh", {
       method: "POST",
       body: JSON.stringify({ refreshToken }),
     });
 
-    // Update access token
-    this.setTokens(response.data.accessToken, refreshToken);
+    // Refresh tokens are rotated: keep the new one, the old one is revoked
+    this.setTokens(response.data.accessToken, response.data.refreshToken);
 
     return response.data.accessToken;
   }
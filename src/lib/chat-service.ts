@@ -387,13 +387,6 @@ export class ChatService {
         throw new Error("Invalid response structure: Missing data property");
       }
 
-      // Send via WebSocket for real-time delivery
-      this.sendWebSocketMessage("chat-message", {
-        message: response.data,
-        userId: this.config.token ? undefined : "current", // Will be set by backend
-        publicUserId: this.config.sessionId ? undefined : "current", // Will be set by backend
-      });
-
       this.emit("message-sent", response.data);
       return response.data;
     } catch (error) {
@@ -443,13 +436,6 @@ export class ChatService {
         throw new Error(response.message || "Failed to edit message");
       }
 
-      // Send via WebSocket for real-time update
-      this.sendWebSocketMessage("chat-message-edit", {
-        message: { id: messageId, content } as ChatMessage,
-        userId: this.config.token ? undefined : "current",
-        publicUserId: this.config.sessionId ? undefined : "current",
-      });
-
       this.emit("message-edited", messageId, content);
     } catch (error) {
       console.error("Failed to edit message:", error);
@@ -494,13 +480,6 @@ export class ChatService {
         throw new Error(response.message || "Failed to delete message");
       }
 
-      // Send via WebSocket for real-time update
-      this.sendWebSocketMessage("chat-message-delete", {
-        message: { id: messageId } as ChatMessage,
-        userId: this.config.token ? undefined : "current",
-        publicUserId: this.config.sessionId ? undefined : "current",
-      });
-
       this.emit("message-deleted", messageId);
     } catch (error) {
       console.error("Failed to delete message:", error);
@@ -546,14 +525,6 @@ export class ChatService {
         throw new Error(response.message || "Failed to add reaction");
       }
 
-      // Send via WebSocket for real-time update
-      this.sendWebSocketMessage("chat-reaction", {
-        message: { id: messageId } as ChatMessage,
-        reaction,
-        userId: this.config.token ? undefined : "current",
-        publicUserId: this.config.sessionId ? undefined : "current",
-      });
-
       this.emit("reaction-added", messageId, reaction);
     } catch (error) {
       console.error("Failed to add reaction:", error);
@@ -623,14 +594,6 @@ export class ChatService {
         throw new Error(response.message || "Failed to mark messages as read");
       }
 
-      // Send via WebSocket for real-time update
-      this.sendWebSocketMessage("chat-read-status", {
-        messageId: "all",
-        userId: this.config.token ? undefined : "current",
-        publicUserId: this.config.sessionId ? undefined : "current",
-        readAt: new Date().toISOString(),
-      });
-
       this.emit("messages-read");
     } catch (error) {
       console.error("Failed to mark messages as read:", error);